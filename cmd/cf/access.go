@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var accessDecisions = []string{"allow", "deny", "bypass", "non_identity"}
+
+type accessApp struct {
+	ID              string `json:"id,omitempty"`
+	Name            string `json:"name"`
+	Domain          string `json:"domain"`
+	Type            string `json:"type"`
+	SessionDuration string `json:"session_duration,omitempty"`
+	AUD             string `json:"aud,omitempty"`
+	CreatedAt       string `json:"created_at,omitempty"`
+}
+
+type accessPolicy struct {
+	ID         string           `json:"id,omitempty"`
+	Name       string           `json:"name"`
+	Decision   string           `json:"decision"`
+	Precedence int              `json:"precedence,omitempty"`
+	Include    []map[string]any `json:"include"`
+	Exclude    []map[string]any `json:"exclude,omitempty"`
+	Require    []map[string]any `json:"require,omitempty"`
+}
+
+func runAccess(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf access apps|policies|service-tokens ...")
+	}
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	base := "/accounts/" + accountID + "/access"
+
+	switch args[0] {
+	case "apps":
+		return runAccessApps(base+"/apps", args[1:])
+	case "policies":
+		return runAccessPolicies(base+"/apps", args[1:])
+	case "service-tokens":
+		return runAccessServiceTokens(base+"/service_tokens", args[1:])
+	}
+	return errors.New("unknown access command. run: cf help")
+}
+
+func runAccessApps(path string, args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+
+	switch action {
+	case "list":
+		apps, err := listAll[accessApp](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "DOMAIN", "TYPE", "SESSION"}}
+		for _, a := range apps {
+			t.Rows = append(t.Rows, []string{a.ID, a.Name, a.Domain, a.Type, a.SessionDuration})
+		}
+		return printList(apps, t, func() {
+			if len(apps) == 0 {
+				fmt.Println("No Access applications.")
+				return
+			}
+			for _, a := range apps {
+				fmt.Printf("%s\t%s\t%s\n", a.ID, a.Name, a.Domain)
+			}
+		})
+	case "create":
+		if len(positional) == 0 || flags["domain"] == "" {
+			return usageErrorf("usage: cf access apps create <name> --domain <host[/path]> [--session-duration 24h] [--include <rules>]")
+		}
+		app := accessApp{Name: positional[0], Domain: flags["domain"], Type: "self_hosted", SessionDuration: flags["session-duration"]}
+		if app.SessionDuration == "" {
+			app.SessionDuration = "24h"
+		}
+		// Parse rules before creating anything so a typo does not leave an
+		// unprotected app behind.
+		var include []map[string]any
+		if v := flags["include"]; v != "" {
+			var err error
+			if include, err = parseAccessRules(v); err != nil {
+				return err
+			}
+		}
+		resp, err := requestCF(http.MethodPost, path, app)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, &app); err != nil {
+			return err
+		}
+		if include != nil {
+			p := accessPolicy{Name: "Allow " + flags["include"], Decision: "allow", Include: include}
+			if _, err := requestCF(http.MethodPost, path+"/"+app.ID+"/policies", p); err != nil {
+				return fmt.Errorf("application %s created but adding its policy failed: %w", app.Name, err)
+			}
+		}
+		return printResult(app, func() {
+			fmt.Printf("Access application created: %s (id=%s) protecting %s\n", app.Name, app.ID, app.Domain)
+			if include == nil {
+				fmt.Printf("Nobody can reach it until you add a policy: cf access policies add --app %s --include email-domain:<domain>\n", app.ID)
+			}
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf access apps delete <id|name|domain> [--force]")
+		}
+		if err := requireConfirmable(flags, "access apps delete"); err != nil {
+			return err
+		}
+		app, err := findAccessApp(path, positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirm(flags, "access apps delete", fmt.Sprintf("Delete Access application %s? %s will no longer be protected.", app.Name, app.Domain))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+app.ID, nil); err != nil {
+			return err
+		}
+		return printResult(app, func() {
+			fmt.Printf("Access application deleted: %s\n", app.Name)
+		})
+	}
+	return usageErrorf("usage: cf access apps list|create|delete")
+}
+
+func runAccessPolicies(appsPath string, args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	if flags["app"] == "" {
+		return usageErrorf("missing required flag for access policies: --app")
+	}
+	app, err := findAccessApp(appsPath, flags["app"])
+	if err != nil {
+		return err
+	}
+	path := appsPath + "/" + app.ID + "/policies"
+
+	switch action {
+	case "list":
+		policies, err := listAll[accessPolicy](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "DECISION", "PRECEDENCE", "INCLUDE"}}
+		for _, p := range policies {
+			t.Rows = append(t.Rows, []string{p.ID, p.Name, p.Decision, fmt.Sprint(p.Precedence), describeAccessRules(p.Include)})
+		}
+		return printList(policies, t, func() {
+			if len(policies) == 0 {
+				fmt.Printf("No policies on %s; nobody can reach it.\n", app.Name)
+				return
+			}
+			for _, p := range policies {
+				fmt.Printf("%s\t%s\t%s\t%s\n", p.ID, p.Name, p.Decision, describeAccessRules(p.Include))
+			}
+		})
+	case "add":
+		if flags["include"] == "" {
+			return usageErrorf("usage: cf access policies add --app <app> --include <type:value,...> [--exclude <rules>] [--require <rules>] [--decision allow] [--name <name>]")
+		}
+		p := accessPolicy{Name: flags["name"], Decision: flags["decision"]}
+		if p.Decision == "" {
+			p.Decision = "allow"
+		}
+		if !slices.Contains(accessDecisions, p.Decision) {
+			return usageErrorf("invalid --decision %q (want one of: %s)", p.Decision, strings.Join(accessDecisions, ", "))
+		}
+		if p.Name == "" {
+			p.Name = p.Decision + " " + flags["include"]
+		}
+		for flag, dst := range map[string]*[]map[string]any{"include": &p.Include, "exclude": &p.Exclude, "require": &p.Require} {
+			if v := flags[flag]; v != "" {
+				rules, err := parseAccessRules(v)
+				if err != nil {
+					return fmt.Errorf("--%s: %w", flag, err)
+				}
+				*dst = rules
+			}
+		}
+		resp, err := requestCF(http.MethodPost, path, p)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, &p); err != nil {
+			return err
+		}
+		return printResult(p, func() {
+			fmt.Printf("Access policy added to %s: %s (id=%s) %s %s\n", app.Name, p.Name, p.ID, p.Decision, describeAccessRules(p.Include))
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf access policies delete <policy-id> --app <app>")
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+positional[0], nil); err != nil {
+			return err
+		}
+		return printResult(map[string]string{"id": positional[0]}, func() {
+			fmt.Printf("Access policy deleted from %s: %s\n", app.Name, positional[0])
+		})
+	}
+	return usageErrorf("usage: cf access policies list|add|delete --app <app>")
+}
+
+func findAccessApp(path, ref string) (*accessApp, error) {
+	apps, err := listAll[accessApp](path, 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range apps {
+		if a.ID == ref || a.Name == ref || strings.EqualFold(a.Domain, ref) {
+			return &a, nil
+		}
+	}
+	return nil, notFoundErrorf("Access application %q not found. run: cf access apps list", ref)
+}
+
+// parseAccessRules turns "email-domain:example.com,email:a@b.com,everyone"
+// into Access rule objects.
+func parseAccessRules(v string) ([]map[string]any, error) {
+	var rules []map[string]any
+	for _, item := range splitList(v) {
+		kind, value, _ := strings.Cut(item, ":")
+		var rule map[string]any
+		switch strings.ToLower(kind) {
+		case "everyone":
+			rule = map[string]any{"everyone": map[string]any{}}
+		case "email":
+			rule = map[string]any{"email": map[string]string{"email": value}}
+		case "email-domain":
+			rule = map[string]any{"email_domain": map[string]string{"domain": strings.TrimPrefix(value, "@")}}
+		case "ip":
+			rule = map[string]any{"ip": map[string]string{"ip": value}}
+		case "country":
+			rule = map[string]any{"geo": map[string]string{"country_code": strings.ToUpper(value)}}
+		case "group":
+			rule = map[string]any{"group": map[string]string{"id": value}}
+		case "service-token":
+			rule = map[string]any{"service_token": map[string]string{"token_id": value}}
+		case "any-service-token":
+			rule = map[string]any{"any_valid_service_token": map[string]any{}}
+		default:
+			return nil, fmt.Errorf("unknown Access rule %q (want everyone, email:, email-domain:, ip:, country:, group:, service-token: or any-service-token)", item)
+		}
+		if value == "" && kind != "everyone" && kind != "any-service-token" {
+			return nil, fmt.Errorf("Access rule %q needs a value", item)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// describeAccessRules is the inverse of parseAccessRules for display.
+func describeAccessRules(rules []map[string]any) string {
+	names := map[string]string{
+		"email_domain":            "email-domain",
+		"geo":                     "country",
+		"service_token":           "service-token",
+		"any_valid_service_token": "any-service-token",
+	}
+	var parts []string
+	for _, r := range rules {
+		for kind, v := range r {
+			name := kind
+			if n, ok := names[kind]; ok {
+				name = n
+			}
+			fields, _ := v.(map[string]any)
+			value := ""
+			for _, fv := range fields {
+				value = fmt.Sprint(fv)
+			}
+			if value == "" {
+				parts = append(parts, name)
+			} else {
+				parts = append(parts, name+":"+value)
+			}
+		}
+	}
+	return strings.Join(parts, ",")
+}
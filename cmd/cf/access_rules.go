@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+var accessRuleModes = []string{"block", "challenge", "js_challenge", "managed_challenge", "whitelist"}
+
+type accessRule struct {
+	ID            string `json:"id"`
+	Mode          string `json:"mode"`
+	Notes         string `json:"notes,omitempty"`
+	Configuration struct {
+		Target string `json:"target"`
+		Value  string `json:"value"`
+	} `json:"configuration"`
+	Scope struct {
+		Type string `json:"type"`
+	} `json:"scope"`
+	CreatedOn string `json:"created_on,omitempty"`
+}
+
+func runFirewall(args []string) error {
+	if len(args) == 0 || args[0] != "access-rules" {
+		return usageErrorf("usage: cf firewall access-rules list|add|delete (--zone <zone> | --account)")
+	}
+	args = args[1:]
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+
+	path, scope, err := accessRulesPath(flags)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "list":
+		rules, err := listAll[accessRule](path, 100, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "MODE", "TARGET", "VALUE", "SCOPE", "NOTES"}}
+		for _, r := range rules {
+			t.Rows = append(t.Rows, []string{r.ID, r.Mode, r.Configuration.Target, r.Configuration.Value, r.Scope.Type, r.Notes})
+		}
+		return printList(rules, t, func() {
+			if len(rules) == 0 {
+				fmt.Printf("No access rules on %s.\n", scope)
+				return
+			}
+			for _, r := range rules {
+				fmt.Printf("%s\t%s\t%s %s\t%s\n", r.ID, r.Mode, r.Configuration.Target, r.Configuration.Value, r.Notes)
+			}
+		})
+	case "add":
+		if len(positional) == 0 || flags["mode"] == "" {
+			return usageErrorf("usage: cf firewall access-rules add <ip|cidr|ASn|country> --mode <block|challenge|whitelist|...> [--notes <text>]")
+		}
+		if !slices.Contains(accessRuleModes, flags["mode"]) {
+			return usageErrorf("invalid --mode %q (want one of: %s)", flags["mode"], strings.Join(accessRuleModes, ", "))
+		}
+		target, value := accessRuleTarget(positional[0])
+		body := map[string]any{
+			"mode":          flags["mode"],
+			"configuration": map[string]string{"target": target, "value": value},
+		}
+		if flags["notes"] != "" {
+			body["notes"] = flags["notes"]
+		}
+		resp, err := requestCF(http.MethodPost, path, body)
+		if err != nil {
+			return err
+		}
+		var r accessRule
+		if err := json.Unmarshal(resp.Result, &r); err != nil {
+			return err
+		}
+		return printResult(r, func() {
+			fmt.Printf("Access rule added on %s: %s %s %s (id=%s)\n", scope, r.Mode, r.Configuration.Target, r.Configuration.Value, r.ID)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf firewall access-rules delete <rule-id|value>")
+		}
+		id := positional[0]
+		if !isHexID(id) {
+			rules, err := listAll[accessRule](path+"?configuration.value="+url.QueryEscape(id), 100, 0)
+			if err != nil {
+				return err
+			}
+			if len(rules) != 1 {
+				return fmt.Errorf("expected one access rule matching %q, found %d", id, len(rules))
+			}
+			id = rules[0].ID
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+id, nil); err != nil {
+			return err
+		}
+		return printResult(map[string]string{"id": id}, func() {
+			fmt.Printf("Access rule deleted: %s\n", id)
+		})
+	}
+	return usageErrorf("usage: cf firewall access-rules list|add|delete (--zone <zone> | --account)")
+}
+
+// accessRulesPath picks the zone or account access rules endpoint; --zone
+// (or the profile's zone) wins unless --account is given.
+func accessRulesPath(flags map[string]string) (path, scope string, err error) {
+	if parseBoolWithDefault(flags["account"], false) {
+		accountID, err := resolveAccountID()
+		if err != nil {
+			return "", "", err
+		}
+		return "/accounts/" + accountID + "/firewall/access_rules/rules", "account " + accountID, nil
+	}
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return "", "", usageErrorf("missing required flag for firewall access-rules: --zone or --account")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return "", "", err
+	}
+	return "/zones/" + z.ID + "/firewall/access_rules/rules", z.Name, nil
+}
+
+// accessRuleTarget infers the configuration target from the value: CIDRs,
+// IPv4/IPv6 addresses, "AS" numbers, or two-letter country codes.
+func accessRuleTarget(v string) (target, value string) {
+	v = strings.TrimSpace(v)
+	if _, _, err := net.ParseCIDR(v); err == nil {
+		return "ip_range", v
+	}
+	if ip := net.ParseIP(v); ip != nil {
+		if ip.To4() == nil {
+			return "ip6", v
+		}
+		return "ip", v
+	}
+	upper := strings.ToUpper(v)
+	if strings.HasPrefix(upper, "AS") && len(upper) > 2 && strings.Trim(upper[2:], "0123456789") == "" {
+		return "asn", upper
+	}
+	if len(v) == 2 {
+		return "country", upper
+	}
+	return "ip", v
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestAccessRuleTarget(t *testing.T) {
+	cases := map[string][2]string{
+		"198.51.100.4":    {"ip", "198.51.100.4"},
+		"198.51.100.0/24": {"ip_range", "198.51.100.0/24"},
+		"2001:db8::1":     {"ip6", "2001:db8::1"},
+		"as64496":         {"asn", "AS64496"},
+		"cn":              {"country", "CN"},
+	}
+	for in, want := range cases {
+		target, value := accessRuleTarget(in)
+		if target != want[0] || value != want[1] {
+			t.Fatalf("accessRuleTarget(%q) = %s %s, want %s %s", in, target, value, want[0], want[1])
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const accessAppsPath = "/accounts/acc1/access/apps"
+
+func TestAccessAppsCreateWithPolicy(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("POST", accessAppsPath, accessApp{ID: "app1", Name: "admin", Domain: "admin.example.com", Type: "self_hosted"})
+	srv.Reply("POST", accessAppsPath+"/app1/policies", accessPolicy{ID: "p1"})
+
+	out, err := captureStdout(t, func() error {
+		return runAccess([]string{"apps", "create", "admin", "--domain", "admin.example.com", "--include", "email-domain:@example.com, email:ops@example.net, country:gb, everyone"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var app accessApp
+	if posts := srv.Calls("POST", accessAppsPath); len(posts) != 1 {
+		t.Fatalf("expected one app POST, got %d", len(posts))
+	} else if err := posts[0].Decode(&app); err != nil {
+		t.Fatal(err)
+	}
+	if app.Name != "admin" || app.Domain != "admin.example.com" || app.Type != "self_hosted" || app.SessionDuration != "24h" {
+		t.Fatalf("unexpected app %+v", app)
+	}
+	var policy struct {
+		Decision string          `json:"decision"`
+		Include  json.RawMessage `json:"include"`
+	}
+	if posts := srv.Calls("POST", accessAppsPath+"/app1/policies"); len(posts) != 1 {
+		t.Fatalf("expected one policy POST, got %d", len(posts))
+	} else if err := posts[0].Decode(&policy); err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"email_domain":{"domain":"example.com"}},{"email":{"email":"ops@example.net"}},{"geo":{"country_code":"GB"}},{"everyone":{}}]`
+	if policy.Decision != "allow" || string(policy.Include) != want {
+		t.Fatalf("unexpected policy:\n got %s %s\nwant allow %s", policy.Decision, policy.Include, want)
+	}
+	if !strings.Contains(out, "Access application created: admin (id=app1) protecting admin.example.com") || strings.Contains(out, "Nobody can reach it") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestAccessAppsCreateErrors(t *testing.T) {
+	srv := useFakeAPI(t)
+	for _, bad := range []string{"email", "team:x", "ip:"} {
+		if err := runAccess([]string{"apps", "create", "admin", "--domain", "admin.example.com", "--include", bad}); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("expected no app to be created for invalid rules, got %d requests", n)
+	}
+
+	srv.Reply("POST", accessAppsPath, accessApp{ID: "app1", Name: "admin"})
+	srv.Fail("POST", accessAppsPath+"/app1/policies", 400, 12130, "access.api.error.invalid_request")
+	err := runAccess([]string{"apps", "create", "admin", "--domain", "admin.example.com", "--include", "everyone"})
+	if err == nil || !strings.Contains(err.Error(), "application admin created but adding its policy failed") {
+		t.Fatalf("expected the policy failure to be reported, got %v", err)
+	}
+}
+
+func TestAccessPoliciesList(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", accessAppsPath, []accessApp{{ID: "app1", Name: "admin", Domain: "admin.example.com"}})
+	// Rules as the API returns them.
+	var include []map[string]any
+	json.Unmarshal([]byte(`[{"email_domain":{"domain":"example.com"}},{"email":{"email":"ops@example.net"}},{"geo":{"country_code":"GB"}},{"everyone":{}}]`), &include)
+	srv.Reply("GET", accessAppsPath+"/app1/policies", []accessPolicy{{ID: "p1", Name: "staff", Decision: "allow", Include: include}})
+
+	out, err := captureStdout(t, func() error { return runAccess([]string{"policies", "list", "--app", "ADMIN.example.com"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "p1\tstaff\tallow\temail-domain:example.com,email:ops@example.net,country:GB,everyone\n" {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if err := runAccess([]string{"policies", "list", "--app", "billing"}); exitCode(err) != exitNotFound {
+		t.Fatalf("expected not found for an unknown app, got %v", err)
+	}
+}
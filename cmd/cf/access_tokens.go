@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type accessServiceToken struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	Duration     string `json:"duration,omitempty"`
+	ExpiresAt    string `json:"expires_at,omitempty"`
+	LastSeenAt   string `json:"last_seen_at,omitempty"`
+}
+
+func runAccessServiceTokens(path string, args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+
+	switch action {
+	case "list":
+		tokens, err := listAll[accessServiceToken](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "CLIENT_ID", "EXPIRES_AT", "LAST_SEEN_AT"}}
+		for _, tk := range tokens {
+			t.Rows = append(t.Rows, []string{tk.ID, tk.Name, tk.ClientID, tk.ExpiresAt, tk.LastSeenAt})
+		}
+		return printList(tokens, t, func() {
+			if len(tokens) == 0 {
+				fmt.Println("No Access service tokens.")
+				return
+			}
+			for _, tk := range tokens {
+				fmt.Printf("%s\t%s\texpires %s\tlast seen %s\n", tk.ID, tk.Name, orDash(tk.ExpiresAt), orDash(tk.LastSeenAt))
+			}
+		})
+	case "create":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf access service-tokens create <name> [--duration 8760h]")
+		}
+		body := map[string]string{"name": positional[0]}
+		if v := flags["duration"]; v != "" {
+			body["duration"] = v
+		}
+		resp, err := requestCF(http.MethodPost, path, body)
+		if err != nil {
+			return err
+		}
+		var tk accessServiceToken
+		if err := json.Unmarshal(resp.Result, &tk); err != nil {
+			return err
+		}
+		return printResult(tk, func() {
+			fmt.Printf("Service token created: %s (id=%s, expires %s)\n", tk.Name, tk.ID, orDash(tk.ExpiresAt))
+			printServiceTokenCredentials(tk)
+			fmt.Printf("Allow it on an app with: cf access policies add --app <app> --decision non_identity --include service-token:%s\n", tk.ID)
+		})
+	case "rotate", "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf access service-tokens %s <id|name> [--force]", action)
+		}
+		if err := requireConfirmable(flags, fmt.Sprintf("access service-tokens %s", action)); err != nil {
+			return err
+		}
+		tk, err := findAccessServiceToken(path, positional[0])
+		if err != nil {
+			return err
+		}
+		q := fmt.Sprintf("Rotate the secret for %s? Clients using the current secret will be rejected.", tk.Name)
+		if action == "delete" {
+			q = fmt.Sprintf("Delete service token %s? Clients using it will be rejected.", tk.Name)
+		}
+		ok, err := confirm(flags, fmt.Sprintf("access service-tokens %s", action), q)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Token unchanged.")
+			return nil
+		}
+		if action == "delete" {
+			if _, err := requestCF(http.MethodDelete, path+"/"+tk.ID, nil); err != nil {
+				return err
+			}
+			return printResult(tk, func() {
+				fmt.Printf("Service token deleted: %s\n", tk.Name)
+			})
+		}
+		resp, err := requestCF(http.MethodPost, path+"/"+tk.ID+"/rotate", nil)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, tk); err != nil {
+			return err
+		}
+		return printResult(tk, func() {
+			fmt.Printf("Service token rotated: %s (id=%s)\n", tk.Name, tk.ID)
+			printServiceTokenCredentials(*tk)
+		})
+	}
+	return usageErrorf("usage: cf access service-tokens list|create|rotate|delete")
+}
+
+func findAccessServiceToken(path, ref string) (*accessServiceToken, error) {
+	tokens, err := listAll[accessServiceToken](path, 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, tk := range tokens {
+		if tk.ID == ref || tk.Name == ref || tk.ClientID == ref {
+			return &tk, nil
+		}
+	}
+	return nil, notFoundErrorf("service token %q not found. run: cf access service-tokens list", ref)
+}
+
+func printServiceTokenCredentials(tk accessServiceToken) {
+	fmt.Println("Send these headers to the protected origin (the secret is shown only once):")
+	fmt.Printf("  CF-Access-Client-Id: %s\n", tk.ClientID)
+	fmt.Printf("  CF-Access-Client-Secret: %s\n", tk.ClientSecret)
+}
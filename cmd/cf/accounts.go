@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// saveAccountChoice is set by the global --save flag: an account picked
+// interactively is written to the config file instead of used for one run.
+var saveAccountChoice bool
+
+// accountRow is one membership as cf accounts list shows it.
+type accountRow struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Roles    []string `json:"roles"`
+	Status   string   `json:"status"`
+	Selected bool     `json:"selected"`
+}
+
+func runAccounts(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf accounts list|use. run: cf accounts --help")
+	}
+	switch args[0] {
+	case "list":
+		return listAccounts()
+	case "use":
+		positional, _ := splitArgs(args[1:])
+		if len(positional) != 1 {
+			return usageErrorf("usage: cf accounts use <id|name>")
+		}
+		return useAccount(positional[0])
+	}
+	return usageErrorf("unknown accounts command %q. run: cf accounts --help", args[0])
+}
+
+func listAccounts() error {
+	creds, err := resolveCredentials()
+	if err != nil {
+		return err
+	}
+	memberships, err := listMemberships(creds)
+	if err != nil {
+		return err
+	}
+	// A failure here only means no account is selected yet.
+	selected, _ := configuredAccountID()
+
+	rows := make([]accountRow, len(memberships))
+	t := table{Headers: []string{"ID", "NAME", "ROLES", "STATUS", "SELECTED"}}
+	for i, m := range memberships {
+		rows[i] = accountRow{ID: m.Account.ID, Name: m.Account.Name, Roles: m.Roles, Status: m.Status, Selected: m.Account.ID == selected}
+		mark := ""
+		if rows[i].Selected {
+			mark = "*"
+		}
+		t.Rows = append(t.Rows, []string{m.Account.ID, m.Account.Name, strings.Join(m.Roles, ", "), m.Status, mark})
+	}
+	return printList(rows, t, func() {
+		if len(rows) == 0 {
+			fmt.Println("No account memberships found for this token.")
+			return
+		}
+		for _, r := range rows {
+			mark := " "
+			if r.Selected {
+				mark = "*"
+			}
+			fmt.Printf("%s %s  %s  roles=%s  status=%s\n", mark, r.ID, r.Name, strings.Join(r.Roles, ","), r.Status)
+		}
+	})
+}
+
+// useAccount saves the account matching idOrName (by ID, or by name
+// ignoring case) as the account to use. It goes into the active profile
+// when there is one, and at the top of the config file otherwise.
+func useAccount(idOrName string) error {
+	creds, err := resolveCredentials()
+	if err != nil {
+		return err
+	}
+	memberships, err := listMemberships(creds)
+	if err != nil {
+		return err
+	}
+	m, err := matchMembership(memberships, idOrName)
+	if err != nil {
+		return err
+	}
+
+	where, err := saveAccountID(m.Account.ID)
+	if err != nil {
+		return err
+	}
+	cachedAccountID = ""
+
+	return printResult(accountRow{ID: m.Account.ID, Name: m.Account.Name, Roles: m.Roles, Status: m.Status, Selected: true}, func() {
+		fmt.Printf("Using account %s (%s); saved to %s\n", m.Account.Name, m.Account.ID, where)
+		for _, env := range []string{"CF_ACCOUNT_ID", "CLOUDFLARE_ACCOUNT_ID"} {
+			if v := strings.TrimSpace(os.Getenv(env)); v != "" && v != m.Account.ID {
+				fmt.Printf("Note: %s=%s is set and still takes precedence.\n", env, v)
+				break
+			}
+		}
+	})
+}
+
+// saveAccountID records accountID in the active profile, or at the top of
+// the config file when no profile applies, and says where it went.
+func saveAccountID(accountID string) (string, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	p, name, _, err := activeProfile()
+	if err != nil {
+		return "", err
+	}
+	where := configPath()
+	if p != nil {
+		p.AccountID = accountID
+		cfg.Profiles[name] = *p
+		where = fmt.Sprintf("profile %q in %s", name, where)
+	} else {
+		cfg.AccountID = accountID
+	}
+	if err := saveConfig(cfg); err != nil {
+		return "", err
+	}
+	return where, nil
+}
+
+// pickAccount asks which of several memberships to use. The answer may be
+// the number shown, an account ID or an account name.
+func pickAccount(reader *bufio.Reader, memberships []membership) (*membership, error) {
+	fmt.Println("This token can access several accounts:")
+	for i, m := range memberships {
+		fmt.Printf("  %d) %s (%s)  roles=%s\n", i+1, m.Account.Name, m.Account.ID, strings.Join(m.Roles, ","))
+	}
+	for {
+		answer, err := prompt(reader, fmt.Sprintf("Account to use [1-%d]", len(memberships)), "")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			return nil, errors.New("no account chosen. run: cf accounts use <id|name>, or set CF_ACCOUNT_ID")
+		}
+		if n, err := strconv.Atoi(answer); err == nil {
+			if n >= 1 && n <= len(memberships) {
+				return &memberships[n-1], nil
+			}
+		} else if m, err := matchMembership(memberships, answer); err == nil {
+			return m, nil
+		}
+		fmt.Printf("Enter a number from 1 to %d, an account ID or a name.\n", len(memberships))
+	}
+}
+
+// matchMembership finds the membership whose account ID or name is
+// idOrName. A name shared by several accounts is ambiguous.
+func matchMembership(memberships []membership, idOrName string) (*membership, error) {
+	var byName []membership
+	for i, m := range memberships {
+		if m.Account.ID == idOrName {
+			return &memberships[i], nil
+		}
+		if strings.EqualFold(m.Account.Name, idOrName) {
+			byName = append(byName, m)
+		}
+	}
+	switch len(byName) {
+	case 1:
+		return &byName[0], nil
+	case 0:
+		choices := make([]string, len(memberships))
+		for i, m := range memberships {
+			choices[i] = fmt.Sprintf("%s (%s)", m.Account.Name, m.Account.ID)
+		}
+		return nil, notFoundErrorf("no account matches %q. available: %s", idOrName, strings.Join(choices, ", "))
+	}
+	ids := make([]string, len(byName))
+	for i, m := range byName {
+		ids[i] = m.Account.ID
+	}
+	return nil, usageErrorf("%d accounts are named %q; pass the ID instead: %s", len(byName), idOrName, strings.Join(ids, ", "))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+)
+
+func testMemberships() []membership {
+	ms := make([]membership, 3)
+	for i, a := range [][2]string{{"acc1", "Personal"}, {"acc2", "Brand Co"}, {"acc3", "brand co"}} {
+		ms[i].Account.ID, ms[i].Account.Name = a[0], a[1]
+		ms[i].Roles = []string{"Administrator"}
+		ms[i].Status = "accepted"
+	}
+	return ms
+}
+
+func TestAccountsUseSavesSelection(t *testing.T) {
+	srv := useFakeAPI(t)
+	t.Setenv("CF_ACCOUNT_ID", "")
+	srv.Reply("GET", "/memberships", testMemberships())
+
+	if _, err := captureStdout(t, func() error { return runAccounts([]string{"use", "acc2"}) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(configPath())
+	if err != nil || !strings.Contains(string(data), `account_id = "acc2"`) {
+		t.Fatalf("expected the account in the config file, got %q (err=%v)", data, err)
+	}
+
+	loadedConfig, cachedAccountID = nil, ""
+	if id, err := resolveAccountID(); err != nil || id != "acc2" {
+		t.Fatalf("expected the saved account, got %q (err=%v)", id, err)
+	}
+
+	out, err := captureStdout(t, func() error { return runAccounts([]string{"list"}) })
+	if err != nil || !strings.Contains(out, "* acc2  Brand Co  roles=Administrator") {
+		t.Fatalf("expected acc2 to be marked selected, got err=%v:\n%s", err, out)
+	}
+}
+
+func TestAccountsUseProfile(t *testing.T) {
+	srv := useFakeAPI(t)
+	useTestConfig(t, testConfig)
+	srv.Reply("GET", "/memberships", testMemberships())
+
+	if _, err := captureStdout(t, func() error { return runAccounts([]string{"use", "Personal"}) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loadedConfig = nil
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Profiles["personal"]; got.AccountID != "acc1" || got.APIToken != "personal-token" {
+		t.Fatalf("expected the default profile to be updated in place, got %+v", got)
+	}
+	if cfg.Profiles["work"].Zone != "work.example.com" || cfg.AccountID != "" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+}
+
+func TestMatchMembership(t *testing.T) {
+	ms := testMemberships()
+	if m, err := matchMembership(ms, "PERSONAL"); err != nil || m.Account.ID != "acc1" {
+		t.Fatalf("expected a case-insensitive name match, got %+v (err=%v)", m, err)
+	}
+	if _, err := matchMembership(ms, "Brand Co"); exitCode(err) != exitUsage || !strings.Contains(err.Error(), "acc2, acc3") {
+		t.Fatalf("expected an ambiguous name to be rejected, got %v", err)
+	}
+	if _, err := matchMembership(ms, "nope"); exitCode(err) != exitNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestPickAccount(t *testing.T) {
+	ms := testMemberships()
+	var m *membership
+	out, err := captureStdout(t, func() (err error) {
+		m, err = pickAccount(bufio.NewReader(strings.NewReader("9\nnope\n2\n")), ms)
+		return err
+	})
+	if err != nil || m.Account.ID != "acc2" {
+		t.Fatalf("expected the second account, got %+v (err=%v)", m, err)
+	}
+	if !strings.Contains(out, "  1) Personal (acc1)  roles=Administrator") || strings.Count(out, "Enter a number from 1 to 3") != 2 {
+		t.Fatalf("unexpected picker output:\n%s", out)
+	}
+
+	if m, err := pickAccount(bufio.NewReader(strings.NewReader("personal\n")), ms); err != nil || m.Account.ID != "acc1" {
+		t.Fatalf("expected a name to be accepted, got %+v (err=%v)", m, err)
+	}
+	if _, err := pickAccount(bufio.NewReader(strings.NewReader("\n")), ms); err == nil {
+		t.Fatal("expected an empty answer to abort")
+	}
+}
+
+func TestConfiguredAccountIDDoesNotPrompt(t *testing.T) {
+	srv := useFakeAPI(t)
+	t.Setenv("CF_ACCOUNT_ID", "")
+	srv.Reply("GET", "/memberships", testMemberships())
+
+	if _, err := configuredAccountID(); err == nil || !strings.Contains(err.Error(), "cf accounts use") {
+		t.Fatalf("expected the ambiguity to be reported, got %v", err)
+	}
+}
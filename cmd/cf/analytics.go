@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// analyticsDimensions maps --by values to httpRequestsAdaptiveGroups
+// dimensions.
+var analyticsDimensions = map[string]string{
+	"status":  "edgeResponseStatus",
+	"country": "clientCountryName",
+	"path":    "clientRequestPath",
+}
+
+type analyticsSummary struct {
+	Zone           string               `json:"zone"`
+	Since          string               `json:"since"`
+	Until          string               `json:"until"`
+	Requests       int64                `json:"requests"`
+	CachedRequests int64                `json:"cached_requests"`
+	Bytes          int64                `json:"bytes"`
+	CachedBytes    int64                `json:"cached_bytes"`
+	Threats        int64                `json:"threats"`
+	CacheRatio     float64              `json:"cache_ratio"`
+	By             string               `json:"by,omitempty"`
+	Breakdown      []analyticsBreakdown `json:"breakdown,omitempty"`
+}
+
+type analyticsBreakdown struct {
+	Value    string `json:"value"`
+	Requests int64  `json:"requests"`
+	Bytes    int64  `json:"bytes"`
+}
+
+const analyticsTotalsQuery = `query($zoneTag: string, $since: Time, $until: Time) {
+  viewer {
+    zones(filter: {zoneTag: $zoneTag}) {
+      httpRequests1hGroups(limit: 1000, filter: {datetime_geq: $since, datetime_lt: $until}) {
+        sum { requests cachedRequests bytes cachedBytes threats }
+      }
+    }
+  }
+}`
+
+const analyticsBreakdownQuery = `query($zoneTag: string, $since: Time, $until: Time, $limit: Int) {
+  viewer {
+    zones(filter: {zoneTag: $zoneTag}) {
+      httpRequestsAdaptiveGroups(limit: $limit, orderBy: [count_DESC], filter: {datetime_geq: $since, datetime_lt: $until}) {
+        count
+        sum { edgeResponseBytes }
+        dimensions { %s }
+      }
+    }
+  }
+}`
+
+func runAnalytics(args []string) error {
+	flags := parseFlags(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for analytics: --zone")
+	}
+	since, err := parseDurationWithDefault(flags["since"], 24*time.Hour)
+	if err != nil || since <= 0 {
+		return usageErrorf("invalid --since %q (example: 6h, 24h, 168h)", flags["since"])
+	}
+	by := strings.ToLower(flags["by"])
+	if _, ok := analyticsDimensions[by]; by != "" && !ok {
+		return usageErrorf("invalid --by %q (want status, country or path)", by)
+	}
+	limit, err := parseIntWithDefault(flags["limit"], 10)
+	if err != nil {
+		return usageErrorf("invalid --limit: %w", err)
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	until := time.Now().UTC().Truncate(time.Minute)
+	start := until.Add(-since)
+	vars := map[string]any{
+		"zoneTag": z.ID,
+		"since":   start.Format(time.RFC3339),
+		"until":   until.Format(time.RFC3339),
+	}
+
+	var totals struct {
+		Viewer struct {
+			Zones []struct {
+				Groups []struct {
+					Sum struct {
+						Requests       int64 `json:"requests"`
+						CachedRequests int64 `json:"cachedRequests"`
+						Bytes          int64 `json:"bytes"`
+						CachedBytes    int64 `json:"cachedBytes"`
+						Threats        int64 `json:"threats"`
+					} `json:"sum"`
+				} `json:"httpRequests1hGroups"`
+			} `json:"zones"`
+		} `json:"viewer"`
+	}
+	if err := queryGraphQL(analyticsTotalsQuery, vars, &totals); err != nil {
+		return err
+	}
+
+	s := analyticsSummary{Zone: z.Name, Since: vars["since"].(string), Until: vars["until"].(string), By: by}
+	for _, zd := range totals.Viewer.Zones {
+		for _, g := range zd.Groups {
+			s.Requests += g.Sum.Requests
+			s.CachedRequests += g.Sum.CachedRequests
+			s.Bytes += g.Sum.Bytes
+			s.CachedBytes += g.Sum.CachedBytes
+			s.Threats += g.Sum.Threats
+		}
+	}
+	if s.Requests > 0 {
+		s.CacheRatio = float64(s.CachedRequests) / float64(s.Requests)
+	}
+
+	if by != "" {
+		vars["limit"] = limit
+		if s.Breakdown, err = analyticsBreakdownBy(analyticsDimensions[by], vars); err != nil {
+			return err
+		}
+	}
+
+	if by != "" {
+		t := table{Headers: []string{strings.ToUpper(by), "REQUESTS", "BYTES"}}
+		for _, b := range s.Breakdown {
+			t.Rows = append(t.Rows, []string{b.Value, strconv.FormatInt(b.Requests, 10), strconv.FormatInt(b.Bytes, 10)})
+		}
+		return printList(s, t, func() { printAnalytics(s, since) })
+	}
+	return printResult(s, func() { printAnalytics(s, since) })
+}
+
+func analyticsBreakdownBy(dimension string, vars map[string]any) ([]analyticsBreakdown, error) {
+	var data struct {
+		Viewer struct {
+			Zones []struct {
+				Groups []struct {
+					Count int64 `json:"count"`
+					Sum   struct {
+						EdgeResponseBytes int64 `json:"edgeResponseBytes"`
+					} `json:"sum"`
+					Dimensions map[string]any `json:"dimensions"`
+				} `json:"httpRequestsAdaptiveGroups"`
+			} `json:"zones"`
+		} `json:"viewer"`
+	}
+	if err := queryGraphQL(fmt.Sprintf(analyticsBreakdownQuery, dimension), vars, &data); err != nil {
+		return nil, err
+	}
+
+	var out []analyticsBreakdown
+	for _, zd := range data.Viewer.Zones {
+		for _, g := range zd.Groups {
+			out = append(out, analyticsBreakdown{
+				Value:    fmt.Sprint(g.Dimensions[dimension]),
+				Requests: g.Count,
+				Bytes:    g.Sum.EdgeResponseBytes,
+			})
+		}
+	}
+	return out, nil
+}
+
+func printAnalytics(s analyticsSummary, since time.Duration) {
+	fmt.Printf("Traffic for %s over the last %s:\n", s.Zone, since)
+	fmt.Printf("  Requests:    %d (%d cached)\n", s.Requests, s.CachedRequests)
+	fmt.Printf("  Bandwidth:   %s (%s cached)\n", humanBytes(strconv.FormatInt(s.Bytes, 10)), humanBytes(strconv.FormatInt(s.CachedBytes, 10)))
+	fmt.Printf("  Threats:     %d\n", s.Threats)
+	fmt.Printf("  Cache ratio: %.1f%%\n", s.CacheRatio*100)
+	if s.By == "" {
+		return
+	}
+	fmt.Printf("Top %s values (sampled):\n", s.By)
+	if len(s.Breakdown) == 0 {
+		fmt.Println("  no requests in this window")
+	}
+	for _, b := range s.Breakdown {
+		fmt.Printf("  %-40s %10d  %s\n", b.Value, b.Requests, humanBytes(strconv.FormatInt(b.Bytes, 10)))
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"cf/pkg/cfapi"
+)
+
+// The CLI uses the API types from pkg/cfapi under its own short names.
+type (
+	apiResponse       = cfapi.Response
+	apiError          = cfapi.Error
+	resultInfo        = cfapi.ResultInfo
+	apiStatusError    = cfapi.APIError
+	credentials       = cfapi.Credentials
+	zone              = cfapi.Zone
+	dnsRecord         = cfapi.DNSRecord
+	registrarDomain   = cfapi.RegistrarDomain
+	membership        = cfapi.Membership
+	tokenVerification = cfapi.TokenVerification
+)
+
+// rootCtx is passed to every API call.
+var rootCtx = context.Background()
+
+// apiBaseURL and httpClient override the client defaults; tests point them
+// at a cftest server.
+var (
+	apiBaseURL string
+	httpClient *http.Client
+)
+
+// newClient returns an API client for creds, configured from the global
+// flags (--retries, --max-rps, --verbose, --dry-run). Mutating requests are
+// also journaled for cf undo.
+func newClient(creds credentials) *cfapi.Client {
+	c := cfapi.NewClient(creds)
+	c.BaseURL = apiBaseURL
+	c.HTTPClient = httpClient
+	c.MaxRetries = resolveMaxRetries()
+	c.Limiter = apiLimiter()
+	c.Logger = debugLogger()
+	c.DryRun = func(method, path, contentType string, body []byte) bool {
+		if skipForDryRun(method, path, contentType, body) {
+			return true
+		}
+		journalRequest(method, path)
+		return false
+	}
+	return c
+}
+
+// apiClient returns a client for the resolved credentials.
+func apiClient() (*cfapi.Client, error) {
+	creds, err := resolveCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return newClient(creds), nil
+}
+
+func requestCF(method, path string, body any) (apiResponse, error) {
+	creds, err := resolveCredentials()
+	if err != nil {
+		return apiResponse{}, err
+	}
+	return requestCFWithCredentials(creds, method, path, body)
+}
+
+func requestCFWithToken(token, method, path string, body any) (apiResponse, error) {
+	return requestCFWithCredentials(credentials{Token: token}, method, path, body)
+}
+
+func requestCFWithCredentials(creds credentials, method, path string, body any) (apiResponse, error) {
+	return deref(newClient(creds).Do(rootCtx, method, path, body))
+}
+
+// requestCFRaw sends a pre-encoded body, e.g. a multipart upload.
+func requestCFRaw(method, path, contentType string, payload []byte) (apiResponse, error) {
+	c, err := apiClient()
+	if err != nil {
+		return apiResponse{}, err
+	}
+	return deref(c.DoRaw(rootCtx, method, path, contentType, payload))
+}
+
+func deref(resp *apiResponse, err error) (apiResponse, error) {
+	if resp == nil {
+		return apiResponse{}, err
+	}
+	return *resp, err
+}
+
+// requestCFBytes fetches an endpoint that returns a raw body instead of the
+// usual JSON envelope, such as a KV value.
+func requestCFBytes(method, path string) ([]byte, error) {
+	return requestCFBytesWithBody(method, path, "", nil)
+}
+
+// requestCFBytesWithBody is requestCFBytes with a request body, for endpoints
+// such as /graphql whose response is not the REST envelope.
+func requestCFBytesWithBody(method, path, contentType string, payload []byte) ([]byte, error) {
+	c, err := apiClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.DoBytes(rootCtx, method, path, contentType, payload)
+}
+
+// listAll fetches every page of a list endpoint, following result_info
+// until the last page or until limit items were collected (limit <= 0 means
+// no limit).
+func listAll[T any](path string, perPage, limit int) ([]T, error) {
+	c, err := apiClient()
+	if err != nil {
+		return nil, err
+	}
+	return cfapi.ListAll[T](rootCtx, c, path, perPage, limit)
+}
+
+func isNotFound(err error) bool {
+	return cfapi.IsNotFound(err)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"cf/internal/cftest"
+)
+
+// useFakeAPI points the CLI at a fake Cloudflare API with a token and
+// account configured, and serves example.com as zone z1.
+func useFakeAPI(t *testing.T) *cftest.Server {
+	t.Helper()
+	useTestConfig(t, "")
+	useFakeKeychain(t, fakeKeychain{})
+	t.Setenv("CF_API_TOKEN", "test-token")
+	t.Setenv("CF_ACCOUNT_ID", "acc1")
+	t.Setenv("CF_AUTH_MODE", "")
+	t.Setenv("CF_MAX_RPS", "0")
+	t.Setenv("CF_CACHE_TTL", "")
+	t.Setenv("CF_CACHE_DIR", t.TempDir())
+	forgetZones()
+	t.Cleanup(func() { zoneCacheLoaded = nil })
+
+	srv := cftest.NewServer(t)
+	apiBaseURL = srv.URL
+	t.Cleanup(func() { apiBaseURL = "" })
+	srv.Reply("GET", "/zones?name=example.com", []zone{{ID: "z1", Name: "example.com", Status: "active"}})
+	srv.Reply("GET", "/zones?name=missing.example", []zone{})
+	return srv
+}
+
+// runCLI runs args as a full command line, global flags included, and
+// returns what it prints to stdout.
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	origCtx, origClient, origTransport := rootCtx, httpClient, outboundTransport
+	t.Cleanup(func() {
+		stopTimeout()
+		rootCtx, httpClient, outboundTransport, timeout = origCtx, origClient, origTransport, 0
+	})
+	return captureStdout(t, func() error { return run(args) })
+}
+
+// captureStdout returns what f prints to stdout.
+func captureStdout(t *testing.T, f func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	err = f()
+	os.Stdout = orig
+	w.Close()
+	return <-done, err
+}
+
+func TestDNSAddAgainstFakeAPI(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{})
+	srv.Reply("POST", "/zones/z1/dns_records", dnsRecord{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1})
+
+	out, err := captureStdout(t, func() error {
+		return runDNS([]string{"add", "--zone", "example.com", "--type", "a", "--name", "www", "--content", "192.0.2.1"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "DNS record created: A www.example.com -> 192.0.2.1 (id=r1)") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	posts := srv.Calls("POST", "/zones/z1/dns_records")
+	if len(posts) != 1 {
+		t.Fatalf("expected one create request, got %d", len(posts))
+	}
+	var body map[string]any
+	if err := posts[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["type"] != "A" || body["name"] != "www" || body["content"] != "192.0.2.1" {
+		t.Fatalf("unexpected request body: %v", body)
+	}
+	if got := posts[0].Header.Get("Authorization"); got != "Bearer test-token" {
+		t.Fatalf("unexpected Authorization header %q", got)
+	}
+}
+
+func TestExitCodesAgainstFakeAPI(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Fail("GET", "/zones/z1/dns_records", 403, 10000, "Authentication error")
+
+	_, err := captureStdout(t, func() error {
+		return runDNS([]string{"list", "--zone", "example.com"})
+	})
+	if exitCode(err) != exitAuth {
+		t.Fatalf("expected auth exit code, got %d (%v)", exitCode(err), err)
+	}
+
+	_, err = captureStdout(t, func() error {
+		return runDNS([]string{"list", "--zone", "missing.example"})
+	})
+	if exitCode(err) != exitNotFound {
+		t.Fatalf("expected not-found exit code, got %d (%v)", exitCode(err), err)
+	}
+}
+
+func TestDryRunSendsNoChanges(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{})
+	dryRun = true
+	t.Cleanup(func() { dryRun, dryRunSkipped = false, 0 })
+
+	if _, err := captureStdout(t, func() error {
+		return runDNS([]string{"add", "--zone", "example.com", "--type", "A", "--name", "www", "--content", "192.0.2.1"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(srv.Calls("POST", "/zones/z1/dns_records")); n != 0 {
+		t.Fatalf("dry run sent %d create requests", n)
+	}
+}
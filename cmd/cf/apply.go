@@ -0,0 +1,451 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// accountFile is the file cf apply reads: the desired state of any number
+// of zones. Anything a zone entry leaves out is not managed.
+type accountFile struct {
+	Zones []accountZone `json:"zones" yaml:"zones"`
+}
+
+type accountZone struct {
+	Name string `json:"name" yaml:"name"`
+	// Type is used when the zone has to be created: full (default) or
+	// partial.
+	Type     string          `json:"type,omitempty" yaml:"type,omitempty"`
+	Settings map[string]any  `json:"settings,omitempty" yaml:"settings,omitempty"`
+	Records  []dnsSyncRecord `json:"records,omitempty" yaml:"records,omitempty"`
+	// Prune deletes live records that are not listed, as dns sync --prune
+	// does. Rules are never pruned.
+	Prune     bool              `json:"prune,omitempty" yaml:"prune,omitempty"`
+	Redirects []accountRedirect `json:"redirects,omitempty" yaml:"redirects,omitempty"`
+	WAF       []accountWAFRule  `json:"waf,omitempty" yaml:"waf,omitempty"`
+}
+
+// accountRedirect is a Single Redirect. Description identifies it among the
+// zone's live rules and defaults to From.
+type accountRedirect struct {
+	Description   string `json:"description,omitempty" yaml:"description,omitempty"`
+	From          string `json:"from,omitempty" yaml:"from,omitempty"`
+	Expression    string `json:"expression,omitempty" yaml:"expression,omitempty"`
+	To            string `json:"to" yaml:"to"`
+	Status        int    `json:"status,omitempty" yaml:"status,omitempty"`
+	PreserveQuery bool   `json:"preserve_query,omitempty" yaml:"preserve_query,omitempty"`
+}
+
+// accountWAFRule is a custom WAF rule, identified by its description.
+type accountWAFRule struct {
+	Description string `json:"description" yaml:"description"`
+	Expression  string `json:"expression" yaml:"expression"`
+	Action      string `json:"action" yaml:"action"`
+	Enabled     *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+}
+
+// applyZonePlan is what cf apply will change in one zone, in the order it
+// is applied: the zone itself, records, settings, then rules.
+type applyZonePlan struct {
+	Zone      string          `json:"zone"`
+	Create    bool            `json:"create,omitempty"`
+	Records   []dnsChange     `json:"records"`
+	Unmanaged []dnsRecord     `json:"unmanaged_records"`
+	Settings  []settingChange `json:"settings"`
+	Rules     []ruleChange    `json:"rules"`
+
+	zoneType string
+	zone     *zone
+}
+
+type settingChange struct {
+	ID     string          `json:"id"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  any             `json:"after"`
+}
+
+type ruleChange struct {
+	Phase  string       `json:"phase"`
+	Action string       `json:"action"`
+	Before *rulesetRule `json:"before,omitempty"`
+	After  rulesetRule  `json:"after"`
+}
+
+type applyResult struct {
+	Applied bool            `json:"applied"`
+	Zones   []applyZonePlan `json:"zones"`
+}
+
+func (p applyZonePlan) empty() bool {
+	return !p.Create && len(p.Records) == 0 && len(p.Settings) == 0 && len(p.Rules) == 0
+}
+
+func runApply(args []string) error {
+	for i, a := range args {
+		if a == "-f" {
+			args[i] = "--file"
+		}
+	}
+	flags := parseFlags(args)
+	if flags["file"] == "" {
+		return usageErrorf("missing required flag for apply: -f/--file")
+	}
+	concurrency, err := parseIntWithDefault(flags["concurrency"], defaultBulkConcurrency)
+	if err != nil {
+		return usageErrorf("invalid --concurrency: %w", err)
+	}
+	if err := requireConfirmable(flags, "apply"); err != nil {
+		return err
+	}
+
+	vars, err := templateVars(args)
+	if err != nil {
+		return err
+	}
+	spec, err := readAccountFile(flags["file"], vars)
+	if err != nil {
+		return err
+	}
+	result := applyResult{Zones: []applyZonePlan{}}
+	changes := 0
+	for _, zs := range spec.Zones {
+		plan, err := planZoneApply(zs)
+		if err != nil {
+			return fmt.Errorf("%s: %w", zs.Name, err)
+		}
+		result.Zones = append(result.Zones, *plan)
+		if !plan.empty() {
+			changes++
+		}
+	}
+	if !machineOutput() {
+		printApplyPlan(result.Zones)
+	}
+	if changes == 0 || dryRun {
+		return printResult(result, func() {})
+	}
+
+	ok, err := confirm(flags, "apply", "Apply these changes?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted. No changes applied.")
+		return nil
+	}
+	for i := range result.Zones {
+		if err := applyZone(&result.Zones[i], concurrency); err != nil {
+			return fmt.Errorf("%s: %w", result.Zones[i].Zone, err)
+		}
+	}
+	result.Applied = true
+	return printResult(result, func() {
+		fmt.Printf("Applied changes to %d zone(s).\n", changes)
+	})
+}
+
+func readAccountFile(path string, vars map[string]string) (*accountFile, error) {
+	data, err := readTemplateFile(path, vars)
+	if err != nil {
+		return nil, err
+	}
+	var spec accountFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &spec)
+	} else {
+		err = yaml.Unmarshal(data, &spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	seen := map[string]bool{}
+	for i, z := range spec.Zones {
+		name := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(z.Name), "."))
+		if name == "" {
+			return nil, fmt.Errorf("%s: zone %d has no name", path, i+1)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s: zone %s is listed twice", path, name)
+		}
+		seen[name] = true
+		spec.Zones[i].Name = name
+	}
+	return &spec, nil
+}
+
+// planZoneApply compares one zone entry with live state. A zone that does
+// not exist yet is planned against nothing.
+func planZoneApply(zs accountZone) (*applyZonePlan, error) {
+	plan := &applyZonePlan{Zone: zs.Name, Records: []dnsChange{}, Unmanaged: []dnsRecord{}, Settings: []settingChange{}, Rules: []ruleChange{}, zoneType: zs.Type}
+	desiredRecords, err := desiredDNSRecords(zs.Name, zs.Records)
+	if err != nil {
+		return nil, err
+	}
+	desiredRules, err := desiredAccountRules(zs)
+	if err != nil {
+		return nil, err
+	}
+
+	z, err := getZoneByName(zs.Name)
+	if err != nil {
+		return nil, err
+	}
+	plan.zone = z
+	var (
+		liveRecords  []dnsRecord
+		liveSettings = map[string]json.RawMessage{}
+		liveRules    = map[string][]rulesetRule{}
+	)
+	if z == nil {
+		plan.Create = true
+	} else {
+		if liveRecords, err = listDNSRecords(z.ID); err != nil {
+			return nil, err
+		}
+		if len(zs.Settings) > 0 {
+			settings, err := listZoneSettings(z.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, s := range settings {
+				liveSettings[s.ID] = s.Value
+			}
+		}
+		for phase := range desiredRules {
+			rs, err := getPhaseRuleset("/zones/"+z.ID, phase)
+			if err != nil {
+				return nil, err
+			}
+			if rs != nil {
+				liveRules[phase] = rs.Rules
+			}
+		}
+	}
+
+	if len(zs.Records) > 0 || zs.Prune {
+		plan.Records, plan.Unmanaged = planDNSSync(desiredRecords, liveRecords, zs.Prune)
+		if plan.Records == nil {
+			plan.Records = []dnsChange{}
+		}
+		if plan.Unmanaged == nil {
+			plan.Unmanaged = []dnsRecord{}
+		}
+	}
+	plan.Settings = planSettings(zs.Settings, liveSettings)
+	for _, phase := range []string{phaseSingleRedirect, phaseFirewallCustom} {
+		plan.Rules = append(plan.Rules, planRules(phase, desiredRules[phase], liveRules[phase])...)
+	}
+	return plan, nil
+}
+
+// desiredAccountRules builds the rules a zone entry asks for, by phase.
+func desiredAccountRules(zs accountZone) (map[string][]rulesetRule, error) {
+	out := map[string][]rulesetRule{}
+	for i, r := range zs.Redirects {
+		desc := r.Description
+		if desc == "" {
+			desc = r.From
+		}
+		if r.To == "" || (r.From == "" && r.Expression == "") || desc == "" {
+			return nil, fmt.Errorf("redirect %d: to and from are required (or expression and description)", i+1)
+		}
+		flags := map[string]string{"from": r.From, "expression": r.Expression, "to": r.To, "preserve-query": strconv.FormatBool(r.PreserveQuery), "description": desc}
+		if r.Status != 0 {
+			flags["status"] = strconv.Itoa(r.Status)
+		}
+		rule, err := singleRedirectRule(flags)
+		if err != nil {
+			return nil, fmt.Errorf("redirect %d: %w", i+1, err)
+		}
+		out[phaseSingleRedirect] = append(out[phaseSingleRedirect], rule)
+	}
+	for i, r := range zs.WAF {
+		if r.Description == "" || r.Expression == "" || r.Action == "" {
+			return nil, fmt.Errorf("waf rule %d: description, expression and action are required", i+1)
+		}
+		if !slices.Contains(wafActions, r.Action) {
+			return nil, fmt.Errorf("waf rule %d: invalid action %q (want one of: %s)", i+1, r.Action, strings.Join(wafActions, ", "))
+		}
+		enabled := r.Enabled == nil || *r.Enabled
+		out[phaseFirewallCustom] = append(out[phaseFirewallCustom], rulesetRule{Action: r.Action, Expression: r.Expression, Description: r.Description, Enabled: &enabled})
+	}
+	for phase, rules := range out {
+		seen := map[string]bool{}
+		for _, r := range rules {
+			if seen[r.Description] {
+				return nil, fmt.Errorf("two %s rules are described %q; descriptions identify rules and must be unique", phase, r.Description)
+			}
+			seen[r.Description] = true
+		}
+	}
+	return out, nil
+}
+
+func planSettings(desired map[string]any, live map[string]json.RawMessage) []settingChange {
+	ids := make([]string, 0, len(desired))
+	for id := range desired {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	changes := []settingChange{}
+	for _, id := range ids {
+		want := desired[id]
+		key := zoneSettingID(id)
+		if before, ok := live[key]; ok && jsonEqual(want, before) {
+			continue
+		}
+		changes = append(changes, settingChange{ID: key, Before: live[key], After: want})
+	}
+	return changes
+}
+
+// planRules matches desired rules to live ones by description. Live rules
+// the file does not describe are left alone.
+func planRules(phase string, desired, live []rulesetRule) []ruleChange {
+	var changes []ruleChange
+	for _, want := range desired {
+		i := slices.IndexFunc(live, func(r rulesetRule) bool { return r.Description == want.Description })
+		if i == -1 {
+			changes = append(changes, ruleChange{Phase: phase, Action: "create", After: want})
+			continue
+		}
+		have := live[i]
+		if have.Action == want.Action && have.Expression == want.Expression && have.enabled() == want.enabled() && jsonSubset(want.ActionParameters, have.ActionParameters) {
+			continue
+		}
+		want.ID = have.ID
+		changes = append(changes, ruleChange{Phase: phase, Action: "update", Before: &have, After: want})
+	}
+	return changes
+}
+
+// jsonEqual compares a value from the file with one from the API as JSON.
+func jsonEqual(want any, have json.RawMessage) bool {
+	data, err := json.Marshal(want)
+	if err != nil {
+		return false
+	}
+	var a, b any
+	return json.Unmarshal(data, &a) == nil && json.Unmarshal(have, &b) == nil && reflect.DeepEqual(a, b)
+}
+
+// jsonSubset reports whether every field set in want has the same value in
+// have. Fields the API adds with default values do not count as drift.
+func jsonSubset(want, have json.RawMessage) bool {
+	var a, b any
+	if len(want) == 0 {
+		return true
+	}
+	if json.Unmarshal(want, &a) != nil || json.Unmarshal(have, &b) != nil {
+		return false
+	}
+	return subsetOf(a, b)
+}
+
+func subsetOf(want, have any) bool {
+	wm, ok := want.(map[string]any)
+	if !ok {
+		return reflect.DeepEqual(want, have)
+	}
+	hm, _ := have.(map[string]any)
+	for k, v := range wm {
+		hv, ok := hm[k]
+		if !ok {
+			if v == nil || v == false || v == "" || v == float64(0) {
+				continue
+			}
+			return false
+		}
+		if !subsetOf(v, hv) {
+			return false
+		}
+	}
+	return true
+}
+
+func printApplyPlan(plans []applyZonePlan) {
+	for _, p := range plans {
+		if p.empty() {
+			fmt.Printf("%s: no changes.\n", p.Zone)
+			continue
+		}
+		if p.Create {
+			fmt.Printf("+ zone %s\n", p.Zone)
+		}
+		if len(p.Records) > 0 || len(p.Unmanaged) > 0 {
+			printDNSSyncPlan(p.Zone, p.Records, p.Unmanaged)
+		}
+		for _, s := range p.Settings {
+			before := "(unset)"
+			if s.Before != nil {
+				before = settingValueString(s.Before)
+			}
+			fmt.Printf("  ~ setting %s: %s -> %v\n", s.ID, before, s.After)
+		}
+		for _, r := range p.Rules {
+			kind := "waf rule"
+			if r.Phase == phaseSingleRedirect {
+				kind = "redirect"
+			}
+			sign := "+"
+			if r.Action == "update" {
+				sign = "~"
+			}
+			fmt.Printf("  %s %s %q: %s if %s\n", sign, kind, r.After.Description, r.After.Action, r.After.Expression)
+		}
+	}
+}
+
+// applyZone makes one zone match its plan: the zone first, since everything
+// else needs its ID, then records, settings and rules.
+func applyZone(p *applyZonePlan, concurrency int) error {
+	if p.Create {
+		z, err := addZone(p.Zone, p.zoneType, false)
+		if err != nil {
+			return err
+		}
+		p.zone = z
+	}
+	if p.zone == nil {
+		return nil
+	}
+	base := "/zones/" + p.zone.ID
+	if err := applyDNSSync(p.zone.ID, p.Records, concurrency); err != nil {
+		return err
+	}
+	if len(p.Settings) > 0 {
+		items := make([]map[string]any, len(p.Settings))
+		for i, s := range p.Settings {
+			items[i] = map[string]any{"id": s.ID, "value": s.After}
+		}
+		if _, err := requestCF(http.MethodPatch, base+"/settings", map[string]any{"items": items}); err != nil {
+			return fmt.Errorf("update settings: %w", err)
+		}
+		infof("Zone settings updated: %d\n", len(items))
+	}
+	for _, r := range p.Rules {
+		var err error
+		if r.Action == "create" {
+			_, err = addPhaseRule(base, r.Phase, r.After)
+		} else {
+			var rs *ruleset
+			if rs, err = getPhaseRuleset(base, r.Phase); err == nil && rs != nil {
+				err = updatePhaseRule(base, rs, r.After)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s rule %q: %w", r.Action, r.After.Description, err)
+		}
+		infof("Rule %sd: %s\n", r.Action, r.After.Description)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testAccountYAML = `zones:
+  - name: example.com
+    settings:
+      ssl: strict
+      always_use_https: "on"
+    records:
+      - {type: A, name: www, content: 192.0.2.9, proxied: true}
+    redirects:
+      - from: /old
+        to: https://example.com/new
+    waf:
+      - description: block bots
+        expression: (cf.client.bot)
+        action: block
+  - name: new.example
+    records:
+      - {type: A, name: "@", content: 192.0.2.1}
+`
+
+func TestApplyAccountFile(t *testing.T) {
+	srv := useFakeAPI(t)
+	enabled := true
+	srv.Reply("GET", "/zones?name=new.example", []zone{})
+	srv.Reply("POST", "/zones", zone{ID: "z2", Name: "new.example", Status: "pending"})
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Proxied: true}})
+	srv.Reply("PUT", "/zones/z1/dns_records/r1", dnsRecord{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.9"})
+	srv.Reply("POST", "/zones/z2/dns_records", dnsRecord{ID: "r2", Type: "A", Name: "new.example", Content: "192.0.2.1"})
+	srv.Reply("GET", "/zones/z1/settings", []zoneSetting{
+		{ID: "ssl", Value: json.RawMessage(`"flexible"`)},
+		{ID: "always_use_https", Value: json.RawMessage(`"on"`)},
+	})
+	srv.Reply("PATCH", "/zones/z1/settings", []zoneSetting{})
+	srv.Reply("GET", "/zones/z1/rulesets/phases/http_request_firewall_custom/entrypoint", ruleset{ID: "waf1", Rules: []rulesetRule{
+		{ID: "w1", Action: "block", Expression: "(cf.client.bot) or (ip.src eq 192.0.2.1)", Description: "block bots", Enabled: &enabled},
+		{ID: "w2", Action: "log", Expression: "true", Description: "hand made"},
+	}})
+	srv.Reply("PATCH", "/zones/z1/rulesets/waf1/rules/w1", ruleset{ID: "waf1"})
+	srv.Reply("PUT", "/zones/z1/rulesets/phases/http_request_dynamic_redirect/entrypoint", ruleset{ID: "rd1"})
+
+	file := filepath.Join(t.TempDir(), "account.yaml")
+	if err := os.WriteFile(file, []byte(testAccountYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := captureStdout(t, func() error { return runApply([]string{"-f", file, "--yes"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"~ setting ssl: flexible -> strict",
+		`+ redirect "/old": redirect if http.request.uri.path eq "/old"`,
+		`~ waf rule "block bots": block if (cf.client.bot)`,
+		"+ zone new.example",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "always_use_https") {
+		t.Fatalf("unchanged setting was planned:\n%s", out)
+	}
+
+	var settings struct {
+		Items []map[string]any `json:"items"`
+	}
+	if calls := srv.Calls("PATCH", "/zones/z1/settings"); len(calls) != 1 {
+		t.Fatalf("expected one settings update, got %d", len(calls))
+	} else if err := calls[0].Decode(&settings); err != nil || len(settings.Items) != 1 || settings.Items[0]["value"] != "strict" {
+		t.Fatalf("unexpected settings body %+v (err=%v)", settings, err)
+	}
+	if n := len(srv.Calls("PUT", "/zones/z1/dns_records/r1")); n != 1 {
+		t.Fatalf("expected the www record to be updated, got %d", n)
+	}
+	if n := len(srv.Calls("PATCH", "/zones/z1/rulesets/waf1/rules/w1")); n != 1 {
+		t.Fatalf("expected the WAF rule to be updated, got %d", n)
+	}
+	if n := len(srv.Calls("PUT", "/zones/z1/rulesets/phases/http_request_dynamic_redirect/entrypoint")); n != 1 {
+		t.Fatalf("expected the redirect to be created, got %d", n)
+	}
+	// The zone must exist before its records are created.
+	var zoneAt, recordAt int
+	for i, r := range srv.Requests() {
+		switch {
+		case r.Method == "POST" && r.Path == "/zones":
+			zoneAt = i
+		case r.Method == "POST" && r.Path == "/zones/z2/dns_records":
+			recordAt = i
+		}
+	}
+	if zoneAt == 0 || recordAt < zoneAt {
+		t.Fatalf("expected the zone to be created before its records (zone at %d, record at %d)", zoneAt, recordAt)
+	}
+}
+
+func TestPlanRulesIgnoresAPIDefaults(t *testing.T) {
+	want, err := singleRedirectRule(map[string]string{"from": "/old", "to": "https://example.com/new", "description": "/old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := want
+	have.ID = "x"
+	have.ActionParameters = json.RawMessage(`{"from_value":{"status_code":301,"target_url":{"value":"https://example.com/new"}}}`)
+	if changes := planRules(phaseSingleRedirect, []rulesetRule{want}, []rulesetRule{have}); len(changes) != 0 {
+		t.Fatalf("expected no change, got %+v", changes)
+	}
+}
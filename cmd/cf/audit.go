@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type auditLog struct {
+	ID     string `json:"id"`
+	When   string `json:"when"`
+	Action struct {
+		Type   string `json:"type"`
+		Result bool   `json:"result"`
+		Info   string `json:"info,omitempty"`
+	} `json:"action"`
+	Actor struct {
+		ID    string `json:"id,omitempty"`
+		Email string `json:"email,omitempty"`
+		IP    string `json:"ip,omitempty"`
+		Type  string `json:"type,omitempty"`
+	} `json:"actor"`
+	Resource struct {
+		ID   string `json:"id,omitempty"`
+		Type string `json:"type,omitempty"`
+	} `json:"resource"`
+	Interface string          `json:"interface,omitempty"`
+	Metadata  json.RawMessage `json:"metadata,omitempty"`
+	OldValue  string          `json:"oldValue,omitempty"`
+	NewValue  string          `json:"newValue,omitempty"`
+}
+
+// zoneName returns the zone the change applied to, when the metadata says.
+func (l auditLog) zoneName() string {
+	var meta struct {
+		ZoneName string `json:"zone_name"`
+	}
+	json.Unmarshal(l.Metadata, &meta)
+	return meta.ZoneName
+}
+
+// who describes the actor, falling back from email to the actor type for
+// API tokens and system changes.
+func (l auditLog) who() string {
+	switch {
+	case l.Actor.Email != "":
+		return l.Actor.Email
+	case l.Actor.Type != "":
+		return l.Actor.Type
+	}
+	return "unknown"
+}
+
+func runAudit(args []string) error {
+	flags := parseFlags(args)
+	since, err := parseDurationWithDefault(flags["since"], 7*24*time.Hour)
+	if err != nil || since <= 0 {
+		return usageErrorf("invalid --since %q (example: 24h, 7d)", flags["since"])
+	}
+	limit, err := parseIntWithDefault(flags["limit"], 500)
+	if err != nil {
+		return usageErrorf("invalid --limit: %w", err)
+	}
+
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	q.Set("since", time.Now().UTC().Add(-since).Format(time.RFC3339))
+	q.Set("direction", "desc")
+	if v := flags["actor"]; v != "" {
+		q.Set("actor.email", v)
+	}
+	if v := flags["action-type"]; v != "" {
+		q.Set("action.type", v)
+	}
+	if v := flags["zone"]; v != "" {
+		q.Set("zone.name", v)
+	}
+	logs, err := listAll[auditLog]("/accounts/"+accountID+"/audit_logs?"+q.Encode(), 100, limit)
+	if err != nil {
+		return err
+	}
+
+	t := table{Headers: []string{"WHEN", "ACTOR", "ACTION", "RESOURCE", "ZONE", "RESULT", "IP"}}
+	for _, l := range logs {
+		t.Rows = append(t.Rows, []string{l.When, l.who(), l.Action.Type, l.Resource.Type, l.zoneName(), strconv.FormatBool(l.Action.Result), l.Actor.IP})
+	}
+	return printList(logs, t, func() {
+		if len(logs) == 0 {
+			fmt.Printf("No audit log entries in the last %s.\n", since)
+			return
+		}
+		for _, l := range logs {
+			line := fmt.Sprintf("%s  %s  %s %s", l.When, l.who(), l.Action.Type, l.Resource.Type)
+			if zone := l.zoneName(); zone != "" {
+				line += " on " + zone
+			}
+			if !l.Action.Result {
+				line += " (failed)"
+			}
+			fmt.Println(line)
+			if l.OldValue != "" || l.NewValue != "" {
+				fmt.Printf("    %s -> %s\n", truncate(l.OldValue, 60), truncate(l.NewValue, 60))
+			}
+		}
+		if limit > 0 && len(logs) == limit {
+			fmt.Printf("Showing the newest %d entries; pass --limit to see more.\n", limit)
+		}
+	})
+}
+
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return "-"
+	}
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
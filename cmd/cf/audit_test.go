@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestAuditLogFields(t *testing.T) {
+	var l auditLog
+	raw := `{"when":"2026-10-01T10:00:00Z","action":{"type":"rec_set","result":true},"actor":{"type":"user","email":"ops@example.com"},"resource":{"type":"DNS_record"},"metadata":{"zone_name":"example.com"}}`
+	if err := json.Unmarshal([]byte(raw), &l); err != nil {
+		t.Fatal(err)
+	}
+	if l.who() != "ops@example.com" || l.zoneName() != "example.com" {
+		t.Fatalf("unexpected actor/zone: %q %q", l.who(), l.zoneName())
+	}
+
+	l = auditLog{}
+	l.Actor.Type = "system"
+	if l.who() != "system" || l.zoneName() != "" {
+		t.Fatalf("unexpected fallback actor/zone: %q %q", l.who(), l.zoneName())
+	}
+
+	if got := truncate("a\n  b", 10); got != "a b" {
+		t.Fatalf("unexpected truncate: %q", got)
+	}
+	if got := truncate("abcdefghijkl", 8); got != "abcde..." {
+		t.Fatalf("unexpected truncate: %q", got)
+	}
+}
+
+func TestParseDurationWithDefaultDays(t *testing.T) {
+	if d, err := parseDurationWithDefault("7d", time.Hour); err != nil || d != 7*24*time.Hour {
+		t.Fatalf("expected 7 days, got %s (err=%v)", d, err)
+	}
+	if d, err := parseDurationWithDefault("", time.Hour); err != nil || d != time.Hour {
+		t.Fatalf("expected fallback, got %s (err=%v)", d, err)
+	}
+	if _, err := parseDurationWithDefault("xd", time.Hour); err == nil {
+		t.Fatalf("expected error for invalid days")
+	}
+}
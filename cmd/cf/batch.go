@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"cf/pkg/cfapi"
+)
+
+// defaultBulkConcurrency is the number of requests bulk commands keep in
+// flight unless --concurrency says otherwise.
+const defaultBulkConcurrency = 4
+
+// batchItemRetries is how often runBatch retries an item that still fails
+// with a rate limit or gateway error after the client's own retries.
+const batchItemRetries = 2
+
+var batchRetryDelay = 2 * time.Second
+
+// runBatch calls do for every index below n with at most concurrency calls
+// in flight and returns one error per item (nil on success). Items that fail
+// transiently are retried; once the run is cancelled no new items start. A
+// progress bar labelled label is drawn on stderr when it is a terminal.
+func runBatch(label string, n, concurrency int, do func(i int) error) []error {
+	errs := make([]error, n)
+	if n == 0 {
+		return errs
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	progress := newBatchProgress(label, n)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = runBatchItem(i, do)
+				progress.add(errs[i] == nil)
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-rootCtx.Done():
+			for j := i; j < n; j++ {
+				errs[j] = context.Cause(rootCtx)
+			}
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	progress.finish()
+	return errs
+}
+
+func runBatchItem(i int, do func(i int) error) error {
+	for attempt := 0; ; attempt++ {
+		err := do(i)
+		if err == nil || attempt >= batchItemRetries || !retryableBatchError(err) {
+			return err
+		}
+		if err := sleep(batchRetryDelay << attempt); err != nil {
+			return err
+		}
+	}
+}
+
+// retryableBatchError reports whether an item is worth another attempt:
+// Cloudflare rate limited it or was unavailable, or an idempotent request hit
+// a gateway error. A create that timed out at the gateway may still have
+// gone through, so retrying it could leave a duplicate.
+func retryableBatchError(err error) bool {
+	var se *apiStatusError
+	if !errors.As(err, &se) {
+		return false
+	}
+	switch se.Status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return cfapi.Idempotent(se.Method)
+	}
+	return false
+}
+
+// countFailed returns how many of errs are non-nil.
+func countFailed(errs []error) int {
+	n := 0
+	for _, err := range errs {
+		if err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// batchProgress draws "label [=====     ] 12/40" on one terminal line.
+type batchProgress struct {
+	mu     sync.Mutex
+	w      io.Writer
+	label  string
+	total  int
+	done   int
+	failed int
+}
+
+func newBatchProgress(label string, total int) *batchProgress {
+	p := &batchProgress{label: label, total: total}
+	// Request logs and machine output would interleave with the bar.
+	if total > 1 && !verbose && !machineOutput() && isTerminal(os.Stderr) {
+		p.w = os.Stderr
+		p.draw()
+	}
+	return p
+}
+
+func (p *batchProgress) add(ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	if !ok {
+		p.failed++
+	}
+	p.draw()
+}
+
+func (p *batchProgress) draw() {
+	if p.w == nil {
+		return
+	}
+	const width = 30
+	filled := width * p.done / p.total
+	line := fmt.Sprintf("\r%s [%s%s] %d/%d", p.label, strings.Repeat("=", filled), strings.Repeat(" ", width-filled), p.done, p.total)
+	if p.failed > 0 {
+		line += fmt.Sprintf(" (%d failed)", p.failed)
+	}
+	fmt.Fprint(p.w, line)
+}
+
+func (p *batchProgress) finish() {
+	if p.w != nil {
+		fmt.Fprintln(p.w)
+	}
+}
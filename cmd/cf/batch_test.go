@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBatch(t *testing.T) {
+	old := sleep
+	sleep = func(time.Duration) error { return nil }
+	t.Cleanup(func() { sleep = old })
+
+	var inFlight, peak atomic.Int32
+	attempts := make([]atomic.Int32, 10)
+	errs := runBatch("test", 10, 3, func(i int) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		switch {
+		case i == 4 && attempts[i].Add(1) == 1:
+			return &apiStatusError{Status: 429}
+		case i == 7:
+			attempts[i].Add(1)
+			return &apiStatusError{Status: 400}
+		}
+		return nil
+	})
+
+	if peak.Load() > 3 {
+		t.Fatalf("expected at most 3 items in flight, saw %d", peak.Load())
+	}
+	if errs[4] != nil {
+		t.Fatalf("rate-limited item should succeed on retry, got %v", errs[4])
+	}
+	if errs[7] == nil || attempts[7].Load() != 1 {
+		t.Fatalf("a 400 should fail without retrying, got %v after %d attempts", errs[7], attempts[7].Load())
+	}
+	if countFailed(errs) != 1 {
+		t.Fatalf("expected 1 failure, got %d", countFailed(errs))
+	}
+}
+
+func TestRetryableBatchError(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{&apiStatusError{Method: "POST", Status: 429}, true},
+		{&apiStatusError{Method: "POST", Status: 503}, true},
+		{&apiStatusError{Method: "PUT", Status: 504}, true},
+		{&apiStatusError{Method: "DELETE", Status: 502}, true},
+		{&apiStatusError{Method: "POST", Status: 504}, false},
+		{&apiStatusError{Method: "PATCH", Status: 502}, false},
+		{&apiStatusError{Status: 500}, false},
+		{&apiStatusError{Status: 404}, false},
+		{errors.New("boom"), false},
+	} {
+		if got := retryableBatchError(tc.err); got != tc.want {
+			t.Fatalf("retryableBatchError(%v) = %t, want %t", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestBulkCreateNotRetriedAfterGatewayTimeout(t *testing.T) {
+	srv := useFakeAPI(t)
+	old := sleep
+	sleep = func(time.Duration) error { return nil }
+	t.Cleanup(func() { sleep = old })
+	// The record may have been created behind the gateway; a retry could
+	// add it a second time.
+	srv.Fail("POST", "/zones/z1/dns_records", 504, 0, "Gateway Timeout")
+
+	path := filepath.Join(t.TempDir(), "records.json")
+	if err := os.WriteFile(path, []byte(`[{"type":"A","name":"www","content":"192.0.2.1"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := captureStdout(t, func() error {
+		return runDNS([]string{"add", "--from-file", path, "--zone", "example.com"})
+	})
+	if err == nil {
+		t.Fatal("expected the create to fail")
+	}
+	if n := len(srv.Calls("POST", "/zones/z1/dns_records")); n != 1 {
+		t.Fatalf("expected a single create attempt, got %d", n)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func runCache(args []string) error {
+	if len(args) == 0 || args[0] != "purge" {
+		return usageErrorf("usage: cf cache purge --zone <zone> ...")
+	}
+	flags := parseFlags(args[1:])
+	concurrency, err := parseIntWithDefault(flags["concurrency"], defaultBulkConcurrency)
+	if err != nil {
+		return usageErrorf("invalid --concurrency: %w", err)
+	}
+	if fanOutRequested(flags) {
+		return purgeCacheZones(flags, concurrency)
+	}
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for cache purge: --zone (or --zones/--all-zones)")
+	}
+	return purgeCache(zoneName, flags, concurrency)
+}
+
+// purgeChunkSize is the most URLs, tags, prefixes or hosts Cloudflare
+// accepts in one purge request.
+const purgeChunkSize = 30
+
+type purgeResult struct {
+	ID string `json:"id"`
+}
+
+// purgeCache wraps POST /zones/:id/purge_cache. Exactly one purge mode must
+// be selected. Long lists are split into requests of purgeChunkSize items,
+// sent with up to concurrency in flight.
+func purgeCache(zoneName string, flags map[string]string, concurrency int) error {
+	if err := readPurgeFile(flags); err != nil {
+		return err
+	}
+	body, err := purgeCacheBody(zoneName, flags)
+	if err != nil {
+		return err
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	chunks := purgeCacheChunks(body)
+	results := make([]purgeResult, len(chunks))
+	errs := runBatch("Purging", len(chunks), concurrency, func(i int) (err error) {
+		results[i], err = sendPurge(z.ID, chunks[i])
+		return err
+	})
+	if err := errors.Join(errs...); err != nil {
+		if failed := countFailed(errs); failed < len(chunks) {
+			return fmt.Errorf("%d of %d purge requests failed: %w", failed, len(chunks), err)
+		}
+		return err
+	}
+
+	if len(chunks) == 1 {
+		r := results[0]
+		return printResult(r, func() {
+			fmt.Printf("Cache purge requested for %s (id=%s)\n", z.Name, r.ID)
+		})
+	}
+	return printResult(results, func() {
+		fmt.Printf("Cache purge requested for %s in %d requests\n", z.Name, len(results))
+	})
+}
+
+// purgeCacheZones runs the same purge in every zone selected by --zones or
+// --all-zones. Each zone's chunks are sent one after another.
+func purgeCacheZones(flags map[string]string, concurrency int) error {
+	if err := readPurgeFile(flags); err != nil {
+		return err
+	}
+	if _, err := purgeCacheBody("", flags); err != nil {
+		return err
+	}
+	zones, err := fanOutZones(flags)
+	if err != nil {
+		return err
+	}
+	return runOnZones(flags, "cache purge", zones, concurrency, func(z *zone) (string, any, error) {
+		body, err := purgeCacheBody(z.Name, flags)
+		if err != nil {
+			return "", nil, err
+		}
+		var results []purgeResult
+		for _, chunk := range purgeCacheChunks(body) {
+			r, err := sendPurge(z.ID, chunk)
+			if err != nil {
+				return "", results, err
+			}
+			results = append(results, r)
+		}
+		return fmt.Sprintf("purge requested (%d request(s))", len(results)), results, nil
+	})
+}
+
+func sendPurge(zoneID string, body map[string]any) (purgeResult, error) {
+	var r purgeResult
+	resp, err := requestCF(http.MethodPost, "/zones/"+zoneID+"/purge_cache", body)
+	if err != nil {
+		return r, err
+	}
+	return r, json.Unmarshal(resp.Result, &r)
+}
+
+// purgeCacheChunks splits the list in body into bodies of at most
+// purgeChunkSize items.
+func purgeCacheChunks(body map[string]any) []map[string]any {
+	for field, v := range body {
+		items, ok := v.([]string)
+		if !ok || len(items) <= purgeChunkSize {
+			continue
+		}
+		var chunks []map[string]any
+		for len(items) > 0 {
+			n := min(purgeChunkSize, len(items))
+			chunks = append(chunks, map[string]any{field: items[:n]})
+			items = items[n:]
+		}
+		return chunks
+	}
+	return []map[string]any{body}
+}
+
+// readPurgeFile adds the URLs listed in --from-file to --urls.
+func readPurgeFile(flags map[string]string) error {
+	if path := flags["from-file"]; path != "" {
+		urls, err := readLines(path)
+		if err != nil {
+			return err
+		}
+		flags["urls"] = strings.Join(append(splitList(flags["urls"]), urls...), ",")
+	}
+	return nil
+}
+
+// readLines returns the non-blank lines of path, skipping # comments.
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			out = append(out, line)
+		}
+	}
+	return out, nil
+}
+
+func purgeCacheBody(zoneName string, flags map[string]string) (map[string]any, error) {
+	body := map[string]any{}
+	if parseBoolWithDefault(flags["everything"], false) {
+		body["purge_everything"] = true
+	}
+	for flag, field := range map[string]string{"urls": "files", "tags": "tags", "prefixes": "prefixes", "hosts": "hosts"} {
+		if items := splitList(flags[flag]); len(items) > 0 {
+			body[field] = items
+		}
+	}
+
+	switch len(body) {
+	case 0:
+		return nil, errors.New("choose what to purge: --everything, --urls, --tags, --prefixes or --hosts")
+	case 1:
+	default:
+		return nil, errors.New("--everything, --urls, --tags, --prefixes and --hosts cannot be combined in one purge")
+	}
+
+	if prefixes, ok := body["prefixes"].([]string); ok {
+		// The API expects host-qualified prefixes without a scheme, e.g.
+		// example.com/img/, so bare paths are anchored to the zone apex.
+		for i, p := range prefixes {
+			p = strings.TrimPrefix(strings.TrimPrefix(p, "https://"), "http://")
+			if strings.HasPrefix(p, "/") {
+				p = zoneName + p
+			}
+			prefixes[i] = p
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPurgeCacheBody(t *testing.T) {
+	body, err := purgeCacheBody("example.com", map[string]string{"prefixes": "/img/, https://cdn.example.com/js/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"example.com/img/", "cdn.example.com/js/"}
+	if !reflect.DeepEqual(body["prefixes"], want) {
+		t.Fatalf("unexpected prefixes: %v", body["prefixes"])
+	}
+
+	body, err = purgeCacheBody("example.com", map[string]string{"urls": "https://example.com/a,https://example.com/b"})
+	if err != nil || len(body["files"].([]string)) != 2 {
+		t.Fatalf("expected urls to map to files, got %v (err=%v)", body, err)
+	}
+
+	if _, err := purgeCacheBody("example.com", map[string]string{}); err == nil {
+		t.Fatalf("expected error when no purge mode is given")
+	}
+	if _, err := purgeCacheBody("example.com", map[string]string{"everything": "true", "tags": "a"}); err == nil {
+		t.Fatalf("expected error when purge modes are combined")
+	}
+}
+
+func TestPurgeCacheChunks(t *testing.T) {
+	urls := make([]string, 65)
+	for i := range urls {
+		urls[i] = "https://example.com/" + string(rune('a'+i%26))
+	}
+	chunks := purgeCacheChunks(map[string]any{"files": urls})
+	if len(chunks) != 3 || len(chunks[0]["files"].([]string)) != 30 || len(chunks[2]["files"].([]string)) != 5 {
+		t.Fatalf("unexpected chunks: %d", len(chunks))
+	}
+
+	if chunks := purgeCacheChunks(map[string]any{"purge_everything": true}); len(chunks) != 1 {
+		t.Fatalf("purge everything should be one request, got %d", len(chunks))
+	}
+}
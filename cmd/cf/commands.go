@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// commands maps each top-level command to its handler, which receives the
+// arguments after the command name.
+var commands = map[string]func(args []string) error{
+	"login":    func(args []string) error { return runLogin(parseFlags(args)) },
+	"logout":   func([]string) error { return runLogout() },
+	"whoami":   func([]string) error { return runWhoAmI() },
+	"doctor":   func([]string) error { return runDoctor() },
+	"probe":    runProbe,
+	"watch":    runWatch,
+	"ddns":     runDDNS,
+	"undo":     runUndo,
+	"export":   runExport,
+	"snapshot": runSnapshot,
+	"apply":    runApply,
+	"accounts": runAccounts,
+	"wizard": func(args []string) error {
+		if len(args) > 0 && isHelp(args[0]) {
+			printWizardHelp()
+			return nil
+		}
+		flags := parseFlags(args)
+		opts, err := parseWizardOptions(flags)
+		if err != nil {
+			return err
+		}
+		if flags["answers"] != "" || parseBoolWithDefault(flags["non-interactive"], false) {
+			answers, err := wizardAnswersFrom(flags)
+			if err != nil {
+				return err
+			}
+			return runWizardUnattended(answers, opts)
+		}
+		return runWizard(opts)
+	},
+	"registrar":        runRegistrar,
+	"zones":            runZones,
+	"domains":          runDomains,
+	"dns":              runDNS,
+	"cache":            runCache,
+	"workers":          runWorkers,
+	"kv":               runKV,
+	"r2":               runR2,
+	"pages":            runPages,
+	"tunnels":          runTunnels,
+	"waf":              runWAF,
+	"firewall":         runFirewall,
+	"ratelimit":        runRateLimit,
+	"redirects":        runRedirects,
+	"pagerules":        runPageRules,
+	"transform":        runTransform,
+	"origin-ca":        runOriginCA,
+	"ssl":              runSSL,
+	"custom-hostnames": runCustomHostnames,
+	"email-routing":    runEmailRouting,
+	"lb":               runLB,
+	"healthchecks":     runHealthchecks,
+	"analytics":        runAnalytics,
+	"logpush":          runLogpush,
+	"audit":            runAudit,
+	"members":          runMembers,
+	"tokens":           runTokens,
+	"turnstile":        runTurnstile,
+	"d1":               runD1,
+	"queues":           runQueues,
+	"waiting-room":     runWaitingRoom,
+	"spectrum":         runSpectrum,
+	"access":           runAccess,
+	"gateway":          runGateway,
+	"images":           runImages,
+	"stream":           runStream,
+}
+
+// usageEntry is one "cf ..." line of helpText plus its description lines.
+type usageEntry struct {
+	text     string
+	words    [][]string // literal command words, each with its | alternatives
+	flags    map[string]bool
+	anyFlags bool
+}
+
+// flagPlaceholders expands flag patterns in helpText into concrete names. A
+// pattern not listed here (such as --<setting>) accepts any flag.
+var flagPlaceholders = map[string]func() []string{
+	"--<type>-<field>": dnsDataFlagNames,
+}
+
+func usageEntries() []usageEntry {
+	var out []usageEntry
+	for _, line := range strings.Split(helpText, "\n") {
+		switch {
+		case strings.HasPrefix(line, "  cf "):
+			out = append(out, parseUsageEntry(line))
+		case len(out) > 0 && strings.HasPrefix(line, strings.Repeat(" ", 42)):
+			out[len(out)-1].text += "\n" + line
+		}
+	}
+	return out
+}
+
+func parseUsageEntry(line string) usageEntry {
+	e := usageEntry{text: line, flags: map[string]bool{}}
+	for _, tok := range strings.Fields(line)[1:] {
+		// The description, when it shares the line, starts with a capital.
+		if strings.ContainsAny(tok[:1], "<[(-") || unicode.IsUpper(rune(tok[0])) {
+			break
+		}
+		e.words = append(e.words, strings.Split(tok, "|"))
+	}
+	for _, tok := range strings.Fields(line) {
+		i := strings.Index(tok, "--")
+		if i == -1 {
+			continue
+		}
+		tok = tok[i:]
+		if strings.HasPrefix(tok, "--<") {
+			expand, ok := flagPlaceholders[strings.TrimRight(tok, "])")]
+			if !ok {
+				e.anyFlags = true
+				continue
+			}
+			for _, name := range expand() {
+				e.flags[name] = true
+			}
+			continue
+		}
+		name := strings.TrimLeft(tok, "-")
+		if end := strings.IndexFunc(name, func(r rune) bool { return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') }); end != -1 {
+			name = name[:end]
+		}
+		if name != "" {
+			e.flags[name] = true
+		}
+	}
+	return e
+}
+
+// matchUsage returns the help entries that share the longest prefix of
+// literal command words with path, and the length of that prefix.
+func matchUsage(path []string) ([]usageEntry, int) {
+	var best []usageEntry
+	bestLen := 0
+	for _, e := range usageEntries() {
+		n := 0
+		for n < len(path) && n < len(e.words) && slices.Contains(e.words[n], path[n]) {
+			n++
+		}
+		switch {
+		case n == 0 || n < bestLen:
+		case n > bestLen:
+			best, bestLen = []usageEntry{e}, n
+		default:
+			best = append(best, e)
+		}
+	}
+	return best, bestLen
+}
+
+// checkFlags rejects flags that no matching help entry mentions, so a typo
+// such as --zne fails instead of being ignored.
+func checkFlags(args []string) error {
+	positional, _ := splitArgs(args)
+	entries, n := matchUsage(positional)
+	if len(entries) == 0 {
+		return nil
+	}
+	allowed := map[string]bool{}
+	for _, e := range entries {
+		if e.anyFlags {
+			return nil
+		}
+		for name := range e.flags {
+			allowed[name] = true
+		}
+	}
+
+	path := "cf " + strings.Join(positional[:n], " ")
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") || arg == "--" {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if allowed[name] {
+			continue
+		}
+		hint := ""
+		if s := closestFlag(name, allowed); s != "" {
+			hint = fmt.Sprintf(" (did you mean --%s?)", s)
+		}
+		return usageErrorf("unknown flag --%s for %s%s. run: %s --help", name, path, hint, path)
+	}
+	return nil
+}
+
+// closestFlag suggests the allowed flag within two edits of name.
+func closestFlag(name string, allowed map[string]bool) string {
+	candidates := make([]string, 0, len(allowed))
+	for c := range allowed {
+		candidates = append(candidates, c)
+	}
+	sort.Strings(candidates)
+	best, bestDist := "", 3
+	for _, c := range candidates {
+		if d := editDistance(name, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// helpRequested reports whether args ask for help with --help or -h, and
+// returns the command words to show help for.
+func helpRequested(args []string) ([]string, bool) {
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" {
+			positional, _ := splitArgs(args)
+			return positional, true
+		}
+	}
+	return nil, false
+}
+
+// printCommandHelp prints the help entries for a command or subcommand.
+func printCommandHelp(path []string) error {
+	if path[0] == "wizard" {
+		printWizardHelp()
+		return nil
+	}
+	entries, n := matchUsage(path)
+	if n == 0 {
+		return usageErrorf("unknown command %q. run: cf help", strings.Join(path, " "))
+	}
+	fmt.Println("Usage:")
+	for _, e := range entries {
+		fmt.Println(e.text)
+	}
+	fmt.Println("\nGlobal flags, credentials and exit codes: run cf help")
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestCheckFlags(t *testing.T) {
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"dns", "add", "--zone", "example.com", "--type", "A", "--name", "@", "--content", "1.2.3.4"}, ""},
+		{[]string{"dns", "add", "--zone=example.com", "--type", "CAA", "--caa-tag", "issue"}, ""},
+		{[]string{"dns", "add", "--zne", "example.com"}, "unknown flag --zne for cf dns add (did you mean --zone?)"},
+		{[]string{"dns", "list", "--concurrency", "4"}, "unknown flag --concurrency for cf dns list"},
+		{[]string{"zones", "settings", "set", "example.com", "--always-use-https", "on"}, ""},
+		{[]string{"queues", "consumers", "add", "--queue", "jobs", "--batch-size", "5"}, ""},
+		{[]string{"queues", "create", "jobs", "--frobnicate"}, "unknown flag --frobnicate for cf queues create."},
+		{[]string{"unknown-command", "--whatever"}, ""},
+	}
+	for _, tc := range cases {
+		err := checkFlags(tc.args)
+		if tc.want == "" {
+			if err != nil {
+				t.Fatalf("%v: unexpected error: %v", tc.args, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%v: expected error containing %q, got %v", tc.args, tc.want, err)
+		}
+		if exitCode(err) != exitUsage {
+			t.Fatalf("%v: expected a usage error, got exit code %d", tc.args, exitCode(err))
+		}
+	}
+}
+
+// Every command in the help text must be dispatchable and every command
+// must have help, so the two cannot drift apart.
+func TestCommandsMatchHelp(t *testing.T) {
+	documented := map[string]bool{}
+	for _, e := range usageEntries() {
+		if len(e.words) == 0 {
+			t.Fatalf("help entry without a command word: %q", e.text)
+		}
+		for _, name := range e.words[0] {
+			if name == "help" {
+				continue // handled by run
+			}
+			documented[name] = true
+			if _, ok := commands[name]; !ok {
+				t.Fatalf("help documents %q but there is no such command", name)
+			}
+		}
+	}
+	for name := range commands {
+		if !documented[name] {
+			t.Fatalf("command %q has no help entry", name)
+		}
+	}
+}
+
+func TestMatchUsage(t *testing.T) {
+	entries, n := matchUsage([]string{"dns", "add"})
+	if n != 2 || len(entries) != 4 {
+		t.Fatalf("expected the four dns add entries, got %d entries at depth %d", len(entries), n)
+	}
+	if _, n := matchUsage([]string{"nope"}); n != 0 {
+		t.Fatalf("expected no match for an unknown command, got depth %d", n)
+	}
+}
+
+// Every help entry that takes a required argument or flag must reject the
+// bare command as a usage error (exit 2), not a generic failure.
+func TestBareCommandsAreUsageErrors(t *testing.T) {
+	useFakeAPI(t)
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	stdin := os.Stdin
+	os.Stdin = devNull
+	defer func() { os.Stdin = stdin }()
+
+	seen := map[string]bool{}
+	for _, e := range usageEntries() {
+		if !requiresInput(e) {
+			continue
+		}
+		last := e.words[len(e.words)-1]
+		for _, path := range expandWords(e.words) {
+			key := strings.Join(path, " ")
+			// In shared entries such as "list|create <name>", list takes
+			// none of the arguments.
+			if len(last) > 1 && path[len(path)-1] == "list" {
+				continue
+			}
+			if seen[key] || path[0] == "help" {
+				continue
+			}
+			seen[key] = true
+			var err error
+			captureStdout(t, func() error { err = commands[path[0]](path[1:]); return nil })
+			if got := exitCode(err); got != exitUsage {
+				t.Errorf("cf %s: expected exit %d, got %d (%v)", key, exitUsage, got, err)
+			}
+		}
+	}
+}
+
+// requiresInput reports whether anything outside [...] follows the entry's
+// literal command words and precedes its description, such as <zone> or
+// --zone <zone>.
+func requiresInput(e usageEntry) bool {
+	line, _, _ := strings.Cut(strings.TrimPrefix(e.text, "  cf "), "\n")
+	fields := strings.Fields(line)[len(e.words):]
+	depth := 0
+	for _, f := range fields {
+		if depth == 0 && unicode.IsUpper(rune(f[0])) {
+			return false // the description
+		}
+		for _, r := range f {
+			switch {
+			case r == '[':
+				depth++
+			case r == ']':
+				depth--
+			case depth == 0 && strings.ContainsRune("<-(", r):
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func expandWords(words [][]string) [][]string {
+	paths := [][]string{nil}
+	for _, alts := range words {
+		var next [][]string
+		for _, p := range paths {
+			for _, w := range alts {
+				next = append(next, append(append([]string(nil), p...), w))
+			}
+		}
+		paths = next
+	}
+	return paths
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+type config struct {
+	DefaultProfile string `toml:"default_profile,omitempty"`
+	// AccountID is the account picked with cf accounts use when no profile
+	// is active.
+	AccountID string             `toml:"account_id,omitempty"`
+	Profiles  map[string]profile `toml:"profiles,omitempty"`
+}
+
+type profile struct {
+	APIToken    string `toml:"api_token,omitempty"`
+	APITokenEnv string `toml:"api_token_env,omitempty"`
+	AccountID   string `toml:"account_id,omitempty"`
+	Zone        string `toml:"zone,omitempty"`
+}
+
+// profileName is set by the global --profile flag.
+var profileName string
+
+var loadedConfig *config
+
+func configPath() string {
+	if v := strings.TrimSpace(os.Getenv("CF_CONFIG")); v != "" {
+		return v
+	}
+	if v := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME")); v != "" {
+		return filepath.Join(v, "cf", "config.toml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "cf", "config.toml")
+}
+
+// loadConfig reads the config file once per run. A missing file is not an
+// error and yields an empty config.
+func loadConfig() (*config, error) {
+	if loadedConfig != nil {
+		return loadedConfig, nil
+	}
+
+	cfg := &config{}
+	path := configPath()
+	if path != "" {
+		if _, err := toml.DecodeFile(path, cfg); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("could not read config %s: %w", path, err)
+		}
+	}
+	loadedConfig = cfg
+	return cfg, nil
+}
+
+// saveConfig writes cfg to the config file. Comments in the file are not
+// kept.
+func saveConfig(cfg *config) error {
+	path := configPath()
+	if path == "" {
+		return errors.New("could not locate the config file; set CF_CONFIG")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+		return err
+	}
+	// The file may hold API tokens.
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("could not write config %s: %w", path, err)
+	}
+	loadedConfig = cfg
+	return nil
+}
+
+// activeProfile returns the selected profile, its name, and whether it was
+// chosen explicitly via --profile or CF_PROFILE (as opposed to the config's
+// default_profile). It returns a nil profile when none applies.
+func activeProfile() (*profile, string, bool, error) {
+	name, explicit := profileName, true
+	if name == "" {
+		name = strings.TrimSpace(os.Getenv("CF_PROFILE"))
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	if name == "" {
+		name, explicit = cfg.DefaultProfile, false
+	}
+	if name == "" {
+		return nil, "", false, nil
+	}
+
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(cfg.Profiles))
+		for n := range cfg.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil, "", false, notFoundErrorf("profile %q not found: no profiles defined in %s", name, configPath())
+		}
+		return nil, "", false, notFoundErrorf("profile %q not found in %s. available: %s", name, configPath(), strings.Join(names, ", "))
+	}
+	return &p, name, explicit, nil
+}
+
+func (p *profile) token() string {
+	if p == nil {
+		return ""
+	}
+	if v := strings.TrimSpace(p.APIToken); v != "" {
+		return v
+	}
+	if p.APITokenEnv != "" {
+		return strings.TrimSpace(os.Getenv(p.APITokenEnv))
+	}
+	return ""
+}
+
+// zoneOrDefault returns zoneName, or the active profile's default zone when
+// zoneName is empty.
+func zoneOrDefault(zoneName string) string {
+	if zoneName != "" {
+		return zoneName
+	}
+	p, _, _, err := activeProfile()
+	if err != nil || p == nil {
+		return ""
+	}
+	return p.Zone
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func useTestConfig(t *testing.T, contents string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CF_CONFIG", path)
+	t.Setenv("CF_PROFILE", "")
+	loadedConfig, profileName, cachedAPIToken, cachedAccountID = nil, "", "", ""
+	t.Cleanup(func() {
+		loadedConfig, profileName, cachedAPIToken, cachedAccountID = nil, "", "", ""
+	})
+}
+
+const testConfig = `
+default_profile = "personal"
+
+[profiles.personal]
+api_token = "personal-token"
+account_id = "personal-account"
+
+[profiles.work]
+api_token_env = "WORK_TOKEN"
+account_id = "work-account"
+zone = "work.example.com"
+`
+
+func TestResolveAPIToken_ExplicitProfileBeatsEnv(t *testing.T) {
+	useTestConfig(t, testConfig)
+	t.Setenv("CF_API_TOKEN", "env-token")
+	t.Setenv("WORK_TOKEN", "work-token")
+	profileName = "work"
+
+	token, err := resolveAPIToken()
+	if err != nil || token != "work-token" {
+		t.Fatalf("expected work profile token, got %q (err=%v)", token, err)
+	}
+	accountID, err := resolveAccountID()
+	if err != nil || accountID != "work-account" {
+		t.Fatalf("expected work profile account, got %q (err=%v)", accountID, err)
+	}
+	if got := zoneOrDefault(""); got != "work.example.com" {
+		t.Fatalf("expected profile default zone, got %q", got)
+	}
+}
+
+func TestResolveAPIToken_EnvBeatsDefaultProfile(t *testing.T) {
+	useTestConfig(t, testConfig)
+	t.Setenv("CF_API_TOKEN", "env-token")
+
+	token, err := resolveAPIToken()
+	if err != nil || token != "env-token" {
+		t.Fatalf("expected env token, got %q (err=%v)", token, err)
+	}
+
+	cachedAPIToken = ""
+	t.Setenv("CF_API_TOKEN", "")
+	t.Setenv("CLOUDFLARE_API_TOKEN", "")
+	token, err = resolveAPIToken()
+	if err != nil || token != "personal-token" {
+		t.Fatalf("expected default profile token, got %q (err=%v)", token, err)
+	}
+}
+
+func TestActiveProfile_Unknown(t *testing.T) {
+	useTestConfig(t, testConfig)
+	t.Setenv("CF_PROFILE", "missing")
+
+	if _, _, _, err := activeProfile(); err == nil {
+		t.Fatalf("expected error for unknown profile")
+	}
+}
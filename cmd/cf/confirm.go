@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bufio"
+	"os"
+)
+
+// assumeYes is set by the global --yes flag. It answers yes to every
+// confirmation prompt, as does CF_ASSUME_YES=1.
+var assumeYes bool
+
+// skipConfirm reports whether a destructive command may go ahead without
+// asking: --yes (global or the command's own), --force, CF_ASSUME_YES, or
+// --dry-run, which sends nothing to confirm.
+func skipConfirm(flags map[string]string) bool {
+	return assumeYes || dryRun ||
+		parseBoolWithDefault(flags["yes"], false) ||
+		parseBoolWithDefault(flags["force"], false) ||
+		parseBoolWithDefault(os.Getenv("CF_ASSUME_YES"), false)
+}
+
+// confirm asks question before a delete or overwrite whose effect the caller
+// has already printed. command names the command in the error returned when
+// machine output leaves nobody to answer.
+func confirm(flags map[string]string, command, question string) (bool, error) {
+	return confirmWith(bufio.NewReader(os.Stdin), flags, command, question)
+}
+
+// confirmWith is confirm for commands that already read answers from
+// reader, such as the wizard, so buffered input is not lost.
+func confirmWith(reader *bufio.Reader, flags map[string]string, command, question string) (bool, error) {
+	if skipConfirm(flags) {
+		return true, nil
+	}
+	if err := requireConfirmable(flags, command); err != nil {
+		return false, err
+	}
+	return promptYesNo(reader, question, false)
+}
+
+// requireConfirmable fails early, before any API calls, when confirm would
+// have to prompt but the output is meant for a machine.
+func requireConfirmable(flags map[string]string, command string) error {
+	if machineOutput() && !skipConfirm(flags) {
+		return usageErrorf("%s with --output json/csv cannot prompt for confirmation; pass --yes or set CF_ASSUME_YES=1", command)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDNSDeleteConfirmation(t *testing.T) {
+	srv := useFakeAPI(t)
+	r1 := dnsRecord{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1}
+	srv.Reply("GET", "/zones/z1/dns_records/r1", r1)
+	srv.Reply("DELETE", "/zones/z1/dns_records/r1", map[string]string{"id": "r1"})
+	args := []string{"delete", "--zone", "example.com", "--id", "r1"}
+
+	outputFormat = outputJSON
+	t.Cleanup(func() { outputFormat = outputPlain })
+	if err := runDNS(args); err == nil || !strings.Contains(err.Error(), "CF_ASSUME_YES") {
+		t.Fatalf("expected json output without --yes to refuse, got %v", err)
+	}
+	if n := len(srv.Calls("DELETE", "/zones/z1/dns_records/r1")); n != 0 {
+		t.Fatalf("expected no delete before confirmation, got %d", n)
+	}
+
+	t.Setenv("CF_ASSUME_YES", "1")
+	if _, err := captureStdout(t, func() error { return runDNS(args) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(srv.Calls("DELETE", "/zones/z1/dns_records/r1")); n != 1 {
+		t.Fatalf("expected CF_ASSUME_YES to confirm the delete, got %d", n)
+	}
+}
+
+func TestGlobalYesFlag(t *testing.T) {
+	t.Cleanup(func() { assumeYes = false })
+	rest, err := parseGlobalFlags([]string{"dns", "sync", "--yes", "--file", "dns.yaml"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(rest, []string{"dns", "sync", "--file", "dns.yaml"}) || !skipConfirm(map[string]string{}) {
+		t.Fatalf("expected --yes to be global, got args %v", rest)
+	}
+}
+
+func TestDNSSyncCSVOutputRefusesToPrompt(t *testing.T) {
+	srv := useFakeAPI(t)
+	path := filepath.Join(t.TempDir(), "dns.yaml")
+	if err := os.WriteFile(path, []byte("zone: example.com\nrecords:\n  - {type: A, name: www, content: 192.0.2.1}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outputFormat = outputCSV
+	t.Cleanup(func() { outputFormat = outputPlain })
+
+	var err error
+	out, _ := captureStdout(t, func() error { err = runDNS([]string{"sync", "--file", path}); return nil })
+	if exitCode(err) != exitUsage || !strings.Contains(err.Error(), "dns sync") {
+		t.Fatalf("expected a usage error for dns sync, got %v", err)
+	}
+	if out != "" {
+		t.Fatalf("expected nothing on stdout, got %q", out)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("expected no API calls, got %d", n)
+	}
+}
+
+func TestMailPresetConfirmationDefaultsToNo(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{
+		{ID: "1", Type: "MX", Name: "example.com", Content: "mx.old-provider.example"},
+	})
+	p, err := findEmailProvider("fastmail")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result *dnsSyncResult
+	_, _ = captureStdout(t, func() error {
+		result, err = applyMailPreset(bufio.NewReader(strings.NewReader("\n")), &zone{ID: "z1", Name: "example.com"}, p, nil)
+		return nil
+	})
+	if err != nil || result != nil {
+		t.Fatalf("expected an empty answer to decline, got %v, %v", result, err)
+	}
+	for _, r := range srv.Requests() {
+		if r.Method != "GET" {
+			t.Fatalf("expected no changes after declining, got %s %s", r.Method, r.Path)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// timeout is set by the global --timeout flag; zero means fall back to
+// CF_TIMEOUT, or no limit.
+var timeout time.Duration
+
+var errInterrupted = errors.New("interrupted")
+
+func setTimeout(v string) error {
+	d, err := parseDurationWithDefault(v, 0)
+	if err != nil || d <= 0 {
+		return usageErrorf("invalid --timeout %q: want a duration such as 30s or 5m", v)
+	}
+	timeout = d
+	return nil
+}
+
+func resolveTimeout() time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	if v := strings.TrimSpace(os.Getenv("CF_TIMEOUT")); v != "" {
+		if d, err := parseDurationWithDefault(v, 0); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// newRootContext returns the context for the whole run. Ctrl-C or SIGTERM
+// cancels it. After the first signal the default handling is restored, so a
+// second Ctrl-C kills the process outright.
+func newRootContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-signals; ok {
+			signal.Stop(signals)
+			cancel(errInterrupted)
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		close(signals)
+		cancel(nil)
+	}
+}
+
+// stopTimeout releases the deadline set by startTimeout.
+var stopTimeout = func() {}
+
+// startTimeout puts the --timeout (or CF_TIMEOUT) deadline on rootCtx. It
+// runs once the global flags are parsed, so that --timeout is known.
+func startTimeout() {
+	if d := resolveTimeout(); d > 0 {
+		rootCtx, stopTimeout = context.WithTimeoutCause(rootCtx, d, fmt.Errorf("timed out after %s (--timeout)", d))
+	}
+}
+
+// contextError replaces the low-level error from a cancelled request with
+// the reason the run was cut short.
+func contextError(ctx context.Context, err error) error {
+	cause := context.Cause(ctx)
+	if cause == nil {
+		return err
+	}
+	code := exitTimeout
+	if errors.Is(cause, errInterrupted) {
+		code = exitInterrupted
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, cause) {
+		return &cliError{code: code, err: cause}
+	}
+	return &cliError{code: code, err: fmt.Errorf("%w: %v", cause, err)}
+}
+
+// sleepContext waits for d, returning early with the cause if ctx ends.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"cf/internal/cftest"
+)
+
+func TestTimeoutFlagCutsOffHungRequest(t *testing.T) {
+	srv := useFakeAPI(t)
+	hung := make(chan struct{})
+	srv.Handle("GET", "/zones", func(cftest.Request) cftest.Response {
+		<-hung
+		return cftest.Response{Result: []zone{}}
+	})
+	t.Cleanup(func() { close(hung) })
+
+	start := time.Now()
+	_, err := runCLI(t, "--timeout", "200ms", "zones", "list")
+	err = contextError(rootCtx, err)
+	if exitCode(err) != exitTimeout || !strings.Contains(err.Error(), "timed out after 200ms (--timeout)") {
+		t.Fatalf("expected the timeout to end the run, got %v (exit %d)", err, exitCode(err))
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("run took %s despite --timeout 200ms", elapsed)
+	}
+
+	if _, err := parseGlobalFlags([]string{"--timeout", "soon"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected usage error for bad --timeout, got %v", err)
+	}
+}
+
+func TestContextError(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	if err := contextError(ctx, errors.New("boom")); err.Error() != "boom" {
+		t.Fatalf("live context should leave errors alone, got %v", err)
+	}
+
+	cancel(errInterrupted)
+	err := contextError(ctx, fmt.Errorf("request failed: %w", context.Canceled))
+	if exitCode(err) != exitInterrupted || err.Error() != "interrupted" {
+		t.Fatalf("got %v (exit %d), want interrupted (exit %d)", err, exitCode(err), exitInterrupted)
+	}
+
+	ctx, stop := context.WithTimeoutCause(context.Background(), 0, errors.New("timed out after 1s (--timeout)"))
+	defer stop()
+	<-ctx.Done()
+	if err := contextError(ctx, context.DeadlineExceeded); exitCode(err) != exitTimeout {
+		t.Fatalf("got exit %d, want %d", exitCode(err), exitTimeout)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+type customHostname struct {
+	ID       string `json:"id"`
+	Hostname string `json:"hostname"`
+	Status   string `json:"status"`
+	SSL      struct {
+		Status            string `json:"status"`
+		Method            string `json:"method"`
+		Type              string `json:"type"`
+		ValidationRecords []struct {
+			TXTName  string   `json:"txt_name,omitempty"`
+			TXTValue string   `json:"txt_value,omitempty"`
+			HTTPURL  string   `json:"http_url,omitempty"`
+			HTTPBody string   `json:"http_body,omitempty"`
+			Emails   []string `json:"emails,omitempty"`
+		} `json:"validation_records,omitempty"`
+		ValidationErrors []struct {
+			Message string `json:"message"`
+		} `json:"validation_errors,omitempty"`
+	} `json:"ssl"`
+	OwnershipVerification *struct {
+		Type  string `json:"type"`
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"ownership_verification,omitempty"`
+	VerificationErrors []string `json:"verification_errors,omitempty"`
+	CustomOriginServer string   `json:"custom_origin_server,omitempty"`
+	CreatedAt          string   `json:"created_at,omitempty"`
+}
+
+// active reports whether both hostname ownership and the certificate are
+// validated.
+func (h *customHostname) active() bool {
+	return h.Status == "active" && h.SSL.Status == "active"
+}
+
+func runCustomHostnames(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for custom-hostnames: --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	path := "/zones/" + z.ID + "/custom_hostnames"
+
+	switch action {
+	case "list":
+		hostnames, err := listAll[customHostname](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "HOSTNAME", "STATUS", "SSL", "METHOD"}}
+		for _, h := range hostnames {
+			t.Rows = append(t.Rows, []string{h.ID, h.Hostname, h.Status, h.SSL.Status, h.SSL.Method})
+		}
+		return printList(hostnames, t, func() {
+			if len(hostnames) == 0 {
+				fmt.Printf("No custom hostnames on %s.\n", z.Name)
+				return
+			}
+			for _, h := range hostnames {
+				fmt.Printf("%s\t%s\tstatus=%s ssl=%s\n", h.ID, h.Hostname, h.Status, h.SSL.Status)
+			}
+		})
+	case "add":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf custom-hostnames add <hostname> --zone <zone> [--method http|txt|email] [--origin <host>]")
+		}
+		method := flags["method"]
+		if method == "" {
+			method = "http"
+		}
+		if !slices.Contains(certValidationMethods, method) {
+			return usageErrorf("invalid --method %q (want one of: %s)", method, strings.Join(certValidationMethods, ", "))
+		}
+		body := map[string]any{
+			"hostname": positional[0],
+			"ssl":      map[string]string{"method": method, "type": "dv"},
+		}
+		if v := flags["origin"]; v != "" {
+			body["custom_origin_server"] = v
+		}
+		resp, err := requestCF(http.MethodPost, path, body)
+		if err != nil {
+			return err
+		}
+		var h customHostname
+		if err := json.Unmarshal(resp.Result, &h); err != nil {
+			return err
+		}
+		return printResult(h, func() { printCustomHostname(&h) })
+	case "status":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf custom-hostnames status <hostname|id> --zone <zone> [--wait]")
+		}
+		interval, err := parseDurationWithDefault(flags["interval"], 15*time.Second)
+		if err != nil {
+			return usageErrorf("invalid --interval: %w", err)
+		}
+		timeout, err := parseDurationWithDefault(flags["timeout"], 15*time.Minute)
+		if err != nil {
+			return usageErrorf("invalid --timeout: %w", err)
+		}
+		return customHostnameStatus(path, positional[0], parseBoolWithDefault(flags["wait"], false), interval, timeout)
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf custom-hostnames delete <hostname|id> --zone <zone> [--force]")
+		}
+		if err := requireConfirmable(flags, "custom-hostnames delete"); err != nil {
+			return err
+		}
+		h, err := findCustomHostname(path, positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirm(flags, "custom-hostnames delete", fmt.Sprintf("Delete custom hostname %s?", h.Hostname))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+h.ID, nil); err != nil {
+			return err
+		}
+		return printResult(h, func() {
+			fmt.Printf("Custom hostname deleted: %s\n", h.Hostname)
+		})
+	}
+	return usageErrorf("usage: cf custom-hostnames list|add|status|delete --zone <zone>")
+}
+
+func findCustomHostname(path, ref string) (*customHostname, error) {
+	if isHexID(strings.ReplaceAll(ref, "-", "")) {
+		resp, err := requestCF(http.MethodGet, path+"/"+ref, nil)
+		if err != nil {
+			return nil, err
+		}
+		var h customHostname
+		if err := json.Unmarshal(resp.Result, &h); err != nil {
+			return nil, err
+		}
+		return &h, nil
+	}
+	hostnames, err := listAll[customHostname](path+"?hostname="+url.QueryEscape(ref), 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hostnames {
+		if strings.EqualFold(h.Hostname, ref) {
+			return &h, nil
+		}
+	}
+	return nil, notFoundErrorf("custom hostname %q not found", ref)
+}
+
+// customHostnameStatus shows validation progress; with wait it polls until
+// the hostname and its certificate are active.
+func customHostnameStatus(path, ref string, wait bool, interval, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	last := ""
+	for {
+		h, err := findCustomHostname(path, ref)
+		if err != nil {
+			return err
+		}
+		if !wait || h.active() {
+			return printResult(h, func() { printCustomHostname(h) })
+		}
+		if summary := h.Status + "|" + h.SSL.Status; summary != last && !machineOutput() {
+			printCustomHostname(h)
+			last = summary
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("custom hostname %s not active after %s", h.Hostname, timeout)
+		}
+		if err := sleep(interval); err != nil {
+			return err
+		}
+	}
+}
+
+func printCustomHostname(h *customHostname) {
+	fmt.Printf("%s (id=%s)\n", h.Hostname, h.ID)
+	fmt.Printf("  status: %s\n", h.Status)
+	fmt.Printf("  ssl:    %s (%s validation)\n", h.SSL.Status, h.SSL.Method)
+	if h.Status != "active" && h.OwnershipVerification != nil {
+		ov := h.OwnershipVerification
+		fmt.Printf("  ownership: add %s record %s = %s\n", strings.ToUpper(ov.Type), ov.Name, ov.Value)
+	}
+	if h.SSL.Status != "active" {
+		for _, r := range h.SSL.ValidationRecords {
+			switch {
+			case r.TXTName != "":
+				fmt.Printf("  certificate: add TXT record %s = %s\n", r.TXTName, r.TXTValue)
+			case r.HTTPURL != "":
+				fmt.Printf("  certificate: serve %s with body %s (automatic once the hostname points at Cloudflare)\n", r.HTTPURL, r.HTTPBody)
+			case len(r.Emails) > 0:
+				fmt.Printf("  certificate: approve the email sent to %s\n", strings.Join(r.Emails, ", "))
+			}
+		}
+	}
+	for _, e := range h.SSL.ValidationErrors {
+		fmt.Printf("  error: %s\n", e.Message)
+	}
+	for _, e := range h.VerificationErrors {
+		fmt.Printf("  error: %s\n", e)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"cf/internal/cftest"
+)
+
+func TestCustomHostnamesAdd(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("POST", "/zones/z1/custom_hostnames", map[string]any{
+		"id": "ch1", "hostname": "shop.customer.test", "status": "pending",
+		"ssl": map[string]any{"status": "pending_validation", "method": "txt", "validation_records": []map[string]string{
+			{"txt_name": "_acme-challenge.shop.customer.test", "txt_value": "token"},
+		}},
+	})
+
+	out, err := captureStdout(t, func() error {
+		return runCustomHostnames([]string{"add", "shop.customer.test", "--zone", "example.com", "--method", "txt", "--origin", "origin.example.com"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body struct {
+		Hostname string            `json:"hostname"`
+		SSL      map[string]string `json:"ssl"`
+		Origin   string            `json:"custom_origin_server"`
+	}
+	if posts := srv.Calls("POST", "/zones/z1/custom_hostnames"); len(posts) != 1 {
+		t.Fatalf("expected one POST, got %d", len(posts))
+	} else if err := posts[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Hostname != "shop.customer.test" || body.SSL["method"] != "txt" || body.SSL["type"] != "dv" || body.Origin != "origin.example.com" {
+		t.Fatalf("unexpected body %+v", body)
+	}
+	if !strings.Contains(out, "certificate: add TXT record _acme-challenge.shop.customer.test = token") {
+		t.Fatalf("expected validation instructions, got:\n%s", out)
+	}
+
+	if err := runCustomHostnames([]string{"add", "shop.customer.test", "--zone", "example.com", "--method", "cname"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error for an unknown method, got %v", err)
+	}
+}
+
+func TestCustomHostnamesStatusWait(t *testing.T) {
+	srv := useFakeAPI(t)
+	old := sleep
+	sleep = func(time.Duration) error { return nil }
+	t.Cleanup(func() { sleep = old })
+
+	polls := 0
+	srv.Handle("GET", "/zones/z1/custom_hostnames?hostname=shop.customer.test", func(cftest.Request) cftest.Response {
+		polls++
+		status := "pending"
+		if polls == 2 {
+			status = "active"
+		}
+		return cftest.Response{Result: []map[string]any{
+			{"id": "ch1", "hostname": "shop.customer.test", "status": status, "ssl": map[string]any{"status": status, "method": "http"}},
+		}}
+	})
+
+	out, err := captureStdout(t, func() error {
+		return runCustomHostnames([]string{"status", "shop.customer.test", "--zone", "example.com", "--wait"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if polls != 2 || !strings.HasSuffix(out, "  status: active\n  ssl:    active (http validation)\n") {
+		t.Fatalf("expected to poll until active, got %d polls:\n%s", polls, out)
+	}
+
+	srv.Reply("GET", "/zones/z1/custom_hostnames?hostname=other.customer.test", []customHostname{})
+	if err := runCustomHostnames([]string{"status", "other.customer.test", "--zone", "example.com"}); exitCode(err) != exitNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestCustomHostnamesDelete(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/custom_hostnames?hostname=shop.customer.test", []map[string]any{{"id": "ch1", "hostname": "shop.customer.test"}})
+	srv.Fail("DELETE", "/zones/z1/custom_hostnames/ch1", 409, 1414, "Custom hostname is in use")
+
+	err := runCustomHostnames([]string{"delete", "shop.customer.test", "--zone", "example.com", "--force"})
+	if exitCode(err) != exitAPI || !strings.Contains(err.Error(), "Custom hostname is in use") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+	if n := len(srv.Calls("DELETE", "/zones/z1/custom_hostnames/ch1")); n != 1 {
+		t.Fatalf("expected one DELETE, got %d", n)
+	}
+
+	srv.Reply("DELETE", "/zones/z1/custom_hostnames/ch1", map[string]string{"id": "ch1"})
+	out, err := captureStdout(t, func() error {
+		return runCustomHostnames([]string{"delete", "shop.customer.test", "--zone", "example.com", "--force"})
+	})
+	if err != nil || out != "Custom hostname deleted: shop.customer.test\n" {
+		t.Fatalf("unexpected result err=%v:\n%s", err, out)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type d1Database struct {
+	UUID      string `json:"uuid"`
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+	NumTables int    `json:"num_tables,omitempty"`
+	FileSize  int64  `json:"file_size,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// d1Result is one statement's output from the /raw query endpoint, which
+// keeps column order (the plain endpoint returns objects).
+type d1Result struct {
+	Results struct {
+		Columns []string `json:"columns"`
+		Rows    [][]any  `json:"rows"`
+	} `json:"results"`
+	Success bool `json:"success"`
+	Meta    struct {
+		Changes     int     `json:"changes"`
+		Duration    float64 `json:"duration"`
+		RowsRead    int     `json:"rows_read"`
+		RowsWritten int     `json:"rows_written"`
+	} `json:"meta"`
+}
+
+func runD1(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	path := "/accounts/" + accountID + "/d1/database"
+
+	switch action {
+	case "list":
+		dbs, err := listAll[d1Database](path, 100, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"UUID", "NAME", "TABLES", "SIZE", "CREATED"}}
+		for _, db := range dbs {
+			t.Rows = append(t.Rows, []string{db.UUID, db.Name, strconv.Itoa(db.NumTables), strconv.FormatInt(db.FileSize, 10), db.CreatedAt})
+		}
+		return printList(dbs, t, func() {
+			if len(dbs) == 0 {
+				fmt.Println("No D1 databases.")
+				return
+			}
+			for _, db := range dbs {
+				fmt.Printf("%s\t%s\t%d tables\t%s\n", db.UUID, db.Name, db.NumTables, humanBytes(strconv.FormatInt(db.FileSize, 10)))
+			}
+		})
+	case "create":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf d1 create <name> [--location weur|eeur|apac|oc|wnam|enam]")
+		}
+		body := map[string]string{"name": positional[0]}
+		if v := flags["location"]; v != "" {
+			body["primary_location_hint"] = v
+		}
+		resp, err := requestCF(http.MethodPost, path, body)
+		if err != nil {
+			return err
+		}
+		var db d1Database
+		if err := json.Unmarshal(resp.Result, &db); err != nil {
+			return err
+		}
+		return printResult(db, func() {
+			fmt.Printf("D1 database created: %s (uuid=%s)\n", db.Name, db.UUID)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf d1 delete <name|uuid> [--force]")
+		}
+		if err := requireConfirmable(flags, "d1 delete"); err != nil {
+			return err
+		}
+		db, err := findD1Database(path, positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirm(flags, "d1 delete", fmt.Sprintf("Delete D1 database %s and all of its data?", db.Name))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+db.UUID, nil); err != nil {
+			return err
+		}
+		return printResult(db, func() {
+			fmt.Printf("D1 database deleted: %s\n", db.Name)
+		})
+	case "query":
+		if len(positional) == 0 || (flags["sql"] == "") == (flags["file"] == "") {
+			return usageErrorf("usage: cf d1 query <name|uuid> (--sql \"SELECT ...\" | --file schema.sql)")
+		}
+		sql := flags["sql"]
+		if f := flags["file"]; f != "" {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				return err
+			}
+			sql = string(data)
+		}
+		db, err := findD1Database(path, positional[0])
+		if err != nil {
+			return err
+		}
+		resp, err := requestCF(http.MethodPost, path+"/"+db.UUID+"/raw", map[string]string{"sql": sql})
+		if err != nil {
+			return err
+		}
+		var results []d1Result
+		if err := json.Unmarshal(resp.Result, &results); err != nil {
+			return err
+		}
+		return printD1Results(results)
+	}
+	return usageErrorf("usage: cf d1 list|create|delete|query")
+}
+
+func findD1Database(path, ref string) (*d1Database, error) {
+	dbs, err := listAll[d1Database](path, 100, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, db := range dbs {
+		if db.UUID == ref || db.Name == ref {
+			return &db, nil
+		}
+	}
+	return nil, notFoundErrorf("D1 database %q not found. run: cf d1 list", ref)
+}
+
+// printD1Results renders every statement that returned columns as a table;
+// statements without rows (DDL, writes) only report their counters.
+func printD1Results(results []d1Result) error {
+	if jsonOutput() {
+		return writeJSON(results)
+	}
+	for _, r := range results {
+		if len(r.Results.Columns) == 0 {
+			infof("OK: %d changes, %d rows written, %.1fms\n", r.Meta.Changes, r.Meta.RowsWritten, r.Meta.Duration)
+			continue
+		}
+		t := d1Table(r)
+		if err := printList(nil, t, func() { writeTable(t) }); err != nil {
+			return err
+		}
+		infof("(%d rows, %.1fms)\n", len(t.Rows), r.Meta.Duration)
+	}
+	return nil
+}
+
+func d1Table(r d1Result) table {
+	t := table{Headers: r.Results.Columns}
+	for _, row := range r.Results.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = d1Cell(v)
+		}
+		t.Rows = append(t.Rows, cells)
+	}
+	return t
+}
+
+func d1Cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestD1Table(t *testing.T) {
+	var results []d1Result
+	raw := `[{"results":{"columns":["id","name","score"],"rows":[[1,"alice",9.5],[2,null,10]]},"success":true,"meta":{"duration":0.3}}]`
+	if err := json.Unmarshal([]byte(raw), &results); err != nil {
+		t.Fatal(err)
+	}
+	got := d1Table(results[0])
+	want := table{
+		Headers: []string{"id", "name", "score"},
+		Rows:    [][]string{{"1", "alice", "9.5"}, {"2", "NULL", "10"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected table: %+v", got)
+	}
+}
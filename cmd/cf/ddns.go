@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"cf/pkg/cfapi"
+)
+
+// ddnsTraceURLs are Cloudflare's trace endpoints, reached by address so the
+// request itself picks the address family being detected.
+var ddnsTraceURLs = map[bool]string{
+	false: "https://1.1.1.1/cdn-cgi/trace",
+	true:  "https://[2606:4700:4700::1111]/cdn-cgi/trace",
+}
+
+// detectPublicIP asks source for the address this machine reaches the
+// internet from. source is "cloudflare" for the trace endpoint, or the URL of
+// a service that answers with the bare address, such as
+// https://api.ipify.org. It is a variable so tests can avoid the network.
+var detectPublicIP = func(ctx context.Context, source string, ipv6 bool) (net.IP, error) {
+	url := source
+	if source == "" || source == "cloudflare" {
+		url = ddnsTraceURLs[ipv6]
+	}
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := outboundClient(0).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("public IP lookup via %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("public IP lookup via %s failed: HTTP %d", url, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return nil, err
+	}
+	return parsePublicIP(string(body), ipv6)
+}
+
+// parsePublicIP reads either a trace response (key=value lines including
+// ip=) or a bare address, and checks it is of the wanted family.
+func parsePublicIP(body string, ipv6 bool) (net.IP, error) {
+	text := strings.TrimSpace(body)
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		if v, ok := strings.CutPrefix(scanner.Text(), "ip="); ok {
+			text = strings.TrimSpace(v)
+		}
+	}
+	ip := net.ParseIP(text)
+	switch {
+	case ip == nil:
+		return nil, fmt.Errorf("public IP service answered %q, not an address", text)
+	case ipv6 && ip.To4() != nil:
+		return nil, fmt.Errorf("public IP service answered IPv4 address %s; use an IPv6-only service with --ipv6", ip)
+	case !ipv6 && ip.To4() == nil:
+		return nil, fmt.Errorf("public IP service answered IPv6 address %s; use an IPv4-only service or pass --ipv6", ip)
+	}
+	return ip, nil
+}
+
+type ddnsOptions struct {
+	Zone     string
+	Name     string
+	IPv6     bool
+	Source   string
+	Proxied  *bool
+	TTL      int
+	Interval time.Duration
+}
+
+func runDDNS(args []string) error {
+	flags := parseFlags(args)
+	opts := ddnsOptions{
+		Zone:   zoneOrDefault(flags["zone"]),
+		Name:   flags["name"],
+		IPv6:   parseBoolWithDefault(flags["ipv6"], false),
+		Source: flags["ip-source"],
+	}
+	if opts.Zone == "" || opts.Name == "" {
+		return usageErrorf("missing required flags for ddns: --zone --name")
+	}
+	var err error
+	if opts.Interval, err = parseDurationWithDefault(flags["interval"], 0); err != nil || opts.Interval < 0 {
+		return usageErrorf("invalid --interval %q: want a duration such as 5m", flags["interval"])
+	}
+	if opts.TTL, err = parseIntWithDefault(flags["ttl"], 1); err != nil {
+		return usageErrorf("invalid --ttl: %w", err)
+	}
+	if v, ok := flags["proxied"]; ok {
+		b := parseBoolWithDefault(v, true)
+		opts.Proxied = &b
+	}
+
+	// A ddns loop would grow one journal run for as long as it runs, and
+	// undoing it would revert hours of updates at once.
+	journalOff = true
+	defer func() { journalOff = false }()
+
+	z, err := requireZone(opts.Zone)
+	if err != nil {
+		return err
+	}
+	if opts.Interval == 0 {
+		_, err := ddnsUpdate(z, opts)
+		return err
+	}
+
+	ddnsLogf(logInfo, "updating %s every %s", qualifyRecordName(opts.Name, z.Name), opts.Interval)
+	for {
+		if _, err := ddnsUpdate(z, opts); err != nil {
+			if code := exitCode(err); code == exitAuth || code == exitNotFound {
+				return err
+			}
+			ddnsLogf(logErr, "%v", err)
+		}
+		if err := sleep(opts.Interval); err != nil {
+			if errors.Is(err, errInterrupted) {
+				ddnsLogf(logInfo, "stopping")
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// ddnsUpdate points the record at the current public address, creating it
+// if it does not exist. It reports whether anything changed.
+func ddnsUpdate(z *zone, opts ddnsOptions) (bool, error) {
+	typeName := "A"
+	if opts.IPv6 {
+		typeName = "AAAA"
+	}
+	name := qualifyRecordName(opts.Name, z.Name)
+
+	ip, err := detectPublicIP(rootCtx, opts.Source, opts.IPv6)
+	if err != nil {
+		return false, err
+	}
+	c, err := apiClient()
+	if err != nil {
+		return false, err
+	}
+	records, err := c.ListDNSRecords(rootCtx, z.ID, cfapi.DNSRecordFilter{Type: typeName, Name: name}, 0)
+	if err != nil {
+		return false, err
+	}
+
+	if len(records) == 0 {
+		r := dnsRecord{Type: typeName, Name: name, Content: ip.String(), TTL: opts.TTL}
+		if opts.Proxied != nil {
+			r.Proxied = *opts.Proxied
+		}
+		if _, err := createDNSRecord(z.ID, r); err != nil {
+			return false, err
+		}
+		ddnsLogf(logNotice, "created %s %s -> %s", typeName, name, ip)
+		return true, nil
+	}
+
+	changed := false
+	for _, r := range records {
+		if net.ParseIP(r.Content).Equal(ip) && (opts.Proxied == nil || r.Proxied == *opts.Proxied) {
+			continue
+		}
+		before := r
+		r.Content = ip.String()
+		if opts.Proxied != nil {
+			r.Proxied = *opts.Proxied
+		}
+		if _, err := updateDNSRecord(z.ID, before, r); err != nil {
+			return changed, err
+		}
+		ddnsLogf(logNotice, "updated %s %s: %s -> %s", typeName, name, before.Content, ip)
+		changed = true
+	}
+	if !changed {
+		ddnsLogf(logInfo, "%s %s already points at %s", typeName, name, ip)
+	}
+	return changed, nil
+}
+
+// Syslog priorities, as systemd's journal reads them from a "<N>" prefix.
+const (
+	logErr    = 3
+	logNotice = 5
+	logInfo   = 6
+)
+
+// ddnsLogf writes one log line. Under systemd (JOURNAL_STREAM is set) it
+// prefixes the priority and leaves timestamps to the journal; otherwise it
+// stamps the line itself. Errors go to stderr either way.
+func ddnsLogf(priority int, format string, args ...any) {
+	w := os.Stdout
+	if priority <= logErr || machineOutput() {
+		w = os.Stderr
+	}
+	line := fmt.Sprintf(format, args...)
+	if os.Getenv("JOURNAL_STREAM") != "" {
+		fmt.Fprintf(w, "<%d>%s\n", priority, line)
+		return
+	}
+	fmt.Fprintf(w, "%s %s\n", time.Now().Format(time.DateTime), line)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePublicIP(t *testing.T) {
+	trace := "fl=123\nh=1.1.1.1\nip=203.0.113.7\nts=1700000000.1\ncolo=LHR\n"
+	if ip, err := parsePublicIP(trace, false); err != nil || ip.String() != "203.0.113.7" {
+		t.Fatalf("unexpected trace result %v (err=%v)", ip, err)
+	}
+	if ip, err := parsePublicIP("2001:db8::5\n", true); err != nil || ip.String() != "2001:db8::5" {
+		t.Fatalf("unexpected bare result %v (err=%v)", ip, err)
+	}
+	if _, err := parsePublicIP("203.0.113.7", true); err == nil {
+		t.Fatal("expected an IPv4 answer to be rejected with --ipv6")
+	}
+	if _, err := parsePublicIP("<html>", false); err == nil {
+		t.Fatal("expected a non-address answer to be rejected")
+	}
+}
+
+func TestDDNS(t *testing.T) {
+	srv := useFakeAPI(t)
+	journal := filepath.Join(t.TempDir(), "journal.jsonl")
+	t.Setenv("CF_JOURNAL", journal)
+	var records []dnsRecord
+	srv.Reply("GET", "/zones/z1/dns_records?name=home.example.com&type=A", &records)
+	srv.Reply("POST", "/zones/z1/dns_records", dnsRecord{ID: "r1"})
+	srv.Reply("PUT", "/zones/z1/dns_records/r1", dnsRecord{ID: "r1"})
+
+	orig := detectPublicIP
+	t.Cleanup(func() { detectPublicIP = orig })
+	detectPublicIP = func(_ context.Context, source string, ipv6 bool) (net.IP, error) {
+		if source != "" || ipv6 {
+			t.Errorf("unexpected lookup source=%q ipv6=%t", source, ipv6)
+		}
+		return net.ParseIP("203.0.113.7"), nil
+	}
+
+	args := []string{"--zone", "example.com", "--name", "home"}
+	if _, err := captureStdout(t, func() error { return runDDNS(args) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var created dnsRecord
+	if calls := srv.Calls("POST", "/zones/z1/dns_records"); len(calls) != 1 {
+		t.Fatalf("expected the record to be created, got %d creates", len(calls))
+	} else if err := calls[0].Decode(&created); err != nil || created.Content != "203.0.113.7" || created.Type != "A" {
+		t.Fatalf("unexpected record %+v (err=%v)", created, err)
+	}
+
+	records = []dnsRecord{{ID: "r1", Type: "A", Name: "home.example.com", Content: "203.0.113.7", TTL: 1}}
+	if _, err := captureStdout(t, func() error { return runDDNS(args) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(srv.Calls("PUT", "/zones/z1/dns_records/r1")); n != 0 {
+		t.Fatalf("expected no update when the address is unchanged, got %d", n)
+	}
+
+	records[0].Content = "198.51.100.1"
+	if _, err := captureStdout(t, func() error { return runDDNS(args) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var updated dnsRecord
+	if calls := srv.Calls("PUT", "/zones/z1/dns_records/r1"); len(calls) != 1 {
+		t.Fatalf("expected one update, got %d", len(calls))
+	} else if err := calls[0].Decode(&updated); err != nil || updated.Content != "203.0.113.7" {
+		t.Fatalf("unexpected update %+v (err=%v)", updated, err)
+	}
+
+	if _, err := os.Stat(journal); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected ddns to stay out of the undo journal, got %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+)
+
+// verbose is set by the global -v/--verbose flag; CF_DEBUG=1 does the same.
+var verbose bool
+
+// debugLogger returns the logger for HTTP traffic, or nil when verbose
+// logging is off.
+func debugLogger() *slog.Logger {
+	if !verbose && !parseBoolWithDefault(os.Getenv("CF_DEBUG"), false) {
+		return nil
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
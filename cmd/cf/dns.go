@@ -0,0 +1,123 @@
+package main
+
+import "strings"
+
+func runDNS(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf dns list|find|add|update|delete|set-proxied|sync|backup|restore|check|email-setup|preset|dnssec|analytics. run: cf dns --help")
+	}
+	switch args[0] {
+	case "add":
+		flags := parseFlags(args[1:])
+		if path := flags["from-file"]; path != "" {
+			concurrency, err := parseIntWithDefault(flags["concurrency"], defaultBulkConcurrency)
+			if err != nil {
+				return usageErrorf("invalid --concurrency: %w", err)
+			}
+			vars, err := templateVars(args[1:])
+			if err != nil {
+				return err
+			}
+			return addDNSRecordsFromFile(path, flags["zone"], concurrency, vars)
+		}
+		fanOut := fanOutRequested(flags)
+		zoneName := flags["zone"]
+		if !fanOut {
+			zoneName = zoneOrDefault(zoneName)
+		}
+		typeName := strings.ToUpper(flags["type"])
+		name := flags["name"]
+		content := flags["content"]
+		ttl, err := parseIntWithDefault(flags["ttl"], 1)
+		if err != nil {
+			return usageErrorf("invalid --ttl: %w", err)
+		}
+		proxied := parseBoolWithDefault(flags["proxied"], false)
+		data, err := dnsRecordData(typeName, flags)
+		if err != nil {
+			return err
+		}
+
+		if (zoneName == "" && !fanOut) || typeName == "" || name == "" || (content == "" && data == nil) {
+			return usageErrorf("missing required flags for dns add: --zone --type --name --content (or --data for SRV/CAA/TLSA/SSHFP)")
+		}
+
+		record := dnsRecord{Type: typeName, Name: name, Content: content, TTL: ttl, Proxied: proxied, Data: data, Comment: flags["comment"], Tags: splitList(flags["tags"])}
+		if fanOut {
+			return addDNSRecordToZones(flags, record)
+		}
+		r, err := addDNSRecord(zoneName, record)
+		if err != nil {
+			return err
+		}
+		return printResult(r, func() {})
+	case "update":
+		flags := parseFlags(args[1:])
+		zoneName := zoneOrDefault(flags["zone"])
+		typeName := strings.ToUpper(flags["type"])
+		if zoneName == "" || (flags["id"] == "" && (typeName == "" || flags["name"] == "")) {
+			return usageErrorf("missing required flags for dns update: --zone and --id, or --zone --type --name")
+		}
+		r, err := updateDNSRecordByName(zoneName, flags["id"], typeName, flags["name"], flags)
+		if err != nil || r == nil {
+			return err
+		}
+		return printResult(r, func() {})
+	case "delete":
+		flags := parseFlags(args[1:])
+		zoneName := zoneOrDefault(flags["zone"])
+		typeName := strings.ToUpper(flags["type"])
+		if zoneName == "" || (flags["id"] == "" && (typeName == "" || flags["name"] == "")) {
+			return usageErrorf("missing required flags for dns delete: --zone and --id, or --zone --type --name")
+		}
+		r, err := deleteDNSRecordByName(zoneName, flags["id"], typeName, flags["name"], flags)
+		if err != nil || r == nil {
+			return err
+		}
+		return printResult(r, func() {})
+	case "list":
+		flags := parseFlags(args[1:])
+		flags["zone"] = zoneOrDefault(flags["zone"])
+		if flags["zone"] == "" {
+			return usageErrorf("missing required flag for dns list: --zone")
+		}
+		limit, err := parseIntWithDefault(flags["limit"], 0)
+		if err != nil {
+			return usageErrorf("invalid --limit: %w", err)
+		}
+		return listDNSRecordsForZone(flags["zone"], strings.ToUpper(flags["type"]), flags["name"], flags["tag"], limit)
+	case "email-setup":
+		return runEmailSetup(parseFlags(args[1:]))
+	case "check":
+		return runDNSCheck(parseFlags(args[1:]))
+	case "backup":
+		return runDNSBackup(parseFlags(args[1:]))
+	case "restore":
+		return runDNSRestore(parseFlags(args[1:]))
+	case "find":
+		return runDNSFind(parseFlags(args[1:]))
+	case "set-proxied":
+		return runDNSSetProxied(parseFlags(args[1:]))
+	case "preset":
+		return runDNSPreset(args[1:])
+	case "dnssec":
+		return runDNSSEC(args[1:])
+	case "analytics":
+		return runDNSAnalytics(args[1:])
+	case "sync":
+		flags := parseFlags(args[1:])
+		if flags["file"] == "" {
+			return usageErrorf("missing required flag for dns sync: --file")
+		}
+		concurrency, err := parseIntWithDefault(flags["concurrency"], defaultBulkConcurrency)
+		if err != nil {
+			return usageErrorf("invalid --concurrency: %w", err)
+		}
+		vars, err := templateVars(args[1:])
+		if err != nil {
+			return err
+		}
+		return syncDNSRecords(flags, flags["file"], zoneOrDefault(flags["zone"]), vars, dryRun, parseBoolWithDefault(flags["prune"], false), concurrency)
+	}
+	return usageErrorf("unknown dns command %q. run: cf dns --help", args[0])
+}
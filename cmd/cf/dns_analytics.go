@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dnsAnalyticsDimensions maps --by values to DNS analytics report
+// dimensions, in the order they are shown.
+var dnsAnalyticsDimensions = []struct{ Flag, Dimension string }{
+	{"name", "queryName"},
+	{"type", "queryType"},
+	{"rcode", "responseCode"},
+}
+
+type dnsAnalyticsReport struct {
+	Totals struct {
+		QueryCount int64 `json:"queryCount"`
+	} `json:"totals"`
+	Data []struct {
+		Dimensions []string  `json:"dimensions"`
+		Metrics    []float64 `json:"metrics"`
+	} `json:"data"`
+}
+
+type dnsAnalyticsRow struct {
+	By      string `json:"by"`
+	Value   string `json:"value"`
+	Queries int64  `json:"queries"`
+}
+
+type dnsAnalyticsSummary struct {
+	Zone    string            `json:"zone"`
+	Since   string            `json:"since"`
+	Until   string            `json:"until"`
+	Queries int64             `json:"queries"`
+	Rows    []dnsAnalyticsRow `json:"rows"`
+}
+
+func runDNSAnalytics(args []string) error {
+	flags := parseFlags(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for dns analytics: --zone")
+	}
+	since, err := parseDurationWithDefault(flags["since"], 6*time.Hour)
+	if err != nil || since <= 0 {
+		return usageErrorf("invalid --since %q (example: 1h, 6h, 24h)", flags["since"])
+	}
+	limit, err := parseIntWithDefault(flags["limit"], 10)
+	if err != nil {
+		return usageErrorf("invalid --limit: %w", err)
+	}
+	dimensions := dnsAnalyticsDimensions
+	if by := strings.ToLower(flags["by"]); by != "" {
+		dimensions = nil
+		for _, d := range dnsAnalyticsDimensions {
+			if d.Flag == by {
+				dimensions = append(dimensions, d)
+			}
+		}
+		if dimensions == nil {
+			return usageErrorf("invalid --by %q (want name, type or rcode)", by)
+		}
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	until := time.Now().UTC().Truncate(time.Minute)
+	s := dnsAnalyticsSummary{
+		Zone:  z.Name,
+		Since: until.Add(-since).Format(time.RFC3339),
+		Until: until.Format(time.RFC3339),
+	}
+	for _, d := range dimensions {
+		q := url.Values{}
+		q.Set("dimensions", d.Dimension)
+		q.Set("metrics", "queryCount")
+		q.Set("sort", "-queryCount")
+		q.Set("limit", strconv.Itoa(limit))
+		q.Set("since", s.Since)
+		q.Set("until", s.Until)
+		resp, err := requestCF(http.MethodGet, "/zones/"+z.ID+"/dns_analytics/report?"+q.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		var report dnsAnalyticsReport
+		if err := json.Unmarshal(resp.Result, &report); err != nil {
+			return err
+		}
+		s.Queries = report.Totals.QueryCount
+		s.Rows = append(s.Rows, dnsAnalyticsRows(d.Flag, report)...)
+	}
+
+	t := table{Headers: []string{"BY", "VALUE", "QUERIES"}}
+	for _, r := range s.Rows {
+		t.Rows = append(t.Rows, []string{r.By, r.Value, strconv.FormatInt(r.Queries, 10)})
+	}
+	return printList(s, t, func() {
+		fmt.Printf("DNS queries for %s over the last %s: %d\n", z.Name, since, s.Queries)
+		by := ""
+		for _, r := range s.Rows {
+			if r.By != by {
+				by = r.By
+				fmt.Printf("By %s:\n", by)
+			}
+			fmt.Printf("  %-40s %10d\n", r.Value, r.Queries)
+		}
+	})
+}
+
+func dnsAnalyticsRows(by string, report dnsAnalyticsReport) []dnsAnalyticsRow {
+	rows := make([]dnsAnalyticsRow, 0, len(report.Data))
+	for _, d := range report.Data {
+		row := dnsAnalyticsRow{By: by}
+		if len(d.Dimensions) > 0 {
+			row.Value = d.Dimensions[0]
+		}
+		if len(d.Metrics) > 0 {
+			row.Queries = int64(d.Metrics[0])
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
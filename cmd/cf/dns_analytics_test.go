@@ -0,0 +1,21 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDNSAnalyticsRows(t *testing.T) {
+	var report dnsAnalyticsReport
+	raw := `{"totals":{"queryCount":1500},"data":[{"dimensions":["NXDOMAIN"],"metrics":[1200]},{"dimensions":["NOERROR"],"metrics":[300]}]}`
+	if err := json.Unmarshal([]byte(raw), &report); err != nil {
+		t.Fatal(err)
+	}
+	rows := dnsAnalyticsRows("rcode", report)
+	if len(rows) != 2 || rows[0] != (dnsAnalyticsRow{By: "rcode", Value: "NXDOMAIN", Queries: 1200}) {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+	if report.Totals.QueryCount != 1500 {
+		t.Fatalf("unexpected total: %d", report.Totals.QueryCount)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// dnsBackup is the file cf dns backup writes: every record in the zone as
+// the API returns it, including IDs, comments, tags and structured data.
+type dnsBackup struct {
+	Zone      string      `json:"zone"`
+	ZoneID    string      `json:"zone_id"`
+	CreatedAt time.Time   `json:"created_at"`
+	Records   []dnsRecord `json:"records"`
+}
+
+// dnsRestoreResult is a sync result plus the conflicting records that were
+// left alone.
+type dnsRestoreResult struct {
+	dnsSyncResult
+	Skipped []dnsChange `json:"skipped"`
+}
+
+func runDNSBackup(flags map[string]string) error {
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for dns backup: --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	records, err := listDNSRecords(z.ID)
+	if err != nil {
+		return err
+	}
+	backup := dnsBackup{Zone: z.Name, ZoneID: z.ID, CreatedAt: time.Now().UTC().Truncate(time.Second), Records: records}
+
+	file := flags["out"]
+	if file == "" || file == "-" {
+		return writeJSON(backup)
+	}
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	infof("Backed up %d DNS records from %s to %s\n", len(records), z.Name, file)
+	return nil
+}
+
+func runDNSRestore(flags map[string]string) error {
+	if flags["file"] == "" {
+		return usageErrorf("missing required flag for dns restore: --file")
+	}
+	onConflict := flags["on-conflict"]
+	if onConflict == "" {
+		onConflict = "skip"
+	}
+	if onConflict != "skip" && onConflict != "overwrite" {
+		return usageErrorf("invalid --on-conflict %q: want skip or overwrite", onConflict)
+	}
+	concurrency, err := parseIntWithDefault(flags["concurrency"], defaultBulkConcurrency)
+	if err != nil {
+		return usageErrorf("invalid --concurrency: %w", err)
+	}
+	if err := requireConfirmable(flags, "dns restore"); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(flags["file"])
+	if err != nil {
+		return err
+	}
+	var backup dnsBackup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return fmt.Errorf("could not parse %s: %w", flags["file"], err)
+	}
+	zoneName := backup.Zone
+	if flags["zone"] != "" {
+		zoneName = flags["zone"]
+	}
+	if zoneName = zoneOrDefault(zoneName); zoneName == "" {
+		return usageErrorf("zone is required: the backup names none, so pass --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	live, err := listDNSRecords(z.ID)
+	if err != nil {
+		return err
+	}
+
+	changes, conflicts := planDNSRestore(rezoneRecords(backup.Records, backup.Zone, z.Name), live, onConflict == "overwrite")
+	result := dnsRestoreResult{dnsSyncResult{Zone: z.Name, Changes: changes, Unmanaged: []dnsRecord{}}, conflicts}
+	if result.Skipped == nil {
+		result.Skipped = []dnsChange{}
+	}
+	if !machineOutput() {
+		printDNSSyncPlan(z.Name, changes, nil)
+		if len(conflicts) > 0 {
+			fmt.Printf("Skipping %d record(s) that differ from the live zone (pass --on-conflict overwrite to restore them):\n", len(conflicts))
+			for _, c := range conflicts {
+				fmt.Printf("  %s %s: live %s, backup %s\n", c.After.Type, c.After.Name, c.Before.Content, c.After.Content)
+			}
+		}
+	}
+	if len(changes) == 0 || dryRun {
+		return printResult(result, func() {})
+	}
+
+	ok, err := confirm(flags, "dns restore", "Restore these records?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted. No changes applied.")
+		return nil
+	}
+	if err := applyDNSSync(z.ID, changes, concurrency); err != nil {
+		return err
+	}
+	result.Applied = true
+	return printResult(result, func() {})
+}
+
+// planDNSRestore recreates backed-up records missing from live. A live
+// record with the same type and name that differs from the backup is a
+// conflict: it is updated to match the backup with overwrite, and otherwise
+// returned for the caller to report. Live records absent from the backup are
+// never touched.
+func planDNSRestore(backup, live []dnsRecord, overwrite bool) ([]dnsChange, []dnsChange) {
+	desired := make([]dnsRecord, len(backup))
+	for i, r := range backup {
+		r.ID = ""
+		desired[i] = r
+	}
+	planned, _ := planDNSSync(desired, live, false)
+	var changes, conflicts []dnsChange
+	for _, c := range planned {
+		if c.Action == "update" && !overwrite {
+			conflicts = append(conflicts, c)
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes, conflicts
+}
+
+// rezoneRecords moves record names from one zone to another, so a backup can
+// be restored into a different zone.
+func rezoneRecords(records []dnsRecord, from, to string) []dnsRecord {
+	from, to = strings.ToLower(from), strings.ToLower(to)
+	if from == "" || from == to {
+		return records
+	}
+	out := make([]dnsRecord, len(records))
+	for i, r := range records {
+		name := strings.ToLower(r.Name)
+		switch {
+		case name == from:
+			r.Name = to
+		case strings.HasSuffix(name, "."+from):
+			r.Name = strings.TrimSuffix(name, from) + to
+		}
+		out[i] = r
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDNSBackupAndRestore(t *testing.T) {
+	srv := useFakeAPI(t)
+	prio := 10
+	saved := []dnsRecord{
+		{ID: "r1", Type: "A", Name: "example.com", Content: "192.0.2.1", TTL: 1, Proxied: true, Comment: "origin", Tags: []string{"team:web"}},
+		{ID: "r2", Type: "MX", Name: "example.com", Content: "mx.example.net", TTL: 3600, Priority: &prio},
+		{ID: "r3", Type: "CNAME", Name: "www.example.com", Content: "example.com", TTL: 1},
+	}
+	srv.Reply("GET", "/zones/z1/dns_records", saved)
+	path := filepath.Join(t.TempDir(), "backup.json")
+	if _, err := captureStdout(t, func() error { return runDNS([]string{"backup", "--zone", "example.com", "--out", path}) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var backup dnsBackup
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &backup); err != nil || backup.Zone != "example.com" || len(backup.Records) != 3 || backup.Records[0].Comment != "origin" {
+		t.Fatalf("unexpected backup %+v (err=%v)", backup, err)
+	}
+
+	// The A record survived with new content, the MX is gone and www is
+	// untouched.
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{
+		{ID: "n1", Type: "A", Name: "example.com", Content: "198.51.100.1", TTL: 1},
+		saved[2],
+	})
+	srv.Reply("POST", "/zones/z1/dns_records", dnsRecord{ID: "n2"})
+	srv.Reply("PUT", "/zones/z1/dns_records/n1", dnsRecord{ID: "n1"})
+
+	out, err := captureStdout(t, func() error { return runDNS([]string{"restore", "--file", path, "--yes"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Skipping 1 record(s)") || !strings.Contains(out, "live 198.51.100.1, backup 192.0.2.1") {
+		t.Fatalf("expected the changed A record to be reported as a conflict, got:\n%s", out)
+	}
+	var created dnsRecord
+	if calls := srv.Calls("POST", "/zones/z1/dns_records"); len(calls) != 1 {
+		t.Fatalf("expected only the MX record to be recreated, got %d creates", len(calls))
+	} else if err := calls[0].Decode(&created); err != nil || created.Type != "MX" || created.Priority == nil || *created.Priority != 10 {
+		t.Fatalf("unexpected create %+v (err=%v)", created, err)
+	}
+	if n := len(srv.Calls("PUT", "/zones/z1/dns_records/n1")); n != 0 {
+		t.Fatalf("expected no overwrite by default, got %d updates", n)
+	}
+
+	if _, err := captureStdout(t, func() error {
+		return runDNS([]string{"restore", "--file", path, "--on-conflict", "overwrite", "--yes"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var updated dnsRecord
+	if calls := srv.Calls("PUT", "/zones/z1/dns_records/n1"); len(calls) != 1 {
+		t.Fatalf("expected the A record to be overwritten, got %d updates", len(calls))
+	} else if err := calls[0].Decode(&updated); err != nil || updated.Content != "192.0.2.1" || !updated.Proxied || updated.Comment != "origin" {
+		t.Fatalf("unexpected update %+v (err=%v)", updated, err)
+	}
+
+	// Machine-readable output carries no plan or conflict lines.
+	outputFormat = outputCSV
+	t.Cleanup(func() { outputFormat = outputPlain })
+	out, err = captureStdout(t, func() error { return runDNS([]string{"restore", "--file", path, "--yes"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out, "Skipping") || strings.Contains(out, "to create") {
+		t.Fatalf("expected only CSV on stdout, got:\n%s", out)
+	}
+}
+
+func TestRezoneRecords(t *testing.T) {
+	got := rezoneRecords([]dnsRecord{{Name: "example.com"}, {Name: "www.example.com"}, {Name: "other.net"}}, "example.com", "example.org")
+	if got[0].Name != "example.org" || got[1].Name != "www.example.org" || got[2].Name != "other.net" {
+		t.Fatalf("unexpected names: %+v", got)
+	}
+}
+
+func TestDNSBackupRestoresStructuredData(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{
+		{ID: "r1", Type: "SRV", Name: "_sip._tcp.example.com", Content: "5 5060 sip.example.com", TTL: 1,
+			Data: map[string]any{"priority": 10, "weight": 5, "port": 5060, "target": "sip.example.com"}},
+		{ID: "r2", Type: "CAA", Name: "example.com", Content: `0 issue "letsencrypt.org"`, TTL: 1,
+			Data: map[string]any{"flags": 0, "tag": "issue", "value": "letsencrypt.org"}},
+	})
+	path := filepath.Join(t.TempDir(), "backup.json")
+	if _, err := captureStdout(t, func() error { return runDNS([]string{"backup", "--zone", "example.com", "--out", path}) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{})
+	srv.Reply("POST", "/zones/z1/dns_records", dnsRecord{ID: "n1"})
+	if _, err := captureStdout(t, func() error { return runDNS([]string{"restore", "--file", path, "--yes"}) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	calls := srv.Calls("POST", "/zones/z1/dns_records")
+	if len(calls) != 2 {
+		t.Fatalf("expected both records to be recreated, got %d creates", len(calls))
+	}
+	var body map[string]any
+	if err := calls[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := body["data"].(map[string]any)
+	if data["port"] != 5060.0 || data["target"] != "sip.example.com" {
+		t.Fatalf("expected the SRV data to be restored, got %v", body)
+	}
+	if _, ok := body["content"]; ok {
+		t.Fatalf("expected content to be left for the API to derive, got %v", body)
+	}
+}
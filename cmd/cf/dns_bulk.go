@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type dnsBulkResult struct {
+	Type    string     `json:"type"`
+	Name    string     `json:"name"`
+	Content string     `json:"content"`
+	Record  *dnsRecord `json:"record,omitempty"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// addDNSRecordsFromFile creates every record in path concurrently, with at
+// most concurrency requests in flight. It returns an error if any record
+// failed, after printing the per-record summary.
+func addDNSRecordsFromFile(path, zoneOverride string, concurrency int, vars map[string]string) error {
+	spec, err := readDNSBulkFile(path, vars)
+	if err != nil {
+		return err
+	}
+
+	zoneName := spec.Zone
+	if zoneOverride != "" {
+		zoneName = zoneOverride
+	}
+	zoneName = zoneOrDefault(zoneName)
+	if zoneName == "" {
+		return usageErrorf("zone is required: set `zone` in the file or pass --zone")
+	}
+	if len(spec.Records) == 0 {
+		return fmt.Errorf("no records found in %s", path)
+	}
+
+	records, err := desiredDNSRecords(zoneName, spec.Records)
+	if err != nil {
+		return err
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	results := make([]dnsBulkResult, len(records))
+	errs := runBatch("Creating records", len(records), concurrency, func(i int) error {
+		created, err := createDNSRecord(z.ID, records[i])
+		results[i].Record = created
+		return err
+	})
+	for i, r := range records {
+		results[i].Type, results[i].Name, results[i].Content = r.Type, r.Name, r.Content
+		if errs[i] != nil {
+			results[i].Error = errs[i].Error()
+		}
+	}
+	failed := countFailed(errs)
+
+	t := table{Headers: []string{"STATUS", "TYPE", "NAME", "CONTENT", "ID", "ERROR"}}
+	for _, res := range results {
+		status, id := "ok", ""
+		if res.Error != "" {
+			status = "failed"
+		} else {
+			id = res.Record.ID
+		}
+		t.Rows = append(t.Rows, []string{status, res.Type, res.Name, res.Content, id, res.Error})
+	}
+	if err := printList(results, t, func() {
+		for _, res := range results {
+			if res.Error != "" {
+				fmt.Printf("FAIL %s %s -> %s: %s\n", res.Type, res.Name, res.Content, res.Error)
+			} else {
+				fmt.Printf("ok   %s %s -> %s (id=%s)\n", res.Type, res.Name, res.Content, res.Record.ID)
+			}
+		}
+		fmt.Printf("%d created, %d failed\n", len(results)-failed, failed)
+	}); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d records failed", failed, len(results))
+	}
+	return nil
+}
+
+// readDNSBulkFile reads records from a .csv file with a header row, or from a
+// JSON file holding either a list of records or a dns sync style object.
+func readDNSBulkFile(path string, vars map[string]string) (*dnsSyncFile, error) {
+	data, err := readTemplateFile(path, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		records, err := parseDNSRecordsCSV(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", path, err)
+		}
+		return &dnsSyncFile{Records: records}, nil
+	}
+
+	var spec dnsSyncFile
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &spec.Records)
+	} else {
+		err = json.Unmarshal(trimmed, &spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// parseDNSRecordsCSV reads rows keyed by a header of type, name, content and
+// optional ttl, proxied, priority, comment and tags (comma-separated) columns.
+func parseDNSRecordsCSV(r io.Reader) ([]dnsSyncRecord, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	col := map[string]int{}
+	for i, h := range rows[0] {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, required := range []string{"type", "name", "content"} {
+		if _, ok := col[required]; !ok {
+			return nil, fmt.Errorf("missing %q column in header", required)
+		}
+	}
+
+	get := func(row []string, name string) string {
+		i, ok := col[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var out []dnsSyncRecord
+	for n, row := range rows[1:] {
+		line := n + 2
+		rec := dnsSyncRecord{
+			Type:    get(row, "type"),
+			Name:    get(row, "name"),
+			Content: get(row, "content"),
+			Comment: get(row, "comment"),
+			Tags:    splitList(get(row, "tags")),
+		}
+		if v := get(row, "ttl"); v != "" {
+			ttl, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid ttl %q", line, v)
+			}
+			rec.TTL = &ttl
+		}
+		if v := get(row, "proxied"); v != "" {
+			proxied, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid proxied %q", line, v)
+			}
+			rec.Proxied = &proxied
+		}
+		if v := get(row, "priority"); v != "" {
+			priority, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid priority %q", line, v)
+			}
+			rec.Priority = &priority
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
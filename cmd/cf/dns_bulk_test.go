@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cf/internal/cftest"
+)
+
+func TestDNSAddFromFile(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Handle("POST", "/zones/z1/dns_records", func(r cftest.Request) cftest.Response {
+		var rec dnsRecord
+		r.Decode(&rec)
+		if rec.Name == "bad.example.com" {
+			return cftest.Response{Status: 400, Errors: []cftest.Error{{Code: 9005, Message: "Content for A record is invalid"}}}
+		}
+		rec.ID = "id-" + rec.Type
+		return cftest.Response{Result: rec}
+	})
+
+	path := filepath.Join(t.TempDir(), "records.csv")
+	csv := "type,name,content,ttl,proxied\nA,www,192.0.2.1,300,true\nA,bad,192.0.2.2,,\n"
+	if err := os.WriteFile(path, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := captureStdout(t, func() error {
+		return runDNS([]string{"add", "--from-file", path, "--zone", "example.com", "--concurrency", "1"})
+	})
+	if err == nil || err.Error() != "1 of 2 records failed" {
+		t.Fatalf("expected one failure, got %v", err)
+	}
+	posts := srv.Calls("POST", "/zones/z1/dns_records")
+	if len(posts) != 2 {
+		t.Fatalf("expected two records created, got %d", len(posts))
+	}
+	var body map[string]any
+	if err := posts[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["type"] != "A" || body["name"] != "www.example.com" || body["content"] != "192.0.2.1" || body["ttl"] != 300.0 || body["proxied"] != true {
+		t.Fatalf("unexpected body %v", body)
+	}
+	for _, want := range []string{
+		"ok   A www.example.com -> 192.0.2.1 (id=id-A)",
+		"FAIL A bad.example.com -> 192.0.2.2: ",
+		"Content for A record is invalid",
+		"1 created, 1 failed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestDNSAddFromFileRequiresZone(t *testing.T) {
+	srv := useFakeAPI(t)
+	path := filepath.Join(t.TempDir(), "records.json")
+	if err := os.WriteFile(path, []byte(`[{"type":"A","name":"www","content":"192.0.2.1"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := runDNS([]string{"add", "--from-file", path})
+	if exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error, got %v", err)
+	}
+	if len(srv.Requests()) != 0 {
+		t.Fatalf("expected no API calls, got %v", srv.Requests())
+	}
+}
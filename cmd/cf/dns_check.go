@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"cf/pkg/cfapi"
+)
+
+// publicResolvers is the default set cf dns check asks: the big anycast
+// services plus a few regional ones that often cache longer.
+var publicResolvers = []publicResolver{
+	{"1.1.1.1", "Cloudflare"},
+	{"8.8.8.8", "Google"},
+	{"9.9.9.9", "Quad9"},
+	{"208.67.222.222", "OpenDNS"},
+	{"94.140.14.14", "AdGuard (EU)"},
+	{"77.88.8.8", "Yandex (RU)"},
+	{"114.114.114.114", "114DNS (CN)"},
+}
+
+type publicResolver struct {
+	Addr string
+	Name string
+}
+
+// resolverCheck is one resolver's view of a record set. Status is "ok"
+// (every expected value answered), "stale" (answers that do not match yet),
+// "missing" (no answer) or "error".
+type resolverCheck struct {
+	Resolver string   `json:"resolver"`
+	Name     string   `json:"name"`
+	Status   string   `json:"status"`
+	Answers  []string `json:"answers"`
+	Error    string   `json:"error,omitempty"`
+}
+
+func (c resolverCheck) label() string {
+	if c.Name == "" {
+		return c.Resolver
+	}
+	return c.Resolver + " (" + c.Name + ")"
+}
+
+type dnsCheckResult struct {
+	Record   string          `json:"record"`
+	Type     string          `json:"type"`
+	Expected []string        `json:"expected"`
+	Proxied  bool            `json:"proxied"`
+	Checks   []resolverCheck `json:"resolvers"`
+}
+
+// lookupVia asks the resolver at server for host's records of typeName. It
+// is a variable so tests can avoid the network.
+var lookupVia = func(ctx context.Context, server, typeName, host string) ([]string, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	r := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+	var out []string
+	switch typeName {
+	case "A", "AAAA":
+		network := "ip4"
+		if typeName == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := r.LookupIP(ctx, network, host)
+		for _, ip := range ips {
+			out = append(out, ip.String())
+		}
+		return out, err
+	case "CNAME":
+		cname, err := r.LookupCNAME(ctx, host)
+		if cname = strings.ToLower(strings.TrimSuffix(cname, ".")); cname != "" && cname != strings.ToLower(host) {
+			out = append(out, cname)
+		}
+		return out, err
+	case "MX":
+		mxs, err := r.LookupMX(ctx, host)
+		for _, mx := range mxs {
+			out = append(out, strings.ToLower(strings.TrimSuffix(mx.Host, ".")))
+		}
+		return out, err
+	case "NS":
+		nss, err := r.LookupNS(ctx, host)
+		for _, ns := range nss {
+			out = append(out, strings.ToLower(strings.TrimSuffix(ns.Host, ".")))
+		}
+		return out, err
+	case "TXT":
+		return r.LookupTXT(ctx, host)
+	}
+	return nil, fmt.Errorf("cannot look up %s records", typeName)
+}
+
+func runDNSCheck(flags map[string]string) error {
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" || flags["name"] == "" {
+		return usageErrorf("missing required flags for dns check: --zone --name")
+	}
+	typeName := strings.ToUpper(flags["type"])
+	if typeName == "" {
+		typeName = "A"
+	}
+	if !slices.Contains([]string{"A", "AAAA", "CNAME", "MX", "NS", "TXT"}, typeName) {
+		return usageErrorf("dns check supports A, AAAA, CNAME, MX, NS and TXT records, not %s", typeName)
+	}
+	resolvers := publicResolvers
+	if v := flags["resolvers"]; v != "" {
+		resolvers = nil
+		for _, addr := range splitList(v) {
+			resolvers = append(resolvers, publicResolver{Addr: addr})
+		}
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	name := qualifyRecordName(flags["name"], z.Name)
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	records, err := c.ListDNSRecords(rootCtx, z.ID, cfapi.DNSRecordFilter{Type: typeName, Name: name}, 0)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return notFoundErrorf("no %s record named %s in %s. run: cf dns list --zone %s", typeName, name, z.Name, z.Name)
+	}
+
+	result := checkPropagation(name, typeName, records, resolvers)
+	t := table{Headers: []string{"RESOLVER", "STATUS", "ANSWERS"}}
+	for _, c := range result.Checks {
+		t.Rows = append(t.Rows, []string{c.label(), c.Status, strings.Join(c.Answers, " ")})
+	}
+	if err := printList(result, t, func() { printDNSCheck(result) }); err != nil {
+		return err
+	}
+	ok := 0
+	for _, c := range result.Checks {
+		if c.Status == "ok" {
+			ok++
+		}
+	}
+	if ok < len(result.Checks) {
+		return fmt.Errorf("%s %s has propagated to %d of %d resolvers", typeName, name, ok, len(result.Checks))
+	}
+	return nil
+}
+
+// checkPropagation queries every resolver in parallel. Proxied records
+// answer with Cloudflare's addresses rather than the stored content, so for
+// those any answer other than the origin counts.
+func checkPropagation(name, typeName string, records []dnsRecord, resolvers []publicResolver) dnsCheckResult {
+	result := dnsCheckResult{Record: name, Type: typeName, Checks: make([]resolverCheck, len(resolvers))}
+	for _, r := range records {
+		result.Expected = append(result.Expected, strings.ToLower(strings.TrimSuffix(unquoteTXT(r.Content), ".")))
+		result.Proxied = result.Proxied || r.Proxied
+	}
+
+	queryType := typeName
+	if result.Proxied && typeName == "CNAME" {
+		queryType = "A"
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, 10*time.Second)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i, res := range resolvers {
+		wg.Add(1)
+		go func(i int, res publicResolver) {
+			defer wg.Done()
+			check := resolverCheck{Resolver: res.Addr, Name: res.Name, Answers: []string{}}
+			answers, err := lookupVia(ctx, res.Addr, queryType, name)
+			for _, a := range answers {
+				check.Answers = append(check.Answers, strings.ToLower(strings.TrimSuffix(unquoteTXT(a), ".")))
+			}
+			check.Status = propagationStatus(result.Expected, check.Answers, result.Proxied)
+			if err != nil && len(answers) == 0 {
+				var dnsErr *net.DNSError
+				if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+					check.Status, check.Error = "error", err.Error()
+				}
+			}
+			result.Checks[i] = check
+		}(i, res)
+	}
+	wg.Wait()
+	return result
+}
+
+func propagationStatus(expected, answers []string, proxied bool) string {
+	if len(answers) == 0 {
+		return "missing"
+	}
+	if proxied {
+		for _, a := range answers {
+			if slices.Contains(expected, a) {
+				return "stale"
+			}
+		}
+		return "ok"
+	}
+	for _, e := range expected {
+		if !slices.Contains(answers, e) {
+			return "stale"
+		}
+	}
+	return "ok"
+}
+
+func printDNSCheck(r dnsCheckResult) {
+	fmt.Printf("%s %s in Cloudflare: %s", r.Type, r.Record, strings.Join(r.Expected, ", "))
+	if r.Proxied {
+		fmt.Print(" (proxied: resolvers should answer with Cloudflare addresses)")
+	}
+	fmt.Println()
+	ok := 0
+	for _, c := range r.Checks {
+		if c.Status == "ok" {
+			ok++
+		}
+		detail := orNone(strings.Join(c.Answers, ", "))
+		if c.Error != "" {
+			detail = c.Error
+		}
+		fmt.Printf("  %-32s %-7s %s\n", c.label(), c.Status, detail)
+	}
+	fmt.Printf("Propagated to %d of %d resolvers.\n", ok, len(r.Checks))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestDNSCheck(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/dns_records?name=www.example.com&type=A", []dnsRecord{{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1"}})
+	orig := lookupVia
+	t.Cleanup(func() { lookupVia = orig })
+	lookupVia = func(_ context.Context, server, typeName, host string) ([]string, error) {
+		if typeName != "A" || host != "www.example.com" {
+			t.Errorf("unexpected lookup %s %s", typeName, host)
+		}
+		switch server {
+		case "1.1.1.1":
+			return []string{"192.0.2.1"}, nil
+		case "8.8.8.8":
+			return []string{"198.51.100.7"}, nil
+		case "9.9.9.9":
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return nil, errors.New("i/o timeout")
+	}
+
+	out, err := captureStdout(t, func() error {
+		return runDNS([]string{"check", "--zone", "example.com", "--name", "www", "--resolvers", "1.1.1.1,8.8.8.8,9.9.9.9,10.0.0.1"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "propagated to 1 of 4 resolvers") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"1.1.1.1                          ok      192.0.2.1",
+		"8.8.8.8                          stale   198.51.100.7",
+		"9.9.9.9                          missing none",
+		"10.0.0.1                         error   i/o timeout",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestPropagationStatusProxied(t *testing.T) {
+	if got := propagationStatus([]string{"192.0.2.1"}, []string{"104.16.1.1"}, true); got != "ok" {
+		t.Fatalf("proxied record answered by Cloudflare should be ok, got %s", got)
+	}
+	if got := propagationStatus([]string{"192.0.2.1"}, []string{"192.0.2.1"}, true); got != "stale" {
+		t.Fatalf("proxied record still answering the origin should be stale, got %s", got)
+	}
+}
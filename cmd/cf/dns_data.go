@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// dnsDataField describes one field of a record type that is sent as
+// structured data rather than a single content string.
+type dnsDataField struct {
+	Name    string
+	Numeric bool
+	Min     int
+	Max     int
+	OneOf   []string
+	Hex     bool
+}
+
+var dnsDataSpecs = map[string][]dnsDataField{
+	"SRV": {
+		{Name: "priority", Numeric: true, Max: 65535},
+		{Name: "weight", Numeric: true, Max: 65535},
+		{Name: "port", Numeric: true, Max: 65535},
+		{Name: "target"},
+	},
+	"CAA": {
+		{Name: "flags", Numeric: true, Max: 255},
+		{Name: "tag", OneOf: []string{"issue", "issuewild", "iodef"}},
+		{Name: "value"},
+	},
+	"TLSA": {
+		{Name: "usage", Numeric: true, Max: 3},
+		{Name: "selector", Numeric: true, Max: 1},
+		{Name: "matching_type", Numeric: true, Max: 2},
+		{Name: "certificate", Hex: true},
+	},
+	"SSHFP": {
+		{Name: "algorithm", Numeric: true, Min: 1, Max: 6},
+		{Name: "type", Numeric: true, Min: 1, Max: 2},
+		{Name: "fingerprint", Hex: true},
+	},
+}
+
+// dnsDataFlagNames lists the typed flags accepted for structured records,
+// e.g. caa-tag or tlsa-matching-type.
+func dnsDataFlagNames() []string {
+	var names []string
+	for typeName, spec := range dnsDataSpecs {
+		for _, f := range spec {
+			names = append(names, strings.ToLower(typeName)+"-"+strings.ReplaceAll(f.Name, "_", "-"))
+		}
+	}
+	return names
+}
+
+// dnsRecordData builds the data object for a record from --data JSON and
+// typed flags such as --caa-tag or --srv-port; typed flags win over --data.
+// It returns nil when neither is given.
+func dnsRecordData(typeName string, flags map[string]string) (map[string]any, error) {
+	var data map[string]any
+	if raw := flags["data"]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			return nil, usageErrorf("invalid --data: %w", err)
+		}
+	}
+
+	spec := dnsDataSpecs[typeName]
+	prefix := strings.ToLower(typeName) + "-"
+	for _, f := range spec {
+		v, ok := flags[prefix+strings.ReplaceAll(f.Name, "_", "-")]
+		if !ok {
+			continue
+		}
+		if data == nil {
+			data = map[string]any{}
+		}
+		data[f.Name] = v
+	}
+	if data == nil {
+		return nil, nil
+	}
+	if spec == nil {
+		// Other structured types (LOC, URI, HTTPS, ...) are passed through and
+		// left to the API to validate.
+		return data, nil
+	}
+	return data, validateDNSRecordData(typeName, data)
+}
+
+// validateDNSRecordData checks required fields and ranges for the types in
+// dnsDataSpecs, converting numeric fields to ints in place.
+func validateDNSRecordData(typeName string, data map[string]any) error {
+	var missing []string
+	for _, f := range dnsDataSpecs[typeName] {
+		v, ok := data[f.Name]
+		if !ok || v == "" {
+			missing = append(missing, f.Name)
+			continue
+		}
+		if f.Numeric {
+			n, err := dnsDataInt(v)
+			if err != nil || n < f.Min || n > f.Max {
+				return fmt.Errorf("%s %s must be an integer from %d to %d, got %v", typeName, f.Name, f.Min, f.Max, v)
+			}
+			data[f.Name] = n
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s %s must be a string, got %v", typeName, f.Name, v)
+		}
+		if len(f.OneOf) > 0 && !slices.Contains(f.OneOf, s) {
+			return fmt.Errorf("%s %s must be one of %s, got %q", typeName, f.Name, strings.Join(f.OneOf, ", "), s)
+		}
+		if f.Hex {
+			if _, err := hex.DecodeString(s); err != nil {
+				return fmt.Errorf("%s %s must be hex-encoded: %w", typeName, f.Name, err)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s record data is missing: %s (pass --data '{...}' or --%s-<field> flags)", typeName, strings.Join(missing, ", "), strings.ToLower(typeName))
+	}
+	return nil
+}
+
+// dnsDataInt accepts JSON numbers and flag strings.
+func dnsDataInt(v any) (int, error) {
+	switch n := v.(type) {
+	case float64:
+		if n != math.Trunc(n) {
+			return 0, fmt.Errorf("not an integer: %v", n)
+		}
+		return int(n), nil
+	case int:
+		return n, nil
+	case string:
+		return strconv.Atoi(strings.TrimSpace(n))
+	}
+	return 0, fmt.Errorf("not a number: %v", v)
+}
+
+// dnsDataMatches reports whether live has every field of want. Numbers
+// compare by value, whether they came from YAML, JSON or flags.
+func dnsDataMatches(want, live map[string]any) bool {
+	if live == nil {
+		return false
+	}
+	for k, v := range want {
+		a, err1 := json.Marshal(v)
+		b, err2 := json.Marshal(live[k])
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		var x, y any
+		if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil || !reflect.DeepEqual(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// dnsRecordValue is the record's content, or its data as JSON when the
+// content is left for the API to derive.
+func dnsRecordValue(r dnsRecord) string {
+	if r.Content != "" || r.Data == nil {
+		return r.Content
+	}
+	data, err := json.Marshal(r.Data)
+	if err != nil {
+		return fmt.Sprint(r.Data)
+	}
+	return string(data)
+}
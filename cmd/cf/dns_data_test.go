@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDNSRecordData(t *testing.T) {
+	data, err := dnsRecordData("CAA", map[string]string{"caa-flags": "0", "caa-tag": "issue", "caa-value": "letsencrypt.org"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data["flags"] != 0 || data["tag"] != "issue" || data["value"] != "letsencrypt.org" {
+		t.Fatalf("unexpected CAA data: %#v", data)
+	}
+
+	data, err = dnsRecordData("SRV", map[string]string{
+		"data":     `{"priority":10,"weight":5,"port":5060,"target":"sip.example.com"}`,
+		"srv-port": "5061",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data["port"] != 5061 || data["priority"] != 10 {
+		t.Fatalf("typed flag should override --data: %#v", data)
+	}
+
+	data, err = dnsRecordData("A", map[string]string{"content": "1.2.3.4"})
+	if err != nil || data != nil {
+		t.Fatalf("expected no data for plain records, got %#v (err=%v)", data, err)
+	}
+}
+
+func TestValidateDNSRecordDataErrors(t *testing.T) {
+	cases := []struct {
+		typeName string
+		flags    map[string]string
+		want     string
+	}{
+		{"CAA", map[string]string{"caa-tag": "issue"}, "missing: flags, value"},
+		{"CAA", map[string]string{"data": `{"flags":0,"tag":"issue-all","value":"ca.example"}`}, "tag must be one of"},
+		{"SRV", map[string]string{"data": `{"priority":1,"weight":1,"port":70000,"target":"x"}`}, "port must be an integer from 0 to 65535"},
+		{"TLSA", map[string]string{"data": `{"usage":3,"selector":1,"matching_type":1,"certificate":"zz"}`}, "certificate must be hex-encoded"},
+		{"SSHFP", map[string]string{"sshfp-algorithm": "0", "sshfp-type": "2", "sshfp-fingerprint": "ab"}, "algorithm must be an integer from 1 to 6"},
+		{"SRV", map[string]string{"data": `{"priority":`}, "invalid --data"},
+	}
+	for _, tc := range cases {
+		_, err := dnsRecordData(tc.typeName, tc.flags)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s %v: expected error containing %q, got %v", tc.typeName, tc.flags, tc.want, err)
+		}
+	}
+}
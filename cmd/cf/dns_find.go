@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cf/pkg/cfapi"
+)
+
+// dnsFindMatch is a record found by cf dns find, with the zone it is in.
+type dnsFindMatch struct {
+	Zone string `json:"zone"`
+	dnsRecord
+}
+
+func runDNSFind(flags map[string]string) error {
+	filter := cfapi.DNSRecordFilter{
+		Type:         strings.ToUpper(flags["type"]),
+		NameContains: strings.ToLower(flags["name"]),
+		Content:      flags["content"],
+	}
+	if filter.NameContains == "" && filter.Content == "" {
+		return usageErrorf("missing required flag for dns find: --content or --name")
+	}
+	concurrency, err := parseIntWithDefault(flags["concurrency"], defaultBulkConcurrency)
+	if err != nil {
+		return usageErrorf("invalid --concurrency: %w", err)
+	}
+
+	zones, err := listAccountZones()
+	if err != nil {
+		return err
+	}
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	if len(zones) == 0 {
+		return errors.New("no zones found in this account")
+	}
+
+	found := make([][]dnsRecord, len(zones))
+	errs := runBatch("Searching zones", len(zones), concurrency, func(i int) error {
+		records, err := c.ListDNSRecords(rootCtx, zones[i].ID, filter, 0)
+		if err != nil {
+			return fmt.Errorf("%s: %w", zones[i].Name, err)
+		}
+		found[i] = records
+		return nil
+	})
+	failed := countFailed(errs)
+	if failed == len(zones) {
+		return errors.Join(errs...)
+	}
+
+	matches := []dnsFindMatch{}
+	for i, records := range found {
+		for _, r := range records {
+			matches = append(matches, dnsFindMatch{Zone: zones[i].Name, dnsRecord: r})
+		}
+	}
+	t := table{Headers: []string{"ZONE", "TYPE", "NAME", "CONTENT", "PROXIED", "ID"}}
+	for _, m := range matches {
+		t.Rows = append(t.Rows, []string{m.Zone, m.Type, m.Name, m.Content, fmt.Sprint(m.Proxied), m.ID})
+	}
+	if err := printList(matches, t, func() {
+		if len(matches) == 0 {
+			fmt.Printf("No matching records in %d zone(s).\n", len(zones)-failed)
+			return
+		}
+		for _, m := range matches {
+			fmt.Printf("%s  %s %s -> %s  proxied=%t  id=%s\n", m.Zone, m.Type, m.Name, m.Content, m.Proxied, m.ID)
+		}
+	}); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d zones could not be searched: %w", failed, len(zones), errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDNSFind(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones?account.id=acc1", []zone{
+		{ID: "z1", Name: "example.com"},
+		{ID: "z2", Name: "example.org"},
+		{ID: "z3", Name: "example.net"},
+	})
+	srv.Reply("GET", "/zones/z1/dns_records?content.exact=203.0.113.7", []dnsRecord{
+		{ID: "r1", Type: "A", Name: "old.example.com", Content: "203.0.113.7"},
+	})
+	srv.Reply("GET", "/zones/z2/dns_records?content.exact=203.0.113.7", []dnsRecord{
+		{ID: "r2", Type: "A", Name: "example.org", Content: "203.0.113.7", Proxied: true},
+	})
+	srv.Fail("GET", "/zones/z3/dns_records", 403, 10000, "Authentication error")
+
+	out, err := captureStdout(t, func() error {
+		return runDNS([]string{"find", "--content", "203.0.113.7"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 zones") || !strings.Contains(err.Error(), "example.net") {
+		t.Fatalf("expected the failed zone to be reported, got %v", err)
+	}
+	for _, want := range []string{"example.com  A old.example.com -> 203.0.113.7", "example.org  A example.org -> 203.0.113.7  proxied=true"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	if err := runDNS([]string{"find", "--type", "A"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected usage error without --content or --name, got %v", err)
+	}
+}
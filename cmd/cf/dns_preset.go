@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func runDNSPreset(args []string) error {
+	if len(args) == 0 || args[0] != "email" {
+		return usageErrorf("usage: cf dns preset email --zone <zone> --provider <provider>. run: cf dns --help")
+	}
+	flags := parseFlags(args[1:])
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for dns preset email: --zone")
+	}
+	if flags["provider"] == "" {
+		return usageErrorf("missing required flag for dns preset email: --provider google-workspace|microsoft-365|fastmail")
+	}
+	if err := requireConfirmable(flags, "dns preset email"); err != nil {
+		return err
+	}
+	p, err := findEmailProvider(flags["provider"])
+	if err != nil {
+		return usageErrorf("%w", err)
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	result, err := applyMailPreset(bufio.NewReader(os.Stdin), z, p, flags)
+	if err != nil || result == nil {
+		return err
+	}
+	return printResult(result, func() {})
+}
+
+// applyMailPreset shows the MX and SPF changes for p, with warnings about
+// the records they replace, and applies them once confirmed. It returns nil
+// when the user declines.
+func applyMailPreset(reader *bufio.Reader, z *zone, p *emailProvider, flags map[string]string) (*dnsSyncResult, error) {
+	live, err := listDNSRecords(z.ID)
+	if err != nil {
+		return nil, err
+	}
+	changes, warnings := planMailPreset(z.Name, p, live)
+	for _, w := range warnings {
+		infof("Warning: %s\n", w)
+	}
+
+	result := &dnsSyncResult{Zone: z.Name, Changes: changes, Unmanaged: []dnsRecord{}}
+	if !machineOutput() {
+		printDNSSyncPlan(z.Name, changes, nil)
+	}
+	if len(changes) == 0 || dryRun {
+		return result, nil
+	}
+	ok, err := confirmWith(reader, flags, "dns preset email", fmt.Sprintf("Apply the %s mail records?", p.Name))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		fmt.Println("Aborted. No changes applied.")
+		return nil, nil
+	}
+	if err := applyDNSSync(z.ID, changes, defaultBulkConcurrency); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// planMailPreset diffs the provider's MX set and SPF include against live
+// records. MX records for other hosts are deleted, since mail would
+// otherwise be split between providers; those deletions come first so the
+// plan applies in order.
+func planMailPreset(zoneName string, p *emailProvider, live []dnsRecord) ([]dnsChange, []string) {
+	apex := qualifyRecordName("@", zoneName)
+	want := p.MX(zoneName)
+
+	var deletes, rest []dnsChange
+	var warnings []string
+	matched := map[string]bool{}
+	for _, r := range live {
+		if r.Type != "MX" || !strings.EqualFold(r.Name, apex) {
+			continue
+		}
+		host := strings.ToLower(strings.TrimSuffix(r.Content, "."))
+		i := mxHostIndex(want, host)
+		if i < 0 {
+			before := r
+			deletes = append(deletes, dnsChange{Action: "delete", Before: &before})
+			warnings = append(warnings, fmt.Sprintf("MX %s at %s sends mail away from %s; it will be removed", r.Content, apex, p.Name))
+			continue
+		}
+		matched[host] = true
+		if r.Priority == nil || *r.Priority != want[i].Priority {
+			before, after := r, r
+			after.Priority = &want[i].Priority
+			rest = append(rest, dnsChange{Action: "update", Before: &before, After: &after})
+		}
+	}
+	for _, h := range want {
+		if matched[h.Host] {
+			continue
+		}
+		priority := h.Priority
+		rest = append(rest, dnsChange{Action: "create", After: &dnsRecord{Type: "MX", Name: apex, Content: h.Host, TTL: 1, Priority: &priority}})
+	}
+
+	spfChanges, spfWarnings := planSPF(apex, p.SPFInclude, live)
+	warnings = append(warnings, spfWarnings...)
+	for _, c := range spfChanges {
+		if c.Action == "delete" {
+			deletes = append(deletes, c)
+		} else {
+			rest = append(rest, c)
+		}
+	}
+	for _, other := range emailProviders {
+		if other.Key == p.Key {
+			continue
+		}
+		for _, r := range live {
+			if strings.EqualFold(r.Name, apex) && isSPF(r) && strings.Contains(strings.ToLower(r.Content), "include:"+other.SPFInclude) {
+				warnings = append(warnings, fmt.Sprintf("SPF at %s still authorizes %s (include:%s); remove it once nothing sends mail through %s", apex, other.Name, other.SPFInclude, other.Name))
+				break
+			}
+		}
+	}
+	return append(deletes, rest...), warnings
+}
+
+func mxHostIndex(hosts []mxHost, host string) int {
+	for i, h := range hosts {
+		if h.Host == host {
+			return i
+		}
+	}
+	return -1
+}
+
+// wizardMailProvider is the optional wizard step for a hosted mailbox
+// provider. It reports whether the records are in place, so the wizard can
+// skip offering Email Routing, which needs its own MX records.
+func wizardMailProvider(reader *bufio.Reader, domain string) (bool, error) {
+	keys := make([]string, 0, len(emailProviders))
+	for _, p := range emailProviders {
+		keys = append(keys, p.Key)
+	}
+	for {
+		key, err := prompt(reader, fmt.Sprintf("Mail provider to set up MX and SPF for (%s; Enter to skip)", strings.Join(keys, ", ")), "")
+		if err != nil || key == "" {
+			return false, err
+		}
+		p, err := findEmailProvider(key)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		z, err := requireZone(domain)
+		if err != nil {
+			return false, err
+		}
+		result, err := applyMailPreset(reader, z, p, nil)
+		if err != nil || result == nil {
+			return false, err
+		}
+		fmt.Printf("For DKIM and DMARC, run: cf dns email-setup --zone %s --provider %s\n", z.Name, p.Key)
+		return true, nil
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPlanMailPreset(t *testing.T) {
+	p, err := findEmailProvider("fastmail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ten := 5
+	live := []dnsRecord{
+		{ID: "1", Type: "MX", Name: "example.com", Content: "route1.mx.cloudflare.net"},
+		{ID: "2", Type: "MX", Name: "example.com", Content: "in1-smtp.messagingengine.com.", Priority: &ten},
+		{ID: "3", Type: "TXT", Name: "example.com", Content: "v=spf1 include:_spf.google.com ~all"},
+		{ID: "4", Type: "MX", Name: "lists.example.com", Content: "mx.example.net"},
+	}
+	changes, warnings := planMailPreset("example.com", p, live)
+
+	var got []string
+	for _, c := range changes {
+		switch c.Action {
+		case "delete":
+			got = append(got, "delete "+c.Before.ID)
+		case "update":
+			got = append(got, "update "+c.After.ID+" "+c.After.Content+mxPriority(c.After))
+		default:
+			got = append(got, "create "+c.After.Content+mxPriority(c.After))
+		}
+	}
+	want := []string{
+		"delete 1",
+		"update 2 in1-smtp.messagingengine.com. 10",
+		"create in2-smtp.messagingengine.com 20",
+		"update 3 v=spf1 include:_spf.google.com include:spf.messagingengine.com ~all",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected plan:\n%s", strings.Join(got, "\n"))
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "route1.mx.cloudflare.net") || !strings.Contains(warnings[1], "Google Workspace") {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestFindEmailProviderAliases(t *testing.T) {
+	p, err := findEmailProvider("Google-Workspace")
+	if err != nil || p.Key != "google" {
+		t.Fatalf("got %v, %v", p, err)
+	}
+	if mx := p.MX("example.com"); len(mx) != 1 || mx[0].Host != "smtp.google.com" {
+		t.Fatalf("unexpected MX: %v", mx)
+	}
+	p, _ = findEmailProvider("microsoft-365")
+	if mx := p.MX("example.co.uk"); mx[0].Host != "example-co-uk.mail.protection.outlook.com" {
+		t.Fatalf("unexpected MX: %v", mx)
+	}
+}
+
+func mxPriority(r *dnsRecord) string {
+	if r.Priority == nil {
+		return ""
+	}
+	return fmt.Sprintf(" %d", *r.Priority)
+}
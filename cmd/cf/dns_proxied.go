@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// proxiableTypes are the record types Cloudflare can proxy.
+var proxiableTypes = map[string]bool{"A": true, "AAAA": true, "CNAME": true}
+
+func runDNSSetProxied(flags map[string]string) error {
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" || flags["name"] == "" || flags["proxied"] == "" {
+		return usageErrorf("missing required flags for dns set-proxied: --zone --name --proxied")
+	}
+	proxied, err := parseOnOff(flags["proxied"])
+	if err != nil {
+		return usageErrorf("invalid --proxied: %w", err)
+	}
+	concurrency, err := parseIntWithDefault(flags["concurrency"], defaultBulkConcurrency)
+	if err != nil {
+		return usageErrorf("invalid --concurrency: %w", err)
+	}
+	if _, err := path.Match(flags["name"], ""); err != nil {
+		return usageErrorf("invalid --name pattern %q: %w", flags["name"], err)
+	}
+	if err := requireConfirmable(flags, "dns set-proxied"); err != nil {
+		return err
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	live, err := listDNSRecords(z.ID)
+	if err != nil {
+		return err
+	}
+	changes := planSetProxied(live, flags["name"], z.Name, proxied)
+	result := dnsSyncResult{Zone: z.Name, Changes: changes, Unmanaged: []dnsRecord{}}
+	if !machineOutput() {
+		printDNSSyncPlan(z.Name, changes, nil)
+	}
+	if len(changes) == 0 || dryRun {
+		return printResult(result, func() {})
+	}
+
+	ok, err := confirm(flags, "dns set-proxied", fmt.Sprintf("Set proxied=%t on %d record(s)?", proxied, len(changes)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted. No changes applied.")
+		return nil
+	}
+	if err := applyDNSSync(z.ID, changes, concurrency); err != nil {
+		return err
+	}
+	result.Applied = true
+	return printResult(result, func() {})
+}
+
+// planSetProxied returns an update for each proxiable record whose name
+// matches pattern and whose proxied status differs. pattern is a glob
+// qualified like a record name, so "*" and "*.staging" stay inside the zone;
+// a bare "*" also matches the apex.
+func planSetProxied(live []dnsRecord, pattern, zoneName string, proxied bool) []dnsChange {
+	all := strings.TrimSpace(pattern) == "*"
+	pattern = qualifyRecordName(pattern, zoneName)
+	var changes []dnsChange
+	for _, r := range live {
+		if !proxiableTypes[r.Type] || r.Proxied == proxied {
+			continue
+		}
+		if ok, _ := path.Match(pattern, strings.ToLower(r.Name)); !ok && !all {
+			continue
+		}
+		before, after := r, r
+		after.Proxied = proxied
+		// Proxied records must use automatic TTL.
+		if proxied {
+			after.TTL = 1
+		}
+		changes = append(changes, dnsChange{Action: "update", Before: &before, After: &after})
+	}
+	return changes
+}
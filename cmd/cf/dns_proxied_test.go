@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlanSetProxied(t *testing.T) {
+	live := []dnsRecord{
+		{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.1", Proxied: true},
+		{ID: "2", Type: "CNAME", Name: "www.staging.example.com", Content: "example.com", Proxied: true},
+		{ID: "3", Type: "A", Name: "api.staging.example.com", Content: "192.0.2.2", Proxied: false},
+		{ID: "4", Type: "TXT", Name: "staging.example.com", Content: "v=spf1 -all"},
+		{ID: "5", Type: "AAAA", Name: "app.example.com", Content: "2001:db8::1", TTL: 300, Proxied: false},
+	}
+
+	ids := func(changes []dnsChange) string {
+		var out []string
+		for _, c := range changes {
+			out = append(out, c.After.ID)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := ids(planSetProxied(live, "*.staging", "example.com", false)); got != "2" {
+		t.Fatalf("expected only the proxied staging record, got %q", got)
+	}
+	if got := ids(planSetProxied(live, "*", "example.com", false)); got != "1,2" {
+		t.Fatalf("expected * to include the apex, got %q", got)
+	}
+	changes := planSetProxied(live, "app", "example.com", true)
+	if len(changes) != 1 || !changes[0].After.Proxied || changes[0].After.TTL != 1 || changes[0].Before.Proxied {
+		t.Fatalf("unexpected plan %+v", changes)
+	}
+}
+
+func TestDNSSetProxied(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{
+		{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Proxied: true},
+		{ID: "r2", Type: "A", Name: "mail.example.com", Content: "192.0.2.2", TTL: 1},
+	})
+	srv.Reply("PUT", "/zones/z1/dns_records/r1", dnsRecord{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1})
+
+	out, err := captureStdout(t, func() error {
+		return runDNS([]string{"set-proxied", "--zone", "example.com", "--name", "*", "--proxied", "false", "--yes"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "0 to create, 1 to update, 0 to delete") {
+		t.Fatalf("expected a preview, got:\n%s", out)
+	}
+	var body dnsRecord
+	if calls := srv.Calls("PUT", "/zones/z1/dns_records/r1"); len(calls) != 1 {
+		t.Fatalf("expected one update, got %d", len(calls))
+	} else if err := calls[0].Decode(&body); err != nil || body.Proxied || body.Content != "192.0.2.1" {
+		t.Fatalf("unexpected update body %+v (err=%v)", body, err)
+	}
+}
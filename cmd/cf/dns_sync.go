@@ -0,0 +1,372 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type dnsSyncFile struct {
+	Zone    string          `json:"zone" yaml:"zone"`
+	Records []dnsSyncRecord `json:"records" yaml:"records"`
+}
+
+type dnsSyncRecord struct {
+	Type     string `json:"type" yaml:"type"`
+	Name     string `json:"name" yaml:"name"`
+	Content  string `json:"content" yaml:"content"`
+	TTL      *int   `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	Proxied  *bool  `json:"proxied,omitempty" yaml:"proxied,omitempty"`
+	Priority *int   `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// Data replaces Content for structured types such as SRV or CAA.
+	Data map[string]any `json:"data,omitempty" yaml:"data,omitempty"`
+	// Comment and Tags are only managed when set; otherwise live values are
+	// kept.
+	Comment string   `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Tags    []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+type dnsChange struct {
+	Action string     `json:"action"`
+	Before *dnsRecord `json:"before,omitempty"`
+	After  *dnsRecord `json:"after,omitempty"`
+}
+
+type dnsSyncResult struct {
+	Zone      string      `json:"zone"`
+	Applied   bool        `json:"applied"`
+	Changes   []dnsChange `json:"changes"`
+	Unmanaged []dnsRecord `json:"unmanaged"`
+}
+
+func syncDNSRecords(flags map[string]string, path, zoneOverride string, vars map[string]string, dryRun, prune bool, concurrency int) error {
+	if err := requireConfirmable(flags, "dns sync"); err != nil {
+		return err
+	}
+
+	spec, err := readDNSSyncFile(path, vars)
+	if err != nil {
+		return err
+	}
+
+	zoneName := spec.Zone
+	if zoneOverride != "" {
+		zoneName = zoneOverride
+	}
+	if zoneName == "" {
+		return usageErrorf("zone is required: set `zone` in the file or pass --zone")
+	}
+
+	desired, err := desiredDNSRecords(zoneName, spec.Records)
+	if err != nil {
+		return err
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	live, err := listDNSRecords(z.ID)
+	if err != nil {
+		return err
+	}
+
+	changes, unmanaged := planDNSSync(desired, live, prune)
+	result := dnsSyncResult{Zone: z.Name, Changes: changes, Unmanaged: unmanaged}
+	if !machineOutput() {
+		printDNSSyncPlan(z.Name, changes, unmanaged)
+	}
+	if len(changes) == 0 || dryRun {
+		return printResult(result, func() {})
+	}
+
+	ok, err := confirm(flags, "dns sync", "Apply these changes?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted. No changes applied.")
+		return nil
+	}
+
+	if err := applyDNSSync(z.ID, changes, concurrency); err != nil {
+		return err
+	}
+	result.Applied = true
+	return printResult(result, func() {})
+}
+
+func readDNSSyncFile(path string, vars map[string]string) (*dnsSyncFile, error) {
+	data, err := readTemplateFile(path, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec dnsSyncFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &spec)
+	} else {
+		err = yaml.Unmarshal(data, &spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+func desiredDNSRecords(zoneName string, in []dnsSyncRecord) ([]dnsRecord, error) {
+	out := make([]dnsRecord, 0, len(in))
+	for i, r := range in {
+		if r.Type == "" || r.Name == "" || (r.Content == "" && r.Data == nil) {
+			return nil, fmt.Errorf("record %d: type, name and content (or data) are required", i+1)
+		}
+		rec := dnsRecord{
+			Type:     strings.ToUpper(r.Type),
+			Name:     qualifyRecordName(r.Name, zoneName),
+			Content:  r.Content,
+			TTL:      1,
+			Priority: r.Priority,
+			Data:     r.Data,
+			Comment:  r.Comment,
+			Tags:     r.Tags,
+		}
+		if rec.Data != nil {
+			if err := validateDNSRecordData(rec.Type, rec.Data); err != nil {
+				return nil, fmt.Errorf("record %d: %w", i+1, err)
+			}
+		}
+		if r.TTL != nil {
+			rec.TTL = *r.TTL
+		}
+		if r.Proxied != nil {
+			rec.Proxied = *r.Proxied
+		}
+		if err := validateDNSRecord(rec); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		if err := zoneCNAMEConflict(zoneName, rec, out); err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+// qualifyRecordName turns "@" and relative names into the fully qualified
+// form Cloudflare returns for existing records.
+func qualifyRecordName(name, zoneName string) string {
+	name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+	zoneName = strings.ToLower(strings.TrimSuffix(zoneName, "."))
+	if name == "@" || name == "" {
+		return zoneName
+	}
+	if name == zoneName || strings.HasSuffix(name, "."+zoneName) {
+		return name
+	}
+	return name + "." + zoneName
+}
+
+// planDNSSync pairs desired records with live ones: exact content matches
+// first, then remaining records sharing a type and name become updates.
+// Live records left over are deleted only when prune is set; otherwise they
+// are returned as unmanaged.
+func planDNSSync(desired, live []dnsRecord, prune bool) ([]dnsChange, []dnsRecord) {
+	used := make([]bool, len(live))
+	pending := make([]dnsRecord, 0, len(desired))
+	var updates []dnsChange
+
+	for _, d := range desired {
+		idx := -1
+		for i, l := range live {
+			if !used[i] && sameRecordKey(d, l) && sameRecordValue(d, l) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			pending = append(pending, d)
+			continue
+		}
+		used[idx] = true
+		if dnsRecordDiffers(d, live[idx]) {
+			before, after := live[idx], d
+			after.ID = before.ID
+			keepRecordMetadata(&after, before)
+			updates = append(updates, dnsChange{Action: "update", Before: &before, After: &after})
+		}
+	}
+
+	var creates []dnsChange
+	for _, d := range pending {
+		idx := -1
+		for i, l := range live {
+			if !used[i] && sameRecordKey(d, l) {
+				idx = i
+				break
+			}
+		}
+		after := d
+		if idx == -1 {
+			creates = append(creates, dnsChange{Action: "create", After: &after})
+			continue
+		}
+		used[idx] = true
+		before := live[idx]
+		after.ID = before.ID
+		keepRecordMetadata(&after, before)
+		updates = append(updates, dnsChange{Action: "update", Before: &before, After: &after})
+	}
+
+	var deletes []dnsChange
+	var unmanaged []dnsRecord
+	for i, l := range live {
+		if used[i] {
+			continue
+		}
+		if prune {
+			before := l
+			deletes = append(deletes, dnsChange{Action: "delete", Before: &before})
+		} else {
+			unmanaged = append(unmanaged, l)
+		}
+	}
+
+	// Deletes go first so a record can be replaced by one of a conflicting
+	// type (e.g. A -> CNAME) in a single run.
+	changes := make([]dnsChange, 0, len(deletes)+len(updates)+len(creates))
+	changes = append(changes, deletes...)
+	changes = append(changes, updates...)
+	changes = append(changes, creates...)
+	return changes, unmanaged
+}
+
+func sameRecordKey(a, b dnsRecord) bool {
+	return strings.EqualFold(a.Type, b.Type) && strings.EqualFold(a.Name, b.Name)
+}
+
+// sameRecordValue compares data for structured records, since the API
+// derives their content, and content otherwise.
+func sameRecordValue(desired, live dnsRecord) bool {
+	if desired.Data != nil {
+		return dnsDataMatches(desired.Data, live.Data)
+	}
+	return desired.Content == live.Content
+}
+
+func dnsRecordDiffers(desired, live dnsRecord) bool {
+	if !sameRecordValue(desired, live) || desired.TTL != live.TTL || desired.Proxied != live.Proxied {
+		return true
+	}
+	if desired.Priority != nil && (live.Priority == nil || *desired.Priority != *live.Priority) {
+		return true
+	}
+	if desired.Comment != "" && desired.Comment != live.Comment {
+		return true
+	}
+	return desired.Tags != nil && !slices.Equal(sortedTags(desired.Tags), sortedTags(live.Tags))
+}
+
+// keepRecordMetadata carries the live comment and tags over to an update
+// that does not set its own, since the update replaces the whole record.
+func keepRecordMetadata(after *dnsRecord, live dnsRecord) {
+	if after.Comment == "" {
+		after.Comment = live.Comment
+	}
+	if after.Tags == nil {
+		after.Tags = live.Tags
+	}
+}
+
+func sortedTags(tags []string) []string {
+	out := slices.Clone(tags)
+	slices.Sort(out)
+	return out
+}
+
+func printDNSSyncPlan(zoneName string, changes []dnsChange, unmanaged []dnsRecord) {
+	counts := map[string]int{}
+	for _, c := range changes {
+		counts[c.Action]++
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("No changes. DNS records for %s are in sync.\n", zoneName)
+	} else {
+		fmt.Printf("Plan for %s: %d to create, %d to update, %d to delete\n", zoneName, counts["create"], counts["update"], counts["delete"])
+		for _, c := range changes {
+			switch c.Action {
+			case "create":
+				fmt.Printf("  + %s %s -> %s (ttl=%d, proxied=%t)\n", c.After.Type, c.After.Name, dnsRecordValue(*c.After), c.After.TTL, c.After.Proxied)
+			case "update":
+				fmt.Printf("  ~ %s %s: %s -> %s (ttl=%d, proxied=%t)\n", c.After.Type, c.After.Name, c.Before.Content, dnsRecordValue(*c.After), c.After.TTL, c.After.Proxied)
+			case "delete":
+				fmt.Printf("  - %s %s -> %s\n", c.Before.Type, c.Before.Name, c.Before.Content)
+			}
+		}
+	}
+
+	if len(unmanaged) > 0 {
+		fmt.Printf("%d live record(s) not in file were left untouched (use --prune to delete them).\n", len(unmanaged))
+	}
+}
+
+// applyDNSSync applies changes with up to concurrency requests in flight.
+// Each action runs as its own batch, in the order the plan lists them, so
+// deletes finish before a conflicting record is created; a failed batch
+// stops the run.
+func applyDNSSync(zoneID string, changes []dnsChange, concurrency int) error {
+	for start := 0; start < len(changes); {
+		end := start + 1
+		for end < len(changes) && changes[end].Action == changes[start].Action {
+			end++
+		}
+		batch := changes[start:end]
+		messages := make([]string, len(batch))
+		errs := runBatch(dnsSyncBatchLabels[batch[0].Action], len(batch), concurrency, func(i int) error {
+			var err error
+			messages[i], err = applyDNSChange(zoneID, batch[i])
+			return err
+		})
+		for i, msg := range messages {
+			if errs[i] == nil {
+				infof("%s", msg)
+			}
+		}
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+var dnsSyncBatchLabels = map[string]string{"create": "Creating records", "update": "Updating records", "delete": "Deleting records"}
+
+func applyDNSChange(zoneID string, c dnsChange) (string, error) {
+	switch c.Action {
+	case "create":
+		r, err := createDNSRecord(zoneID, *c.After)
+		if err != nil {
+			return "", fmt.Errorf("create %s %s: %w", c.After.Type, c.After.Name, err)
+		}
+		return fmt.Sprintf("DNS record created: %s %s -> %s (id=%s)\n", r.Type, r.Name, r.Content, r.ID), nil
+	case "update":
+		r, err := updateDNSRecord(zoneID, *c.Before, *c.After)
+		if err != nil {
+			return "", fmt.Errorf("update %s %s: %w", c.After.Type, c.After.Name, err)
+		}
+		return fmt.Sprintf("DNS record updated: %s %s -> %s (id=%s)\n", r.Type, r.Name, r.Content, r.ID), nil
+	case "delete":
+		if err := deleteDNSRecord(zoneID, *c.Before); err != nil {
+			return "", fmt.Errorf("delete %s %s: %w", c.Before.Type, c.Before.Name, err)
+		}
+		return fmt.Sprintf("DNS record deleted: %s %s -> %s (id=%s)\n", c.Before.Type, c.Before.Name, c.Before.Content, c.Before.ID), nil
+	}
+	return "", fmt.Errorf("unknown change %q", c.Action)
+}
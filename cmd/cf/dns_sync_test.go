@@ -0,0 +1,212 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQualifyRecordName(t *testing.T) {
+	cases := map[string]string{
+		"@":                "example.com",
+		"www":              "www.example.com",
+		"www.example.com":  "www.example.com",
+		"WWW.Example.com.": "www.example.com",
+		"example.com":      "example.com",
+	}
+	for in, want := range cases {
+		if got := qualifyRecordName(in, "example.com"); got != want {
+			t.Fatalf("qualifyRecordName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPlanDNSSync(t *testing.T) {
+	live := []dnsRecord{
+		{ID: "1", Type: "A", Name: "example.com", Content: "1.1.1.1", TTL: 1},
+		{ID: "2", Type: "A", Name: "www.example.com", Content: "2.2.2.2", TTL: 1},
+		{ID: "3", Type: "TXT", Name: "old.example.com", Content: "stale", TTL: 1},
+		{ID: "4", Type: "A", Name: "api.example.com", Content: "4.4.4.4", TTL: 1},
+	}
+	desired := []dnsRecord{
+		{Type: "A", Name: "example.com", Content: "1.1.1.1", TTL: 1},
+		{Type: "A", Name: "www.example.com", Content: "3.3.3.3", TTL: 1},
+		{Type: "CNAME", Name: "docs.example.com", Content: "example.com", TTL: 1},
+		{Type: "A", Name: "api.example.com", Content: "4.4.4.4", TTL: 1, Proxied: true},
+	}
+
+	changes, unmanaged := planDNSSync(desired, live, false)
+	if len(unmanaged) != 1 || unmanaged[0].ID != "3" {
+		t.Fatalf("expected TXT record to be unmanaged, got %+v", unmanaged)
+	}
+	got := map[string]int{}
+	for _, c := range changes {
+		got[c.Action]++
+		if c.Action == "update" && c.After.ID != c.Before.ID {
+			t.Fatalf("update should keep live record ID, got %+v", c)
+		}
+	}
+	if got["create"] != 1 || got["update"] != 2 || got["delete"] != 0 {
+		t.Fatalf("unexpected plan without prune: %v", got)
+	}
+
+	changes, unmanaged = planDNSSync(desired, live, true)
+	if len(unmanaged) != 0 {
+		t.Fatalf("expected no unmanaged records with prune, got %+v", unmanaged)
+	}
+	if len(changes) == 0 || changes[0].Action != "delete" || changes[0].Before.ID != "3" {
+		t.Fatalf("expected delete of TXT record first, got %+v", changes)
+	}
+}
+
+func TestPlanDNSSyncKeepsMetadata(t *testing.T) {
+	live := []dnsRecord{
+		{ID: "1", Type: "A", Name: "www.example.com", Content: "1.1.1.1", TTL: 1, Comment: "web team", Tags: []string{"team:web"}},
+		{ID: "2", Type: "A", Name: "api.example.com", Content: "2.2.2.2", TTL: 1, Tags: []string{"team:api"}},
+	}
+	desired := []dnsRecord{
+		{Type: "A", Name: "www.example.com", Content: "3.3.3.3", TTL: 1},
+		{Type: "A", Name: "api.example.com", Content: "2.2.2.2", TTL: 1, Tags: []string{"team:platform"}},
+	}
+
+	changes, _ := planDNSSync(desired, live, false)
+	if len(changes) != 2 {
+		t.Fatalf("expected two updates, got %+v", changes)
+	}
+	for _, c := range changes {
+		switch c.After.ID {
+		case "1":
+			if c.After.Comment != "web team" || !reflect.DeepEqual(c.After.Tags, []string{"team:web"}) {
+				t.Fatalf("expected live comment and tags to be kept, got %+v", c.After)
+			}
+		case "2":
+			if !reflect.DeepEqual(c.After.Tags, []string{"team:platform"}) {
+				t.Fatalf("expected tags from the file, got %+v", c.After)
+			}
+		}
+	}
+}
+
+func TestParseDNSRecordsCSV(t *testing.T) {
+	in := "type,name,content,ttl,proxied,priority\nA,api,1.2.3.4,300,true,\nMX,@,mail.example.com,,,10\n"
+	got, err := parseDNSRecordsCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(got))
+	}
+	if got[0].TTL == nil || *got[0].TTL != 300 || got[0].Proxied == nil || !*got[0].Proxied || got[0].Priority != nil {
+		t.Fatalf("unexpected first record: %+v", got[0])
+	}
+	if got[1].Priority == nil || *got[1].Priority != 10 || got[1].TTL != nil {
+		t.Fatalf("unexpected second record: %+v", got[1])
+	}
+
+	got, err = parseDNSRecordsCSV(strings.NewReader("type,name,content,comment,tags\nA,www,1.2.3.4,owned by web,\"team:web,env:prod\"\n"))
+	if err != nil || got[0].Comment != "owned by web" || !reflect.DeepEqual(got[0].Tags, []string{"team:web", "env:prod"}) {
+		t.Fatalf("unexpected comment and tags: %+v (err=%v)", got, err)
+	}
+
+	if _, err := parseDNSRecordsCSV(strings.NewReader("type,name\nA,www\n")); err == nil {
+		t.Fatalf("expected error for missing content column")
+	}
+}
+
+func TestDNSSyncStructuredData(t *testing.T) {
+	srv := useFakeAPI(t)
+	path := filepath.Join(t.TempDir(), "dns.yaml")
+	file := `zone: example.com
+records:
+  - type: SRV
+    name: _sip._tcp
+    data: {priority: 10, weight: 5, port: 5061, target: sip.example.com}
+  - type: CAA
+    name: "@"
+    data: {flags: 0, tag: issue, value: letsencrypt.org}
+`
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{
+		{ID: "r1", Type: "SRV", Name: "_sip._tcp.example.com", Content: "5 5060 sip.example.com", TTL: 1,
+			Data: map[string]any{"priority": 10.0, "weight": 5.0, "port": 5060.0, "target": "sip.example.com"}},
+		{ID: "r2", Type: "CAA", Name: "example.com", Content: `0 issue "letsencrypt.org"`, TTL: 1,
+			Data: map[string]any{"flags": 0.0, "tag": "issue", "value": "letsencrypt.org"}},
+	})
+	srv.Reply("PUT", "/zones/z1/dns_records/r1", dnsRecord{ID: "r1"})
+
+	out, err := captureStdout(t, func() error { return runDNS([]string{"sync", "--file", path, "--yes"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "0 to create, 1 to update, 0 to delete") {
+		t.Fatalf("expected only the SRV port change, got:\n%s", out)
+	}
+	var body map[string]any
+	if calls := srv.Calls("PUT", "/zones/z1/dns_records/r1"); len(calls) != 1 {
+		t.Fatalf("expected one update, got %d", len(calls))
+	} else if err := calls[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := body["data"].(map[string]any); data["port"] != 5061.0 {
+		t.Fatalf("expected the new port in data, got %v", body)
+	}
+
+	bad := strings.Replace(file, "port: 5061", "port: 70000", 1)
+	if err := os.WriteFile(path, []byte(bad), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := runDNS([]string{"sync", "--file", path, "--yes"}); err == nil || !strings.Contains(err.Error(), "record 1: SRV port") {
+		t.Fatalf("expected invalid data to be rejected, got %v", err)
+	}
+}
+
+func TestDNSSyncApexCNAMEBesideMX(t *testing.T) {
+	srv := useFakeAPI(t)
+	path := filepath.Join(t.TempDir(), "dns.yaml")
+	file := `zone: example.com
+records:
+  - {type: MX, name: "@", content: mx.example.net, priority: 10}
+  - {type: CNAME, name: "@", content: blog.pages.dev, proxied: true}
+  - {type: TXT, name: "@", content: "v=spf1 include:_spf.example.net -all"}
+`
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{})
+	srv.Reply("POST", "/zones/z1/dns_records", dnsRecord{ID: "new"})
+
+	out, err := captureStdout(t, func() error { return runDNS([]string{"sync", "--file", path, "--yes"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	posts := srv.Calls("POST", "/zones/z1/dns_records")
+	if len(posts) != 3 || !strings.Contains(out, "3 to create, 0 to update, 0 to delete") {
+		t.Fatalf("expected all three apex records to be created, got %d:\n%s", len(posts), out)
+	}
+	var body dnsRecord
+	for _, p := range posts {
+		var r dnsRecord
+		if err := p.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		if r.Type == "CNAME" {
+			body = r
+		}
+	}
+	if body.Name != "example.com" || body.Content != "blog.pages.dev" || !body.Proxied {
+		t.Fatalf("unexpected apex CNAME %+v", body)
+	}
+
+	clash := file + "  - {type: A, name: \"@\", content: 192.0.2.1}\n"
+	if err := os.WriteFile(path, []byte(clash), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err = runDNS([]string{"sync", "--file", path, "--yes"})
+	if err == nil || !strings.Contains(err.Error(), "record 4: A record example.com conflicts with the CNAME record") {
+		t.Fatalf("expected an apex A record to clash with the CNAME, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDNSUpdateAndDeleteByID(t *testing.T) {
+	srv := useFakeAPI(t)
+	roundRobin := []dnsRecord{
+		{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1},
+		{ID: "r2", Type: "A", Name: "www.example.com", Content: "192.0.2.2", TTL: 1},
+	}
+	srv.Reply("GET", "/zones/z1/dns_records?name=www.example.com&type=A", roundRobin)
+	srv.Reply("GET", "/zones/z1/dns_records/r2", roundRobin[1])
+	srv.Reply("PUT", "/zones/z1/dns_records/r2", dnsRecord{ID: "r2", Type: "A", Name: "www.example.com", Content: "192.0.2.9"})
+	srv.Reply("DELETE", "/zones/z1/dns_records/r2", map[string]string{"id": "r2"})
+
+	err := runDNS([]string{"update", "--zone", "example.com", "--type", "A", "--name", "www", "--content", "192.0.2.9"})
+	if exitCode(err) != exitUsage || !strings.Contains(err.Error(), "pass --id") || !strings.Contains(err.Error(), "id=r2") {
+		t.Fatalf("expected an ambiguity error listing IDs, got %v", err)
+	}
+
+	if _, err := captureStdout(t, func() error {
+		return runDNS([]string{"update", "--zone", "example.com", "--id", "r2", "--content", "192.0.2.9", "--yes"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body dnsRecord
+	if calls := srv.Calls("PUT", "/zones/z1/dns_records/r2"); len(calls) != 1 {
+		t.Fatalf("expected one update, got %d", len(calls))
+	} else if err := calls[0].Decode(&body); err != nil || body.Content != "192.0.2.9" || body.Type != "A" {
+		t.Fatalf("unexpected update body %+v (err=%v)", body, err)
+	}
+
+	if _, err := captureStdout(t, func() error {
+		return runDNS([]string{"delete", "--zone", "example.com", "--id", "r2", "--yes"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(srv.Calls("DELETE", "/zones/z1/dns_records/r2")); n != 1 {
+		t.Fatalf("expected one delete, got %d", n)
+	}
+
+	if err := runDNS([]string{"delete", "--zone", "example.com", "--type", "A", "--name", "gone"}); exitCode(err) != exitNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := runDNS([]string{"delete", "--zone", "example.com", "--name", "www"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected usage error without --id or --type, got %v", err)
+	}
+}
+
+func TestDNSCommentsAndTags(t *testing.T) {
+	srv := useFakeAPI(t)
+	tagged := dnsRecord{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Comment: "web team", Tags: []string{"team:web"}}
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{tagged})
+	srv.Reply("GET", "/zones/z1/dns_records/r1", tagged)
+	srv.Reply("POST", "/zones/z1/dns_records", dnsRecord{ID: "r2"})
+	srv.Reply("PUT", "/zones/z1/dns_records/r1", tagged)
+
+	out, err := captureStdout(t, func() error {
+		return runDNS([]string{"list", "--zone", "example.com", "--tag", "team:web"})
+	})
+	if err != nil || !strings.Contains(out, `tags=team:web  comment="web team"`) {
+		t.Fatalf("expected tags and comment in list output, got err=%v:\n%s", err, out)
+	}
+	if calls := srv.Calls("GET", "/zones/z1/dns_records"); calls[len(calls)-1].Query.Get("tag.exact") != "team:web" {
+		t.Fatalf("expected a tag.exact filter, got %v", calls[len(calls)-1].Query)
+	}
+
+	if _, err := captureStdout(t, func() error {
+		return runDNS([]string{"add", "--zone", "example.com", "--type", "A", "--name", "api", "--content", "192.0.2.2", "--comment", "api team", "--tags", "team:api, env:prod"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var created dnsRecord
+	if err := srv.Calls("POST", "/zones/z1/dns_records")[0].Decode(&created); err != nil || created.Comment != "api team" || !reflect.DeepEqual(created.Tags, []string{"team:api", "env:prod"}) {
+		t.Fatalf("unexpected create body %+v (err=%v)", created, err)
+	}
+
+	// An update that does not mention them must not drop them.
+	if _, err := captureStdout(t, func() error {
+		return runDNS([]string{"update", "--zone", "example.com", "--id", "r1", "--content", "192.0.2.9", "--yes"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var updated dnsRecord
+	if err := srv.Calls("PUT", "/zones/z1/dns_records/r1")[0].Decode(&updated); err != nil || updated.Comment != "web team" || !reflect.DeepEqual(updated.Tags, []string{"team:web"}) {
+		t.Fatalf("update dropped metadata: %+v (err=%v)", updated, err)
+	}
+}
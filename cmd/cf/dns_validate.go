@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// maxTXTContentLength is Cloudflare's limit for a whole TXT record; longer
+// single strings are split into 255-byte chunks by the API.
+const maxTXTContentLength = 2048
+
+// validateDNSRecord checks a record's content against its type so mistakes
+// are reported in plain words instead of as Cloudflare error codes.
+func validateDNSRecord(r dnsRecord) error {
+	label := fmt.Sprintf("%s record %s", r.Type, r.Name)
+	if r.Proxied && r.Type != "A" && r.Type != "AAAA" && r.Type != "CNAME" {
+		return fmt.Errorf("%s cannot be proxied; only A, AAAA and CNAME records can (pass --proxied false)", label)
+	}
+	if r.Data != nil {
+		return nil
+	}
+
+	switch r.Type {
+	case "A":
+		addr, err := netip.ParseAddr(r.Content)
+		if err != nil || !addr.Is4() {
+			return fmt.Errorf("%s: %q is not an IPv4 address", label, r.Content)
+		}
+	case "AAAA":
+		addr, err := netip.ParseAddr(r.Content)
+		if err != nil || !addr.Is6() || addr.Is4In6() {
+			return fmt.Errorf("%s: %q is not an IPv6 address", label, r.Content)
+		}
+	case "CNAME":
+		if _, err := netip.ParseAddr(r.Content); err == nil {
+			return fmt.Errorf("%s: CNAME must point to a hostname, not the IP %s (use an A or AAAA record)", label, r.Content)
+		}
+		if strings.ContainsAny(r.Content, " /:") {
+			return fmt.Errorf("%s: %q is not a hostname", label, r.Content)
+		}
+	case "TXT":
+		if len(r.Content) > maxTXTContentLength {
+			return fmt.Errorf("%s: content is %d characters; TXT records are limited to %d", label, len(r.Content), maxTXTContentLength)
+		}
+	}
+	return nil
+}
+
+// apexAddressRecords keeps the A, AAAA and CNAME records from existing.
+// Cloudflare flattens a CNAME at the zone apex, so there it only clashes with
+// other address records; MX, TXT and the rest may stay.
+func apexAddressRecords(existing []dnsRecord) []dnsRecord {
+	var out []dnsRecord
+	for _, e := range existing {
+		switch e.Type {
+		case "A", "AAAA", "CNAME":
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// zoneCNAMEConflict is cnameConflict with the apex rule applied: at the
+// zone name only address records clash with a CNAME.
+func zoneCNAMEConflict(zoneName string, r dnsRecord, existing []dnsRecord) error {
+	if strings.EqualFold(r.Name, zoneName) {
+		switch r.Type {
+		case "A", "AAAA", "CNAME":
+			existing = apexAddressRecords(existing)
+		default:
+			return nil
+		}
+	}
+	return cnameConflict(r, existing)
+}
+
+// cnameConflict reports whether adding r next to existing would put a CNAME
+// at a name that has other records, which DNS does not allow.
+func cnameConflict(r dnsRecord, existing []dnsRecord) error {
+	for _, e := range existing {
+		if (e.ID != "" && e.ID == r.ID) || !strings.EqualFold(e.Name, r.Name) {
+			continue
+		}
+		if r.Type == "CNAME" || e.Type == "CNAME" {
+			return fmt.Errorf("%s record %s conflicts with the %s record -> %s: a CNAME cannot share a name with other records", r.Type, r.Name, e.Type, e.Content)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDNSRecord(t *testing.T) {
+	cases := []struct {
+		r    dnsRecord
+		want string
+	}{
+		{dnsRecord{Type: "A", Name: "example.com", Content: "1.2.3.4", Proxied: true}, ""},
+		{dnsRecord{Type: "A", Name: "example.com", Content: "2001:db8::1"}, "not an IPv4 address"},
+		{dnsRecord{Type: "A", Name: "example.com", Content: "1.2.3"}, "not an IPv4 address"},
+		{dnsRecord{Type: "AAAA", Name: "example.com", Content: "2001:db8::1"}, ""},
+		{dnsRecord{Type: "AAAA", Name: "example.com", Content: "1.2.3.4"}, "not an IPv6 address"},
+		{dnsRecord{Type: "CNAME", Name: "www.example.com", Content: "1.2.3.4"}, "must point to a hostname"},
+		{dnsRecord{Type: "CNAME", Name: "www.example.com", Content: "https://example.net/"}, "not a hostname"},
+		{dnsRecord{Type: "TXT", Name: "example.com", Content: strings.Repeat("a", maxTXTContentLength+1)}, "TXT records are limited"},
+		{dnsRecord{Type: "MX", Name: "example.com", Content: "mx.example.com", Proxied: true}, "cannot be proxied"},
+		{dnsRecord{Type: "CAA", Name: "example.com", Data: map[string]any{"tag": "issue"}}, ""},
+	}
+	for _, tc := range cases {
+		err := validateDNSRecord(tc.r)
+		if tc.want == "" {
+			if err != nil {
+				t.Fatalf("%s %q: unexpected error: %v", tc.r.Type, tc.r.Content, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s %q: expected error containing %q, got %v", tc.r.Type, tc.r.Content, tc.want, err)
+		}
+	}
+}
+
+func TestCNAMEConflict(t *testing.T) {
+	existing := []dnsRecord{
+		{ID: "1", Type: "A", Name: "www.example.com", Content: "1.2.3.4"},
+		{ID: "2", Type: "CNAME", Name: "blog.example.com", Content: "example.net"},
+	}
+	if err := cnameConflict(dnsRecord{Type: "CNAME", Name: "WWW.example.com", Content: "example.net"}, existing); err == nil {
+		t.Fatal("expected a CNAME next to an A record to conflict")
+	}
+	if err := cnameConflict(dnsRecord{Type: "TXT", Name: "blog.example.com", Content: "hello"}, existing); err == nil {
+		t.Fatal("expected a record next to a CNAME to conflict")
+	}
+	if err := cnameConflict(dnsRecord{Type: "A", Name: "www.example.com", Content: "5.6.7.8"}, existing); err != nil {
+		t.Fatalf("round-robin A records should not conflict: %v", err)
+	}
+	if err := cnameConflict(dnsRecord{ID: "2", Type: "CNAME", Name: "blog.example.com", Content: "example.org"}, existing); err != nil {
+		t.Fatalf("a record should not conflict with itself: %v", err)
+	}
+}
+
+func TestZoneCNAMEConflictAtApex(t *testing.T) {
+	cname := dnsRecord{Type: "CNAME", Name: "example.com", Content: "blog.pages.dev"}
+	existing := []dnsRecord{
+		{Type: "MX", Name: "example.com", Content: "mx.example.net"},
+		{Type: "TXT", Name: "example.com", Content: "v=spf1 -all"},
+	}
+	if err := zoneCNAMEConflict("example.com", cname, existing); err != nil {
+		t.Fatalf("a flattened apex CNAME may sit beside MX and TXT: %v", err)
+	}
+	if err := zoneCNAMEConflict("example.com", existing[0], []dnsRecord{cname}); err != nil {
+		t.Fatalf("an apex MX may sit beside a flattened CNAME: %v", err)
+	}
+	existing = append(existing, dnsRecord{Type: "A", Name: "example.com", Content: "192.0.2.1"})
+	if err := zoneCNAMEConflict("example.com", cname, existing); err == nil {
+		t.Fatal("expected an apex CNAME to clash with an apex A record")
+	}
+	www := dnsRecord{Type: "CNAME", Name: "www.example.com", Content: "example.net"}
+	if err := zoneCNAMEConflict("example.com", www, []dnsRecord{{Type: "TXT", Name: "www.example.com", Content: "x"}}); err == nil {
+		t.Fatal("below the apex a CNAME must still clash with other records")
+	}
+}
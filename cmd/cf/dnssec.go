@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type dnssecStatus struct {
+	Status          string `json:"status"`
+	Flags           int    `json:"flags,omitempty"`
+	Algorithm       string `json:"algorithm,omitempty"`
+	KeyType         string `json:"key_type,omitempty"`
+	DigestType      string `json:"digest_type,omitempty"`
+	DigestAlgorithm string `json:"digest_algorithm,omitempty"`
+	Digest          string `json:"digest,omitempty"`
+	DS              string `json:"ds,omitempty"`
+	KeyTag          int    `json:"key_tag,omitempty"`
+	PublicKey       string `json:"public_key,omitempty"`
+	ModifiedOn      string `json:"modified_on,omitempty"`
+
+	// OnCloudflareRegistrar is filled in by cf, not the API.
+	OnCloudflareRegistrar bool `json:"on_cloudflare_registrar"`
+}
+
+func runDNSSEC(args []string) error {
+	positional, flags := splitArgs(args)
+	action := "status"
+	if len(positional) > 0 {
+		action = positional[0]
+	}
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for dns dnssec: --zone")
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	path := "/zones/" + z.ID + "/dnssec"
+	var resp apiResponse
+	switch action {
+	case "enable":
+		resp, err = requestCF(http.MethodPatch, path, map[string]string{"status": "active"})
+	case "disable":
+		resp, err = requestCF(http.MethodPatch, path, map[string]string{"status": "disabled"})
+	case "status":
+		resp, err = requestCF(http.MethodGet, path, nil)
+	default:
+		return usageErrorf("usage: cf dns dnssec enable|disable|status --zone <zone>")
+	}
+	if err != nil {
+		return err
+	}
+
+	var s dnssecStatus
+	if err := json.Unmarshal(resp.Result, &s); err != nil {
+		return err
+	}
+
+	// Cloudflare Registrar publishes the DS record itself, so users only need
+	// to copy it by hand when the domain is registered elsewhere.
+	if d, err := getRegistrarDomain(z.Name); err == nil && strings.Contains(strings.ToLower(d.CurrentRegistrar), "cloudflare") {
+		s.OnCloudflareRegistrar = true
+	}
+
+	return printResult(s, func() { printDNSSECStatus(z.Name, &s) })
+}
+
+func printDNSSECStatus(zoneName string, s *dnssecStatus) {
+	fmt.Printf("DNSSEC for %s: %s\n", zoneName, s.Status)
+	if s.Status == "disabled" || s.DS == "" {
+		if s.Status == "pending-disabled" || s.Status == "disabled" {
+			fmt.Println("If a DS record is still published at your registrar, remove it to avoid resolution failures.")
+		}
+		return
+	}
+
+	fmt.Printf("  DS record:   %s\n", s.DS)
+	fmt.Printf("  key tag:     %d\n", s.KeyTag)
+	fmt.Printf("  algorithm:   %s\n", s.Algorithm)
+	fmt.Printf("  digest type: %s\n", s.DigestType)
+	fmt.Printf("  digest:      %s\n", s.Digest)
+
+	if s.OnCloudflareRegistrar {
+		fmt.Println("This domain is on Cloudflare Registrar: the DS record is added at the registry automatically.")
+	} else if s.Status == "pending" {
+		fmt.Println("Add the DS record above at your registrar to finish enabling DNSSEC.")
+	}
+}
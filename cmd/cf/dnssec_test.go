@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDNSSECEnable(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("PATCH", "/zones/z1/dnssec", dnssecStatus{Status: "pending", DS: "example.com. 3600 IN DS 2371 13 2 ABCD", KeyTag: 2371, Algorithm: "13", DigestType: "2", Digest: "ABCD"})
+	srv.Fail("GET", "/accounts/acc1/registrar/domains/example.com", 404, 1000, "domain not found")
+
+	out, err := captureStdout(t, func() error { return runDNS([]string{"dnssec", "enable", "--zone", "example.com"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body map[string]string
+	if calls := srv.Calls("PATCH", "/zones/z1/dnssec"); len(calls) != 1 {
+		t.Fatalf("expected one PATCH, got %d", len(calls))
+	} else if err := calls[0].Decode(&body); err != nil || body["status"] != "active" {
+		t.Fatalf("unexpected body %v (err=%v)", body, err)
+	}
+	for _, want := range []string{"DNSSEC for example.com: pending", "DS record:   example.com. 3600 IN DS 2371 13 2 ABCD", "Add the DS record above at your registrar"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestDNSSECStatusOnCloudflareRegistrar(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/dnssec", dnssecStatus{Status: "active", DS: "example.com. 3600 IN DS 2371 13 2 ABCD"})
+	srv.Reply("GET", "/accounts/acc1/registrar/domains/example.com", registrarDomain{Name: "example.com", CurrentRegistrar: "Cloudflare"})
+
+	out, err := captureStdout(t, func() error { return runDNS([]string{"dnssec", "--zone", "example.com"}) })
+	if err != nil || !strings.Contains(out, "added at the registry automatically") {
+		t.Fatalf("expected the Registrar note, got err=%v:\n%s", err, out)
+	}
+	if n := len(srv.Calls("PATCH", "/zones/z1/dnssec")); n != 0 {
+		t.Fatalf("expected status to only read, got %d PATCHes", n)
+	}
+}
+
+func TestDNSSECErrors(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Fail("PATCH", "/zones/z1/dnssec", 400, 1003, "DNSSEC is already being enabled")
+
+	err := runDNS([]string{"dnssec", "disable", "--zone", "example.com"})
+	if exitCode(err) != exitAPI || !strings.Contains(err.Error(), "DNSSEC is already being enabled") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+	if err := runDNS([]string{"dnssec", "rotate", "--zone", "example.com"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error for an unknown action, got %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"cf/pkg/cfapi"
+)
+
+// doctorCheck is one line of cf doctor's report. Status is "ok", "warn" or
+// "fail"; Fix says what to do about anything other than ok.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+type doctorReport struct {
+	Checks []doctorCheck `json:"checks"`
+}
+
+func (r *doctorReport) add(name, status, detail, fix string) {
+	r.Checks = append(r.Checks, doctorCheck{name, status, detail, fix})
+}
+
+// maxClockSkew is how far the local clock may drift from Cloudflare's before
+// doctor warns; token expiry and signed URLs depend on it.
+const maxClockSkew = 30 * time.Second
+
+// runDoctor checks everything a command needs before it can reach the API,
+// reporting each problem with a fix instead of stopping at the first.
+func runDoctor() error {
+	r := &doctorReport{}
+	doctorConfig(r)
+	doctorAPI(r)
+	creds, ok := doctorCredentials(r)
+	if ok {
+		doctorToken(r, creds)
+		if accountID, err := configuredAccountID(); err != nil {
+			r.add("Account", "fail", err.Error(), "run cf accounts use <id|name>, or set CF_ACCOUNT_ID (cf accounts list shows them)")
+		} else {
+			r.add("Account", "ok", accountID, "")
+		}
+	}
+	doctorWrangler(r)
+
+	if err := printResult(r, func() { printDoctorReport(r) }); err != nil {
+		return err
+	}
+	failed := 0
+	for _, c := range r.Checks {
+		if c.Status == "fail" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d doctor check(s) failed", failed)
+	}
+	return nil
+}
+
+func doctorConfig(r *doctorReport) {
+	path := configPath()
+	info, err := os.Stat(path)
+	if err != nil {
+		r.add("Config file", "ok", "none at "+path+" (optional)", "")
+	} else if _, err := loadConfig(); err != nil {
+		r.add("Config file", "fail", err.Error(), "fix the TOML syntax in "+path)
+		return
+	} else if info.Mode().Perm()&0o077 != 0 && configHasToken() {
+		r.add("Config file", "warn", fmt.Sprintf("%s holds an API token and is readable by others (mode %o)", path, info.Mode().Perm()), "chmod 600 "+path)
+	} else {
+		r.add("Config file", "ok", path, "")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return
+	}
+	if cfg.DefaultProfile != "" {
+		if _, ok := cfg.Profiles[cfg.DefaultProfile]; !ok {
+			r.add("Default profile", "fail", fmt.Sprintf("default_profile %q is not defined", cfg.DefaultProfile), "add [profiles."+cfg.DefaultProfile+"] to "+path+" or change default_profile")
+		}
+	}
+	for name, p := range cfg.Profiles {
+		if p.APITokenEnv != "" && os.Getenv(p.APITokenEnv) == "" && p.APIToken == "" {
+			r.add("Profile "+name, "warn", "reads its token from $"+p.APITokenEnv+", which is not set", "export "+p.APITokenEnv+" before using --profile "+name)
+		}
+	}
+	if _, _, _, err := activeProfile(); err != nil {
+		r.add("Profile", "fail", err.Error(), "pass an existing --profile or unset CF_PROFILE")
+	}
+
+	a, b := strings.TrimSpace(os.Getenv("CF_API_TOKEN")), strings.TrimSpace(os.Getenv("CLOUDFLARE_API_TOKEN"))
+	if a != "" && b != "" && a != b {
+		r.add("Environment", "warn", "CF_API_TOKEN and CLOUDFLARE_API_TOKEN are both set and differ; CF_API_TOKEN wins", "unset the one you do not mean to use")
+	}
+}
+
+func configHasToken() bool {
+	cfg, err := loadConfig()
+	if err != nil {
+		return false
+	}
+	for _, p := range cfg.Profiles {
+		if p.APIToken != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// doctorAPI checks that the API answers and compares its clock with ours.
+// /ips needs no credentials, so this works before any are set up.
+func doctorAPI(r *doctorReport) {
+	base := apiBaseURL
+	if base == "" {
+		base = cfapi.DefaultBaseURL
+	}
+	client := httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx, cancel := context.WithTimeout(rootCtx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/ips", nil)
+	if err != nil {
+		r.add("API reachability", "fail", err.Error(), "")
+		return
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		r.add("API reachability", "fail", err.Error(), "check network access to "+base+" (proxies, firewalls, DNS)")
+		return
+	}
+	resp.Body.Close()
+	elapsed := time.Since(start)
+	if resp.StatusCode >= 500 {
+		r.add("API reachability", "fail", fmt.Sprintf("HTTP %d from %s", resp.StatusCode, base), "check https://www.cloudflarestatus.com and retry")
+		return
+	}
+	r.add("API reachability", "ok", fmt.Sprintf("%s answered in %s", base, elapsed.Round(time.Millisecond)), "")
+
+	server, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+	// Date has one-second resolution, so allow for that and the round trip.
+	skew := time.Until(server).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew+elapsed {
+		r.add("Clock", "warn", fmt.Sprintf("local clock is %s off Cloudflare's", skew), "enable time sync (NTP) on this machine")
+	} else {
+		r.add("Clock", "ok", "in sync with Cloudflare", "")
+	}
+}
+
+func doctorCredentials(r *doctorReport) (credentials, bool) {
+	creds, err := resolveCredentials()
+	if err != nil {
+		r.add("Credentials", "fail", err.Error(), "run cf login, or set CF_API_TOKEN to an API token from https://dash.cloudflare.com/profile/api-tokens")
+		return creds, false
+	}
+	if creds.APIKey != "" {
+		r.add("Credentials", "warn", authSource+" has full account access", "create a scoped API token and set CF_API_TOKEN instead")
+	} else {
+		r.add("Credentials", "ok", authSource, "")
+	}
+	return creds, true
+}
+
+func doctorToken(r *doctorReport, creds credentials) {
+	if creds.APIKey != "" {
+		if _, err := requestCFWithCredentials(creds, http.MethodGet, "/user", nil); err != nil {
+			r.add("API key", "fail", err.Error(), "check CF_API_KEY and CF_API_EMAIL")
+		} else {
+			r.add("API key", "ok", "valid for "+creds.Email, "")
+		}
+		return
+	}
+
+	v, err := verifyToken(creds.Token)
+	switch {
+	case err != nil && authSource == "wrangler":
+		// Wrangler OAuth tokens are not API tokens and cannot be verified.
+		r.add("Token", "warn", "Wrangler login tokens cannot be verified", "create an API token and run cf login for predictable permissions")
+		return
+	case err != nil:
+		r.add("Token", "fail", err.Error(), "create a new token at https://dash.cloudflare.com/profile/api-tokens and run cf login")
+		return
+	case v.Status != "active":
+		r.add("Token", "fail", "status "+v.Status, "reactivate or replace the token in the dashboard")
+		return
+	}
+	detail, status, fix := "active", "ok", ""
+	if v.ExpiresOn != "" {
+		detail += ", expires " + v.ExpiresOn
+		if t, err := time.Parse(time.RFC3339, v.ExpiresOn); err == nil && time.Until(t) < 7*24*time.Hour {
+			status, fix = "warn", "roll the token before it expires: cf tokens roll <name>"
+		}
+	}
+	r.add("Token", status, detail, fix)
+
+	policies, err := tokenPolicies(creds.Token, v.ID)
+	if err != nil {
+		r.add("Token permissions", "warn", "cannot read the token's own policies", "grant the token \"API Tokens Read\" so cf whoami and cf doctor can show its permissions")
+		return
+	}
+	var groups []string
+	for _, p := range policies {
+		for _, g := range p.PermissionGroups {
+			groups = append(groups, g.Name)
+		}
+	}
+	joined := strings.Join(groups, ", ")
+	if !strings.Contains(joined, "Zone") || !strings.Contains(joined, "DNS") {
+		r.add("Token permissions", "warn", orNone(joined), "most commands need Zone Read and DNS Write; edit the token in the dashboard")
+		return
+	}
+	r.add("Token permissions", "ok", joined, "")
+}
+
+func doctorWrangler(r *doctorReport) {
+	out, err := cmdRunner("wrangler", "--version")
+	if err != nil {
+		r.add("Wrangler", "warn", "not available", "optional: install it (npm i -g wrangler) to use Wrangler's login as a credential fallback")
+		return
+	}
+	r.add("Wrangler", "ok", strings.TrimSpace(string(out)), "")
+}
+
+func printDoctorReport(r *doctorReport) {
+	for _, c := range r.Checks {
+		label := map[string]string{"ok": " ok ", "warn": "WARN", "fail": "FAIL"}[c.Status]
+		fmt.Printf("[%s] %s", colorStatus(c.Status, label), c.Name)
+		if c.Detail != "" {
+			fmt.Printf(": %s", c.Detail)
+		}
+		fmt.Println()
+		if c.Fix != "" {
+			fmt.Printf("       fix: %s\n", c.Fix)
+		}
+	}
+}
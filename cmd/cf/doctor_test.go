@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDoctor(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/ips", map[string]any{"ipv4_cidrs": []string{"173.245.48.0/20"}})
+	srv.Reply("GET", "/user/tokens/verify", tokenVerification{ID: "tok1", Status: "active"})
+	srv.Reply("GET", "/user/tokens/tok1", map[string]any{"policies": []map[string]any{
+		{"effect": "allow", "permission_groups": []map[string]string{{"id": "1", "name": "Zone Read"}}},
+	}})
+	origRunner := cmdRunner
+	t.Cleanup(func() { cmdRunner = origRunner })
+	cmdRunner = func(string, ...string) ([]byte, error) { return nil, errors.New("not found") }
+
+	out, err := captureStdout(t, runDoctor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"[ ok ] API reachability",
+		"[ ok ] Clock",
+		"[ ok ] Credentials: env CF_API_TOKEN",
+		"[ ok ] Token: active",
+		"[WARN] Token permissions: Zone Read",
+		"fix: most commands need Zone Read and DNS Write",
+		"[ ok ] Account: acc1",
+		"[WARN] Wrangler: not available",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestDoctorReportsFailures(t *testing.T) {
+	srv := useFakeAPI(t)
+	useTestConfig(t, "default_profile = \"work\"\n")
+	t.Setenv("CF_API_TOKEN", "")
+	t.Setenv("CF_ACCOUNT_ID", "")
+	srv.Reply("GET", "/ips", map[string]any{})
+	origRunner := cmdRunner
+	t.Cleanup(func() { cmdRunner = origRunner })
+	cmdRunner = func(string, ...string) ([]byte, error) { return nil, errors.New("not found") }
+
+	out, err := captureStdout(t, runDoctor)
+	if err == nil {
+		t.Fatalf("expected doctor to fail:\n%s", out)
+	}
+	if !strings.Contains(out, `[FAIL] Default profile: default_profile "work" is not defined`) || !strings.Contains(out, "[FAIL] Credentials") {
+		t.Fatalf("unexpected report:\n%s", out)
+	}
+}
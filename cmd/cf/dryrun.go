@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// dryRun is set by the global --dry-run flag. Mutating requests are printed
+// instead of sent; reads still go out so commands can plan against live
+// state.
+var dryRun bool
+
+// dryRunSkipped counts the requests that were printed but not sent.
+// dryRunMu guards it and keeps each printed request together when batch
+// workers skip requests concurrently.
+var (
+	dryRunMu      sync.Mutex
+	dryRunSkipped int
+)
+
+// skipForDryRun prints the request it stands in for and reports whether the
+// caller should skip sending it.
+func skipForDryRun(method, path, contentType string, payload []byte) bool {
+	if !dryRun || method == http.MethodGet || method == http.MethodHead || isReadOnlyPost(path) {
+		return false
+	}
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+	dryRunSkipped++
+	fmt.Fprintf(os.Stderr, "[dry-run] %s %s\n", method, path)
+	if len(payload) > 0 {
+		fmt.Fprintln(os.Stderr, describeDryRunBody(contentType, payload))
+	}
+	return true
+}
+
+// isReadOnlyPost reports endpoints that use POST to read, such as GraphQL
+// analytics queries.
+func isReadOnlyPost(path string) bool {
+	return path == "/graphql"
+}
+
+func describeDryRunBody(contentType string, payload []byte) string {
+	if contentType == "" || strings.HasPrefix(contentType, "application/json") {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, payload, "  ", "  "); err == nil {
+			return "  " + buf.String()
+		}
+	}
+	if i := strings.Index(contentType, ";"); i != -1 {
+		contentType = contentType[:i]
+	}
+	return fmt.Sprintf("  <%d bytes of %s>", len(payload), contentType)
+}
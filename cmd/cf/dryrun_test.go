@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSkipForDryRun(t *testing.T) {
+	t.Cleanup(func() { dryRun, dryRunSkipped = false, 0 })
+
+	if skipForDryRun(http.MethodPost, "/zones/abc/dns_records", "application/json", []byte(`{}`)) {
+		t.Fatal("requests should be sent when --dry-run is off")
+	}
+
+	dryRun = true
+	if skipForDryRun(http.MethodGet, "/zones", "", nil) {
+		t.Fatal("reads should still be sent in dry-run mode")
+	}
+	if skipForDryRun(http.MethodPost, "/graphql", "application/json", []byte(`{}`)) {
+		t.Fatal("GraphQL queries should still be sent in dry-run mode")
+	}
+	if !skipForDryRun(http.MethodDelete, "/zones/abc/dns_records/1", "application/json", nil) {
+		t.Fatal("deletes should be skipped in dry-run mode")
+	}
+	if dryRunSkipped != 1 {
+		t.Fatalf("expected one skipped request, got %d", dryRunSkipped)
+	}
+}
+
+func TestDescribeDryRunBody(t *testing.T) {
+	got := describeDryRunBody("application/json", []byte(`{"type":"A","ttl":1}`))
+	if !strings.Contains(got, "\n    \"type\": \"A\"") {
+		t.Fatalf("expected indented JSON, got %q", got)
+	}
+	got = describeDryRunBody("multipart/form-data; boundary=x", make([]byte, 12))
+	if got != "  <12 bytes of multipart/form-data>" {
+		t.Fatalf("unexpected multipart description: %q", got)
+	}
+}
+
+// Batch workers skip requests concurrently; run with -race.
+func TestSkipForDryRunConcurrent(t *testing.T) {
+	t.Cleanup(func() { dryRun, dryRunSkipped = false, 0 })
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	stderr := os.Stderr
+	os.Stderr = devNull
+	defer func() { os.Stderr = stderr }()
+
+	dryRun = true
+	runBatch("purge", 50, 8, func(int) error {
+		skipForDryRun(http.MethodPost, "/zones/abc/purge_cache", "application/json", []byte(`{"purge_everything":true}`))
+		return nil
+	})
+	if dryRunSkipped != 50 {
+		t.Fatalf("expected 50 skipped requests, got %d", dryRunSkipped)
+	}
+}
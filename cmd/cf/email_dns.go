@@ -0,0 +1,394 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type emailProvider struct {
+	Key        string
+	Name       string
+	Aliases    []string
+	SPFInclude string
+	MX         func(domain string) []mxHost
+	// DKIMCNAMEs returns selector -> target for providers that host the DKIM
+	// key; nil means the key is a TXT value copied from the provider's admin.
+	DKIMCNAMEs    func(domain, tenant string) map[string]string
+	DKIMSelector  string
+	DKIMAdminHint string
+}
+
+var emailProviders = []emailProvider{
+	{
+		Key:        "google",
+		Name:       "Google Workspace",
+		Aliases:    []string{"google-workspace", "gmail"},
+		SPFInclude: "_spf.google.com",
+		MX: func(string) []mxHost {
+			return []mxHost{{"smtp.google.com", 1}}
+		},
+		DKIMSelector:  "google",
+		DKIMAdminHint: "Admin console > Apps > Google Workspace > Gmail > Authenticate email",
+	},
+	{
+		Key:        "microsoft",
+		Name:       "Microsoft 365",
+		Aliases:    []string{"microsoft-365", "office365", "outlook"},
+		SPFInclude: "spf.protection.outlook.com",
+		MX: func(domain string) []mxHost {
+			return []mxHost{{strings.ReplaceAll(domain, ".", "-") + ".mail.protection.outlook.com", 0}}
+		},
+		DKIMCNAMEs: func(domain, tenant string) map[string]string {
+			d := strings.ReplaceAll(domain, ".", "-")
+			return map[string]string{
+				"selector1": "selector1-" + d + "._domainkey." + tenant + ".onmicrosoft.com",
+				"selector2": "selector2-" + d + "._domainkey." + tenant + ".onmicrosoft.com",
+			}
+		},
+	},
+	{
+		Key:        "fastmail",
+		Name:       "Fastmail",
+		SPFInclude: "spf.messagingengine.com",
+		MX: func(string) []mxHost {
+			return []mxHost{{"in1-smtp.messagingengine.com", 10}, {"in2-smtp.messagingengine.com", 20}}
+		},
+		DKIMCNAMEs: func(domain, _ string) map[string]string {
+			return map[string]string{
+				"fm1": "fm1." + domain + ".dkim.fmhosted.com",
+				"fm2": "fm2." + domain + ".dkim.fmhosted.com",
+				"fm3": "fm3." + domain + ".dkim.fmhosted.com",
+			}
+		},
+	},
+}
+
+func findEmailProvider(key string) (*emailProvider, error) {
+	keys := make([]string, 0, len(emailProviders))
+	for i, p := range emailProviders {
+		if p.Key == strings.ToLower(key) || slices.Contains(p.Aliases, strings.ToLower(key)) {
+			return &emailProviders[i], nil
+		}
+		keys = append(keys, p.Key)
+	}
+	return nil, fmt.Errorf("unknown provider %q (want one of: %s)", key, strings.Join(keys, ", "))
+}
+
+// mxHost is one of a provider's inbound mail servers.
+type mxHost struct {
+	Host     string
+	Priority int
+}
+
+type emailSetupOptions struct {
+	Provider    *emailProvider
+	DMARCPolicy string
+	DMARCReport string
+	DKIM        bool
+	DKIMValue   string
+	Tenant      string
+}
+
+func runEmailSetup(flags map[string]string) error {
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for dns email-setup: --zone")
+	}
+	interactive := !machineOutput() && isTerminal(os.Stdin)
+	if !interactive && flags["provider"] == "" {
+		return errors.New("dns email-setup needs --provider when not run interactively")
+	}
+	if err := requireConfirmable(flags, "dns email-setup"); err != nil {
+		return err
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	opts, err := emailSetupOptionsFrom(reader, flags, interactive)
+	if err != nil {
+		return err
+	}
+
+	live, err := listDNSRecords(z.ID)
+	if err != nil {
+		return err
+	}
+	changes, warnings := planEmailRecords(z.Name, opts, live)
+	for _, w := range warnings {
+		infof("Warning: %s\n", w)
+	}
+
+	result := dnsSyncResult{Zone: z.Name, Changes: changes, Unmanaged: []dnsRecord{}}
+	if !machineOutput() {
+		printDNSSyncPlan(z.Name, changes, nil)
+	}
+	if len(changes) == 0 || dryRun {
+		return printResult(result, func() {})
+	}
+	ok, err := confirmWith(reader, flags, "dns email-setup", "Apply these changes?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted. No changes applied.")
+		return nil
+	}
+	if err := applyDNSSync(z.ID, changes, defaultBulkConcurrency); err != nil {
+		return err
+	}
+	result.Applied = true
+	return printResult(result, func() {})
+}
+
+func emailSetupOptionsFrom(reader *bufio.Reader, flags map[string]string, interactive bool) (*emailSetupOptions, error) {
+	opts := &emailSetupOptions{
+		DMARCPolicy: flags["dmarc-policy"],
+		DMARCReport: flags["dmarc-rua"],
+		DKIMValue:   flags["dkim-value"],
+		Tenant:      flags["tenant"],
+	}
+
+	key := flags["provider"]
+	if key == "" {
+		fmt.Println("Mail providers:")
+		for _, p := range emailProviders {
+			fmt.Printf("  %-10s %s\n", p.Key, p.Name)
+		}
+		var err error
+		if key, err = prompt(reader, "Provider", "google"); err != nil {
+			return nil, err
+		}
+	}
+	p, err := findEmailProvider(key)
+	if err != nil {
+		return nil, err
+	}
+	opts.Provider = p
+
+	if opts.DMARCPolicy == "" {
+		opts.DMARCPolicy = "none"
+		if interactive {
+			fmt.Println("DMARC policy: none (monitor only), quarantine (send failures to spam), reject (block failures).")
+			if opts.DMARCPolicy, err = prompt(reader, "DMARC policy", "none"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !slices.Contains([]string{"none", "quarantine", "reject"}, opts.DMARCPolicy) {
+		return nil, fmt.Errorf("invalid DMARC policy %q (want none, quarantine or reject)", opts.DMARCPolicy)
+	}
+	if opts.DMARCReport == "" && interactive {
+		if opts.DMARCReport, err = prompt(reader, "Send DMARC aggregate reports to (blank for none)", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	opts.DKIM = parseBoolWithDefault(flags["dkim"], opts.DKIMValue != "" || opts.Tenant != "")
+	if !opts.DKIM && interactive {
+		if opts.DKIM, err = promptYesNo(reader, "Add DKIM records too?", true); err != nil {
+			return nil, err
+		}
+	}
+	if opts.DKIM {
+		switch {
+		case p.DKIMCNAMEs == nil && opts.DKIMValue == "":
+			if !interactive {
+				return nil, usageErrorf("--dkim-value is required for %s DKIM", p.Name)
+			}
+			fmt.Printf("Generate the DKIM key in %s, then paste the TXT value.\n", p.DKIMAdminHint)
+			if opts.DKIMValue, err = prompt(reader, "DKIM TXT value (v=DKIM1; k=rsa; p=...)", ""); err != nil {
+				return nil, err
+			}
+			if opts.DKIMValue == "" {
+				opts.DKIM = false
+			}
+		case p.Key == "microsoft" && opts.Tenant == "":
+			if !interactive {
+				return nil, usageErrorf("--tenant (the <tenant>.onmicrosoft.com name) is required for Microsoft 365 DKIM")
+			}
+			if opts.Tenant, err = prompt(reader, "Microsoft 365 tenant (the part before .onmicrosoft.com)", ""); err != nil {
+				return nil, err
+			}
+			if opts.Tenant == "" {
+				opts.DKIM = false
+			}
+		}
+	}
+	return opts, nil
+}
+
+func unquoteTXT(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = strings.ReplaceAll(s[1:len(s)-1], `" "`, "")
+	}
+	return s
+}
+
+func isSPF(r dnsRecord) bool {
+	return r.Type == "TXT" && strings.HasPrefix(strings.ToLower(unquoteTXT(r.Content)), "v=spf1")
+}
+
+// mergeSPF combines one or more SPF records and adds include:<domain>,
+// keeping mechanisms in order and the strictest "all" qualifier found.
+func mergeSPF(records []string, include string) string {
+	var mechanisms []string
+	all := ""
+	for _, rec := range records {
+		for _, term := range strings.Fields(unquoteTXT(rec))[1:] {
+			lower := strings.ToLower(term)
+			if strings.HasSuffix(lower, "all") && len(lower) <= 4 {
+				if all == "" || spfAllRank(lower) > spfAllRank(all) {
+					all = lower
+				}
+				continue
+			}
+			if !slices.Contains(mechanisms, lower) {
+				mechanisms = append(mechanisms, lower)
+			}
+		}
+	}
+	if inc := "include:" + include; !slices.Contains(mechanisms, inc) {
+		mechanisms = append(mechanisms, inc)
+	}
+	if all == "" {
+		all = "~all"
+	}
+	return strings.Join(append(append([]string{"v=spf1"}, mechanisms...), all), " ")
+}
+
+func spfAllRank(q string) int {
+	switch q {
+	case "-all":
+		return 3
+	case "~all":
+		return 2
+	case "?all":
+		return 1
+	}
+	return 0
+}
+
+func dmarcRecord(policy, report string) string {
+	v := "v=DMARC1; p=" + policy
+	if report != "" {
+		if !strings.HasPrefix(report, "mailto:") {
+			report = "mailto:" + report
+		}
+		v += "; rua=" + report
+	}
+	return v
+}
+
+// planEmailRecords diffs the SPF, DMARC and DKIM records implied by opts
+// against live records. Multiple SPF records at the apex are invalid, so
+// they are merged into one and the rest deleted.
+func planEmailRecords(zoneName string, opts *emailSetupOptions, live []dnsRecord) ([]dnsChange, []string) {
+	var changes []dnsChange
+	var warnings []string
+	apex := qualifyRecordName("@", zoneName)
+	dmarcName := qualifyRecordName("_dmarc", zoneName)
+
+	changes, warnings = planSPF(apex, opts.Provider.SPFInclude, live)
+
+	var dmarc []dnsRecord
+	for _, r := range live {
+		if strings.EqualFold(r.Name, dmarcName) && r.Type == "TXT" && strings.HasPrefix(strings.ToUpper(unquoteTXT(r.Content)), "V=DMARC1") {
+			dmarc = append(dmarc, r)
+		}
+	}
+
+	wantDMARC := dmarcRecord(opts.DMARCPolicy, opts.DMARCReport)
+	if len(dmarc) > 1 {
+		warnings = append(warnings, fmt.Sprintf("%d DMARC records found at %s; keeping one", len(dmarc), dmarcName))
+	}
+	changes = append(changes, upsertTXT(dmarcName, wantDMARC, dmarc)...)
+
+	if opts.DKIM {
+		p := opts.Provider
+		if p.DKIMCNAMEs == nil {
+			name := qualifyRecordName(p.DKIMSelector+"._domainkey", zoneName)
+			changes = append(changes, upsertRecord(dnsRecord{Type: "TXT", Name: name, Content: opts.DKIMValue, TTL: 1}, live)...)
+		} else {
+			targets := p.DKIMCNAMEs(zoneName, opts.Tenant)
+			selectors := make([]string, 0, len(targets))
+			for sel := range targets {
+				selectors = append(selectors, sel)
+			}
+			sort.Strings(selectors)
+			for _, sel := range selectors {
+				name := qualifyRecordName(sel+"._domainkey", zoneName)
+				changes = append(changes, upsertRecord(dnsRecord{Type: "CNAME", Name: name, Content: targets[sel], TTL: 1}, live)...)
+			}
+		}
+	}
+	return changes, warnings
+}
+
+// planSPF merges include into the SPF record at apex, folding duplicate SPF
+// records into one.
+func planSPF(apex, include string, live []dnsRecord) ([]dnsChange, []string) {
+	var spf []dnsRecord
+	for _, r := range live {
+		if strings.EqualFold(r.Name, apex) && isSPF(r) {
+			spf = append(spf, r)
+		}
+	}
+	contents := make([]string, len(spf))
+	for i, r := range spf {
+		contents[i] = r.Content
+	}
+	var warnings []string
+	if len(spf) > 1 {
+		warnings = append(warnings, fmt.Sprintf("%d SPF records found at %s; receivers treat that as a permanent error, merging them into one", len(spf), apex))
+	}
+	return upsertTXT(apex, mergeSPF(contents, include), spf), warnings
+}
+
+// upsertTXT keeps the first existing record (updating it if needed) and
+// deletes any duplicates.
+func upsertTXT(name, content string, existing []dnsRecord) []dnsChange {
+	want := &dnsRecord{Type: "TXT", Name: name, Content: content, TTL: 1}
+	if len(existing) == 0 {
+		return []dnsChange{{Action: "create", After: want}}
+	}
+	var changes []dnsChange
+	first := existing[0]
+	if unquoteTXT(first.Content) != content {
+		want.ID, want.TTL = first.ID, first.TTL
+		changes = append(changes, dnsChange{Action: "update", Before: &first, After: want})
+	}
+	for i := range existing[1:] {
+		changes = append(changes, dnsChange{Action: "delete", Before: &existing[i+1]})
+	}
+	return changes
+}
+
+// upsertRecord creates want, or updates the record with the same name when
+// its type or content differ.
+func upsertRecord(want dnsRecord, live []dnsRecord) []dnsChange {
+	for _, r := range live {
+		if !strings.EqualFold(r.Name, want.Name) {
+			continue
+		}
+		if r.Type == want.Type && strings.TrimSuffix(unquoteTXT(r.Content), ".") == want.Content {
+			return nil
+		}
+		before := r
+		if r.Type != want.Type {
+			return []dnsChange{{Action: "delete", Before: &before}, {Action: "create", After: &want}}
+		}
+		want.ID = r.ID
+		return []dnsChange{{Action: "update", Before: &before, After: &want}}
+	}
+	return []dnsChange{{Action: "create", After: &want}}
+}
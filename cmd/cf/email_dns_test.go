@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+func TestMergeSPF(t *testing.T) {
+	cases := []struct {
+		in   []string
+		want string
+	}{
+		{nil, "v=spf1 include:_spf.google.com ~all"},
+		{[]string{`"v=spf1 mx -all"`}, "v=spf1 mx include:_spf.google.com -all"},
+		{[]string{"v=spf1 include:_spf.google.com ~all"}, "v=spf1 include:_spf.google.com ~all"},
+		{[]string{"v=spf1 ip4:192.0.2.1 ?all", "v=spf1 include:mailgun.org ~all"}, "v=spf1 ip4:192.0.2.1 include:mailgun.org include:_spf.google.com ~all"},
+	}
+	for _, tc := range cases {
+		if got := mergeSPF(tc.in, "_spf.google.com"); got != tc.want {
+			t.Fatalf("mergeSPF(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestPlanEmailRecords(t *testing.T) {
+	p, err := findEmailProvider("fastmail")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	live := []dnsRecord{
+		{ID: "1", Type: "TXT", Name: "example.com", Content: "v=spf1 mx ~all"},
+		{ID: "2", Type: "TXT", Name: "example.com", Content: "v=spf1 include:mailgun.org ~all"},
+		{ID: "3", Type: "TXT", Name: "example.com", Content: "google-site-verification=abc"},
+		{ID: "4", Type: "CNAME", Name: "fm1._domainkey.example.com", Content: "fm1.example.com.dkim.fmhosted.com"},
+	}
+	opts := &emailSetupOptions{Provider: p, DMARCPolicy: "none", DKIM: true}
+	changes, warnings := planEmailRecords("example.com", opts, live)
+	if len(warnings) != 1 {
+		t.Fatalf("expected a multiple-SPF warning, got %v", warnings)
+	}
+
+	var got []string
+	for _, c := range changes {
+		switch c.Action {
+		case "delete":
+			got = append(got, "delete "+c.Before.ID)
+		default:
+			got = append(got, c.Action+" "+c.After.Name+" "+c.After.Content)
+		}
+	}
+	want := []string{
+		"update example.com v=spf1 mx include:mailgun.org include:spf.messagingengine.com ~all",
+		"delete 2",
+		"create _dmarc.example.com v=DMARC1; p=none",
+		"create fm2._domainkey.example.com fm2.example.com.dkim.fmhosted.com",
+		"create fm3._domainkey.example.com fm3.example.com.dkim.fmhosted.com",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected plan:\n%v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("change %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type emailRoutingSettings struct {
+	Enabled    bool   `json:"enabled"`
+	Name       string `json:"name"`
+	Status     string `json:"status,omitempty"`
+	SkipWizard bool   `json:"skip_wizard,omitempty"`
+	ModifiedOn string `json:"modified,omitempty"`
+}
+
+type emailAddress struct {
+	ID       string `json:"id"`
+	Email    string `json:"email"`
+	Verified string `json:"verified,omitempty"`
+	Created  string `json:"created,omitempty"`
+}
+
+type emailRule struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Enabled  bool   `json:"enabled"`
+	Priority int    `json:"priority,omitempty"`
+	Matchers []struct {
+		Type  string `json:"type"`
+		Field string `json:"field,omitempty"`
+		Value string `json:"value,omitempty"`
+	} `json:"matchers"`
+	Actions []struct {
+		Type  string   `json:"type"`
+		Value []string `json:"value,omitempty"`
+	} `json:"actions"`
+}
+
+func (r emailRule) summary() (match, forward string) {
+	match = "*"
+	for _, m := range r.Matchers {
+		if m.Value != "" {
+			match = m.Value
+		}
+	}
+	var targets []string
+	for _, a := range r.Actions {
+		if len(a.Value) > 0 {
+			targets = append(targets, a.Value...)
+		} else {
+			targets = append(targets, a.Type)
+		}
+	}
+	return match, strings.Join(targets, ", ")
+}
+
+func runEmailRouting(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf email-routing enable|status|addresses|rules")
+	}
+	sub := args[0]
+	positional, flags := splitArgs(args[1:])
+
+	if sub == "addresses" {
+		action := "list"
+		if len(positional) > 0 {
+			action, positional = positional[0], positional[1:]
+		}
+		return runEmailAddresses(action, positional, flags)
+	}
+
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for email-routing %s: --zone", sub)
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "status":
+		s, err := getEmailRouting(z.ID)
+		if err != nil {
+			return err
+		}
+		return printResult(s, func() {
+			fmt.Printf("Email Routing for %s: enabled=%t status=%s\n", z.Name, s.Enabled, s.Status)
+		})
+	case "enable":
+		s, err := enableEmailRouting(z)
+		if err != nil {
+			return err
+		}
+		return printResult(s, func() {
+			fmt.Printf("Email Routing enabled for %s (status=%s)\n", z.Name, s.Status)
+		})
+	case "rules":
+		action := "list"
+		if len(positional) > 0 {
+			action, positional = positional[0], positional[1:]
+		}
+		return runEmailRules(z, action, positional, flags)
+	}
+	return errors.New("unknown email-routing command. run: cf help")
+}
+
+func getEmailRouting(zoneID string) (*emailRoutingSettings, error) {
+	resp, err := requestCF(http.MethodGet, "/zones/"+zoneID+"/email/routing", nil)
+	if err != nil {
+		return nil, err
+	}
+	var s emailRoutingSettings
+	if err := json.Unmarshal(resp.Result, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// enableEmailRouting creates the MX and SPF records Email Routing needs
+// (skipping any that already exist) and then turns it on.
+func enableEmailRouting(z *zone) (*emailRoutingSettings, error) {
+	resp, err := requestCF(http.MethodGet, "/zones/"+z.ID+"/email/routing/dns", nil)
+	if err != nil {
+		return nil, err
+	}
+	var required []dnsRecord
+	if err := json.Unmarshal(resp.Result, &required); err != nil {
+		return nil, err
+	}
+
+	live, err := listDNSRecords(z.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range missingDNSRecords(required, live) {
+		created, err := createDNSRecord(z.ID, dnsRecord{Type: r.Type, Name: r.Name, Content: r.Content, TTL: 1, Priority: r.Priority})
+		if err != nil {
+			return nil, fmt.Errorf("could not create %s record %s: %w", r.Type, r.Name, err)
+		}
+		infof("DNS record created: %s %s -> %s (id=%s)\n", created.Type, created.Name, created.Content, created.ID)
+	}
+
+	resp, err = requestCF(http.MethodPost, "/zones/"+z.ID+"/email/routing/enable", map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+	var s emailRoutingSettings
+	if err := json.Unmarshal(resp.Result, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// missingDNSRecords returns the records in want that have no live record
+// with the same type, name and content.
+func missingDNSRecords(want, live []dnsRecord) []dnsRecord {
+	var out []dnsRecord
+	for _, w := range want {
+		found := false
+		for _, l := range live {
+			if strings.EqualFold(w.Type, l.Type) && strings.EqualFold(w.Name, l.Name) && strings.EqualFold(strings.TrimSuffix(w.Content, "."), strings.TrimSuffix(l.Content, ".")) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func emailAddressesPath() (string, error) {
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return "", err
+	}
+	return "/accounts/" + accountID + "/email/routing/addresses", nil
+}
+
+func runEmailAddresses(action string, positional []string, flags map[string]string) error {
+	path, err := emailAddressesPath()
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "list":
+		addresses, err := listAll[emailAddress](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"EMAIL", "VERIFIED", "ID"}}
+		for _, a := range addresses {
+			t.Rows = append(t.Rows, []string{a.Email, a.Verified, a.ID})
+		}
+		return printList(addresses, t, func() {
+			for _, a := range addresses {
+				state := "verified"
+				if a.Verified == "" {
+					state = "pending verification"
+				}
+				fmt.Printf("%s\t%s\n", a.Email, state)
+			}
+		})
+	case "add":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf email-routing addresses add <email>")
+		}
+		a, err := addEmailAddress(path, positional[0])
+		if err != nil {
+			return err
+		}
+		return printResult(a, func() {
+			fmt.Printf("Destination address added: %s\n", a.Email)
+			fmt.Println("Cloudflare sent a verification email; click the link in it, then run: cf email-routing addresses verify " + a.Email)
+		})
+	case "verify":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf email-routing addresses verify <email> [--wait]")
+		}
+		// Verification happens by clicking the emailed link; the API can only
+		// report whether that has happened.
+		timeout, err := parseDurationWithDefault(flags["timeout"], 10*time.Minute)
+		if err != nil {
+			return usageErrorf("invalid --timeout: %w", err)
+		}
+		a, err := waitEmailVerified(path, positional[0], parseBoolWithDefault(flags["wait"], false), timeout)
+		if err != nil {
+			return err
+		}
+		return printResult(a, func() {
+			if a.Verified == "" {
+				fmt.Printf("%s is not verified yet. Click the link in the verification email (or pass --wait).\n", a.Email)
+				return
+			}
+			fmt.Printf("%s verified at %s\n", a.Email, a.Verified)
+		})
+	}
+	return usageErrorf("usage: cf email-routing addresses list|add|verify")
+}
+
+func addEmailAddress(path, email string) (*emailAddress, error) {
+	resp, err := requestCF(http.MethodPost, path, map[string]string{"email": email})
+	if err != nil {
+		return nil, err
+	}
+	var a emailAddress
+	if err := json.Unmarshal(resp.Result, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func findEmailAddress(path, email string) (*emailAddress, error) {
+	addresses, err := listAll[emailAddress](path, 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addresses {
+		if strings.EqualFold(a.Email, email) {
+			return &a, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not a destination address. run: cf email-routing addresses add %s", email, email)
+}
+
+func waitEmailVerified(path, email string, wait bool, timeout time.Duration) (*emailAddress, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		a, err := findEmailAddress(path, email)
+		if err != nil {
+			return nil, err
+		}
+		if !wait || a.Verified != "" {
+			return a, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s not verified after %s", email, timeout)
+		}
+		if err := sleep(10 * time.Second); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func runEmailRules(z *zone, action string, positional []string, flags map[string]string) error {
+	path := "/zones/" + z.ID + "/email/routing/rules"
+
+	switch action {
+	case "list":
+		rules, err := listAll[emailRule](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "ENABLED", "MATCH", "FORWARD TO"}}
+		for _, r := range rules {
+			match, forward := r.summary()
+			t.Rows = append(t.Rows, []string{r.ID, fmt.Sprint(r.Enabled), match, forward})
+		}
+		return printList(rules, t, func() {
+			if len(rules) == 0 {
+				fmt.Printf("No email routing rules on %s.\n", z.Name)
+				return
+			}
+			for _, r := range rules {
+				match, forward := r.summary()
+				fmt.Printf("%s\t%s -> %s\n", r.ID, match, forward)
+			}
+		})
+	case "add":
+		if flags["match"] == "" || flags["forward"] == "" {
+			return usageErrorf("missing required flags for email-routing rules add: --match --forward")
+		}
+		r, err := addEmailRule(z, flags["match"], flags["forward"])
+		if err != nil {
+			return err
+		}
+		return printResult(r, func() {
+			fmt.Printf("Email rule added: %s -> %s (id=%s)\n", flags["match"], flags["forward"], r.ID)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf email-routing rules delete <rule-id> --zone <zone>")
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+url.PathEscape(positional[0]), nil); err != nil {
+			return err
+		}
+		return printResult(map[string]string{"id": positional[0]}, func() {
+			fmt.Printf("Email rule deleted: %s\n", positional[0])
+		})
+	}
+	return usageErrorf("usage: cf email-routing rules list|add|delete --zone <zone>")
+}
+
+// addEmailRule forwards mail for match (an address on the zone) to forward.
+// An unverified destination is accepted by the API but mail is held until
+// it is verified, so warn about it.
+func addEmailRule(z *zone, match, forward string) (*emailRule, error) {
+	if !strings.Contains(match, "@") {
+		match += "@" + z.Name
+	}
+	if path, err := emailAddressesPath(); err == nil {
+		if a, err := findEmailAddress(path, forward); err != nil {
+			infof("Warning: %v\n", err)
+		} else if a.Verified == "" {
+			infof("Warning: %s is not verified yet; forwarding starts once it is.\n", forward)
+		}
+	}
+
+	body := map[string]any{
+		"name":     "cf: " + match,
+		"enabled":  true,
+		"matchers": []map[string]string{{"type": "literal", "field": "to", "value": match}},
+		"actions":  []map[string]any{{"type": "forward", "value": []string{forward}}},
+	}
+	resp, err := requestCF(http.MethodPost, "/zones/"+z.ID+"/email/routing/rules", body)
+	if err != nil {
+		return nil, err
+	}
+	var r emailRule
+	if err := json.Unmarshal(resp.Result, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// wizardEmailForwarding is the optional wizard step after zone creation.
+func wizardEmailForwarding(reader *bufio.Reader, domain string) error {
+	setup, err := promptYesNo(reader, "Set up email forwarding (Email Routing) for this domain?", false)
+	if err != nil || !setup {
+		return err
+	}
+
+	z, err := requireZone(domain)
+	if err != nil {
+		return err
+	}
+	match, err := prompt(reader, "Address to receive mail at", "hello@"+z.Name)
+	if err != nil {
+		return err
+	}
+	forward, err := prompt(reader, "Forward to (your existing inbox)", "")
+	if err != nil {
+		return err
+	}
+	if forward == "" {
+		fmt.Println("No destination given; skipping email forwarding.")
+		return nil
+	}
+
+	return setUpEmailForwarding(z, match, forward)
+}
+
+// setUpEmailForwarding enables Email Routing and forwards match to forward,
+// registering forward as a destination address if needed.
+func setUpEmailForwarding(z *zone, match, forward string) error {
+	if _, err := enableEmailRouting(z); err != nil {
+		return err
+	}
+	path, err := emailAddressesPath()
+	if err != nil {
+		return err
+	}
+	if _, err := findEmailAddress(path, forward); err != nil {
+		if _, err := addEmailAddress(path, forward); err != nil {
+			return err
+		}
+		fmt.Printf("Verification email sent to %s; forwarding starts after you click the link.\n", forward)
+	}
+	if _, err := addEmailRule(z, match, forward); err != nil {
+		return err
+	}
+	fmt.Printf("Email to %s will be forwarded to %s.\n", match, forward)
+	return nil
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestMissingDNSRecords(t *testing.T) {
+	want := []dnsRecord{
+		{Type: "MX", Name: "example.com", Content: "route1.mx.cloudflare.net"},
+		{Type: "MX", Name: "example.com", Content: "route2.mx.cloudflare.net"},
+		{Type: "TXT", Name: "example.com", Content: "v=spf1 include:_spf.mx.cloudflare.net ~all"},
+	}
+	live := []dnsRecord{
+		{Type: "MX", Name: "Example.com", Content: "route1.mx.cloudflare.net."},
+		{Type: "A", Name: "example.com", Content: "192.0.2.1"},
+	}
+	got := missingDNSRecords(want, live)
+	if len(got) != 2 || got[0].Content != "route2.mx.cloudflare.net" || got[1].Type != "TXT" {
+		t.Fatalf("unexpected missing records: %+v", got)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Exit codes let scripts branch on the class of failure.
+const (
+	exitFailure     = 1
+	exitUsage       = 2
+	exitAuth        = 3
+	exitNotFound    = 4
+	exitAPI         = 5
+	exitRateLimited = 6
+	exitTimeout     = 7
+
+	// exitDrift tells "changed" apart from "broken" for checks such as
+	// snapshot diff, like diff's exit 1 next to its 2 for trouble.
+	exitDrift = 8
+
+	// exitInterrupted follows the shell convention of 128 + SIGINT.
+	exitInterrupted = 130
+)
+
+// cfAuthErrorCode is Cloudflare's "Authentication error", which some
+// endpoints return with HTTP 400.
+const cfAuthErrorCode = 10000
+
+// cliError tags an error from the command layer with its exit code.
+type cliError struct {
+	code int
+	err  error
+}
+
+func (e *cliError) Error() string { return e.err.Error() }
+func (e *cliError) Unwrap() error { return e.err }
+
+// usageErrorf reports bad arguments or flags.
+func usageErrorf(format string, a ...any) error {
+	return &cliError{code: exitUsage, err: fmt.Errorf(format, a...)}
+}
+
+// authErrorf reports missing or unusable credentials.
+func authErrorf(format string, a ...any) error {
+	return &cliError{code: exitAuth, err: fmt.Errorf(format, a...)}
+}
+
+// notFoundErrorf reports a resource the user named that does not exist.
+func notFoundErrorf(format string, a ...any) error {
+	return &cliError{code: exitNotFound, err: fmt.Errorf(format, a...)}
+}
+
+// driftErrorf reports differences found by a drift check.
+func driftErrorf(format string, a ...any) error {
+	return &cliError{code: exitDrift, err: fmt.Errorf(format, a...)}
+}
+
+// exitCode maps err to the process exit code. API errors are classified by
+// HTTP status; anything unclassified exits 1.
+func exitCode(err error) int {
+	var ce *cliError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	var se *apiStatusError
+	if errors.As(err, &se) {
+		switch se.Status {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return exitAuth
+		case http.StatusNotFound:
+			return exitNotFound
+		case http.StatusTooManyRequests:
+			return exitRateLimited
+		}
+		for _, ae := range se.Errors {
+			if ae.Code == cfAuthErrorCode {
+				return exitAuth
+			}
+		}
+		return exitAPI
+	}
+	return exitFailure
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{errors.New("boom"), exitFailure},
+		{usageErrorf("usage: cf queues create <name>"), exitUsage},
+		{fmt.Errorf("record 3: %w", usageErrorf("invalid --ttl")), exitUsage},
+		{authErrorf("missing API token"), exitAuth},
+		{notFoundErrorf("queue %q not found", "jobs"), exitNotFound},
+		{driftErrorf("drift detected: 2 change(s)"), exitDrift},
+		{&apiStatusError{Status: 403}, exitAuth},
+		{&apiStatusError{Status: 400, Errors: []apiError{{Code: cfAuthErrorCode, Message: "Authentication error"}}}, exitAuth},
+		{&apiStatusError{Status: 404}, exitNotFound},
+		{&apiStatusError{Status: 429}, exitRateLimited},
+		{&apiStatusError{Status: 400, Errors: []apiError{{Code: 81057, Message: "Record already exists."}}}, exitAPI},
+	}
+	for _, tc := range cases {
+		if got := exitCode(tc.err); got != tc.want {
+			t.Fatalf("exitCode(%v) = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+}
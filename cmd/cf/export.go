@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func runExport(args []string) error {
+	if len(args) == 0 || args[0] != "terraform" {
+		return usageErrorf("usage: cf export terraform [--zone <zone>] [--out <file.tf>] [--imports <import.sh>]")
+	}
+	flags := parseFlags(args[1:])
+
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	var zones []zone
+	if name := zoneOrDefault(flags["zone"]); name != "" {
+		z, err := requireZone(name)
+		if err != nil {
+			return err
+		}
+		zones = []zone{*z}
+	} else if zones, err = listAccountZones(); err != nil {
+		return err
+	}
+
+	e := newTFExport(accountID)
+	for _, z := range zones {
+		if err := e.addZone(z); err != nil {
+			return fmt.Errorf("%s: %w", z.Name, err)
+		}
+	}
+
+	hcl := e.hcl.String()
+	if flags["imports"] != "" {
+		script := "#!/bin/sh\nset -e\n" + strings.Join(e.imports, "\n") + "\n"
+		if err := os.WriteFile(flags["imports"], []byte(script), 0o755); err != nil {
+			return err
+		}
+	} else if len(e.imports) > 0 {
+		hcl += "# Bring the existing resources under Terraform with:\n#   " + strings.Join(e.imports, "\n#   ") + "\n"
+	}
+	if flags["out"] == "" || flags["out"] == "-" {
+		fmt.Print(hcl)
+		return nil
+	}
+	if err := os.WriteFile(flags["out"], []byte(hcl), 0o644); err != nil {
+		return err
+	}
+	infof("Exported %d resource(s) from %d zone(s) to %s\n", len(e.imports), len(zones), flags["out"])
+	return nil
+}
+
+// tfExport accumulates Terraform resources for the Cloudflare provider and
+// the terraform import command for each.
+type tfExport struct {
+	accountID string
+	hcl       strings.Builder
+	imports   []string
+	names     map[string]int
+}
+
+func newTFExport(accountID string) *tfExport {
+	return &tfExport{accountID: accountID, names: map[string]int{}}
+}
+
+// hclField is one attribute or block in a resource body. Values are strings,
+// numbers, bools, hclExpr, string lists, nested []hclField blocks, or the
+// maps and slices that decoding API JSON produces.
+type hclField struct {
+	Key   string
+	Value any
+}
+
+// hclExpr is written as is, for references such as cloudflare_zone.x.id.
+type hclExpr string
+
+func (e *tfExport) addZone(z zone) error {
+	zoneFields := []hclField{{"account_id", e.accountID}, {"zone", z.Name}}
+	if z.Type != "" {
+		zoneFields = append(zoneFields, hclField{"type", z.Type})
+	}
+	zoneRes := e.resource("cloudflare_zone", z.ID, []string{z.Name}, zoneFields)
+	zoneID := hclExpr("cloudflare_zone." + zoneRes + ".id")
+
+	records, err := listDNSRecords(z.ID)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		label := strings.TrimSuffix(strings.TrimSuffix(r.Name, z.Name), ".")
+		if label == "" {
+			label = "apex"
+		}
+		fields := []hclField{{"zone_id", zoneID}, {"name", r.Name}, {"type", r.Type}}
+		if r.Data != nil {
+			fields = append(fields, hclField{"data", r.Data})
+		} else {
+			fields = append(fields, hclField{"content", r.Content})
+		}
+		fields = append(fields, hclField{"ttl", r.TTL}, hclField{"proxied", r.Proxied})
+		if r.Priority != nil {
+			fields = append(fields, hclField{"priority", *r.Priority})
+		}
+		if r.Comment != "" {
+			fields = append(fields, hclField{"comment", r.Comment})
+		}
+		if len(r.Tags) > 0 {
+			fields = append(fields, hclField{"tags", r.Tags})
+		}
+		e.resource("cloudflare_record", z.ID+"/"+r.ID, []string{z.Name, r.Type, label}, fields)
+	}
+
+	// Page rules and redirect rules need extra token permissions; a zone
+	// without them is still exported.
+	rules, err := listPageRules("/zones/" + z.ID + "/pagerules")
+	if err != nil {
+		infof("Warning: page rules for %s were not exported: %v\n", z.Name, err)
+	}
+	for i, pr := range rules {
+		actions := make([]hclField, 0, len(pr.Actions))
+		for _, a := range pr.Actions {
+			v := a.Value
+			if v == nil {
+				v = true
+			}
+			actions = append(actions, hclField{a.ID, v})
+		}
+		e.resource("cloudflare_page_rule", z.ID+"/"+pr.ID, []string{z.Name, "page_rule", strconv.Itoa(i + 1)}, []hclField{
+			{"zone_id", zoneID},
+			{"target", pr.pattern()},
+			{"priority", pr.Priority},
+			{"status", pr.Status},
+			{"actions", actions},
+		})
+	}
+
+	rs, err := getPhaseRuleset("/zones/"+z.ID, phaseSingleRedirect)
+	if err != nil {
+		infof("Warning: redirect rules for %s were not exported: %v\n", z.Name, err)
+	}
+	if rs != nil && len(rs.Rules) > 0 {
+		name := rs.Name
+		if name == "" {
+			name = "default"
+		}
+		fields := []hclField{
+			{"zone_id", zoneID},
+			{"name", name},
+			{"kind", "zone"},
+			{"phase", phaseSingleRedirect},
+		}
+		for _, r := range rs.Rules {
+			rule := []hclField{{"action", r.Action}, {"expression", r.Expression}}
+			if r.Description != "" {
+				rule = append(rule, hclField{"description", r.Description})
+			}
+			rule = append(rule, hclField{"enabled", r.enabled()})
+			if len(r.ActionParameters) > 0 {
+				var params any
+				if err := json.Unmarshal(r.ActionParameters, &params); err != nil {
+					return err
+				}
+				rule = append(rule, hclField{"action_parameters", params})
+			}
+			fields = append(fields, hclField{"rules", rule})
+		}
+		e.resource("cloudflare_ruleset", "zone/"+z.ID+"/"+rs.ID, []string{z.Name, "redirect_rules"}, fields)
+	}
+	return nil
+}
+
+// resource writes one resource block and its import command, and returns
+// the resource name it chose.
+func (e *tfExport) resource(kind, importID string, nameParts []string, fields []hclField) string {
+	name := e.resourceName(kind, nameParts)
+	fmt.Fprintf(&e.hcl, "resource %q %q {\n", kind, name)
+	writeHCLBody(&e.hcl, "  ", fields)
+	e.hcl.WriteString("}\n\n")
+	e.imports = append(e.imports, fmt.Sprintf("terraform import %s.%s %s", kind, name, importID))
+	return name
+}
+
+// resourceName turns parts into a Terraform identifier, numbering repeats so
+// round-robin records get distinct names.
+func (e *tfExport) resourceName(kind string, parts []string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.Join(parts, "_")) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		case r == '*':
+			b.WriteString("wildcard")
+		default:
+			b.WriteRune('_')
+		}
+	}
+	name := b.String()
+	if name == "" || name[0] >= '0' && name[0] <= '9' || name[0] == '-' {
+		name = "r_" + name
+	}
+	e.names[kind+"."+name]++
+	if n := e.names[kind+"."+name]; n > 1 {
+		name = fmt.Sprintf("%s_%d", name, n)
+	}
+	return name
+}
+
+// writeHCLBody writes attributes, aligned, and then blocks, as terraform fmt
+// lays them out.
+func writeHCLBody(b *strings.Builder, indent string, fields []hclField) {
+	width := 0
+	var attrs, blocks []hclField
+	for _, f := range fields {
+		if isHCLBlock(f.Value) {
+			blocks = append(blocks, f)
+			continue
+		}
+		attrs = append(attrs, f)
+		width = max(width, len(f.Key))
+	}
+	for _, f := range append(attrs, blocks...) {
+		switch v := f.Value.(type) {
+		case []hclField:
+			fmt.Fprintf(b, "%s%s {\n", indent, f.Key)
+			writeHCLBody(b, indent+"  ", v)
+			fmt.Fprintf(b, "%s}\n", indent)
+		case map[string]any:
+			writeHCLBody(b, indent, []hclField{{f.Key, sortedHCLFields(v)}})
+		case []any:
+			if isHCLBlock(v) {
+				for _, item := range v {
+					writeHCLBody(b, indent, []hclField{{f.Key, item}})
+				}
+				continue
+			}
+			fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, f.Key, hclValue(v))
+		default:
+			fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, f.Key, hclValue(v))
+		}
+	}
+}
+
+// isHCLBlock reports whether v is written as a block rather than an
+// attribute: nested objects, and lists of them.
+func isHCLBlock(v any) bool {
+	switch v := v.(type) {
+	case []hclField, map[string]any:
+		return true
+	case []any:
+		return len(v) > 0 && isHCLBlock(v[0])
+	}
+	return false
+}
+
+func sortedHCLFields(m map[string]any) []hclField {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]hclField, len(keys))
+	for i, k := range keys {
+		fields[i] = hclField{k, m[k]}
+	}
+	return fields
+}
+
+func hclValue(v any) string {
+	switch v := v.(type) {
+	case hclExpr:
+		return string(v)
+	case string:
+		return hclString(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		items := make([]string, len(v))
+		for i, s := range v {
+			items[i] = hclString(s)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []any:
+		items := make([]string, len(v))
+		for i, s := range v {
+			items[i] = hclValue(s)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(v)
+}
+
+// hclString quotes s, escaping HCL's template sequences so ${ and %{ in
+// record content stay literal.
+func hclString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	q := strings.TrimSuffix(buf.String(), "\n")
+	q = strings.ReplaceAll(q, "${", "$${")
+	return strings.ReplaceAll(q, "%{", "%%{")
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportTerraform(t *testing.T) {
+	srv := useFakeAPI(t)
+	prio := 10
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{
+		{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Proxied: true, Tags: []string{"team:web"}},
+		{ID: "r2", Type: "A", Name: "www.example.com", Content: "192.0.2.2", TTL: 1, Proxied: true},
+		{ID: "r3", Type: "MX", Name: "example.com", Content: "mx.example.net", TTL: 300, Priority: &prio},
+		{ID: "r4", Type: "TXT", Name: "example.com", Content: "tpl ${var}", TTL: 300},
+	})
+	srv.Reply("GET", "/zones/z1/pagerules", []map[string]any{{
+		"id":       "p1",
+		"targets":  []any{map[string]any{"target": "url", "constraint": map[string]any{"operator": "matches", "value": "example.com/old/*"}}},
+		"actions":  []any{map[string]any{"id": "forwarding_url", "value": map[string]any{"url": "https://example.com/new", "status_code": 301}}, map[string]any{"id": "always_use_https"}},
+		"priority": 1,
+		"status":   "active",
+	}})
+	srv.Reply("GET", "/zones/z1/rulesets/phases/http_request_dynamic_redirect/entrypoint", ruleset{
+		ID: "rs1",
+		Rules: []rulesetRule{{
+			Action:           "redirect",
+			Expression:       `http.request.uri.path eq "/blog"`,
+			Description:      "blog",
+			ActionParameters: json.RawMessage(`{"from_value":{"status_code":301,"target_url":{"value":"https://blog.example.com"}}}`),
+		}},
+	})
+
+	imports := filepath.Join(t.TempDir(), "import.sh")
+	out, err := captureStdout(t, func() error {
+		return runExport([]string{"terraform", "--zone", "example.com", "--imports", imports})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`resource "cloudflare_zone" "example_com" {`,
+		`resource "cloudflare_record" "example_com_a_www" {`,
+		`resource "cloudflare_record" "example_com_a_www_2" {`,
+		`  zone_id = cloudflare_zone.example_com.id`,
+		`  tags    = ["team:web"]`,
+		`  priority = 10`,
+		`  content = "tpl $${var}"`,
+		`resource "cloudflare_page_rule" "example_com_page_rule_1" {`,
+		"  actions {\n    always_use_https = true\n    forwarding_url {\n      status_code = 301\n      url         = \"https://example.com/new\"\n    }\n  }",
+		`resource "cloudflare_ruleset" "example_com_redirect_rules" {`,
+		"    action_parameters {\n      from_value {\n        status_code = 301\n        target_url {\n          value = \"https://blog.example.com\"",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	script, err := os.ReadFile(imports)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"terraform import cloudflare_zone.example_com z1",
+		"terraform import cloudflare_record.example_com_a_www_2 z1/r2",
+		"terraform import cloudflare_page_rule.example_com_page_rule_1 z1/p1",
+		"terraform import cloudflare_ruleset.example_com_redirect_rules zone/z1/rs1",
+	} {
+		if !strings.Contains(string(script), want) {
+			t.Fatalf("expected %q in import script:\n%s", want, script)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type gatewayRule struct {
+	ID          string   `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Action      string   `json:"action"`
+	Enabled     bool     `json:"enabled"`
+	Filters     []string `json:"filters"`
+	Traffic     string   `json:"traffic"`
+	Precedence  int      `json:"precedence,omitempty"`
+}
+
+type gatewayCategory struct {
+	ID            int               `json:"id"`
+	Name          string            `json:"name"`
+	Class         string            `json:"class,omitempty"`
+	Subcategories []gatewayCategory `json:"subcategories,omitempty"`
+}
+
+type gatewayNetwork struct {
+	Network string `json:"network"`
+}
+
+type gatewayLocation struct {
+	ID              string           `json:"id,omitempty"`
+	Name            string           `json:"name"`
+	ClientDefault   bool             `json:"client_default"`
+	Networks        []gatewayNetwork `json:"networks,omitempty"`
+	DOHSubdomain    string           `json:"doh_subdomain,omitempty"`
+	IP              string           `json:"ip,omitempty"`
+	IPv4Destination string           `json:"ipv4_destination,omitempty"`
+}
+
+func runGateway(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf gateway policies|categories|locations ...")
+	}
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	base := "/accounts/" + accountID + "/gateway"
+
+	switch args[0] {
+	case "policies":
+		return runGatewayPolicies(base, args[1:])
+	case "categories":
+		categories, err := listGatewayCategories(base)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "PARENT"}}
+		for _, c := range categories {
+			t.Rows = append(t.Rows, []string{strconv.Itoa(c.ID), c.Name, ""})
+			for _, sub := range c.Subcategories {
+				t.Rows = append(t.Rows, []string{strconv.Itoa(sub.ID), sub.Name, c.Name})
+			}
+		}
+		return printList(categories, t, func() {
+			for _, c := range categories {
+				fmt.Printf("%d\t%s\n", c.ID, c.Name)
+				for _, sub := range c.Subcategories {
+					fmt.Printf("  %d\t%s\n", sub.ID, sub.Name)
+				}
+			}
+		})
+	case "locations":
+		return runGatewayLocations(base+"/locations", args[1:])
+	}
+	return errors.New("unknown gateway command. run: cf help")
+}
+
+func runGatewayPolicies(base string, args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	path := base + "/rules"
+
+	switch action {
+	case "list":
+		rules, err := listAll[gatewayRule](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		dns := rules[:0]
+		for _, r := range rules {
+			if len(r.Filters) == 1 && r.Filters[0] == "dns" {
+				dns = append(dns, r)
+			}
+		}
+		t := table{Headers: []string{"ID", "NAME", "ACTION", "ENABLED", "PRECEDENCE", "TRAFFIC"}}
+		for _, r := range dns {
+			t.Rows = append(t.Rows, []string{r.ID, r.Name, r.Action, strconv.FormatBool(r.Enabled), strconv.Itoa(r.Precedence), r.Traffic})
+		}
+		return printList(dns, t, func() {
+			if len(dns) == 0 {
+				fmt.Println("No Gateway DNS policies.")
+				return
+			}
+			for _, r := range dns {
+				fmt.Printf("%s\t%s\t%s\t%s\n", r.ID, r.Name, r.Action, r.Traffic)
+			}
+		})
+	case "create":
+		if len(positional) == 0 || (flags["domains"] == "" && flags["categories"] == "" && flags["traffic"] == "") {
+			return usageErrorf("usage: cf gateway policies create <name> (--domains <a,b> | --categories <names-or-ids> | --traffic <expr>) [--action block|allow|safesearch] [--precedence <n>]")
+		}
+		var categories []gatewayCategory
+		if flags["categories"] != "" {
+			var err error
+			if categories, err = listGatewayCategories(base); err != nil {
+				return err
+			}
+		}
+		traffic, err := gatewayTraffic(flags, categories)
+		if err != nil {
+			return err
+		}
+		r := gatewayRule{Name: positional[0], Description: flags["description"], Action: flags["action"], Enabled: true, Filters: []string{"dns"}, Traffic: traffic}
+		if r.Action == "" {
+			r.Action = "block"
+		}
+		if r.Precedence, err = parseIntWithDefault(flags["precedence"], 0); err != nil {
+			return usageErrorf("invalid --precedence: %w", err)
+		}
+		resp, err := requestCF(http.MethodPost, path, r)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, &r); err != nil {
+			return err
+		}
+		return printResult(r, func() {
+			fmt.Printf("Gateway DNS policy created: %s (id=%s) %s %s\n", r.Name, r.ID, r.Action, r.Traffic)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf gateway policies delete <id|name> [--force]")
+		}
+		if err := requireConfirmable(flags, "gateway policies delete"); err != nil {
+			return err
+		}
+		rules, err := listAll[gatewayRule](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		var rule *gatewayRule
+		for i, r := range rules {
+			if r.ID == positional[0] || r.Name == positional[0] {
+				rule = &rules[i]
+				break
+			}
+		}
+		if rule == nil {
+			return notFoundErrorf("Gateway policy %q not found. run: cf gateway policies list", positional[0])
+		}
+		ok, err := confirm(flags, "gateway policies delete", fmt.Sprintf("Delete Gateway policy %s?", rule.Name))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+rule.ID, nil); err != nil {
+			return err
+		}
+		return printResult(rule, func() {
+			fmt.Printf("Gateway DNS policy deleted: %s\n", rule.Name)
+		})
+	}
+	return usageErrorf("usage: cf gateway policies list|create|delete")
+}
+
+func listGatewayCategories(base string) ([]gatewayCategory, error) {
+	resp, err := requestCF(http.MethodGet, base+"/categories", nil)
+	if err != nil {
+		return nil, err
+	}
+	var categories []gatewayCategory
+	if err := json.Unmarshal(resp.Result, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// gatewayTraffic builds the wirefilter expression for a DNS policy from
+// --domains and --categories, or passes --traffic through unchanged.
+func gatewayTraffic(flags map[string]string, categories []gatewayCategory) (string, error) {
+	if v := flags["traffic"]; v != "" {
+		return v, nil
+	}
+	var parts []string
+	if v := flags["domains"]; v != "" {
+		var quoted []string
+		for _, d := range splitList(v) {
+			quoted = append(quoted, strconv.Quote(strings.ToLower(strings.TrimSuffix(d, "."))))
+		}
+		parts = append(parts, fmt.Sprintf("any(dns.domains[*] in {%s})", strings.Join(quoted, " ")))
+	}
+	if v := flags["categories"]; v != "" {
+		var ids []string
+		for _, ref := range splitList(v) {
+			id, err := gatewayCategoryID(categories, ref)
+			if err != nil {
+				return "", err
+			}
+			ids = append(ids, strconv.Itoa(id))
+		}
+		parts = append(parts, fmt.Sprintf("any(dns.content_category[*] in {%s})", strings.Join(ids, " ")))
+	}
+	return strings.Join(parts, " or "), nil
+}
+
+func gatewayCategoryID(categories []gatewayCategory, ref string) (int, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		return id, nil
+	}
+	for _, c := range categories {
+		if strings.EqualFold(c.Name, ref) {
+			return c.ID, nil
+		}
+		for _, sub := range c.Subcategories {
+			if strings.EqualFold(sub.Name, ref) {
+				return sub.ID, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unknown content category %q. run: cf gateway categories", ref)
+}
+
+func runGatewayLocations(path string, args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+
+	switch action {
+	case "list":
+		locations, err := listAll[gatewayLocation](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "DEFAULT", "DOH_SUBDOMAIN", "IPV4_DESTINATION", "NETWORKS"}}
+		for _, l := range locations {
+			t.Rows = append(t.Rows, []string{l.ID, l.Name, strconv.FormatBool(l.ClientDefault), l.DOHSubdomain, l.IPv4Destination, gatewayNetworks(l)})
+		}
+		return printList(locations, t, func() {
+			for _, l := range locations {
+				fmt.Printf("%s\t%s\thttps://%s.cloudflare-gateway.com/dns-query\t%s\n", l.ID, l.Name, l.DOHSubdomain, gatewayNetworks(l))
+			}
+		})
+	case "create":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf gateway locations create <name> [--networks <cidr,...>] [--default]")
+		}
+		l := gatewayLocation{Name: positional[0], ClientDefault: parseBoolWithDefault(flags["default"], false)}
+		for _, n := range splitList(flags["networks"]) {
+			l.Networks = append(l.Networks, gatewayNetwork{Network: n})
+		}
+		resp, err := requestCF(http.MethodPost, path, l)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, &l); err != nil {
+			return err
+		}
+		return printResult(l, func() {
+			fmt.Printf("Gateway location created: %s (id=%s)\n", l.Name, l.ID)
+			fmt.Printf("  DoH endpoint: https://%s.cloudflare-gateway.com/dns-query\n", l.DOHSubdomain)
+			if l.IPv4Destination != "" {
+				fmt.Printf("  IPv4 resolver: %s\n", l.IPv4Destination)
+			}
+			if l.IP != "" {
+				fmt.Printf("  IPv6 resolver: %s\n", l.IP)
+			}
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf gateway locations delete <id|name>")
+		}
+		locations, err := listAll[gatewayLocation](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		for _, l := range locations {
+			if l.ID != positional[0] && l.Name != positional[0] {
+				continue
+			}
+			if _, err := requestCF(http.MethodDelete, path+"/"+l.ID, nil); err != nil {
+				return err
+			}
+			return printResult(l, func() {
+				fmt.Printf("Gateway location deleted: %s\n", l.Name)
+			})
+		}
+		return notFoundErrorf("Gateway location %q not found. run: cf gateway locations list", positional[0])
+	}
+	return usageErrorf("usage: cf gateway locations list|create|delete")
+}
+
+func gatewayNetworks(l gatewayLocation) string {
+	nets := make([]string, len(l.Networks))
+	for i, n := range l.Networks {
+		nets[i] = n.Network
+	}
+	return strings.Join(nets, ",")
+}
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestGatewayTraffic(t *testing.T) {
+	categories := []gatewayCategory{
+		{ID: 17, Name: "Security Threats", Subcategories: []gatewayCategory{{ID: 80, Name: "Malware"}}},
+		{ID: 99, Name: "Gambling"},
+	}
+	got, err := gatewayTraffic(map[string]string{"domains": "Bad.example.", "categories": "malware,99"}, categories)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `any(dns.domains[*] in {"bad.example"}) or any(dns.content_category[*] in {80 99})`
+	if got != want {
+		t.Fatalf("unexpected traffic:\n got %s\nwant %s", got, want)
+	}
+
+	if _, err := gatewayTraffic(map[string]string{"categories": "Knitting"}, categories); err == nil {
+		t.Fatalf("expected error for unknown category")
+	}
+	if got, _ := gatewayTraffic(map[string]string{"traffic": "dns.fqdn == \"x\""}, nil); got != `dns.fqdn == "x"` {
+		t.Fatalf("expected --traffic to pass through, got %s", got)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// graphQLResponse is the GraphQL Analytics API envelope, which differs from
+// the REST one: there is no success flag and errors carry paths.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// queryGraphQL runs query against the GraphQL Analytics API and decodes the
+// data field into out.
+func queryGraphQL(query string, variables map[string]any, out any) error {
+	payload, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	data, err := requestCFBytesWithBody(http.MethodPost, "/graphql", "application/json", payload)
+	if err != nil {
+		return err
+	}
+
+	var resp graphQLResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		msgs := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			msgs[i] = e.Message
+		}
+		return errors.New("graphql: " + strings.Join(msgs, "; "))
+	}
+	return json.Unmarshal(resp.Data, out)
+}
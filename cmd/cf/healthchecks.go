@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type healthcheck struct {
+	ID            string           `json:"id,omitempty"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description,omitempty"`
+	Address       string           `json:"address"`
+	Type          string           `json:"type"`
+	Status        string           `json:"status,omitempty"`
+	FailureReason string           `json:"failure_reason,omitempty"`
+	Suspended     bool             `json:"suspended"`
+	Interval      int              `json:"interval,omitempty"`
+	Retries       int              `json:"retries,omitempty"`
+	Timeout       int              `json:"timeout,omitempty"`
+	CheckRegions  []string         `json:"check_regions,omitempty"`
+	HTTPConfig    *healthcheckHTTP `json:"http_config,omitempty"`
+	TCPConfig     *healthcheckTCP  `json:"tcp_config,omitempty"`
+}
+
+type healthcheckHTTP struct {
+	Method        string   `json:"method,omitempty"`
+	Path          string   `json:"path,omitempty"`
+	Port          int      `json:"port,omitempty"`
+	ExpectedCodes []string `json:"expected_codes,omitempty"`
+}
+
+type healthcheckTCP struct {
+	Method string `json:"method,omitempty"`
+	Port   int    `json:"port,omitempty"`
+}
+
+// healthcheckRegion is the latest result a single check region reported.
+type healthcheckRegion struct {
+	Region        string `json:"region"`
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason,omitempty"`
+	ResponseCode  int    `json:"response_code,omitempty"`
+	RTTMs         int    `json:"rtt_ms,omitempty"`
+	CheckedAt     string `json:"checked_at"`
+}
+
+type healthcheckStatus struct {
+	healthcheck
+	Regions []healthcheckRegion `json:"regions"`
+}
+
+func runHealthchecks(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for healthchecks: --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	path := "/zones/" + z.ID + "/healthchecks"
+
+	switch action {
+	case "list":
+		checks, err := listAll[healthcheck](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "TYPE", "ADDRESS", "STATUS", "INTERVAL"}}
+		for _, h := range checks {
+			t.Rows = append(t.Rows, []string{h.ID, h.Name, h.Type, h.Address + h.target(), h.Status, strconv.Itoa(h.Interval)})
+		}
+		return printList(checks, t, func() {
+			if len(checks) == 0 {
+				fmt.Printf("No health checks on %s.\n", z.Name)
+				return
+			}
+			for _, h := range checks {
+				fmt.Printf("%s\t%s\t%s %s%s\t%s\n", h.ID, h.Name, h.Type, h.Address, h.target(), h.Status)
+			}
+		})
+	case "create":
+		if len(positional) == 0 || flags["address"] == "" {
+			return usageErrorf("usage: cf healthchecks create <name> --zone <zone> --address <host> [--type HTTPS|HTTP|TCP] [--path /] [--expected-codes 200] [--interval 60] [--notify <emails>]")
+		}
+		h, err := healthcheckFromFlags(positional[0], flags)
+		if err != nil {
+			return err
+		}
+		resp, err := requestCF(http.MethodPost, path, h)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, &h); err != nil {
+			return err
+		}
+		if emails := splitList(flags["notify"]); len(emails) > 0 {
+			if err := notifyHealthcheck(z, h, emails); err != nil {
+				return fmt.Errorf("health check %s created but notification setup failed: %w", h.Name, err)
+			}
+			infof("Notifications for %s will go to: %s\n", h.Name, strings.Join(emails, ", "))
+		}
+		return printResult(h, func() {
+			fmt.Printf("Health check created: %s (id=%s) %s %s%s every %ds\n", h.Name, h.ID, h.Type, h.Address, h.target(), h.Interval)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf healthchecks delete <name|id> --zone <zone> [--force]")
+		}
+		if err := requireConfirmable(flags, "healthchecks delete"); err != nil {
+			return err
+		}
+		h, err := findHealthcheck(path, positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirm(flags, "healthchecks delete", fmt.Sprintf("Delete health check %s?", h.Name))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+h.ID, nil); err != nil {
+			return err
+		}
+		return printResult(h, func() {
+			fmt.Printf("Health check deleted: %s\n", h.Name)
+		})
+	case "status":
+		since, err := parseDurationWithDefault(flags["since"], time.Hour)
+		if err != nil {
+			return usageErrorf("invalid --since: %w", err)
+		}
+		var checks []healthcheck
+		if len(positional) > 0 {
+			h, err := findHealthcheck(path, positional[0])
+			if err != nil {
+				return err
+			}
+			checks = []healthcheck{*h}
+		} else if checks, err = listAll[healthcheck](path, 50, 0); err != nil {
+			return err
+		}
+
+		statuses := make([]healthcheckStatus, 0, len(checks))
+		for _, h := range checks {
+			regions, err := healthcheckRegions(z.ID, h.ID, time.Now().Add(-since))
+			if err != nil {
+				return err
+			}
+			statuses = append(statuses, healthcheckStatus{healthcheck: h, Regions: regions})
+		}
+		t := table{Headers: []string{"NAME", "REGION", "STATUS", "CODE", "RTT_MS", "CHECKED_AT", "REASON"}}
+		for _, s := range statuses {
+			for _, r := range s.Regions {
+				t.Rows = append(t.Rows, []string{s.Name, r.Region, r.Status, strconv.Itoa(r.ResponseCode), strconv.Itoa(r.RTTMs), r.CheckedAt, r.FailureReason})
+			}
+		}
+		return printList(statuses, t, func() {
+			for _, s := range statuses {
+				printHealthcheckStatus(s, since)
+			}
+		})
+	}
+	return usageErrorf("usage: cf healthchecks list|create|delete|status --zone <zone>")
+}
+
+// target renders the port and path part of the checked URL.
+func (h healthcheck) target() string {
+	switch {
+	case h.HTTPConfig != nil:
+		s := h.HTTPConfig.Path
+		if h.HTTPConfig.Port != 0 {
+			s = ":" + strconv.Itoa(h.HTTPConfig.Port) + s
+		}
+		return s
+	case h.TCPConfig != nil && h.TCPConfig.Port != 0:
+		return ":" + strconv.Itoa(h.TCPConfig.Port)
+	}
+	return ""
+}
+
+func healthcheckFromFlags(name string, flags map[string]string) (healthcheck, error) {
+	h := healthcheck{
+		Name:         name,
+		Description:  flags["description"],
+		Address:      flags["address"],
+		Type:         strings.ToUpper(flags["type"]),
+		CheckRegions: splitList(strings.ToUpper(flags["regions"])),
+	}
+	if h.Type == "" {
+		h.Type = "HTTPS"
+	}
+	var err error
+	if h.Interval, err = parseIntWithDefault(flags["interval"], 60); err != nil {
+		return h, usageErrorf("invalid --interval: %w", err)
+	}
+	if h.Retries, err = parseIntWithDefault(flags["check-retries"], 2); err != nil {
+		return h, usageErrorf("invalid --check-retries: %w", err)
+	}
+	if h.Timeout, err = parseIntWithDefault(flags["timeout"], 5); err != nil {
+		return h, usageErrorf("invalid --timeout: %w", err)
+	}
+	port, err := parseIntWithDefault(flags["port"], 0)
+	if err != nil {
+		return h, usageErrorf("invalid --port: %w", err)
+	}
+
+	switch h.Type {
+	case "HTTP", "HTTPS":
+		h.HTTPConfig = &healthcheckHTTP{
+			Method:        strings.ToUpper(flags["method"]),
+			Path:          flags["path"],
+			Port:          port,
+			ExpectedCodes: splitList(flags["expected-codes"]),
+		}
+		if h.HTTPConfig.Method == "" {
+			h.HTTPConfig.Method = "GET"
+		}
+		if h.HTTPConfig.Path == "" {
+			h.HTTPConfig.Path = "/"
+		}
+		if len(h.HTTPConfig.ExpectedCodes) == 0 {
+			h.HTTPConfig.ExpectedCodes = []string{"200"}
+		}
+	case "TCP":
+		if port == 0 {
+			return h, usageErrorf("--port is required for TCP health checks")
+		}
+		h.TCPConfig = &healthcheckTCP{Method: "connection_established", Port: port}
+	default:
+		return h, usageErrorf("invalid --type %q (want HTTP, HTTPS or TCP)", h.Type)
+	}
+	return h, nil
+}
+
+func findHealthcheck(path, ref string) (*healthcheck, error) {
+	checks, err := listAll[healthcheck](path, 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range checks {
+		if h.ID == ref || h.Name == ref {
+			return &h, nil
+		}
+	}
+	return nil, notFoundErrorf("health check %q not found. run: cf healthchecks list --zone <zone>", ref)
+}
+
+// notifyHealthcheck creates a Notifications policy that emails on status
+// changes; the health check object itself no longer carries notification
+// settings.
+func notifyHealthcheck(z *zone, h healthcheck, emails []string) error {
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	mechanisms := make([]map[string]string, len(emails))
+	for i, e := range emails {
+		mechanisms[i] = map[string]string{"id": e}
+	}
+	_, err = requestCF(http.MethodPost, "/accounts/"+accountID+"/alerting/v3/policies", map[string]any{
+		"name":       "Health check: " + h.Name,
+		"alert_type": "health_check_status_notification",
+		"enabled":    true,
+		"mechanisms": map[string]any{"email": mechanisms},
+		"filters": map[string][]string{
+			"health_check_id": {h.ID},
+			"zones":           {z.ID},
+		},
+	})
+	return err
+}
+
+const healthcheckEventsQuery = `query($zoneTag: string, $id: string, $since: Time) {
+  viewer {
+    zones(filter: {zoneTag: $zoneTag}) {
+      healthCheckEventsAdaptive(limit: 1000, orderBy: [datetime_DESC], filter: {healthCheckId: $id, datetime_geq: $since}) {
+        datetime
+        healthStatus
+        region
+        failureReason
+        responseStatusCode
+        rttMs
+      }
+    }
+  }
+}`
+
+// healthcheckRegions returns the most recent event per check region since
+// the given time.
+func healthcheckRegions(zoneID, checkID string, since time.Time) ([]healthcheckRegion, error) {
+	var data struct {
+		Viewer struct {
+			Zones []struct {
+				Events []struct {
+					Datetime           string `json:"datetime"`
+					HealthStatus       string `json:"healthStatus"`
+					Region             string `json:"region"`
+					FailureReason      string `json:"failureReason"`
+					ResponseStatusCode int    `json:"responseStatusCode"`
+					RTTMs              int    `json:"rttMs"`
+				} `json:"healthCheckEventsAdaptive"`
+			} `json:"zones"`
+		} `json:"viewer"`
+	}
+	err := queryGraphQL(healthcheckEventsQuery, map[string]any{
+		"zoneTag": zoneID,
+		"id":      checkID,
+		"since":   since.UTC().Format(time.RFC3339),
+	}, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	var regions []healthcheckRegion
+	var seen []string
+	for _, zd := range data.Viewer.Zones {
+		for _, e := range zd.Events {
+			if slices.Contains(seen, e.Region) {
+				continue
+			}
+			seen = append(seen, e.Region)
+			regions = append(regions, healthcheckRegion{
+				Region:        e.Region,
+				Status:        e.HealthStatus,
+				FailureReason: e.FailureReason,
+				ResponseCode:  e.ResponseStatusCode,
+				RTTMs:         e.RTTMs,
+				CheckedAt:     e.Datetime,
+			})
+		}
+	}
+	sort.Slice(regions, func(i, j int) bool { return regions[i].Region < regions[j].Region })
+	return regions, nil
+}
+
+func printHealthcheckStatus(s healthcheckStatus, since time.Duration) {
+	fmt.Printf("%s (%s %s%s): %s", s.Name, s.Type, s.Address, s.target(), s.Status)
+	if s.FailureReason != "" {
+		fmt.Printf(" - %s", s.FailureReason)
+	}
+	fmt.Println()
+	if len(s.Regions) == 0 {
+		fmt.Printf("  no checks reported in the last %s\n", since)
+		return
+	}
+	for _, r := range s.Regions {
+		line := fmt.Sprintf("  %-6s %-9s", r.Region, r.Status)
+		if r.ResponseCode != 0 {
+			line += fmt.Sprintf(" %d", r.ResponseCode)
+		}
+		if r.RTTMs != 0 {
+			line += fmt.Sprintf(" %dms", r.RTTMs)
+		}
+		if r.FailureReason != "" {
+			line += " " + r.FailureReason
+		}
+		fmt.Println(line + "  (" + r.CheckedAt + ")")
+	}
+}
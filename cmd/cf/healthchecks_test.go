@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHealthcheckFromFlags(t *testing.T) {
+	h, err := healthcheckFromFlags("api", map[string]string{
+		"address":        "origin.example.com",
+		"path":           "/health",
+		"expected-codes": "200,204",
+		"regions":        "wnam,weu",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.Type != "HTTPS" || h.Interval != 60 || h.HTTPConfig == nil || h.HTTPConfig.Method != "GET" {
+		t.Fatalf("unexpected defaults: %+v", h)
+	}
+	if got := h.HTTPConfig.ExpectedCodes; len(got) != 2 || got[1] != "204" {
+		t.Fatalf("unexpected expected codes: %v", got)
+	}
+	if len(h.CheckRegions) != 2 || h.CheckRegions[0] != "WNAM" {
+		t.Fatalf("unexpected regions: %v", h.CheckRegions)
+	}
+	if h.target() != "/health" {
+		t.Fatalf("unexpected target: %q", h.target())
+	}
+
+	tcp, err := healthcheckFromFlags("db", map[string]string{"address": "db.example.com", "type": "tcp", "port": "5432"})
+	if err != nil || tcp.TCPConfig == nil || tcp.HTTPConfig != nil || tcp.target() != ":5432" {
+		t.Fatalf("unexpected TCP check: %+v (err=%v)", tcp, err)
+	}
+	if _, err := healthcheckFromFlags("db", map[string]string{"address": "db.example.com", "type": "tcp"}); err == nil {
+		t.Fatalf("expected error for TCP check without --port")
+	}
+	if _, err := healthcheckFromFlags("x", map[string]string{"address": "a", "type": "icmp"}); err == nil {
+		t.Fatalf("expected error for unsupported type")
+	}
+}
+
+func TestHealthchecksCreate(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("POST", "/zones/z1/healthchecks", healthcheck{ID: "hc1", Name: "api", Type: "HTTPS", Address: "origin.example.com", Interval: 60})
+	origRetries := maxRetries
+	t.Cleanup(func() { maxRetries = origRetries })
+
+	// --retries is the global API retry flag; the check's own setting is
+	// --check-retries.
+	out, err := runCLI(t, "healthchecks", "create", "api", "--zone", "example.com", "--address", "origin.example.com", "--check-retries", "5", "--retries", "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var h healthcheck
+	if posts := srv.Calls("POST", "/zones/z1/healthchecks"); len(posts) != 1 {
+		t.Fatalf("expected one POST, got %d", len(posts))
+	} else if err := posts[0].Decode(&h); err != nil {
+		t.Fatal(err)
+	}
+	if h.Retries != 5 || h.Name != "api" || h.Address != "origin.example.com" || h.Type != "HTTPS" {
+		t.Fatalf("unexpected health check %+v", h)
+	}
+	if !strings.Contains(out, "Health check created: api (id=hc1)") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	_, err = runCLI(t, "healthchecks", "create", "api", "--zone", "example.com", "--address", "origin.example.com", "--check-retries", "often")
+	if exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error for invalid --check-retries, got %v", err)
+	}
+	if n := len(srv.Calls("POST", "/zones/z1/healthchecks")); n != 1 {
+		t.Fatalf("expected no request for invalid flags, got %d POSTs", n)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cf/pkg/cfapi"
+)
+
+type cfImage struct {
+	ID                string   `json:"id"`
+	Filename          string   `json:"filename,omitempty"`
+	Uploaded          string   `json:"uploaded,omitempty"`
+	RequireSignedURLs bool     `json:"requireSignedURLs"`
+	Variants          []string `json:"variants,omitempty"`
+}
+
+// directUpload is a one-time upload URL; Images returns id and Stream uid.
+type directUpload struct {
+	ID        string `json:"id,omitempty"`
+	UID       string `json:"uid,omitempty"`
+	UploadURL string `json:"uploadURL"`
+}
+
+func runImages(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	base := "/accounts/" + accountID + "/images"
+
+	switch action {
+	case "list":
+		limit, err := parseIntWithDefault(flags["limit"], 100)
+		if err != nil {
+			return usageErrorf("invalid --limit: %w", err)
+		}
+		images, err := listImages(base, limit)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "FILENAME", "UPLOADED", "SIGNED", "VARIANTS"}}
+		for _, img := range images {
+			t.Rows = append(t.Rows, []string{img.ID, img.Filename, img.Uploaded, strconv.FormatBool(img.RequireSignedURLs), strconv.Itoa(len(img.Variants))})
+		}
+		return printList(images, t, func() {
+			if len(images) == 0 {
+				fmt.Println("No images.")
+				return
+			}
+			for _, img := range images {
+				fmt.Printf("%s\t%s\t%s\n", img.ID, img.Filename, img.Uploaded)
+			}
+		})
+	case "upload":
+		fields := map[string]string{}
+		if v := flags["id"]; v != "" {
+			fields["id"] = v
+		}
+		if parseBoolWithDefault(flags["direct"], false) {
+			body, contentType, err := multipartForm(fields, "", "", nil)
+			if err != nil {
+				return err
+			}
+			resp, err := requestCFRaw(http.MethodPost, base+"/v2/direct_upload", contentType, body)
+			if err != nil {
+				return err
+			}
+			var u directUpload
+			if err := json.Unmarshal(resp.Result, &u); err != nil {
+				return err
+			}
+			return printResult(u, func() {
+				fmt.Printf("One-time upload URL for image %s (POST a multipart \"file\" field to it):\n", u.ID)
+				fmt.Printf("  %s\n", u.UploadURL)
+			})
+		}
+
+		var body []byte
+		var contentType string
+		switch {
+		case flags["url"] != "":
+			fields["url"] = flags["url"]
+			body, contentType, err = multipartForm(fields, "", "", nil)
+		case len(positional) > 0:
+			data, rerr := os.ReadFile(positional[0])
+			if rerr != nil {
+				return rerr
+			}
+			body, contentType, err = multipartForm(fields, "file", positional[0], data)
+		default:
+			return usageErrorf("usage: cf images upload (<file> | --url <url> | --direct) [--id <custom-id>]")
+		}
+		if err != nil {
+			return err
+		}
+		resp, err := requestCFRaw(http.MethodPost, base+"/v1", contentType, body)
+		if err != nil {
+			return err
+		}
+		var img cfImage
+		if err := json.Unmarshal(resp.Result, &img); err != nil {
+			return err
+		}
+		return printResult(img, func() {
+			fmt.Printf("Image uploaded: %s (id=%s)\n", img.Filename, img.ID)
+			for _, v := range img.Variants {
+				fmt.Printf("  %s\n", v)
+			}
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf images delete <id> [--force]")
+		}
+		ok, err := confirm(flags, "images delete", fmt.Sprintf("Delete image %s?", positional[0]))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, base+"/v1/"+positional[0], nil); err != nil {
+			return err
+		}
+		return printResult(map[string]string{"id": positional[0]}, func() {
+			fmt.Printf("Image deleted: %s\n", positional[0])
+		})
+	}
+	return usageErrorf("usage: cf images list|upload|delete")
+}
+
+// listImages pages through /images/v1, whose result wraps the list in an
+// object so listAll does not apply.
+func listImages(base string, limit int) ([]cfImage, error) {
+	const perPage = 100
+	var out []cfImage
+	for page := 1; ; page++ {
+		resp, err := requestCF(http.MethodGet, cfapi.WithPageParams(base+"/v1", page, perPage), nil)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Images []cfImage `json:"images"`
+		}
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			return nil, err
+		}
+		out = append(out, result.Images...)
+		if limit > 0 && len(out) >= limit {
+			return out[:limit], nil
+		}
+		if len(result.Images) < perPage {
+			return out, nil
+		}
+	}
+}
+
+// multipartForm encodes plain fields plus an optional file part.
+func multipartForm(fields map[string]string, fileField, filename string, data []byte) ([]byte, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := w.WriteField(k, fields[k]); err != nil {
+			return nil, "", err
+		}
+	}
+	if fileField != "" {
+		part, err := w.CreateFormFile(fileField, filepath.Base(filename))
+		if err != nil {
+			return nil, "", err
+		}
+		part.Write(data)
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"testing"
+)
+
+func TestMultipartForm(t *testing.T) {
+	body, contentType, err := multipartForm(map[string]string{"id": "logo"}, "file", "/tmp/assets/logo.png", []byte("PNG"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+
+	part, err := r.NextPart()
+	if err != nil || part.FormName() != "id" {
+		t.Fatalf("expected id field first, got %v (err=%v)", part, err)
+	}
+	if v, _ := io.ReadAll(part); string(v) != "logo" {
+		t.Fatalf("unexpected id value: %q", v)
+	}
+
+	part, err = r.NextPart()
+	if err != nil || part.FormName() != "file" || part.FileName() != "logo.png" {
+		t.Fatalf("expected file part named logo.png, got %v (err=%v)", part, err)
+	}
+	if v, _ := io.ReadAll(part); string(v) != "PNG" {
+		t.Fatalf("unexpected file content: %q", v)
+	}
+	if _, err := r.NextPart(); err != io.EOF {
+		t.Fatalf("expected exactly two parts, got err=%v", err)
+	}
+}
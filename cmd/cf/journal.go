@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// journalEntry records one DNS record change with enough of the record to
+// reverse it. Entries written by the same cf invocation share a Run, and
+// cf undo reverts a run as a whole. Other changes are recorded with Action
+// "request" and only their method and path, so undo can say what it does
+// not revert.
+type journalEntry struct {
+	Run     string     `json:"run"`
+	Time    time.Time  `json:"time"`
+	Command string     `json:"command"`
+	ZoneID  string     `json:"zone_id,omitempty"`
+	Action  string     `json:"action"`
+	Before  *dnsRecord `json:"before,omitempty"`
+	After   *dnsRecord `json:"after,omitempty"`
+	Method  string     `json:"method,omitempty"`
+	Path    string     `json:"path,omitempty"`
+}
+
+// covered reports whether cf undo can revert e.
+func (e journalEntry) covered() bool {
+	return e.Action != "request"
+}
+
+// maxJournalRuns bounds the journal; older runs are dropped as new ones are
+// added.
+const maxJournalRuns = 100
+
+var (
+	journalMu      sync.Mutex
+	journalRun     = fmt.Sprintf("%d-%d", time.Now().UnixNano(), os.Getpid())
+	journalStarted bool
+	// journalOff stops cf undo from journaling its own changes, and keeps
+	// ddns, which may run for days, out of the journal.
+	journalOff bool
+)
+
+// journalPath is next to the config file, or CF_JOURNAL when set.
+func journalPath() string {
+	if v := strings.TrimSpace(os.Getenv("CF_JOURNAL")); v != "" {
+		return v
+	}
+	if p := configPath(); p != "" {
+		return filepath.Join(filepath.Dir(p), "journal.jsonl")
+	}
+	return ""
+}
+
+// journalCommand names the running command without flag values, which may
+// hold secrets.
+func journalCommand() string {
+	words := []string{"cf"}
+	for _, a := range os.Args[1:] {
+		if strings.HasPrefix(a, "-") {
+			break
+		}
+		words = append(words, a)
+	}
+	return strings.Join(words, " ")
+}
+
+// journalDNSChange appends a change to the journal. It is best effort: a
+// change that went through is not failed because it could not be journaled.
+func journalDNSChange(zoneID, action string, before, after *dnsRecord) {
+	appendJournal(journalEntry{ZoneID: zoneID, Action: action, Before: before, After: after})
+}
+
+// dnsRecordWrite matches the record writes journalDNSChange records.
+var dnsRecordWrite = regexp.MustCompile(`^/zones/[^/]+/dns_records(/[^/?]+)?$`)
+
+// journalRequest records a mutating API call that is not a DNS record
+// write. It is called before the request is sent, so a failed call is
+// recorded too; undo only reports these.
+func journalRequest(method, path string) {
+	if method == http.MethodGet || method == http.MethodHead || isReadOnlyPost(path) {
+		return
+	}
+	if method != http.MethodPatch && dnsRecordWrite.MatchString(path) {
+		return
+	}
+	appendJournal(journalEntry{Action: "request", Method: method, Path: path})
+}
+
+func appendJournal(e journalEntry) {
+	path := journalPath()
+	if dryRun || journalOff || path == "" {
+		return
+	}
+	e.Run, e.Time, e.Command = journalRun, time.Now().UTC(), journalCommand()
+	line, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	journalMu.Lock()
+	defer journalMu.Unlock()
+	if !journalStarted {
+		trimJournal()
+		journalStarted = true
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not write undo journal: %v\n", err)
+		return
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not write undo journal: %v\n", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not write undo journal: %v\n", err)
+	}
+}
+
+func readJournal() ([]journalEntry, error) {
+	f, err := os.Open(journalPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []journalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e journalEntry
+		// A line cut short by a crash is skipped rather than blocking undo.
+		if json.Unmarshal(scanner.Bytes(), &e) == nil && e.Run != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+func writeJournal(entries []journalEntry) error {
+	var b strings.Builder
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	tmp := journalPath() + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, journalPath())
+}
+
+// journalRuns groups entries by run, oldest first.
+func journalRuns(entries []journalEntry) [][]journalEntry {
+	var runs [][]journalEntry
+	index := map[string]int{}
+	for _, e := range entries {
+		i, ok := index[e.Run]
+		if !ok {
+			i = len(runs)
+			index[e.Run] = i
+			runs = append(runs, nil)
+		}
+		runs[i] = append(runs[i], e)
+	}
+	return runs
+}
+
+// trimJournal drops all but the newest maxJournalRuns runs.
+func trimJournal() {
+	entries, err := readJournal()
+	if err != nil {
+		return
+	}
+	runs := journalRuns(entries)
+	if len(runs) <= maxJournalRuns {
+		return
+	}
+	var keep []journalEntry
+	for _, run := range runs[len(runs)-maxJournalRuns:] {
+		keep = append(keep, run...)
+	}
+	_ = writeJournal(keep)
+}
+
+// inverse returns the change that reverts e.
+func (e journalEntry) inverse() (dnsChange, error) {
+	switch {
+	case e.Action == "create" && e.After != nil:
+		return dnsChange{Action: "delete", Before: e.After}, nil
+	case e.Action == "update" && e.Before != nil && e.After != nil:
+		before, after := *e.After, *e.Before
+		after.ID = before.ID
+		return dnsChange{Action: "update", Before: &before, After: &after}, nil
+	case e.Action == "delete" && e.Before != nil:
+		after := *e.Before
+		after.ID = ""
+		return dnsChange{Action: "create", After: &after}, nil
+	}
+	return dnsChange{}, fmt.Errorf("journal entry %s %s cannot be undone", e.Action, e.Time.Format(time.RFC3339))
+}
+
+func runUndo(args []string) error {
+	flags := parseFlags(args)
+	entries, err := readJournal()
+	if err != nil {
+		return fmt.Errorf("could not read the undo journal: %w", err)
+	}
+	runs := journalRuns(entries)
+	if parseBoolWithDefault(flags["list"], false) {
+		return printUndoList(runs)
+	}
+	if len(runs) == 0 {
+		return notFoundErrorf("nothing to undo: no changes are recorded in %s", journalPath())
+	}
+
+	run := runs[len(runs)-1]
+	var last, uncovered []journalEntry
+	for _, e := range run {
+		if e.covered() {
+			last = append(last, e)
+		} else {
+			uncovered = append(uncovered, e)
+		}
+	}
+	if len(last) == 0 {
+		// Nothing here can be reverted; drop the run so the next undo
+		// reaches the changes before it.
+		if !dryRun {
+			if err := writeJournal(withoutRun(entries, run[0].Run)); err != nil {
+				return fmt.Errorf("could not update the undo journal: %w", err)
+			}
+		}
+		return fmt.Errorf("cf undo cannot revert %q: it made no DNS record changes, and cf undo does not cover %s; revert them by hand (run cf undo again for the changes before it)", run[0].Command, describeRequests(uncovered))
+	}
+
+	changes := make([]dnsChange, 0, len(last))
+	for i := len(last) - 1; i >= 0; i-- {
+		c, err := last[i].inverse()
+		if err != nil {
+			return err
+		}
+		changes = append(changes, c)
+	}
+	infof("Undo %q from %s (%d change(s)):\n", last[0].Command, last[0].Time.Local().Format(time.DateTime), len(changes))
+	for _, c := range changes {
+		switch c.Action {
+		case "create":
+			infof("  recreate %s %s -> %s\n", c.After.Type, c.After.Name, c.After.Content)
+		case "update":
+			infof("  revert   %s %s: %s -> %s\n", c.After.Type, c.After.Name, c.Before.Content, c.After.Content)
+		case "delete":
+			infof("  delete   %s %s -> %s\n", c.Before.Type, c.Before.Name, c.Before.Content)
+		}
+	}
+	if len(uncovered) > 0 {
+		infof("Not reverted, as cf undo only covers DNS records: %s\n", describeRequests(uncovered))
+	}
+	if dryRun {
+		return nil
+	}
+	ok, err := confirm(flags, "undo", "Revert these changes?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted. No changes applied.")
+		return nil
+	}
+
+	// Changes are reverted one at a time, newest first, and each is dropped
+	// from the journal as it succeeds so a failure can be retried.
+	journalOff = true
+	defer func() { journalOff = false }()
+	done := 0
+	var applyErr error
+	for i, c := range changes {
+		msg, err := applyDNSChange(last[len(last)-1-i].ZoneID, c)
+		if err != nil {
+			applyErr = err
+			break
+		}
+		infof("%s", msg)
+		done++
+	}
+
+	remaining := withoutRun(entries, last[0].Run)
+	if done < len(last) {
+		remaining = append(remaining, last[:len(last)-done]...)
+		remaining = append(remaining, uncovered...)
+	}
+	if err := writeJournal(remaining); err != nil {
+		return errors.Join(applyErr, fmt.Errorf("could not update the undo journal: %w", err))
+	}
+	if applyErr != nil {
+		return fmt.Errorf("undo stopped after %d of %d change(s): %w", done, len(changes), applyErr)
+	}
+	infof("Reverted %d change(s).\n", done)
+	return nil
+}
+
+func withoutRun(entries []journalEntry, run string) []journalEntry {
+	var out []journalEntry
+	for _, e := range entries {
+		if e.Run != run {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// describeRequests lists uncovered calls, e.g. "PATCH /zones/z1/settings/ssl".
+func describeRequests(entries []journalEntry) string {
+	calls := make([]string, len(entries))
+	for i, e := range entries {
+		calls[i] = e.Method + " " + e.Path
+	}
+	return strings.Join(calls, ", ")
+}
+
+func printUndoList(runs [][]journalEntry) error {
+	type runSummary struct {
+		Time      time.Time `json:"time"`
+		Command   string    `json:"command"`
+		Changes   int       `json:"changes"`
+		Uncovered int       `json:"uncovered"`
+	}
+	out := []runSummary{}
+	for i := len(runs) - 1; i >= 0; i-- {
+		s := runSummary{Time: runs[i][0].Time, Command: runs[i][0].Command}
+		for _, e := range runs[i] {
+			if e.covered() {
+				s.Changes++
+			} else {
+				s.Uncovered++
+			}
+		}
+		out = append(out, s)
+	}
+	t := table{Headers: []string{"TIME", "COMMAND", "CHANGES", "UNCOVERED"}}
+	for _, r := range out {
+		t.Rows = append(t.Rows, []string{r.Time.Local().Format(time.DateTime), r.Command, fmt.Sprint(r.Changes), fmt.Sprint(r.Uncovered)})
+	}
+	return printList(out, t, func() {
+		if len(out) == 0 {
+			fmt.Println("No changes are recorded.")
+			return
+		}
+		for i, r := range out {
+			marker := "  "
+			if i == 0 {
+				marker = "> "
+			}
+			fmt.Printf("%s%s  %-40s %d change(s)", marker, r.Time.Local().Format(time.DateTime), r.Command, r.Changes)
+			if r.Uncovered > 0 {
+				fmt.Printf(", %d not covered by undo", r.Uncovered)
+			}
+			fmt.Println()
+		}
+		fmt.Println("cf undo reverts the run marked >.")
+	})
+}
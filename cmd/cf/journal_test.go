@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUndoRevertsLastRun(t *testing.T) {
+	srv := useFakeAPI(t)
+	t.Setenv("CF_JOURNAL", t.TempDir()+"/journal.jsonl")
+	orig := journalRun
+	t.Cleanup(func() { journalRun = orig })
+
+	www := dnsRecord{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Comment: "web", Tags: []string{"team:web"}}
+	srv.Reply("GET", "/zones/z1/dns_records/r1", www)
+	srv.Reply("PUT", "/zones/z1/dns_records/r1", dnsRecord{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.9", TTL: 1})
+	srv.Reply("DELETE", "/zones/z1/dns_records/r1", map[string]string{"id": "r1"})
+	srv.Reply("POST", "/zones/z1/dns_records", dnsRecord{ID: "r2", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1})
+
+	// Two separate runs: an update, then a delete.
+	journalRun = "run-1"
+	if _, err := captureStdout(t, func() error {
+		return runDNS([]string{"update", "--zone", "example.com", "--id", "r1", "--content", "192.0.2.9", "--yes"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	journalRun = "run-2"
+	if _, err := captureStdout(t, func() error { return runDNS([]string{"delete", "--zone", "example.com", "--id", "r1", "--yes"}) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error { return runUndo([]string{"--list"}) })
+	if err != nil || strings.Count(out, "1 change(s)") != 2 {
+		t.Fatalf("expected two runs in the journal, got err=%v:\n%s", err, out)
+	}
+
+	journalRun = "run-3"
+	out, err = captureStdout(t, func() error { return runUndo([]string{"--yes"}) })
+	if err != nil || !strings.Contains(out, "recreate A www.example.com -> 192.0.2.1") {
+		t.Fatalf("expected the delete to be undone, got err=%v:\n%s", err, out)
+	}
+	var created dnsRecord
+	if calls := srv.Calls("POST", "/zones/z1/dns_records"); len(calls) != 1 {
+		t.Fatalf("expected the record to be recreated, got %d creates", len(calls))
+	} else if err := calls[0].Decode(&created); err != nil || created.Content != "192.0.2.1" || created.Comment != "web" {
+		t.Fatalf("unexpected recreated record %+v (err=%v)", created, err)
+	}
+
+	// The update is next; undoing it puts the old content back.
+	out, err = captureStdout(t, func() error { return runUndo([]string{"--yes"}) })
+	if err != nil || !strings.Contains(out, "revert   A www.example.com: 192.0.2.9 -> 192.0.2.1") {
+		t.Fatalf("expected the update to be undone, got err=%v:\n%s", err, out)
+	}
+	puts := srv.Calls("PUT", "/zones/z1/dns_records/r1")
+	var reverted dnsRecord
+	if err := puts[len(puts)-1].Decode(&reverted); err != nil || reverted.Content != "192.0.2.1" || reverted.Comment != "web" {
+		t.Fatalf("unexpected revert %+v (err=%v)", reverted, err)
+	}
+
+	if err := runUndo([]string{"--yes"}); exitCode(err) != exitNotFound {
+		t.Fatalf("expected nothing left to undo, got %v", err)
+	}
+}
+
+func TestUndoReportsUncoveredChanges(t *testing.T) {
+	srv := useFakeAPI(t)
+	t.Setenv("CF_JOURNAL", t.TempDir()+"/journal.jsonl")
+	orig := journalRun
+	t.Cleanup(func() { journalRun = orig })
+
+	www := dnsRecord{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1}
+	srv.Reply("GET", "/zones/z1/dns_records/r1", www)
+	srv.Reply("PUT", "/zones/z1/dns_records/r1", dnsRecord{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.9", TTL: 1})
+	srv.Reply("PATCH", "/zones/z1/settings", []zoneSetting{{ID: "ssl", Value: []byte(`"strict"`)}})
+
+	journalRun = "run-1"
+	if _, err := captureStdout(t, func() error {
+		return runDNS([]string{"update", "--zone", "example.com", "--id", "r1", "--content", "192.0.2.9", "--yes"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	journalRun = "run-2"
+	if _, err := captureStdout(t, func() error {
+		return runZones([]string{"settings", "set", "example.com", "--ssl", "strict"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error { return runUndo([]string{"--list"}) })
+	if err != nil || !strings.Contains(out, "0 change(s), 1 not covered by undo") {
+		t.Fatalf("expected the settings change in the journal, got err=%v:\n%s", err, out)
+	}
+
+	// The settings change cannot be reverted, so undo says so instead of
+	// reaching past it.
+	journalRun = "run-3"
+	err = runUndo([]string{"--yes"})
+	if err == nil || !strings.Contains(err.Error(), "does not cover PATCH /zones/z1/settings") {
+		t.Fatalf("expected undo to report the uncovered change, got %v", err)
+	}
+	if n := len(srv.Calls("PUT", "/zones/z1/dns_records/r1")); n != 1 {
+		t.Fatalf("expected no DNS revert yet, got %d updates", n)
+	}
+
+	out, err = captureStdout(t, func() error { return runUndo([]string{"--yes"}) })
+	if err != nil || !strings.Contains(out, "revert   A www.example.com: 192.0.2.9 -> 192.0.2.1") {
+		t.Fatalf("expected the next undo to revert the DNS update, got err=%v:\n%s", err, out)
+	}
+}
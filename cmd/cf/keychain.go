@@ -0,0 +1,25 @@
+package main
+
+import "errors"
+
+// keychainService is the service name cf tokens are stored under in the OS
+// keychain. Entries are keyed by profile name ("default" without a profile).
+const keychainService = "cf"
+
+var errKeychainNotFound = errors.New("no token stored in keychain")
+
+type tokenStore interface {
+	Get(account string) (string, error)
+	Set(account, token string) error
+	Delete(account string) error
+}
+
+var keychain tokenStore = systemKeychain{}
+
+// keychainAccount returns the keychain entry name for the active profile.
+func keychainAccount() string {
+	if _, name, _, err := activeProfile(); err == nil && name != "" {
+		return name
+	}
+	return "default"
+}
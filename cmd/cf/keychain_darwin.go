@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// systemKeychain stores tokens in the macOS login keychain via security(1).
+type systemKeychain struct{}
+
+func (systemKeychain) Get(account string) (string, error) {
+	out, err := cmdRunner("security", "find-generic-password", "-s", keychainService, "-a", account, "-w")
+	if err != nil {
+		if strings.Contains(string(out), "could not be found") {
+			return "", errKeychainNotFound
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (systemKeychain) Set(account, token string) error {
+	out, err := securityAddCommand(account, token).CombinedOutput()
+	// security -i exits 0 when a command it read fails, so any output other
+	// than its prompt is the error.
+	msg := strings.TrimSpace(strings.ReplaceAll(string(out), "security>", ""))
+	if err != nil {
+		return fmt.Errorf("security add-generic-password: %w: %s", err, msg)
+	}
+	if msg != "" {
+		return fmt.Errorf("security add-generic-password: %s", msg)
+	}
+	return nil
+}
+
+// securityAddCommand runs add-generic-password through security's
+// interactive mode, so the token is read from stdin and never shows up in
+// the process list.
+func securityAddCommand(account, token string) *exec.Cmd {
+	args := []string{"add-generic-password", "-U", "-s", keychainService, "-a", account, "-l", "cf API token", "-w", token}
+	for i, a := range args {
+		args[i] = securityQuote(a)
+	}
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(strings.Join(args, " ") + "\n")
+	return cmd
+}
+
+// securityQuote quotes an argument for a security -i command line.
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func (systemKeychain) Delete(account string) error {
+	out, err := cmdRunner("security", "delete-generic-password", "-s", keychainService, "-a", account)
+	if err != nil && strings.Contains(string(out), "could not be found") {
+		return errKeychainNotFound
+	}
+	return err
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSecurityAddCommandKeepsTokenOutOfArgv(t *testing.T) {
+	token := "s3cr3t-token"
+	cmd := securityAddCommand("work", token)
+	for _, a := range cmd.Args {
+		if strings.Contains(a, token) {
+			t.Fatalf("token in argv: %q", cmd.Args)
+		}
+	}
+	stdin, err := io.ReadAll(cmd.Stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `"add-generic-password" "-U" "-s" "cf" "-a" "work" "-l" "cf API token" "-w" "s3cr3t-token"` + "\n"
+	if string(stdin) != want {
+		t.Fatalf("unexpected stdin:\n%q\nwant\n%q", stdin, want)
+	}
+}
+
+func TestSecurityQuote(t *testing.T) {
+	if got := securityQuote(`a "b" \c`); got != `"a \"b\" \\c"` {
+		t.Fatalf("got %s", got)
+	}
+}
@@ -0,0 +1,48 @@
+//go:build !darwin && !windows
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// systemKeychain stores tokens in the Secret Service (GNOME Keyring, KWallet)
+// via libsecret's secret-tool.
+type systemKeychain struct{}
+
+func (systemKeychain) Get(account string) (string, error) {
+	out, err := cmdRunner("secret-tool", "lookup", "service", keychainService, "account", account)
+	if err != nil {
+		// secret-tool exits 1 with no output when nothing matches.
+		if _, ok := err.(*exec.ExitError); ok && len(strings.TrimSpace(string(out))) == 0 {
+			return "", errKeychainNotFound
+		}
+		return "", err
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", errKeychainNotFound
+	}
+	return token, nil
+}
+
+func (systemKeychain) Set(account, token string) error {
+	// secret-tool reads the secret from stdin so the token never shows up in
+	// the process list.
+	cmd := exec.Command("secret-tool", "store", "--label=cf API token", "service", keychainService, "account", account)
+	cmd.Stdin = strings.NewReader(token)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("secret-tool store: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (k systemKeychain) Delete(account string) error {
+	if _, err := k.Get(account); err != nil {
+		return err
+	}
+	_, err := cmdRunner("secret-tool", "clear", "service", keychainService, "account", account)
+	return err
+}
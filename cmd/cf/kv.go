@@ -0,0 +1,279 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+type kvNamespace struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type kvKey struct {
+	Name       string `json:"name"`
+	Expiration int64  `json:"expiration,omitempty"`
+}
+
+type kvValue struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+}
+
+func runKV(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf kv namespace|list|get|put|delete")
+	}
+	if args[0] == "namespace" {
+		return runKVNamespace(args[1:])
+	}
+
+	positional, flags := splitArgs(args[1:])
+	if flags["namespace"] == "" {
+		return usageErrorf("missing required flag for kv %s: --namespace", args[0])
+	}
+	base, err := kvNamespacePath(flags["namespace"])
+	if err != nil {
+		return err
+	}
+
+	if args[0] == "list" {
+		limit, err := parseIntWithDefault(flags["limit"], 0)
+		if err != nil {
+			return usageErrorf("invalid --limit: %w", err)
+		}
+		return listKVKeys(base, flags["prefix"], limit)
+	}
+
+	if len(positional) == 0 {
+		return usageErrorf("usage: cf kv %s <key> --namespace <id>", args[0])
+	}
+	key := positional[0]
+	valuePath := base + "/values/" + url.PathEscape(key)
+
+	switch args[0] {
+	case "get":
+		data, err := requestCFBytes(http.MethodGet, valuePath)
+		if err != nil {
+			return err
+		}
+		return printResult(kvValue{Namespace: flags["namespace"], Key: key, Value: string(data)}, func() {
+			os.Stdout.Write(data)
+		})
+	case "put":
+		value, err := readKVValue(positional[1:], flags["file"])
+		if err != nil {
+			return err
+		}
+		query := url.Values{}
+		if v := flags["ttl"]; v != "" {
+			if _, err := strconv.Atoi(v); err != nil {
+				return usageErrorf("invalid --ttl: %w", err)
+			}
+			query.Set("expiration_ttl", v)
+		}
+		if v := flags["expiration"]; v != "" {
+			if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+				return usageErrorf("invalid --expiration: %w", err)
+			}
+			query.Set("expiration", v)
+		}
+		if len(query) > 0 {
+			valuePath += "?" + query.Encode()
+		}
+		if _, err := requestCFRaw(http.MethodPut, valuePath, "application/octet-stream", value); err != nil {
+			return err
+		}
+		return printResult(kvValue{Namespace: flags["namespace"], Key: key, Value: string(value)}, func() {
+			fmt.Printf("Wrote %s (%d bytes)\n", key, len(value))
+		})
+	case "delete":
+		if _, err := requestCF(http.MethodDelete, valuePath, nil); err != nil {
+			return err
+		}
+		return printResult(kvKey{Name: key}, func() {
+			fmt.Printf("Deleted %s\n", key)
+		})
+	}
+	return errors.New("unknown kv command. run: cf help")
+}
+
+// readKVValue takes the value from the command line, a file, or stdin, in
+// that order. "-" as the value also reads stdin.
+func readKVValue(positional []string, file string) ([]byte, error) {
+	switch {
+	case file != "":
+		return os.ReadFile(file)
+	case len(positional) > 0 && positional[0] != "-":
+		return []byte(positional[0]), nil
+	case len(positional) > 0 || !isTerminal(os.Stdin):
+		return io.ReadAll(os.Stdin)
+	}
+	return nil, errors.New("no value given: pass it as an argument, with --file, or on stdin")
+}
+
+func runKVNamespace(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf kv namespace list|create|delete")
+	}
+	positional, flags := splitArgs(args[1:])
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	path := "/accounts/" + accountID + "/storage/kv/namespaces"
+
+	switch args[0] {
+	case "list":
+		namespaces, err := listAll[kvNamespace](path, 100, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "TITLE"}}
+		for _, ns := range namespaces {
+			t.Rows = append(t.Rows, []string{ns.ID, ns.Title})
+		}
+		return printList(namespaces, t, func() {
+			for _, ns := range namespaces {
+				fmt.Printf("%s\t%s\n", ns.ID, ns.Title)
+			}
+		})
+	case "create":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf kv namespace create <title>")
+		}
+		resp, err := requestCF(http.MethodPost, path, map[string]string{"title": positional[0]})
+		if err != nil {
+			return err
+		}
+		var ns kvNamespace
+		if err := json.Unmarshal(resp.Result, &ns); err != nil {
+			return err
+		}
+		return printResult(ns, func() {
+			fmt.Printf("Namespace created: %s (id=%s)\n", ns.Title, ns.ID)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf kv namespace delete <id|title> [--force]")
+		}
+		if err := requireConfirmable(flags, "kv namespace delete"); err != nil {
+			return err
+		}
+		ns, err := findKVNamespace(path, positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirm(flags, "kv namespace delete", fmt.Sprintf("Delete namespace %s (id=%s) and all of its keys?", ns.Title, ns.ID))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+ns.ID, nil); err != nil {
+			return err
+		}
+		return printResult(ns, func() {
+			fmt.Printf("Namespace deleted: %s (id=%s)\n", ns.Title, ns.ID)
+		})
+	}
+	return errors.New("unknown kv namespace command. run: cf help")
+}
+
+// findKVNamespace matches a namespace by ID or title.
+func findKVNamespace(path, ref string) (*kvNamespace, error) {
+	namespaces, err := listAll[kvNamespace](path, 100, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range namespaces {
+		if ns.ID == ref || ns.Title == ref {
+			return &ns, nil
+		}
+	}
+	return nil, notFoundErrorf("kv namespace %q not found. run: cf kv namespace list", ref)
+}
+
+// kvNamespacePath returns the API path for a namespace given its ID or title.
+func kvNamespacePath(ref string) (string, error) {
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return "", err
+	}
+	path := "/accounts/" + accountID + "/storage/kv/namespaces"
+	if isHexID(ref) {
+		return path + "/" + ref, nil
+	}
+	ns, err := findKVNamespace(path, ref)
+	if err != nil {
+		return "", err
+	}
+	return path + "/" + ns.ID, nil
+}
+
+func isHexID(s string) bool {
+	if len(s) != 32 {
+		return false
+	}
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// listKVKeys follows the cursor-based pagination of the keys endpoint.
+func listKVKeys(base, prefix string, limit int) error {
+	var keys []kvKey
+	cursor := ""
+	for {
+		query := url.Values{"limit": {"1000"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		resp, err := requestCF(http.MethodGet, base+"/keys?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		var page []kvKey
+		if err := json.Unmarshal(resp.Result, &page); err != nil {
+			return err
+		}
+		keys = append(keys, page...)
+		if limit > 0 && len(keys) >= limit {
+			keys = keys[:limit]
+			break
+		}
+		if resp.ResultInfo == nil || resp.ResultInfo.Cursor == "" || len(page) == 0 {
+			break
+		}
+		cursor = resp.ResultInfo.Cursor
+	}
+
+	t := table{Headers: []string{"KEY", "EXPIRATION"}}
+	for _, k := range keys {
+		exp := ""
+		if k.Expiration > 0 {
+			exp = strconv.FormatInt(k.Expiration, 10)
+		}
+		t.Rows = append(t.Rows, []string{k.Name, exp})
+	}
+	return printList(keys, t, func() {
+		for _, k := range keys {
+			fmt.Println(k.Name)
+		}
+	})
+}
@@ -0,0 +1,14 @@
+package main
+
+import "testing"
+
+func TestIsHexID(t *testing.T) {
+	if !isHexID("0f2ac74b498b48028cb68387c421e279") {
+		t.Fatalf("expected namespace ID to be recognised")
+	}
+	for _, s := range []string{"flags", "0F2AC74B498B48028CB68387C421E279", "0f2ac74b"} {
+		if isHexID(s) {
+			t.Fatalf("did not expect %q to be treated as an ID", s)
+		}
+	}
+}
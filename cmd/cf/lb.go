@@ -0,0 +1,389 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var lbSteeringPolicies = []string{"off", "geo", "random", "dynamic_latency", "proximity", "least_outstanding_requests", "least_connections"}
+
+type lbOrigin struct {
+	Name    string  `json:"name"`
+	Address string  `json:"address"`
+	Enabled bool    `json:"enabled"`
+	Weight  float64 `json:"weight,omitempty"`
+}
+
+type lbPool struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Enabled     bool       `json:"enabled"`
+	Healthy     *bool      `json:"healthy,omitempty"`
+	Monitor     string     `json:"monitor,omitempty"`
+	Origins     []lbOrigin `json:"origins"`
+}
+
+type lbMonitor struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	Description   string `json:"description,omitempty"`
+	Method        string `json:"method,omitempty"`
+	Path          string `json:"path,omitempty"`
+	ExpectedCodes string `json:"expected_codes,omitempty"`
+	Interval      int    `json:"interval,omitempty"`
+	Timeout       int    `json:"timeout,omitempty"`
+	Retries       int    `json:"retries,omitempty"`
+}
+
+type loadBalancer struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Enabled        bool     `json:"enabled"`
+	Proxied        bool     `json:"proxied"`
+	DefaultPools   []string `json:"default_pools"`
+	FallbackPool   string   `json:"fallback_pool"`
+	SteeringPolicy string   `json:"steering_policy,omitempty"`
+}
+
+func runLB(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf lb list|create|pools|monitors")
+	}
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	base := "/accounts/" + accountID + "/load_balancers"
+
+	switch args[0] {
+	case "pools":
+		return runLBPools(base+"/pools", base+"/monitors", args[1:])
+	case "monitors":
+		return runLBMonitors(base+"/monitors", args[1:])
+	case "list", "create":
+		return runLoadBalancers(base+"/pools", args[0], args[1:])
+	}
+	return errors.New("unknown lb command. run: cf help")
+}
+
+// parseOrigins reads "name=address[:weight]" entries. A bare address is
+// also its own name.
+func parseOrigins(v string) ([]lbOrigin, error) {
+	var out []lbOrigin
+	for _, item := range splitList(v) {
+		name, addr, ok := strings.Cut(item, "=")
+		if !ok {
+			name, addr = item, item
+		}
+		o := lbOrigin{Name: name, Address: addr, Enabled: true, Weight: 1}
+		if i := strings.LastIndex(addr, ":"); i > 0 && !strings.Contains(addr[:i], ":") {
+			w, err := strconv.ParseFloat(addr[i+1:], 64)
+			if err != nil || w < 0 || w > 1 {
+				return nil, fmt.Errorf("invalid weight in origin %q (want 0-1)", item)
+			}
+			o.Address, o.Weight = addr[:i], w
+			if !ok {
+				o.Name = o.Address
+			}
+		}
+		out = append(out, o)
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no origins given")
+	}
+	return out, nil
+}
+
+func runLBPools(path, monitorsPath string, args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+
+	switch action {
+	case "list":
+		pools, err := listAll[lbPool](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "ENABLED", "HEALTHY", "ORIGINS", "MONITOR"}}
+		for _, p := range pools {
+			t.Rows = append(t.Rows, []string{p.ID, p.Name, fmt.Sprint(p.Enabled), poolHealth(p), originSummary(p.Origins), p.Monitor})
+		}
+		return printList(pools, t, func() {
+			for _, p := range pools {
+				fmt.Printf("%s\t%s\t%s\t%s\n", p.ID, p.Name, poolHealth(p), originSummary(p.Origins))
+			}
+		})
+	case "create", "update":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf lb pools %s <name> --origins <name=addr[:weight],...> [--monitor <id>]", action)
+		}
+		body := map[string]any{}
+		if v := flags["origins"]; v != "" {
+			origins, err := parseOrigins(v)
+			if err != nil {
+				return err
+			}
+			body["origins"] = origins
+		} else if action == "create" {
+			return usageErrorf("missing required flag for lb pools create: --origins")
+		}
+		if v := flags["monitor"]; v != "" {
+			if v == "none" {
+				body["monitor"] = nil
+			} else {
+				body["monitor"] = v
+			}
+		}
+		if v := flags["description"]; v != "" {
+			body["description"] = v
+		}
+		if v := flags["enabled"]; v != "" {
+			on, err := parseOnOff(v)
+			if err != nil {
+				return usageErrorf("invalid --enabled: %w", err)
+			}
+			body["enabled"] = on
+		}
+		if v := flags["notification-email"]; v != "" {
+			body["notification_email"] = v
+		}
+
+		var resp apiResponse
+		var err error
+		if action == "create" {
+			body["name"] = positional[0]
+			resp, err = requestCF(http.MethodPost, path, body)
+		} else {
+			if len(body) == 0 {
+				return errors.New("nothing to update: pass --origins, --monitor, --enabled or --description")
+			}
+			var p *lbPool
+			if p, err = findLBPool(path, positional[0]); err != nil {
+				return err
+			}
+			resp, err = requestCF(http.MethodPatch, path+"/"+p.ID, body)
+		}
+		if err != nil {
+			return err
+		}
+		var p lbPool
+		if err := json.Unmarshal(resp.Result, &p); err != nil {
+			return err
+		}
+		return printResult(p, func() {
+			fmt.Printf("Pool %sd: %s (id=%s) origins=%s\n", action, p.Name, p.ID, originSummary(p.Origins))
+		})
+	}
+	return usageErrorf("usage: cf lb pools list|create|update")
+}
+
+func poolHealth(p lbPool) string {
+	if p.Healthy == nil {
+		return "unknown"
+	}
+	if *p.Healthy {
+		return "healthy"
+	}
+	return "unhealthy"
+}
+
+func originSummary(origins []lbOrigin) string {
+	parts := make([]string, len(origins))
+	for i, o := range origins {
+		parts[i] = o.Address
+		if !o.Enabled {
+			parts[i] += " (disabled)"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func findLBPool(path, ref string) (*lbPool, error) {
+	pools, err := listAll[lbPool](path, 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pools {
+		if p.ID == ref || p.Name == ref {
+			return &p, nil
+		}
+	}
+	return nil, notFoundErrorf("pool %q not found. run: cf lb pools list", ref)
+}
+
+func runLBMonitors(path string, args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+
+	switch action {
+	case "list":
+		monitors, err := listAll[lbMonitor](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "TYPE", "PATH", "EXPECTED", "INTERVAL", "DESCRIPTION"}}
+		for _, m := range monitors {
+			t.Rows = append(t.Rows, []string{m.ID, m.Type, m.Path, m.ExpectedCodes, strconv.Itoa(m.Interval), m.Description})
+		}
+		return printList(monitors, t, func() {
+			for _, m := range monitors {
+				fmt.Printf("%s\t%s %s\texpect %s every %ds\n", m.ID, m.Type, m.Path, m.ExpectedCodes, m.Interval)
+			}
+		})
+	case "create":
+		m, err := lbMonitorFromFlags(flags)
+		if err != nil {
+			return err
+		}
+		resp, err := requestCF(http.MethodPost, path, m)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, &m); err != nil {
+			return err
+		}
+		return printResult(m, func() {
+			fmt.Printf("Monitor created: %s %s (id=%s)\n", m.Type, m.Path, m.ID)
+			fmt.Printf("Attach it with: cf lb pools update <pool> --monitor %s\n", m.ID)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf lb monitors delete <monitor-id>")
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+positional[0], nil); err != nil {
+			return err
+		}
+		return printResult(map[string]string{"id": positional[0]}, func() {
+			fmt.Printf("Monitor deleted: %s\n", positional[0])
+		})
+	}
+	return usageErrorf("usage: cf lb monitors list|create|delete")
+}
+
+func lbMonitorFromFlags(flags map[string]string) (lbMonitor, error) {
+	m := lbMonitor{
+		Type:          strings.ToLower(flags["type"]),
+		Description:   flags["description"],
+		Path:          flags["path"],
+		ExpectedCodes: flags["expected-codes"],
+		Method:        strings.ToUpper(flags["method"]),
+	}
+	if m.Type == "" {
+		m.Type = "https"
+	}
+	if !slices.Contains([]string{"http", "https", "tcp", "udp_icmp", "icmp_ping", "smtp"}, m.Type) {
+		return m, usageErrorf("invalid --type %q", m.Type)
+	}
+	if m.Type == "http" || m.Type == "https" {
+		if m.Path == "" {
+			m.Path = "/"
+		}
+		if m.ExpectedCodes == "" {
+			m.ExpectedCodes = "2xx"
+		}
+		if m.Method == "" {
+			m.Method = "GET"
+		}
+	}
+	var err error
+	if m.Interval, err = parseIntWithDefault(flags["interval"], 60); err != nil {
+		return m, usageErrorf("invalid --interval: %w", err)
+	}
+	if m.Timeout, err = parseIntWithDefault(flags["timeout"], 5); err != nil {
+		return m, usageErrorf("invalid --timeout: %w", err)
+	}
+	if m.Retries, err = parseIntWithDefault(flags["monitor-retries"], 2); err != nil {
+		return m, usageErrorf("invalid --monitor-retries: %w", err)
+	}
+	return m, nil
+}
+
+func runLoadBalancers(poolsPath, action string, args []string) error {
+	positional, flags := splitArgs(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for lb %s: --zone", action)
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	path := "/zones/" + z.ID + "/load_balancers"
+
+	if action == "list" {
+		lbs, err := listAll[loadBalancer](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "HOSTNAME", "ENABLED", "STEERING", "POOLS", "FALLBACK"}}
+		for _, lb := range lbs {
+			t.Rows = append(t.Rows, []string{lb.ID, lb.Name, fmt.Sprint(lb.Enabled), lb.SteeringPolicy, strings.Join(lb.DefaultPools, ","), lb.FallbackPool})
+		}
+		return printList(lbs, t, func() {
+			if len(lbs) == 0 {
+				fmt.Printf("No load balancers on %s.\n", z.Name)
+				return
+			}
+			for _, lb := range lbs {
+				fmt.Printf("%s\t%s\tsteering=%s pools=%d\n", lb.ID, lb.Name, lb.SteeringPolicy, len(lb.DefaultPools))
+			}
+		})
+	}
+
+	if len(positional) == 0 || flags["pools"] == "" {
+		return usageErrorf("usage: cf lb create <hostname> --zone <zone> --pools <a,b> [--fallback <pool>] [--steering <policy>] [--proxied true|false]")
+	}
+	var poolIDs []string
+	for _, ref := range splitList(flags["pools"]) {
+		p, err := findLBPool(poolsPath, ref)
+		if err != nil {
+			return err
+		}
+		poolIDs = append(poolIDs, p.ID)
+	}
+	fallback := poolIDs[len(poolIDs)-1]
+	if v := flags["fallback"]; v != "" {
+		p, err := findLBPool(poolsPath, v)
+		if err != nil {
+			return err
+		}
+		fallback = p.ID
+	}
+	steering := flags["steering"]
+	if steering == "" {
+		steering = "off"
+	}
+	if !slices.Contains(lbSteeringPolicies, steering) {
+		return usageErrorf("invalid --steering %q (want one of: %s)", steering, strings.Join(lbSteeringPolicies, ", "))
+	}
+
+	resp, err := requestCF(http.MethodPost, path, map[string]any{
+		"name":            qualifyRecordName(positional[0], z.Name),
+		"default_pools":   poolIDs,
+		"fallback_pool":   fallback,
+		"steering_policy": steering,
+		"proxied":         parseBoolWithDefault(flags["proxied"], true),
+	})
+	if err != nil {
+		return err
+	}
+	var lb loadBalancer
+	if err := json.Unmarshal(resp.Result, &lb); err != nil {
+		return err
+	}
+	return printResult(lb, func() {
+		fmt.Printf("Load balancer created: %s (id=%s) pools=%d fallback=%s steering=%s\n", lb.Name, lb.ID, len(lb.DefaultPools), lb.FallbackPool, lb.SteeringPolicy)
+	})
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseOrigins(t *testing.T) {
+	got, err := parseOrigins("primary=203.0.113.10, backup=origin.example.net:0.5, 198.51.100.7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []lbOrigin{
+		{Name: "primary", Address: "203.0.113.10", Enabled: true, Weight: 1},
+		{Name: "backup", Address: "origin.example.net", Enabled: true, Weight: 0.5},
+		{Name: "198.51.100.7", Address: "198.51.100.7", Enabled: true, Weight: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected origins: %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("origin %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	v6, err := parseOrigins("v6=2001:db8::1")
+	if err != nil || v6[0].Address != "2001:db8::1" || v6[0].Weight != 1 {
+		t.Fatalf("IPv6 address must not be split into a weight: %+v (err=%v)", v6, err)
+	}
+	if _, err := parseOrigins("a=origin.example.net:2"); err == nil {
+		t.Fatalf("expected error for weight above 1")
+	}
+	if _, err := parseOrigins(""); err == nil {
+		t.Fatalf("expected error for empty origins")
+	}
+}
+
+func TestLBMonitorsCreate(t *testing.T) {
+	srv := useFakeAPI(t)
+	const path = "/accounts/acc1/load_balancers/monitors"
+	srv.Reply("POST", path, lbMonitor{ID: "m1", Type: "https", Path: "/health"})
+	origRetries := maxRetries
+	t.Cleanup(func() { maxRetries = origRetries })
+
+	// --retries is the global API retry flag, so the monitor's own setting
+	// needs a name of its own to survive global flag parsing.
+	out, err := runCLI(t, "lb", "monitors", "create", "--type", "https", "--path", "/health", "--monitor-retries", "4", "--retries", "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var m lbMonitor
+	if posts := srv.Calls("POST", path); len(posts) != 1 {
+		t.Fatalf("expected one POST, got %d", len(posts))
+	} else if err := posts[0].Decode(&m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Retries != 4 || m.Type != "https" || m.Path != "/health" || m.ExpectedCodes != "2xx" || m.Method != "GET" {
+		t.Fatalf("unexpected monitor %+v", m)
+	}
+	if !strings.Contains(out, "Monitor created: https /health (id=m1)") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	if _, err := runCLI(t, "lb", "monitors", "create", "--monitor-retries", "often"); exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error for invalid --monitor-retries, got %v", err)
+	}
+	if n := len(srv.Calls("POST", path)); n != 1 {
+		t.Fatalf("expected no request for invalid flags, got %d POSTs", n)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const tokenDashboardURL = "https://dash.cloudflare.com/profile/api-tokens"
+
+func runLogin(flags map[string]string) error {
+	token := strings.TrimSpace(flags["token"])
+	if token == "" {
+		var err error
+		token, err = readLoginToken()
+		if err != nil {
+			return err
+		}
+	}
+	if token == "" {
+		return errors.New("no API token provided")
+	}
+
+	v, err := verifyToken(token)
+	if err != nil {
+		return fmt.Errorf("token verification failed: %w", err)
+	}
+	if v.Status != "active" {
+		return fmt.Errorf("token %s is %s, not active", v.ID, v.Status)
+	}
+
+	account := keychainAccount()
+	if err := keychain.Set(account, token); err != nil {
+		return fmt.Errorf("could not store token in OS keychain: %w", err)
+	}
+
+	return printResult(v, func() {
+		fmt.Printf("Token verified (id=%s, status=%s) and stored in the OS keychain as %q.\n", v.ID, v.Status, account)
+	})
+}
+
+// readLoginToken reads the token from stdin when it is piped in, otherwise
+// offers to open the dashboard and prompts without echoing input.
+func readLoginToken() (string, error) {
+	reader := bufio.NewReader(os.Stdin)
+	if !isTerminal(os.Stdin) {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	fmt.Println("Create an API token in the Cloudflare dashboard (e.g. with the \"Edit zone DNS\" template):")
+	fmt.Println(tokenDashboardURL)
+	openNow, err := promptYesNo(reader, "Open the dashboard URL in your browser now?", true)
+	if err != nil {
+		return "", err
+	}
+	if openNow {
+		if err := openURL(tokenDashboardURL); err != nil {
+			fmt.Printf("Could not open browser automatically: %v\n", err)
+		}
+	}
+
+	return readSecret(reader, "Paste API token")
+}
+
+func runLogout() error {
+	account := keychainAccount()
+	err := keychain.Delete(account)
+	if errors.Is(err, errKeychainNotFound) {
+		fmt.Printf("No token stored in the OS keychain for %q.\n", account)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed token for %q from the OS keychain.\n", account)
+	return nil
+}
+
+func verifyToken(token string) (*tokenVerification, error) {
+	return newClient(credentials{Token: token}).VerifyToken(rootCtx)
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// readSecret prompts for a value with terminal echo disabled where stty is
+// available.
+func readSecret(reader *bufio.Reader, question string) (string, error) {
+	if runtime.GOOS != "windows" && setTerminalEcho(false) == nil {
+		defer func() {
+			setTerminalEcho(true)
+			fmt.Println()
+		}()
+	}
+	return prompt(reader, question, "")
+}
+
+func setTerminalEcho(on bool) error {
+	arg := "-echo"
+	if on {
+		arg = "echo"
+	}
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
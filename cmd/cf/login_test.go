@@ -0,0 +1,69 @@
+package main
+
+import "testing"
+
+type fakeKeychain map[string]string
+
+func (k fakeKeychain) Get(account string) (string, error) {
+	if v, ok := k[account]; ok {
+		return v, nil
+	}
+	return "", errKeychainNotFound
+}
+
+func (k fakeKeychain) Set(account, token string) error {
+	k[account] = token
+	return nil
+}
+
+func (k fakeKeychain) Delete(account string) error {
+	if _, ok := k[account]; !ok {
+		return errKeychainNotFound
+	}
+	delete(k, account)
+	return nil
+}
+
+func useFakeKeychain(t *testing.T, entries fakeKeychain) {
+	t.Helper()
+	orig := keychain
+	keychain = entries
+	t.Cleanup(func() {
+		keychain = orig
+		authSource = ""
+	})
+}
+
+func TestResolveAPIToken_Keychain(t *testing.T) {
+	useTestConfig(t, `
+[profiles.work]
+account_id = "work-account"
+`)
+	useFakeKeychain(t, fakeKeychain{"default": "default-token", "work": "work-token"})
+	t.Setenv("CF_API_TOKEN", "")
+	t.Setenv("CLOUDFLARE_API_TOKEN", "")
+
+	token, err := resolveAPIToken()
+	if err != nil || token != "default-token" {
+		t.Fatalf("expected default keychain token, got %q (err=%v)", token, err)
+	}
+	if authSource != "keychain (default)" {
+		t.Fatalf("unexpected auth source: %s", authSource)
+	}
+
+	cachedAPIToken = ""
+	profileName = "work"
+	token, err = resolveAPIToken()
+	if err != nil || token != "work-token" {
+		t.Fatalf("expected profile keychain token, got %q (err=%v)", token, err)
+	}
+}
+
+func TestRunLogout_NothingStored(t *testing.T) {
+	useTestConfig(t, "")
+	useFakeKeychain(t, fakeKeychain{})
+
+	if err := runLogout(); err != nil {
+		t.Fatalf("expected logout without stored token to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type logpushJob struct {
+	ID              int                   `json:"id,omitempty"`
+	Name            string                `json:"name,omitempty"`
+	Dataset         string                `json:"dataset"`
+	Enabled         bool                  `json:"enabled"`
+	DestinationConf string                `json:"destination_conf"`
+	OutputOptions   *logpushOutputOptions `json:"output_options,omitempty"`
+	Filter          string                `json:"filter,omitempty"`
+	LastComplete    string                `json:"last_complete,omitempty"`
+	LastError       string                `json:"last_error,omitempty"`
+	ErrorMessage    string                `json:"error_message,omitempty"`
+
+	OwnershipChallenge string `json:"ownership_challenge,omitempty"`
+}
+
+type logpushOutputOptions struct {
+	FieldNames      []string `json:"field_names"`
+	TimestampFormat string   `json:"timestamp_format,omitempty"`
+}
+
+type logpushOwnership struct {
+	Filename string `json:"filename"`
+	Message  string `json:"message"`
+	Valid    bool   `json:"valid"`
+}
+
+func runLogpush(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for logpush: --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	base := "/zones/" + z.ID + "/logpush"
+
+	switch action {
+	case "list":
+		resp, err := requestCF(http.MethodGet, base+"/jobs", nil)
+		if err != nil {
+			return err
+		}
+		var jobs []logpushJob
+		if err := json.Unmarshal(resp.Result, &jobs); err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "DATASET", "ENABLED", "DESTINATION", "LAST_COMPLETE", "LAST_ERROR"}}
+		for _, j := range jobs {
+			t.Rows = append(t.Rows, []string{strconv.Itoa(j.ID), j.Name, j.Dataset, strconv.FormatBool(j.Enabled), redactDestination(j.DestinationConf), j.LastComplete, j.LastError})
+		}
+		return printList(jobs, t, func() {
+			if len(jobs) == 0 {
+				fmt.Printf("No Logpush jobs on %s.\n", z.Name)
+				return
+			}
+			for _, j := range jobs {
+				fmt.Printf("%d\t%s\t%s -> %s\tenabled=%t\n", j.ID, j.Name, j.Dataset, redactDestination(j.DestinationConf), j.Enabled)
+				if j.ErrorMessage != "" {
+					fmt.Printf("\tlast error: %s\n", j.ErrorMessage)
+				}
+			}
+		})
+	case "create":
+		dest := flags["destination"]
+		if dest == "" {
+			return usageErrorf("usage: cf logpush create --zone <zone> --destination <r2://...|s3://...|gs://...|https://...> [--dataset http_requests] [--fields a,b,c] [--name <name>] [--ownership-challenge <token>]")
+		}
+		kind, err := logpushDestinationKind(dest)
+		if err != nil {
+			return err
+		}
+		challenge := flags["ownership-challenge"]
+		if challenge == "" && logpushNeedsOwnership(kind) {
+			return fmt.Errorf("%s destinations need an ownership challenge. run: cf logpush validate --zone %s --destination '%s'", kind, z.Name, dest)
+		}
+		dataset := flags["dataset"]
+		if dataset == "" {
+			dataset = "http_requests"
+		}
+		fields := splitList(flags["fields"])
+		if len(fields) == 0 {
+			if fields, err = logpushFields(base, dataset); err != nil {
+				return err
+			}
+		}
+		job := logpushJob{
+			Name:               flags["name"],
+			Dataset:            dataset,
+			Enabled:            parseBoolWithDefault(flags["enabled"], true),
+			DestinationConf:    dest,
+			Filter:             flags["filter"],
+			OwnershipChallenge: challenge,
+			OutputOptions:      &logpushOutputOptions{FieldNames: fields, TimestampFormat: "rfc3339"},
+		}
+		if job.Name == "" {
+			job.Name = strings.ReplaceAll(z.Name, ".", "-") + "-" + strings.ReplaceAll(dataset, "_", "-")
+		}
+		resp, err := requestCF(http.MethodPost, base+"/jobs", job)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, &job); err != nil {
+			return err
+		}
+		return printResult(job, func() {
+			fmt.Printf("Logpush job created: %s (id=%d) %s -> %s, %d fields\n", job.Name, job.ID, job.Dataset, redactDestination(job.DestinationConf), len(fields))
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf logpush delete <job-id> --zone <zone> [--force]")
+		}
+		ok, err := confirm(flags, "logpush delete", fmt.Sprintf("Delete Logpush job %s on %s?", positional[0], z.Name))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, base+"/jobs/"+positional[0], nil); err != nil {
+			return err
+		}
+		return printResult(map[string]string{"id": positional[0]}, func() {
+			fmt.Printf("Logpush job deleted: %s\n", positional[0])
+		})
+	case "validate":
+		return validateLogpushDestination(base, z.Name, flags["destination"], flags["challenge"])
+	case "fields":
+		dataset := flags["dataset"]
+		if len(positional) > 0 {
+			dataset = positional[0]
+		}
+		if dataset == "" {
+			dataset = "http_requests"
+		}
+		fields, err := logpushFields(base, dataset)
+		if err != nil {
+			return err
+		}
+		return printResult(fields, func() {
+			for _, f := range fields {
+				fmt.Println(f)
+			}
+		})
+	}
+	return usageErrorf("usage: cf logpush list|create|delete|validate|fields --zone <zone>")
+}
+
+// validateLogpushDestination runs the two-step ownership flow: without a
+// challenge Cloudflare writes a token file to the destination; with one it
+// checks the token.
+func validateLogpushDestination(base, zoneName, dest, challenge string) error {
+	if dest == "" {
+		return usageErrorf("usage: cf logpush validate --zone <zone> --destination <conf> [--challenge <token>]")
+	}
+	kind, err := logpushDestinationKind(dest)
+	if err != nil {
+		return err
+	}
+
+	if challenge == "" {
+		if !logpushNeedsOwnership(kind) {
+			infof("%s destinations do not need an ownership challenge; create the job directly.\n", kind)
+		}
+		resp, err := requestCF(http.MethodPost, base+"/ownership", map[string]string{"destination_conf": dest})
+		if err != nil {
+			return err
+		}
+		var o logpushOwnership
+		if err := json.Unmarshal(resp.Result, &o); err != nil {
+			return err
+		}
+		return printResult(o, func() {
+			fmt.Printf("Cloudflare wrote an ownership challenge to %s\n", o.Filename)
+			fmt.Println("Copy the token from that file, then run:")
+			fmt.Printf("  cf logpush validate --zone %s --destination '%s' --challenge <token>\n", zoneName, dest)
+		})
+	}
+
+	resp, err := requestCF(http.MethodPost, base+"/ownership/validate", map[string]string{
+		"destination_conf":    dest,
+		"ownership_challenge": challenge,
+	})
+	if err != nil {
+		return err
+	}
+	var o logpushOwnership
+	if err := json.Unmarshal(resp.Result, &o); err != nil {
+		return err
+	}
+	if err := printResult(o, func() {
+		if o.Valid {
+			fmt.Println("Ownership challenge is valid. Create the job with:")
+			fmt.Printf("  cf logpush create --zone %s --destination '%s' --ownership-challenge %s\n", zoneName, dest, challenge)
+		}
+	}); err != nil {
+		return err
+	}
+	if !o.Valid {
+		return errors.New("ownership challenge is not valid for this destination")
+	}
+	return nil
+}
+
+func logpushFields(base, dataset string) ([]string, error) {
+	resp, err := requestCF(http.MethodGet, base+"/datasets/"+dataset+"/fields", nil)
+	if err != nil {
+		return nil, err
+	}
+	var byName map[string]string
+	if err := json.Unmarshal(resp.Result, &byName); err != nil {
+		return nil, err
+	}
+	fields := make([]string, 0, len(byName))
+	for name := range byName {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// logpushDestinationKind names the destination type from its scheme.
+func logpushDestinationKind(dest string) (string, error) {
+	scheme, _, ok := strings.Cut(dest, "://")
+	if ok {
+		switch strings.ToLower(scheme) {
+		case "r2":
+			return "R2", nil
+		case "s3":
+			return "S3", nil
+		case "gs":
+			return "GCS", nil
+		case "https":
+			return "HTTP", nil
+		}
+	}
+	return "", fmt.Errorf("unsupported destination %q (want r2://, s3://, gs:// or https://)", redactDestination(dest))
+}
+
+// logpushNeedsOwnership reports whether Cloudflare requires proof of
+// ownership before pushing to this kind of destination. R2 is proven by the
+// credentials in the URL and HTTP endpoints are not challenged.
+func logpushNeedsOwnership(kind string) bool {
+	return kind == "S3" || kind == "GCS"
+}
+
+// redactDestination hides query parameters, which carry R2 secrets and
+// HTTP auth headers.
+func redactDestination(dest string) string {
+	if base, _, ok := strings.Cut(dest, "?"); ok {
+		return base + "?..."
+	}
+	return dest
+}
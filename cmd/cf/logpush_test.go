@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestLogpushDestinationKind(t *testing.T) {
+	cases := map[string]string{
+		"r2://logs/http/{DATE}?account-id=abc&access-key-id=k&secret-access-key=s": "R2",
+		"s3://logs/http?region=us-east-1":                                          "S3",
+		"gs://logs/http":                                                           "GCS",
+		"https://logs.example.com/ingest?header_Authorization=Bearer%20x":          "HTTP",
+	}
+	for dest, want := range cases {
+		got, err := logpushDestinationKind(dest)
+		if err != nil || got != want {
+			t.Fatalf("logpushDestinationKind(%q) = %q, %v; want %q", dest, got, err, want)
+		}
+	}
+	if _, err := logpushDestinationKind("ftp://logs"); err == nil {
+		t.Fatalf("expected error for unsupported scheme")
+	}
+	if logpushNeedsOwnership("R2") || !logpushNeedsOwnership("S3") {
+		t.Fatalf("unexpected ownership requirements")
+	}
+}
+
+func TestRedactDestination(t *testing.T) {
+	if got := redactDestination("r2://logs?secret-access-key=s"); got != "r2://logs?..." {
+		t.Fatalf("unexpected redaction: %q", got)
+	}
+	if got := redactDestination("gs://logs/http"); got != "gs://logs/http" {
+		t.Fatalf("unexpected redaction: %q", got)
+	}
+}
@@ -2,234 +2,570 @@ package main
 
 import (
 	"bufio"
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"runtime"
 	"strconv"
 	"strings"
-)
+	"time"
 
-const apiBase = "https://api.cloudflare.com/client/v4"
+	"cf/pkg/cfapi"
+)
 
 var cachedAPIToken string
+
+// authSource describes where cachedAPIToken came from, for diagnostics.
+var authSource string
 var cachedAccountID string
 var cmdRunner = func(name string, args ...string) ([]byte, error) {
-	return exec.Command(name, args...).CombinedOutput()
-}
-
-type apiError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
-
-type apiResponse struct {
-	Success bool            `json:"success"`
-	Errors  []apiError      `json:"errors"`
-	Result  json.RawMessage `json:"result"`
-}
-
-type registrarDomain struct {
-	Name      string `json:"name"`
-	AutoRenew bool   `json:"auto_renew"`
-	Locked    bool   `json:"locked"`
-	Privacy   bool   `json:"privacy"`
-}
-
-type zone struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Status string `json:"status"`
-}
-
-type dnsRecord struct {
-	ID      string `json:"id"`
-	Type    string `json:"type"`
-	Name    string `json:"name"`
-	Content string `json:"content"`
+	return exec.CommandContext(rootCtx, name, args...).CombinedOutput()
 }
 
 func main() {
-	if err := run(); err != nil {
+	ctx, stop := newRootContext()
+	rootCtx = ctx
+	err := run(os.Args[1:])
+	if err != nil {
+		err = contextError(rootCtx, err)
+	}
+	stopTimeout()
+	stop()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+	if dryRun {
+		fmt.Fprintf(os.Stderr, "Dry run: %d change request(s) printed, nothing was sent.\n", dryRunSkipped)
 	}
 }
 
-func run() error {
-	args := os.Args[1:]
+func run(args []string) error {
+	args, err := parseGlobalFlags(args)
+	if err != nil {
+		return err
+	}
+	startTimeout()
 	if len(args) == 0 || isHelp(args[0]) {
+		if len(args) > 1 {
+			return printCommandHelp(args[1:])
+		}
 		printHelp()
 		return nil
 	}
 
-	switch args[0] {
-	case "wizard":
-		if len(args) > 1 && isHelp(args[1]) {
-			printWizardHelp()
-			return nil
-		}
-		return runWizard()
-	case "registrar":
-		if len(args) > 1 && args[1] == "list" {
-			return listRegistrarDomains()
-		}
-	case "zones":
-		if len(args) > 1 {
-			switch args[1] {
-			case "list":
-				return listZones()
-			case "add":
-				if len(args) < 3 {
-					return errors.New("usage: cf zones add <domain>")
-				}
-				_, err := addZone(args[2])
-				return err
-			}
-		}
-	case "dns":
-		if len(args) > 1 && args[1] == "add" {
-			flags := parseFlags(args[2:])
-			zoneName := flags["zone"]
-			typeName := strings.ToUpper(flags["type"])
-			name := flags["name"]
-			content := flags["content"]
-			ttl, err := parseIntWithDefault(flags["ttl"], 1)
-			if err != nil {
-				return fmt.Errorf("invalid --ttl: %w", err)
-			}
-			proxied := parseBoolWithDefault(flags["proxied"], false)
-
-			if zoneName == "" || typeName == "" || name == "" || content == "" {
-				return errors.New("missing required flags for dns add: --zone --type --name --content")
-			}
-
-			return addDNSRecord(zoneName, typeName, name, content, ttl, proxied)
-		}
+	handler, ok := commands[args[0]]
+	if !ok {
+		return usageErrorf("unknown command %q. run: cf help", args[0])
 	}
-
-	return errors.New("unknown command. run: cf help")
+	if path, ok := helpRequested(args); ok {
+		return printCommandHelp(path)
+	}
+	if err := checkFlags(args); err != nil {
+		return err
+	}
+	if err := setupHTTP(); err != nil {
+		return err
+	}
+	return handler(args[1:])
 }
 
 func isHelp(v string) bool {
 	return v == "help" || v == "--help" || v == "-h"
 }
 
-func printHelp() {
-	fmt.Println(`cf: Cloudflare domain helper CLI
+// helpText is the full usage. Its "  cf ..." lines also drive per-command
+// help and flag checking (see commands.go), so every flag a command reads
+// must appear on one of its lines.
+const helpText = `cf: Cloudflare domain helper CLI
 
 Usage:
-  cf help                                 Show this help message
-  cf wizard                               Guided flow to add a domain to Cloudflare
+  cf help [<command> ...]                 Show this help message, or the help for one command
+  cf login [--token <token>]              Verify an API token and store it in the OS keychain
+  cf logout                               Remove the stored API token from the OS keychain
+  cf whoami                               Show auth source, token status and permissions, and accounts
+  cf accounts list                        List the accounts the token can access, with roles
+  cf accounts use <id|name>               Save the account to use in the config file (or the active profile)
+  cf doctor                               Check config, credentials, token scopes, account, API reachability, clock and Wrangler
+  cf probe <url> [--no-follow] [--timeout 15s] [--require-cloudflare]
+                                          Fetch a URL and report redirects, timing, TLS and whether Cloudflare served it (cf-ray, cache status)
+  cf watch zone <zone> [--interval 30s] [--until-active]
+                                          Poll a zone and print status and nameserver changes until interrupted or active
+  cf watch dns --zone <zone> [--name <name>] [--type <type>] [--interval 30s]
+                                          Poll DNS records and print additions, removals and content changes
+  cf ddns --zone <zone> --name <name> [--interval 5m] [--ipv6] [--ip-source cloudflare|<url>] [--proxied true|false] [--ttl 1]
+                                          Point an A (or AAAA) record at this machine's public IP; with --interval, keep it updated
+  cf wizard [--wait] [--interval 1m] [--timeout 2h]
+                                          Guided flow to add a domain to Cloudflare
+  cf wizard --non-interactive [--answers onboard.yaml] [--domain <domain>] [--zone-type full|partial] [--scan true|false] [--website <preset>] [--website-target <name>] [--replace] [--ssl off|flexible|full|strict] [--mail-provider <provider>] [--forward-from <addr>] [--forward-to <addr>] [--wait] [--interval 1m] [--timeout 2h]
+                                          Run the wizard unattended from an answers file and flags
   cf wizard --help                        Show detailed wizard behavior and limits
-  cf registrar list                       List domains in Cloudflare Registrar
-  cf zones list                           List zones in the Cloudflare account
-  cf zones add <domain>                   Add a domain as a Cloudflare zone
-  cf dns add --zone <zone-name> --type <A|AAAA|CNAME|TXT|...> --name <record-name> --content <value> [--ttl 1] [--proxied true|false]
+  cf registrar list [--limit <n>]         List domains in Cloudflare Registrar
+  cf registrar get <domain>               Show Registrar details for a domain
+  cf registrar update <domain>... | --all [--auto-renew on|off] [--locked on|off] [--privacy on|off] [--concurrency 4]
+                                          Update Registrar settings for one or more domains, or all of them
+  cf registrar transfer <domain>          Guided transfer of a domain into Cloudflare Registrar
+  cf registrar transfer status <domain> [--wait] [--interval 30s] [--timeout 30m]
+                                          Show (or poll) transfer progress
+  cf domains check <domain>               Check availability and pricing through Cloudflare Registrar
+  cf domains whois <domain>               Look up registrar, expiry, nameservers, lock and DNSSEC status via RDAP
+  cf zones list [--limit <n>]             List zones in the Cloudflare account
+  cf zones get <zone>                     Show zone details: plan, nameservers, original registrar, paused state, dates, features
+  cf zones add <domain> [--type full|partial] [--no-scan | --jump-start false]
+                                          Add a domain as a Cloudflare zone (partial: CNAME setup, DNS stays at your provider);
+                                          --no-scan skips importing the domain's existing DNS records
+  cf zones plan get <zone>                Show a zone's plan and the plans it can switch to
+  cf zones plan set <zone>... --plan free|pro|business|enterprise [--frequency monthly|annual] [--yes] [--concurrency 4]
+                                          Change plans, after confirming the price difference
+  cf zones pause|unpause <zone>           Stop or resume proxying a zone through Cloudflare (DNS keeps resolving)
+  cf zones delete <zone> [--force]        Delete a zone (asks you to type the zone name unless --force)
+  cf zones nameservers <zone>             Show the Cloudflare nameservers assigned to a zone
+  cf zones check --zone <zone>            Run the activation check and verify nameserver delegation
+  cf zones settings get <zone> [setting...] [--all]
+                                          Show zone settings (--all dumps every setting as JSON)
+  cf zones settings set <zone> --<setting> <value> [...]
+                                          Update zone settings, e.g. --ssl strict --always-use-https on
+  cf zones settings set (--zones <glob,...> | --all-zones) --<setting> <value> [...] [--concurrency 4] [--yes]
+                                          Update the same settings in every matching zone, e.g. --zones "*.example.com"
+  cf zones dev-mode on|off|status --zone <zone>
+                                          Toggle development mode (bypasses cache for 3 hours)
+  cf dns list --zone <zone-name> [--type <type>] [--name <record-name>] [--tag <name[:value]>] [--limit <n>]
+                                          List DNS records in a zone
+  cf dns find (--content <value> | --name <text>) [--type <type>] [--concurrency 4]
+                                          Search every zone in the account for records with this content or a name containing text
+  cf dns add --zone <zone-name> --type <A|AAAA|CNAME|TXT|...> --name <record-name> --content <value> [--ttl 1] [--proxied true|false] [--comment <text>] [--tags <name:value,...>]
                                           Create a DNS record in a zone
+  cf dns add --zone <zone-name> --type <SRV|CAA|TLSA|SSHFP> --name <record-name> (--data '<json>' | --<type>-<field> <value> ...)
+                                          Create a structured record, e.g. --caa-tag issue --caa-value letsencrypt.org
+  cf dns add (--zones <glob,...> | --all-zones) --type <type> --name <record-name> (--content <value> | --data '<json>') [--ttl 1] [--proxied true|false] [--concurrency 4] [--yes]
+                                          Create the same record in every matching zone; a relative name is added to each
+  cf dns update --zone <zone-name> (--id <record-id> | --type <type> --name <record-name>) [--content <value> | --data '<json>'] [--ttl <n>] [--proxied true|false] [--comment <text>] [--tags <name:value,...>] [--yes]
+                                          Update one record, by ID or as the single record matching type and name, after confirming
+  cf dns delete --zone <zone-name> (--id <record-id> | --type <type> --name <record-name>) [--yes]
+                                          Delete one record; use --id when several records share a name (IDs are in dns list)
+  cf dns set-proxied --zone <zone-name> --name <pattern> --proxied true|false [--yes] [--concurrency 4]
+                                          Turn proxying on or off for every A/AAAA/CNAME record matching a glob such as '*' or '*.staging'
+  cf dns add --from-file <records.csv|records.json> [--zone <zone-name>] [--concurrency 4] [--set KEY=value ...]
+                                          Create many DNS records concurrently
+  cf dns sync --file <records.yaml|records.json> [--zone <zone-name>] [--dry-run] [--prune] [--yes] [--concurrency 4] [--set KEY=value ...]
+                                          Diff desired DNS records against live records and apply changes
+  cf dns backup --zone <zone-name> [--out backup.json]
+                                          Save every record, with IDs, comments, tags and structured data, as JSON
+  cf dns restore --file <backup.json> [--zone <zone-name>] [--on-conflict skip|overwrite] [--dry-run] [--yes] [--concurrency 4]
+                                          Recreate records missing from the zone; conflicting records are skipped unless overwrite
+  cf undo [--yes] [--list]                Revert the DNS record changes made by the last cf command (--list shows the journal)
+  cf export terraform [--zone <zone>] [--out <file.tf>] [--imports <import.sh>]
+                                          Write zones, DNS records, page rules and redirect rules as Terraform, with terraform import commands
+  cf apply -f <account.yaml> [--dry-run] [--yes] [--concurrency 4] [--set KEY=value ...]
+                                          Make zones, records, settings, redirects and WAF rules match one file, after showing the plan
+  cf snapshot save [--zone <zone>] [--out <snapshot.json>]
+                                          Record zones, DNS records, settings and rules (all zones unless --zone)
+  cf snapshot diff --file <snapshot.json> Report drift between a snapshot and live state; exits 8 when anything changed
+  cf dns check --zone <zone-name> --name <record-name> [--type A] [--resolvers 1.1.1.1,8.8.8.8,...]
+                                          Compare public resolvers' answers with the record in Cloudflare
+  cf dns email-setup --zone <zone> [--provider google|microsoft|fastmail] [--dmarc-policy none|quarantine|reject] [--dmarc-rua <addr>] [--dkim] [--dkim-value <txt>] [--tenant <name>] [--dry-run] [--yes]
+                                          Build SPF, DMARC and DKIM records for a mail provider (interactive by default)
+  cf dns preset email --zone <zone> --provider google-workspace|microsoft-365|fastmail [--dry-run] [--yes]
+                                          Create a mail provider's MX and SPF records, replacing other MX hosts
+  cf dns dnssec enable|disable|status --zone <zone-name>
+                                          Manage DNSSEC and show the DS record for the registrar
+  cf dns analytics --zone <zone-name> [--since 6h] [--by name|type|rcode] [--limit 10]
+                                          Show DNS query counts by record name, query type and response code
+  cf email-routing status|enable --zone <zone>
+                                          Show or enable Email Routing (creates the required MX/SPF records)
+  cf email-routing addresses list|add <email>|verify <email> [--wait] [--timeout 10m]
+                                          Manage destination addresses
+  cf email-routing rules list|add|delete --zone <zone> [--match <addr>] [--forward <addr>]
+                                          Manage forwarding rules
+  cf cache purge --zone <zone-name> (--everything | --urls <a,b> | --tags <t1,t2> | --prefixes <p1,p2> | --hosts <h1,h2> | --from-file <urls.txt>) [--concurrency 4]
+                                          Purge cached content for a zone
+  cf cache purge (--zones <glob,...> | --all-zones) (--everything | --urls <a,b> | --tags <t1,t2> | --prefixes <p1,p2> | --hosts <h1,h2> | --from-file <urls.txt>) [--concurrency 4] [--yes]
+                                          Run the same purge in every matching zone
+  cf workers list                         List Worker scripts in the account
+  cf workers deploy <name> --file <worker.js> [--compatibility-date <YYYY-MM-DD>]
+                                          Upload a single-file Worker script
+  cf workers delete <name> [--force]      Delete a Worker script
+  cf workers routes list --zone <zone>    List Worker routes on a zone
+  cf workers routes add --zone <zone> --pattern <example.com/api/*> --script <name>
+                                          Route requests matching a pattern to a Worker
+  cf workers routes delete --zone <zone> (--id <route-id> | --pattern <pattern>)
+                                          Delete a Worker route
+  cf workers cron get|set --script <name> ["<cron>" ...] [--clear]
+                                          Show or replace a Worker's Cron Triggers
+  cf workers tail <name> [--status ok|error|canceled] [--method GET,POST] [--search <text>] [--ip <a,b>] [--sampling-rate 1]
+                                          Stream live Worker logs (one JSON event per line with --json)
+  cf kv namespace list|create <title>|delete <id|title> [--force]
+                                          Manage Workers KV namespaces
+  cf kv list --namespace <id|title> [--prefix <p>] [--limit <n>]
+                                          List keys in a KV namespace
+  cf kv get <key> --namespace <id|title>  Print a KV value
+  cf kv put <key> [<value>|-] --namespace <id|title> [--file <path>] [--ttl <seconds>] [--expiration <unix>]
+                                          Write a KV value from an argument, file, or stdin
+  cf kv delete <key> --namespace <id|title>
+                                          Delete a KV key
+  cf r2 list                              List R2 buckets
+  cf r2 create <bucket> [--location <hint>]
+                                          Create an R2 bucket (location hint e.g. weur, enam, apac)
+  cf r2 delete <bucket> [--force]         Delete an empty R2 bucket
+  cf r2 usage [bucket...]                 Show object count and storage per bucket
+  cf pages list                           List Pages projects
+  cf pages deployments <project> [--env production|preview] [--limit 20]
+                                          List deployments for a Pages project
+  cf pages retry <project> [deployment-id]
+                                          Retry a deployment (default: latest production)
+  cf pages rollback <project> [deployment-id]
+                                          Roll back production (default: previous successful deployment)
+  cf tunnels list                         List Cloudflare Tunnels
+  cf tunnels create <name>                Create a remotely-managed tunnel and print its connector token
+  cf tunnels token <tunnel>               Print the connector token for a tunnel
+  cf tunnels delete <tunnel> [--force]    Delete a tunnel
+  cf tunnels config <tunnel> [get]        Show a tunnel's ingress rules
+  cf tunnels config <tunnel> set --file <config.yml>
+                                          Replace ingress rules from a cloudflared-style config file
+  cf tunnels config <tunnel> add|remove --hostname <host> [--path <regex>] [--service <url>]
+                                          Add/replace or remove a single ingress rule
+  cf tunnels route dns <tunnel> <hostname> [--zone <zone>]
+                                          Create a proxied CNAME from hostname to the tunnel
+  cf waf rules list --zone <zone>         List WAF custom rules
+  cf waf rules add --zone <zone> --expression <expr> --action <block|challenge|managed_challenge|log|...> [--description <text>] [--enabled true|false]
+                                          Add a WAF custom rule
+  cf waf rules enable|disable|delete <rule-id|description> --zone <zone>
+                                          Toggle or delete a WAF custom rule
+  cf firewall access-rules list (--zone <zone> | --account)
+                                          List IP/ASN/country access rules
+  cf firewall access-rules add <ip|cidr|ASn|country> --mode <block|challenge|whitelist|...> (--zone <zone> | --account) [--notes <text>]
+                                          Add an access rule
+  cf firewall access-rules delete <rule-id|value> (--zone <zone> | --account)
+                                          Delete an access rule
+  cf ratelimit list --zone <zone>         List rate limiting rules
+  cf ratelimit add --zone <zone> --requests <n> [--period 60] [--expression <expr>] [--action block] [--timeout <seconds>] [--characteristics <ip.src,...>] [--description <text>]
+                                          Add a rate limiting rule (counted per IP unless --characteristics is set)
+  cf ratelimit delete <rule-id|description> --zone <zone>
+                                          Delete a rate limiting rule
+  cf redirects lists [create <name>] [--description <text>]
+                                          List (or create) account Bulk Redirect lists
+  cf redirects items <list>               Show the redirects in a list
+  cf redirects import <list> --file <redirects.csv> [--status 301] [--replace]
+                                          Import source,target[,status] rows into a list (created if missing)
+  cf redirects enable <list>              Add the Bulk Redirect rule that activates a list
+  cf redirects rules list --zone <zone>   List Single Redirect rules on a zone
+  cf redirects rules add --zone <zone> (--from </path|url> | --expression <expr>) --to <url> [--status 301] [--preserve-query] [--description <text>]
+                                          Add a Single Redirect rule
+  cf redirects rules delete <rule-id|description> --zone <zone>
+                                          Delete a Single Redirect rule
+  cf pagerules list --zone <zone>         List legacy page rules
+  cf pagerules add --zone <zone> --url <pattern> [--forward <url> [--status 301|302]] [--cache-level <level>] [--ssl <mode>] [--always-use-https] [--priority 1] [--disabled]
+                                          Add a page rule
+  cf pagerules delete <rule-id> --zone <zone>
+                                          Delete a page rule
+  cf pagerules export --zone <zone> [--file <out.json>]
+                                          Export page rules with the modern rule type each action maps to
+  cf transform rewrite list|add|delete --zone <zone> [--expression <expr>] [--path <p> | --path-expression <expr>] [--query <q> | --query-expression <expr>] [--description <text>]
+                                          Manage URL rewrite rules
+  cf transform headers list|add|delete --zone <zone> [--request] [--expression <expr>] [--set "Name: value; ..."] [--remove <names>] [--description <text>]
+                                          Manage response (or with --request, request) header rules
+  cf ssl status --zone <zone>             Show SSL mode, universal SSL, certificate packs and verification
+  cf ssl mode get|set [off|flexible|full|strict] --zone <zone>
+                                          Show or change the SSL/TLS encryption mode
+  cf ssl order --zone <zone> [--hostnames <a,b>] [--ca lets_encrypt|google|ssl_com] [--validation txt|http|email] [--validity 90]
+                                          Order an advanced certificate pack
+  cf ssl total-tls enable|disable|status --zone <zone> [--ca <ca>]
+                                          Issue certificates for every proxied hostname (Total TLS)
+  cf custom-hostnames list --zone <zone>  List custom hostnames (SSL for SaaS)
+  cf custom-hostnames add <hostname> --zone <zone> [--method http|txt|email] [--origin <host>]
+                                          Add a custom hostname and show the validation records
+  cf custom-hostnames status <hostname|id> --zone <zone> [--wait] [--interval 15s] [--timeout 15m]
+                                          Show (or poll) hostname and certificate validation
+  cf custom-hostnames delete <hostname|id> --zone <zone> [--force]
+                                          Delete a custom hostname
+  cf origin-ca list --zone <zone>         List Origin CA certificates for a zone
+  cf origin-ca create (--zone <zone> | --hostnames <a,b>) [--validity 5475] [--key-type rsa|ecc] [--cert-file <path>] [--key-file <path>]
+                                          Issue an Origin CA certificate (key is generated locally)
+  cf origin-ca revoke <certificate-id> [--force]
+                                          Revoke an Origin CA certificate
+  cf lb list|create <hostname> --zone <zone> [--pools <a,b>] [--fallback <pool>] [--steering off|geo|random|dynamic_latency|proximity] [--proxied true|false]
+                                          List or create load balancers on a zone
+  cf lb pools list|create|update <name> [--origins <name=addr[:weight],...>] [--monitor <id>|none] [--enabled on|off] [--description <text>] [--notification-email <addr>]
+                                          Manage origin pools (account-level)
+  cf lb monitors list|create|delete [--type https] [--path /health] [--expected-codes 2xx] [--interval 60] [--method GET] [--timeout 5] [--monitor-retries 2] [--description <text>]
+                                          Manage health monitors attached to pools
+  cf healthchecks list|create|delete --zone <zone> [<name>] [--address <host>] [--type HTTPS|HTTP|TCP] [--path /] [--expected-codes 200] [--interval 60] [--notify <emails>] [--regions <WNAM,ENAM,...>] [--method GET] [--port <n>] [--timeout 5] [--check-retries 2] [--description <text>] [--force]
+                                          Manage standalone health checks
+  cf healthchecks status [<name>] --zone <zone> [--since 1h]
+                                          Show current health per check region
+  cf analytics --zone <zone> [--since 24h] [--by status|country|path] [--limit 10]
+                                          Show requests, bandwidth, threats and cache ratio
+  cf logpush list|fields|delete <job-id> --zone <zone> [--force]
+                                          List Logpush jobs, dataset fields, or delete a job
+  cf logpush create --zone <zone> --destination <r2://|s3://|gs://|https://...> [--dataset http_requests] [--fields a,b] [--ownership-challenge <token>] [--name <name>] [--filter <json>] [--enabled true|false]
+                                          Create a Logpush job (all dataset fields by default)
+  cf logpush validate --zone <zone> --destination <conf> [--challenge <token>]
+                                          Run the destination ownership-challenge flow
+  cf audit [--since 7d] [--actor <email>] [--action-type <type>] [--zone <zone>] [--limit 500]
+                                          Show who changed what from the account audit logs
+  cf members list [--pending]|roles       List account members, pending invitations or available roles
+  cf members invite <email> --roles <a,b> Invite a member with roles (names or IDs)
+  cf members update-role <email> --roles <a,b>
+                                          Replace a member's roles
+  cf members remove <email> [--force]     Remove a member from the account
+  cf tokens list|templates                List API tokens or the create templates
+  cf tokens create <name> (--template dns-edit | --permissions <a,b>) [--zone <zone,...>] [--expires 90d]
+                                          Create a scoped API token (value is shown once)
+  cf tokens roll|delete <name|id> [--force]
+                                          Rotate a token's secret or delete it
+  cf turnstile list                       List Turnstile widgets
+  cf turnstile create <name> --domains <a,b> [--mode managed|non-interactive|invisible]
+                                          Create a widget and print its sitekey and secret
+  cf turnstile delete <sitekey|name> [--force]
+                                          Delete a widget
+  cf turnstile rotate-secret <sitekey|name> [--invalidate-immediately]
+                                          Issue a new secret (the old one stays valid for 2h unless invalidated)
+  cf d1 list                              List D1 databases
+  cf d1 create <name> [--location <hint>] Create a D1 database
+  cf d1 delete <name|uuid> [--force]      Delete a D1 database
+  cf d1 query <name|uuid> (--sql "SELECT ..." | --file schema.sql)
+                                          Run SQL and print results as a table
+  cf queues list|create <name>            List or create Queues
+  cf queues delete <name|id> [--force]    Delete a queue
+  cf queues consumers list|add|remove --queue <queue> [--script <worker>] [--batch-size 10] [--max-retries 3] [--max-wait 5s] [--dead-letter-queue <queue>]
+                                          Manage the Workers that consume a queue
+  cf waiting-room list --zone <zone>      List waiting rooms
+  cf waiting-room create|update <name> --zone <zone> [--host <host>] [--path /] [--total-active-users <n>] [--new-users-per-minute <n>] [--queueing-method fifo|random|passthrough|reject] [--suspended on|off] [--session-duration <minutes>] [--description <text>]
+                                          Create or change a waiting room
+  cf waiting-room status <name> --zone <zone>
+                                          Show queue depth and estimated wait
+  cf spectrum list --zone <zone>          List Spectrum applications
+  cf spectrum create --zone <zone> --dns <hostname> --port <port|start-end> --origin <ip[:port]|host[:port]> [--protocol tcp|udp] [--proxy-protocol off|v1|v2|simple] [--tls off|flexible|full|strict]
+                                          Proxy a TCP/UDP service through Cloudflare
+  cf spectrum delete <id|hostname> --zone <zone> [--force]
+                                          Delete a Spectrum application
+  cf access apps list                     List Access applications
+  cf access apps create <name> --domain <host[/path]> [--session-duration 24h] [--include <rules>]
+                                          Protect a hostname with Access (optionally adding an allow policy)
+  cf access apps delete <id|name|domain> [--force]
+                                          Delete an Access application
+  cf access policies list|add|delete --app <app> [--include email-domain:example.com,email:a@b.com,ip:<cidr>,country:<cc>,everyone] [--exclude <rules>] [--require <rules>] [--decision allow|deny|bypass] [--name <name>]
+                                          Manage an application's policies
+  cf access service-tokens list|create <name> [--duration 8760h]
+                                          List or create service tokens (client ID and secret printed once)
+  cf access service-tokens rotate|delete <id|name> [--force]
+                                          Rotate a service token's secret or delete it
+  cf gateway policies list                List Gateway DNS policies
+  cf gateway policies create <name> (--domains <a,b> | --categories <names-or-ids> | --traffic <expr>) [--action block|allow|safesearch] [--precedence <n>] [--description <text>]
+                                          Add a DNS filtering policy
+  cf gateway policies delete <id|name> [--force]
+                                          Delete a DNS policy
+  cf gateway categories                   List content categories for --categories
+  cf gateway locations list|create <name> [--networks <cidr,...>] [--default]|delete <id|name>
+                                          Manage DNS locations and show their resolver addresses
+  cf images list [--limit 100]            List Cloudflare Images
+  cf images upload (<file> | --url <url> | --direct) [--id <custom-id>]
+                                          Upload an image, or print a one-time direct upload URL
+  cf images delete <id> [--force]         Delete an image
+  cf stream list                          List Stream videos
+  cf stream upload (<file> | --url <url> | --direct) [--name <name>] [--max-duration 3600]
+                                          Upload a video (files up to 200 MiB), copy one from a URL, or print a direct upload URL
+  cf stream delete <uid> [--force]        Delete a video
+
+Global flags:
+  --output <plain|table|csv|json>         Output format for results (default plain)
+  --json                                  Shorthand for --output json
+  --retries <n>                           Retries for rate-limited/transient API errors (default 3, or CF_MAX_RETRIES)
+  --max-rps <n>                           Pace API requests to n per second (default 4, Cloudflare's 1200 per 5 minutes; or CF_MAX_RPS; 0 disables)
+  --profile <name>                        Use a named profile from the config file (or CF_PROFILE)
+  --auth-mode <auto|token|key>            Force API token or legacy API key auth (or CF_AUTH_MODE)
+  --proxy <url>                           Send all requests through this proxy instead of HTTPS_PROXY/NO_PROXY; CF_CA_BUNDLE adds trusted CAs
+  --cache-ttl <duration>                  Keep zone lookups on disk for this long between runs, e.g. 5m (or CF_CACHE_TTL; default off)
+  --dry-run                               Print the method, path and body of every change instead of sending it
+  --save                                  Save the account chosen when several are available to the config file
+  --yes                                   Skip confirmation before deletes and overwrites (or CF_ASSUME_YES=1); --force works too
+  -v, --verbose                           Log each HTTP request with status, latency and Cloudflare ray ID (or CF_DEBUG=1)
+  --timeout <duration>                    Before the command: abort the run after this long (or CF_TIMEOUT); Ctrl-C also cancels cleanly
+
+Exit codes:
+  0 success, 1 other failure, 2 usage error, 3 auth error, 4 not found, 5 API error, 6 rate limited,
+  7 timed out (--timeout), 8 drift found (snapshot diff), 130 interrupted (Ctrl-C)
 
 Required env vars:
   CF_API_TOKEN or CLOUDFLARE_API_TOKEN
-  CF_ACCOUNT_ID or CLOUDFLARE_ACCOUNT_ID
-  (or Wrangler login for token fallback)
+  CF_ACCOUNT_ID or CLOUDFLARE_ACCOUNT_ID (or pick one with cf accounts use)
+  (or CF_API_KEY + CF_API_EMAIL for legacy global API key auth,
+   or a token stored by cf login, or Wrangler login for token fallback)
+
+Config file:
+  ~/.config/cf/config.toml (override with CF_CONFIG) may define named profiles:
+    default_profile = "work"
+    [profiles.work]
+    api_token_env = "WORK_CF_API_TOKEN"
+    account_id = "..."
+    zone = "example.com"
 
 Examples:
   CF_API_TOKEN=... CF_ACCOUNT_ID=... cf registrar list
   CF_API_TOKEN=... CF_ACCOUNT_ID=... cf wizard
-  CF_API_TOKEN=... CF_ACCOUNT_ID=... cf dns add --zone example.com --type A --name @ --content 1.2.3.4 --proxied false`)
-}
-
-func printWizardHelp() {
-	fmt.Print(`cf wizard: guided flow to add a domain to Cloudflare
+  CF_API_TOKEN=... CF_ACCOUNT_ID=... cf dns add --zone example.com --type A --name @ --content 1.2.3.4 --proxied false`
 
-What it does:
-  1. Ask for the domain name
-  2. If not registered, show dashboard registration URL (and optionally open browser)
-  3. Add the domain as a Cloudflare zone
-  4. Optionally add DNS records interactively
-
-What it does not do:
-  - It does not fully automate purchasing/registering a new domain via API.
-    Domain purchase still happens in Cloudflare Dashboard.
-`)
+func printHelp() {
+	fmt.Println(helpText)
 }
 
-func requestCF(method, path string, body any) (apiResponse, error) {
-	var out apiResponse
-	token, err := resolveAPIToken()
-	if err != nil {
-		return out, err
+// authMode is set by the global --auth-mode flag (or CF_AUTH_MODE).
+var authMode string
+
+func setAuthMode(v string) error {
+	switch strings.ToLower(v) {
+	case "auto", "token", "key":
+		authMode = strings.ToLower(v)
+		return nil
 	}
+	return usageErrorf("invalid --auth-mode %q: use auto, token or key", v)
+}
 
-	fullURL := apiBase + path
-	var reqBody io.Reader
-	if body != nil {
-		payload, err := json.Marshal(body)
-		if err != nil {
-			return out, err
+// resolveCredentials picks how API calls authenticate. In auto mode API
+// tokens win: the legacy global API key (CF_API_KEY + CF_API_EMAIL) is only
+// used when neither a token env var nor an explicitly selected profile
+// provides a token. --auth-mode token|key forces one or the other.
+func resolveCredentials() (credentials, error) {
+	mode := authMode
+	if mode == "" {
+		mode = strings.ToLower(strings.TrimSpace(os.Getenv("CF_AUTH_MODE")))
+	}
+
+	key, email, keyVar := legacyAPIKeyFromEnv()
+	switch mode {
+	case "key":
+		if key == "" || email == "" {
+			return credentials{}, authErrorf("--auth-mode key requires CF_API_KEY (or CLOUDFLARE_API_KEY) and CF_API_EMAIL (or CLOUDFLARE_EMAIL)")
+		}
+		authSource = "legacy API key (env " + keyVar + ")"
+		return credentials{APIKey: key, Email: email}, nil
+	case "token":
+	case "", "auto":
+		if key != "" && email != "" && !explicitTokenConfigured() {
+			authSource = "legacy API key (env " + keyVar + ")"
+			return credentials{APIKey: key, Email: email}, nil
 		}
-		reqBody = bytes.NewBuffer(payload)
+	default:
+		return credentials{}, usageErrorf("invalid CF_AUTH_MODE %q: use auto, token or key", mode)
 	}
 
-	req, err := http.NewRequest(method, fullURL, reqBody)
+	token, err := resolveAPIToken()
 	if err != nil {
-		return out, err
+		return credentials{}, err
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
+	return credentials{Token: token}, nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return out, err
+func legacyAPIKeyFromEnv() (key, email, keyVar string) {
+	for _, name := range []string{"CF_API_KEY", "CLOUDFLARE_API_KEY"} {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			key, keyVar = v, name
+			break
+		}
 	}
-	defer resp.Body.Close()
-
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return out, err
+	for _, name := range []string{"CF_API_EMAIL", "CLOUDFLARE_EMAIL"} {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			email = v
+			break
+		}
 	}
+	return key, email, keyVar
+}
 
-	if resp.StatusCode >= 400 || !out.Success {
-		return out, formatAPIErrors(out.Errors, resp.StatusCode)
+// explicitTokenConfigured reports whether a token env var or an explicitly
+// selected profile provides an API token.
+func explicitTokenConfigured() bool {
+	if strings.TrimSpace(os.Getenv("CF_API_TOKEN")) != "" || strings.TrimSpace(os.Getenv("CLOUDFLARE_API_TOKEN")) != "" {
+		return true
 	}
-
-	return out, nil
+	p, _, explicit, err := activeProfile()
+	return err == nil && explicit && p.token() != ""
 }
 
+// resolveAPIToken picks the token from, in order: an explicitly selected
+// profile (--profile or CF_PROFILE), env vars, the config's default
+// profile, the OS keychain (cf login), and finally Wrangler.
 func resolveAPIToken() (string, error) {
 	if cachedAPIToken != "" {
 		return cachedAPIToken, nil
 	}
 
+	p, name, explicit, err := activeProfile()
+	if err != nil {
+		return "", err
+	}
+	if explicit {
+		if v := p.token(); v != "" {
+			cachedAPIToken, authSource = v, "config profile "+name
+			return v, nil
+		}
+	}
+
 	if v := strings.TrimSpace(os.Getenv("CF_API_TOKEN")); v != "" {
-		cachedAPIToken = v
+		cachedAPIToken, authSource = v, "env CF_API_TOKEN"
 		return v, nil
 	}
 
 	if v := strings.TrimSpace(os.Getenv("CLOUDFLARE_API_TOKEN")); v != "" {
-		cachedAPIToken = v
+		cachedAPIToken, authSource = v, "env CLOUDFLARE_API_TOKEN"
+		return v, nil
+	}
+
+	if v := p.token(); v != "" {
+		cachedAPIToken, authSource = v, "config profile "+name
+		return v, nil
+	}
+	if p != nil && p.APITokenEnv != "" {
+		return "", authErrorf("profile %q reads its token from $%s, which is not set", name, p.APITokenEnv)
+	}
+
+	account := keychainAccount()
+	if v, err := keychain.Get(account); err == nil && v != "" {
+		cachedAPIToken, authSource = v, "keychain ("+account+")"
 		return v, nil
 	}
 
 	token, err := tokenFromWrangler()
 	if err == nil && token != "" {
-		cachedAPIToken = token
+		cachedAPIToken, authSource = token, "wrangler"
 		return token, nil
 	}
 
-	return "", errors.New("missing API token. set CF_API_TOKEN (or CLOUDFLARE_API_TOKEN), or login via Wrangler")
+	return "", authErrorf("missing API token. run: cf login, set CF_API_TOKEN (or CLOUDFLARE_API_TOKEN), or login via Wrangler")
 }
 
+// resolveAccountID follows the same precedence as resolveAPIToken, then
+// uses the account picked with cf accounts use, and finally infers the
+// account from /memberships, asking on a terminal when there are several.
 func resolveAccountID() (string, error) {
+	return lookupAccountID(true)
+}
+
+// configuredAccountID resolves the account like resolveAccountID but never
+// prompts, for commands that only report on the setup.
+func configuredAccountID() (string, error) {
+	return lookupAccountID(false)
+}
+
+func lookupAccountID(interactive bool) (string, error) {
 	if cachedAccountID != "" {
 		return cachedAccountID, nil
 	}
 
+	p, _, explicit, err := activeProfile()
+	if err != nil {
+		return "", err
+	}
+	if explicit && p.AccountID != "" {
+		cachedAccountID = p.AccountID
+		return p.AccountID, nil
+	}
+
 	if v := strings.TrimSpace(os.Getenv("CF_ACCOUNT_ID")); v != "" {
 		cachedAccountID = v
 		return v, nil
@@ -240,12 +576,22 @@ func resolveAccountID() (string, error) {
 		return v, nil
 	}
 
-	token, err := resolveAPIToken()
+	if p != nil && p.AccountID != "" {
+		cachedAccountID = p.AccountID
+		return p.AccountID, nil
+	}
+
+	if cfg, err := loadConfig(); err == nil && cfg.AccountID != "" {
+		cachedAccountID = cfg.AccountID
+		return cfg.AccountID, nil
+	}
+
+	creds, err := resolveCredentials()
 	if err != nil {
 		return "", err
 	}
 
-	accountID, err := inferAccountIDFromMemberships(token)
+	accountID, err := inferAccountIDFromMemberships(creds, interactive)
 	if err != nil {
 		return "", err
 	}
@@ -272,117 +618,107 @@ func tokenFromWrangler() (string, error) {
 	return parsed.Token, nil
 }
 
-func inferAccountIDFromMemberships(token string) (string, error) {
-	req, err := http.NewRequest(http.MethodGet, apiBase+"/memberships", nil)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
+func listMemberships(creds credentials) ([]membership, error) {
+	return newClient(creds).ListMemberships(rootCtx)
+}
 
-	resp, err := http.DefaultClient.Do(req)
+func inferAccountIDFromMemberships(creds credentials, interactive bool) (string, error) {
+	memberships, err := listMemberships(creds)
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-
-	var payload struct {
-		Success bool       `json:"success"`
-		Errors  []apiError `json:"errors"`
-		Result  []struct {
-			Account struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
-			} `json:"account"`
-		} `json:"result"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return "", err
-	}
-	if resp.StatusCode >= 400 || !payload.Success {
-		return "", formatAPIErrors(payload.Errors, resp.StatusCode)
-	}
 
-	if len(payload.Result) == 0 {
+	if len(memberships) == 0 {
 		return "", errors.New("no Cloudflare account memberships found for token")
 	}
-	if len(payload.Result) == 1 {
-		return payload.Result[0].Account.ID, nil
+	if len(memberships) == 1 {
+		return memberships[0].Account.ID, nil
 	}
 
-	choices := make([]string, 0, len(payload.Result))
-	for _, item := range payload.Result {
-		choices = append(choices, fmt.Sprintf("%s (%s)", item.Account.Name, item.Account.ID))
+	if interactive && !machineOutput() && isTerminal(os.Stdin) {
+		m, err := pickAccount(bufio.NewReader(os.Stdin), memberships)
+		if err != nil {
+			return "", err
+		}
+		if saveAccountChoice {
+			where, err := saveAccountID(m.Account.ID)
+			if err != nil {
+				return "", err
+			}
+			infof("Saved account %s to %s\n", m.Account.Name, where)
+		} else {
+			infof("Using %s for this run; pass --save or run cf accounts use to keep it.\n", m.Account.Name)
+		}
+		return m.Account.ID, nil
 	}
-	return "", fmt.Errorf("multiple accounts found; set CF_ACCOUNT_ID. available: %s", strings.Join(choices, ", "))
-}
 
-func formatAPIErrors(errs []apiError, status int) error {
-	if len(errs) == 0 {
-		return fmt.Errorf("Cloudflare API request failed (HTTP %d)", status)
-	}
-	parts := make([]string, 0, len(errs))
-	for _, e := range errs {
-		parts = append(parts, fmt.Sprintf("%d: %s", e.Code, e.Message))
+	choices := make([]string, 0, len(memberships))
+	for _, item := range memberships {
+		choices = append(choices, fmt.Sprintf("%s (%s)", item.Account.Name, item.Account.ID))
 	}
-	return errors.New(strings.Join(parts, "; "))
+	return "", fmt.Errorf("multiple accounts found; run cf accounts use <id|name> or set CF_ACCOUNT_ID. available: %s", strings.Join(choices, ", "))
 }
 
-func listRegistrarDomains() error {
+func listRegistrarDomains(limit int) error {
 	accountID, err := resolveAccountID()
 	if err != nil {
 		return err
 	}
 
-	resp, err := requestCF(http.MethodGet, "/accounts/"+accountID+"/registrar/domains", nil)
+	c, err := apiClient()
 	if err != nil {
 		return err
 	}
-
-	var domains []registrarDomain
-	if err := json.Unmarshal(resp.Result, &domains); err != nil {
+	domains, err := c.ListRegistrarDomains(rootCtx, accountID, limit)
+	if err != nil {
 		return err
 	}
 
-	if len(domains) == 0 {
-		fmt.Println("No registrar domains found in this account.")
-		return nil
-	}
-
+	t := table{Headers: []string{"NAME", "AUTO_RENEW", "LOCKED", "PRIVACY"}}
 	for _, d := range domains {
-		fmt.Printf("%s  auto_renew=%t  locked=%t  privacy=%t\n", d.Name, d.AutoRenew, d.Locked, d.Privacy)
+		t.Rows = append(t.Rows, []string{d.Name, strconv.FormatBool(d.AutoRenew), strconv.FormatBool(d.Locked), strconv.FormatBool(d.Privacy)})
 	}
-	return nil
+
+	return printList(domains, t, func() {
+		if len(domains) == 0 {
+			fmt.Println("No registrar domains found in this account.")
+			return
+		}
+		for _, d := range domains {
+			fmt.Printf("%s  auto_renew=%t  locked=%t  privacy=%t\n", d.Name, d.AutoRenew, d.Locked, d.Privacy)
+		}
+	})
 }
 
-func listZones() error {
+func listZones(limit int) error {
 	accountID, err := resolveAccountID()
 	if err != nil {
 		return err
 	}
 
-	path := "/zones?account.id=" + url.QueryEscape(accountID) + "&per_page=100"
-	resp, err := requestCF(http.MethodGet, path, nil)
+	c, err := apiClient()
 	if err != nil {
 		return err
 	}
-
-	var zones []zone
-	if err := json.Unmarshal(resp.Result, &zones); err != nil {
+	zones, err := c.ListZones(rootCtx, accountID, limit)
+	if err != nil {
 		return err
 	}
 
-	if len(zones) == 0 {
-		fmt.Println("No zones found in this account.")
-		return nil
-	}
-
+	t := table{Headers: []string{"NAME", "STATUS", "ID"}}
 	for _, z := range zones {
-		fmt.Printf("%s  status=%s  id=%s\n", z.Name, z.Status, z.ID)
+		t.Rows = append(t.Rows, []string{z.Name, z.Status, z.ID})
 	}
 
-	return nil
+	return printList(zones, t, func() {
+		if len(zones) == 0 {
+			fmt.Println("No zones found in this account.")
+			return
+		}
+		for _, z := range zones {
+			fmt.Printf("%s  status=%s  id=%s\n", z.Name, z.Status, z.ID)
+		}
+	})
 }
 
 func getZoneByName(name string) (*zone, error) {
@@ -390,44 +726,78 @@ func getZoneByName(name string) (*zone, error) {
 	if err != nil {
 		return nil, err
 	}
+	if z, ok := cachedZoneByName(accountID, name); ok {
+		return z, nil
+	}
+	return fetchZoneByName(accountID, name)
+}
 
-	path := "/zones?account.id=" + url.QueryEscape(accountID) + "&name=" + url.QueryEscape(name) + "&per_page=1"
-	resp, err := requestCF(http.MethodGet, path, nil)
+// fetchZoneByName looks the zone up without the cache, for callers waiting
+// on it to change, and caches what it finds.
+func fetchZoneByName(accountID, name string) (*zone, error) {
+	c, err := apiClient()
 	if err != nil {
 		return nil, err
 	}
+	z, err := c.ZoneByName(rootCtx, accountID, name)
+	if err == nil && z != nil {
+		storeZone(accountID, *z)
+	}
+	return z, err
+}
 
-	var zones []zone
-	if err := json.Unmarshal(resp.Result, &zones); err != nil {
+// requireZone looks up a zone by name and turns "not found" into an error
+// pointing at cf zones add.
+func requireZone(name string) (*zone, error) {
+	z, err := getZoneByName(name)
+	if err != nil {
 		return nil, err
 	}
-
-	if len(zones) == 0 {
-		return nil, nil
+	if z == nil {
+		return nil, notFoundErrorf("zone not found for %s. run: cf zones add %s", name, name)
 	}
+	return z, nil
+}
 
-	return &zones[0], nil
+// requireFreshZone is requireZone without the cache, for commands that
+// poll a zone's status.
+func requireFreshZone(name string) (*zone, error) {
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return nil, err
+	}
+	z, err := fetchZoneByName(accountID, name)
+	if err != nil {
+		return nil, err
+	}
+	if z == nil {
+		return nil, notFoundErrorf("zone not found for %s. run: cf zones add %s", name, name)
+	}
+	return z, nil
 }
 
-func addZone(domain string) (*zone, error) {
+// addZone creates a zone of zoneType ("full" or "partial"), or returns the
+// existing one. With jumpStart Cloudflare scans the domain's current DNS and
+// imports the records it finds, which are listed afterwards.
+func addZone(domain, zoneType string, jumpStart bool) (*zone, error) {
 	accountID, err := resolveAccountID()
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := requestCF(http.MethodPost, "/zones", map[string]any{
-		"account":    map[string]string{"id": accountID},
-		"jump_start": true,
-		"name":       domain,
-		"type":       "full",
-	})
+	c, err := apiClient()
+	if err != nil {
+		return nil, err
+	}
+	z, err := c.CreateZone(rootCtx, cfapi.CreateZoneParams{AccountID: accountID, Name: domain, Type: zoneType, JumpStart: jumpStart})
+	forgetZones()
 	if err == nil {
-		var z zone
-		if unmarshalErr := json.Unmarshal(resp.Result, &z); unmarshalErr != nil {
-			return nil, unmarshalErr
+		infof("Zone created: %s (id=%s, status=%s)\n", z.Name, z.ID, z.Status)
+		if jumpStart && z.ID != "" {
+			printImportedRecords(z)
 		}
-		fmt.Printf("Zone created: %s (id=%s, status=%s)\n", z.Name, z.ID, z.Status)
-		return &z, nil
+		printActivationInstructions(z)
+		return z, nil
 	}
 
 	if strings.Contains(err.Error(), "1061") {
@@ -436,7 +806,8 @@ func addZone(domain string) (*zone, error) {
 			return nil, existingErr
 		}
 		if existing != nil {
-			fmt.Printf("Zone already exists: %s (id=%s, status=%s)\n", existing.Name, existing.ID, existing.Status)
+			infof("Zone already exists: %s (id=%s, status=%s)\n", existing.Name, existing.ID, existing.Status)
+			printActivationInstructions(existing)
 			return existing, nil
 		}
 	}
@@ -472,6 +843,25 @@ func explainZoneCreatePermissionError(err error) error {
 		b.WriteString("  1. Ensure you selected the intended account in the wizard.\n")
 		b.WriteString("  2. Confirm your Cloudflare member role can create zones for that account.\n")
 		b.WriteString("  3. Re-auth with Wrangler (`wrangler login`) if account context is wrong.\n")
+	case "api_key":
+		fmt.Fprintf(&b, "Auth mode detected: %s.\n", authSource)
+		b.WriteString("Next steps:\n")
+		b.WriteString("  1. Confirm CF_API_EMAIL belongs to a member whose role can create zones in the selected account.\n")
+		b.WriteString("  2. Verify the account ID points to that account.\n")
+		b.WriteString("  3. Or switch to a scoped API token with `--auth-mode token`.\n")
+	case "keychain":
+		fmt.Fprintf(&b, "Auth mode detected: API token stored in the OS keychain by `cf login` (%s).\n", authSource)
+		b.WriteString("Next steps:\n")
+		b.WriteString("  1. Create a token with zone-creation capability for the selected account.\n")
+		b.WriteString("  2. Store it with `cf login` (this replaces the current token).\n")
+		b.WriteString("  3. Verify the account ID points to the account where your role permits zone creation.\n")
+	case "profile":
+		_, name, _, _ := activeProfile()
+		fmt.Fprintf(&b, "Auth mode detected: API token from config profile `%s` (%s).\n", name, configPath())
+		b.WriteString("Next steps:\n")
+		b.WriteString("  1. Use a token with zone-creation capability for the selected account.\n")
+		b.WriteString("  2. Verify the profile's account_id points to the account where your role permits zone creation.\n")
+		b.WriteString("  3. Retry after updating the profile or selecting another one with --profile.\n")
 	default:
 		b.WriteString("Auth mode detected: API token from environment (`CF_API_TOKEN` or `CLOUDFLARE_API_TOKEN`).\n")
 		b.WriteString("Next steps:\n")
@@ -484,9 +874,22 @@ func explainZoneCreatePermissionError(err error) error {
 }
 
 func detectAuthMode() string {
+	if strings.HasPrefix(authSource, "keychain") {
+		return "keychain"
+	}
+	if strings.HasPrefix(authSource, "legacy API key") {
+		return "api_key"
+	}
+	p, _, explicit, _ := activeProfile()
+	if explicit && p.token() != "" {
+		return "profile"
+	}
 	if strings.TrimSpace(os.Getenv("CF_API_TOKEN")) != "" || strings.TrimSpace(os.Getenv("CLOUDFLARE_API_TOKEN")) != "" {
 		return "api_token"
 	}
+	if p.token() != "" {
+		return "profile"
+	}
 	return "wrangler"
 }
 
@@ -498,173 +901,430 @@ func wranglerWhoAmI() (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
-func addDNSRecord(zoneName, typeName, name, content string, ttl int, proxied bool) error {
-	z, err := getZoneByName(zoneName)
+func addDNSRecord(zoneName string, r dnsRecord) (*dnsRecord, error) {
+	z, err := requireZone(zoneName)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if z == nil {
-		return fmt.Errorf("zone not found for %s. run: cf zones add %s", zoneName, zoneName)
+	created, err := addZoneDNSRecord(z, r)
+	if err != nil {
+		return nil, err
 	}
+	infof("DNS record created: %s %s -> %s (id=%s)\n", created.Type, created.Name, created.Content, created.ID)
+	return created, nil
+}
 
-	resp, err := requestCF(http.MethodPost, "/zones/"+z.ID+"/dns_records", map[string]any{
-		"type":    typeName,
-		"name":    name,
-		"content": content,
-		"ttl":     ttl,
-		"proxied": proxied,
-	})
+// addDNSRecordToZones creates r in every zone selected by --zones or
+// --all-zones. A relative name is qualified per zone.
+func addDNSRecordToZones(flags map[string]string, r dnsRecord) error {
+	if err := validateDNSRecord(r); err != nil {
+		return err
+	}
+	concurrency, err := parseIntWithDefault(flags["concurrency"], defaultBulkConcurrency)
+	if err != nil {
+		return usageErrorf("invalid --concurrency: %w", err)
+	}
+	zones, err := fanOutZones(flags)
 	if err != nil {
 		return err
 	}
+	return runOnZones(flags, "dns add", zones, concurrency, func(z *zone) (string, any, error) {
+		created, err := addZoneDNSRecord(z, r)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("created %s %s -> %s (id=%s)", created.Type, created.Name, created.Content, created.ID), created, nil
+	})
+}
 
-	var r dnsRecord
-	if err := json.Unmarshal(resp.Result, &r); err != nil {
-		return err
+// addZoneDNSRecord validates r and creates it in z unless it would clash
+// with a CNAME.
+func addZoneDNSRecord(z *zone, r dnsRecord) (*dnsRecord, error) {
+	if err := validateDNSRecord(r); err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("DNS record created: %s %s -> %s (id=%s)\n", r.Type, r.Name, r.Content, r.ID)
-	return nil
+	name := qualifyRecordName(r.Name, z.Name)
+	existing, err := listAll[dnsRecord]("/zones/"+z.ID+"/dns_records?"+url.Values{"name": {name}}.Encode(), 100, 0)
+	if err != nil {
+		return nil, err
+	}
+	candidate := r
+	candidate.Name = name
+	if err := zoneCNAMEConflict(z.Name, candidate, existing); err != nil {
+		return nil, err
+	}
+
+	return createDNSRecord(z.ID, r)
 }
 
-func parseFlags(args []string) map[string]string {
-	out := map[string]string{}
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if !strings.HasPrefix(arg, "--") {
-			continue
+// findDNSRecord returns the one record an update or delete targets: the
+// record with id when it is set, otherwise the single record matching type
+// and name. Several matches (round-robin A records, say) are an error that
+// lists their IDs.
+func findDNSRecord(z *zone, id, typeName, name, verb string) (*dnsRecord, error) {
+	c, err := apiClient()
+	if err != nil {
+		return nil, err
+	}
+	if id != "" {
+		r, err := c.GetDNSRecord(rootCtx, z.ID, id)
+		if err != nil && exitCode(err) == exitNotFound {
+			return nil, notFoundErrorf("no DNS record with id %s in %s. run: cf dns list --zone %s", id, z.Name, z.Name)
 		}
+		return r, err
+	}
 
-		if idx := strings.Index(arg, "="); idx != -1 {
-			out[strings.TrimPrefix(arg[:idx], "--")] = arg[idx+1:]
-			continue
-		}
+	fqdn := qualifyRecordName(name, z.Name)
+	matches, err := c.ListDNSRecords(rootCtx, z.ID, cfapi.DNSRecordFilter{Type: typeName, Name: fqdn}, 0)
+	if err != nil {
+		return nil, err
+	}
+	switch len(matches) {
+	case 0:
+		return nil, notFoundErrorf("no %s record named %s in %s. run: cf dns list --zone %s", typeName, fqdn, z.Name, z.Name)
+	case 1:
+		return &matches[0], nil
+	}
+	lines := make([]string, 0, len(matches))
+	for _, m := range matches {
+		lines = append(lines, fmt.Sprintf("  %s %s -> %s  id=%s", m.Type, m.Name, m.Content, m.ID))
+	}
+	return nil, usageErrorf("%d %s records are named %s; pass --id to pick one for dns %s:\n%s", len(matches), typeName, fqdn, verb, strings.Join(lines, "\n"))
+}
 
-		key := strings.TrimPrefix(arg, "--")
-		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
-			out[key] = args[i+1]
-			i++
-		} else {
-			out[key] = "true"
-		}
+// updateDNSRecordByName changes the record chosen by findDNSRecord. Fields
+// without a flag keep their current values.
+func updateDNSRecordByName(zoneName, id, typeName, name string, flags map[string]string) (*dnsRecord, error) {
+	if err := requireConfirmable(flags, "dns update"); err != nil {
+		return nil, err
 	}
-	return out
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return nil, err
+	}
+	found, err := findDNSRecord(z, id, typeName, name, "update")
+	if err != nil {
+		return nil, err
+	}
+
+	r := *found
+	data, err := dnsRecordData(r.Type, flags)
+	if err != nil {
+		return nil, err
+	}
+	if v := flags["content"]; v != "" {
+		r.Content = v
+		r.Data = nil
+	}
+	if data != nil {
+		r.Content = ""
+		r.Data = data
+	}
+	if r.TTL, err = parseIntWithDefault(flags["ttl"], r.TTL); err != nil {
+		return nil, usageErrorf("invalid --ttl: %w", err)
+	}
+	r.Proxied = parseBoolWithDefault(flags["proxied"], r.Proxied)
+	// Passing an empty --comment or --tags clears them.
+	if v, ok := flags["comment"]; ok {
+		r.Comment = v
+	}
+	if v, ok := flags["tags"]; ok {
+		r.Tags = splitList(v)
+	}
+
+	if !skipConfirm(flags) {
+		fmt.Printf("  ~ %s %s: %s -> %s (ttl=%d, proxied=%t)\n", r.Type, r.Name, found.Content, r.Content, r.TTL, r.Proxied)
+	}
+	ok, err := confirm(flags, "dns update", "Update this record?")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		fmt.Println("Aborted. Record unchanged.")
+		return nil, nil
+	}
+
+	updated, err := updateDNSRecord(z.ID, *found, r)
+	if err != nil {
+		return nil, err
+	}
+	infof("DNS record updated: %s %s -> %s (id=%s)\n", updated.Type, updated.Name, updated.Content, updated.ID)
+	return updated, nil
 }
 
-func parseBoolWithDefault(v string, fallback bool) bool {
-	if strings.TrimSpace(v) == "" {
-		return fallback
+// deleteDNSRecordByName removes the record chosen by findDNSRecord, after
+// showing it and asking for confirmation.
+func deleteDNSRecordByName(zoneName, id, typeName, name string, flags map[string]string) (*dnsRecord, error) {
+	if err := requireConfirmable(flags, "dns delete"); err != nil {
+		return nil, err
 	}
-	return strings.EqualFold(v, "true") || strings.EqualFold(v, "yes") || v == "1"
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return nil, err
+	}
+	r, err := findDNSRecord(z, id, typeName, name, "delete")
+	if err != nil {
+		return nil, err
+	}
+	ok, err := confirm(flags, "dns delete", fmt.Sprintf("Delete %s %s -> %s (id=%s)?", r.Type, r.Name, r.Content, r.ID))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		fmt.Println("Aborted. Nothing deleted.")
+		return nil, nil
+	}
+	if err := deleteDNSRecord(z.ID, *r); err != nil {
+		return nil, err
+	}
+	infof("DNS record deleted: %s %s -> %s (id=%s)\n", r.Type, r.Name, r.Content, r.ID)
+	return r, nil
 }
 
-func parseIntWithDefault(v string, fallback int) (int, error) {
-	if strings.TrimSpace(v) == "" {
-		return fallback, nil
+func createDNSRecord(zoneID string, r dnsRecord) (*dnsRecord, error) {
+	if err := validateDNSRecord(r); err != nil {
+		return nil, err
 	}
-	n, err := strconv.Atoi(v)
+	c, err := apiClient()
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return n, nil
+	created, err := c.CreateDNSRecord(rootCtx, zoneID, r)
+	if err != nil {
+		return nil, err
+	}
+	journalDNSChange(zoneID, "create", nil, created)
+	return created, nil
 }
 
-func runWizard() error {
-	reader := bufio.NewReader(os.Stdin)
-	domain, err := prompt(reader, "Domain you want to onboard (example.com)", "")
+func listDNSRecordsForZone(zoneName, typeName, name, tag string, limit int) error {
+	z, err := requireZone(zoneName)
 	if err != nil {
 		return err
 	}
-	if domain == "" {
-		return errors.New("domain is required")
-	}
 
-	alreadyRegistered, err := promptYesNo(reader, "Is this domain already registered somewhere?", true)
+	filter := cfapi.DNSRecordFilter{Type: typeName, Tag: tag}
+	if name != "" {
+		filter.Name = qualifyRecordName(name, z.Name)
+	}
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	records, err := c.ListDNSRecords(rootCtx, z.ID, filter, limit)
 	if err != nil {
 		return err
 	}
 
-	if !alreadyRegistered {
-		dashboardURL := "https://dash.cloudflare.com/?to=/:account/domains"
-		fmt.Println("\nManual step required: register domain in Cloudflare Dashboard:")
-		fmt.Println(dashboardURL)
+	t := table{Headers: []string{"TYPE", "NAME", "CONTENT", "TTL", "PROXIED", "ID", "TAGS", "COMMENT"}}
+	for _, r := range records {
+		t.Rows = append(t.Rows, []string{r.Type, r.Name, r.Content, strconv.Itoa(r.TTL), strconv.FormatBool(r.Proxied), r.ID, strings.Join(r.Tags, ","), r.Comment})
+	}
 
-		openNow, err := promptYesNo(reader, "Open the dashboard URL in your browser now?", true)
-		if err != nil {
-			return err
+	return printList(records, t, func() {
+		if len(records) == 0 {
+			fmt.Printf("No DNS records found in %s.\n", z.Name)
+			return
 		}
-		if openNow {
-			if err := openURL(dashboardURL); err != nil {
-				fmt.Printf("Could not open browser automatically: %v\n", err)
-			} else {
-				fmt.Println("Opened browser tab.")
+		for _, r := range records {
+			fmt.Printf("%s %s -> %s  ttl=%d  proxied=%t  id=%s", r.Type, r.Name, r.Content, r.TTL, r.Proxied, r.ID)
+			if len(r.Tags) > 0 {
+				fmt.Printf("  tags=%s", strings.Join(r.Tags, ","))
 			}
+			if r.Comment != "" {
+				fmt.Printf("  comment=%q", r.Comment)
+			}
+			fmt.Println()
 		}
+	})
+}
 
-		if _, err := prompt(reader, "Press Enter when registration is complete and you want to continue", ""); err != nil {
-			return err
-		}
+func listDNSRecords(zoneID string) ([]dnsRecord, error) {
+	c, err := apiClient()
+	if err != nil {
+		return nil, err
 	}
+	return c.ListDNSRecords(rootCtx, zoneID, cfapi.DNSRecordFilter{}, 0)
+}
 
-	addZoneNow, err := promptYesNo(reader, fmt.Sprintf("Add %s as a zone in Cloudflare now?", domain), true)
+// updateDNSRecord replaces before with r. before is journaled so cf undo
+// can put it back.
+func updateDNSRecord(zoneID string, before, r dnsRecord) (*dnsRecord, error) {
+	if err := validateDNSRecord(r); err != nil {
+		return nil, err
+	}
+	c, err := apiClient()
+	if err != nil {
+		return nil, err
+	}
+	updated, err := c.UpdateDNSRecord(rootCtx, zoneID, r)
+	if err != nil {
+		return nil, err
+	}
+	journalDNSChange(zoneID, "update", &before, updated)
+	return updated, nil
+}
+
+// deleteDNSRecord removes r, journaling it so cf undo can recreate it.
+func deleteDNSRecord(zoneID string, r dnsRecord) error {
+	c, err := apiClient()
 	if err != nil {
 		return err
 	}
-	if addZoneNow {
-		z, err := addZone(domain)
-		if err != nil {
-			return err
-		}
-		if z != nil && z.Status != "active" {
-			fmt.Printf("Zone status is '%s'. You may still need to update nameservers at your current registrar.\n", z.Status)
-		}
+	if err := c.DeleteDNSRecord(rootCtx, zoneID, r.ID); err != nil {
+		return err
 	}
+	journalDNSChange(zoneID, "delete", &r, nil)
+	return nil
+}
 
-	for {
-		addRecord, err := promptYesNo(reader, "Add a DNS record now?", true)
-		if err != nil {
-			return err
-		}
-		if !addRecord {
-			break
-		}
+type globalFlag struct {
+	takesValue bool
+	set        func(v string) error
 
-		zoneName, err := prompt(reader, "Zone name", domain)
-		if err != nil {
-			return err
+	// leading flags are only global before the command name, because some
+	// commands have a flag of the same name.
+	leading bool
+}
+
+// globalFlags are accepted anywhere on the command line (leading ones only
+// before the command) and are removed before command dispatch.
+var globalFlags = map[string]globalFlag{
+	"--json":      {set: func(string) error { outputFormat = outputJSON; return nil }},
+	"--output":    {takesValue: true, set: setOutputFormat},
+	"-o":          {takesValue: true, set: setOutputFormat},
+	"--retries":   {takesValue: true, set: setMaxRetries},
+	"--max-rps":   {takesValue: true, set: setMaxRPS},
+	"--cache-ttl": {takesValue: true, set: setCacheTTL},
+	"--proxy":     {takesValue: true, set: setProxy},
+	"--profile":   {takesValue: true, set: func(v string) error { profileName = v; return nil }},
+	"--auth-mode": {takesValue: true, set: setAuthMode},
+	"--dry-run":   {set: func(string) error { dryRun = true; return nil }},
+	"--yes":       {set: func(string) error { assumeYes = true; return nil }},
+	"--save":      {set: func(string) error { saveAccountChoice = true; return nil }},
+	"--verbose":   {set: func(string) error { verbose = true; return nil }},
+	"-v":          {set: func(string) error { verbose = true; return nil }},
+	"--timeout":   {takesValue: true, set: setTimeout, leading: true},
+}
+
+// parseGlobalFlags applies global flags found in args and returns the
+// remaining arguments.
+func parseGlobalFlags(args []string) ([]string, error) {
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		flag, ok := globalFlags[name]
+		if !ok || (hasValue && !flag.takesValue) || (flag.leading && len(rest) > 0) {
+			rest = append(rest, args[i])
+			continue
 		}
-		typeName, err := prompt(reader, "Record type", "A")
-		if err != nil {
-			return err
+		if flag.takesValue && !hasValue {
+			if i+1 >= len(args) {
+				return nil, usageErrorf("%s requires a value", name)
+			}
+			value = args[i+1]
+			i++
 		}
-		name, err := prompt(reader, "Record name", "@")
-		if err != nil {
-			return err
+		if err := flag.set(value); err != nil {
+			return nil, err
 		}
-		content, err := prompt(reader, "Record content (IP or hostname)", "")
-		if err != nil {
-			return err
+	}
+	return rest, nil
+}
+
+// splitArgs separates positional arguments from --flags.
+func splitArgs(args []string) ([]string, map[string]string) {
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		if strings.HasPrefix(args[i], "--") {
+			if !strings.Contains(args[i], "=") && i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				i++
+			}
+			continue
 		}
-		ttlRaw, err := prompt(reader, "TTL (1 means auto)", "1")
-		if err != nil {
-			return err
+		positional = append(positional, args[i])
+	}
+	return positional, parseFlags(args)
+}
+
+func parseFlags(args []string) map[string]string {
+	out := map[string]string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			continue
 		}
-		ttl, err := strconv.Atoi(ttlRaw)
-		if err != nil {
-			return fmt.Errorf("invalid TTL: %w", err)
+
+		if idx := strings.Index(arg, "="); idx != -1 {
+			out[strings.TrimPrefix(arg[:idx], "--")] = arg[idx+1:]
+			continue
 		}
-		proxied, err := promptYesNo(reader, "Proxied through Cloudflare (orange cloud)?", false)
-		if err != nil {
-			return err
+
+		key := strings.TrimPrefix(arg, "--")
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+			out[key] = args[i+1]
+			i++
+		} else {
+			out[key] = "true"
 		}
+	}
+	return out
+}
 
-		if err := addDNSRecord(zoneName, strings.ToUpper(typeName), name, content, ttl, proxied); err != nil {
-			return err
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
 		}
 	}
+	return out
+}
 
-	fmt.Println("\nWizard complete.")
-	return nil
+func parseBoolWithDefault(v string, fallback bool) bool {
+	if strings.TrimSpace(v) == "" {
+		return fallback
+	}
+	return strings.EqualFold(v, "true") || strings.EqualFold(v, "yes") || v == "1"
+}
+
+// parseOnOff accepts on/off as well as the usual boolean spellings.
+func parseOnOff(v string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "on", "true", "yes", "1":
+		return true, nil
+	case "off", "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid value %q: use on or off", v)
+}
+
+// parseDurationWithDefault accepts Go durations plus whole days ("7d"),
+// which time.ParseDuration does not.
+func parseDurationWithDefault(v string, fallback time.Duration) (time.Duration, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return fallback, nil
+	}
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	return time.ParseDuration(v)
+}
+
+func parseIntWithDefault(v string, fallback int) (int, error) {
+	if strings.TrimSpace(v) == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
 }
 
 func prompt(reader *bufio.Reader, question, fallback string) (string, error) {
@@ -673,11 +1333,29 @@ func prompt(reader *bufio.Reader, question, fallback string) (string, error) {
 		suffix = " [" + fallback + "]"
 	}
 	fmt.Printf("%s%s: ", question, suffix)
-	text, err := reader.ReadString('\n')
-	if err != nil && !errors.Is(err, io.EOF) {
-		return "", err
-	}
-	text = strings.TrimSpace(text)
+
+	// Read in the background so Ctrl-C or --timeout can end a prompt that is
+	// waiting on the terminal.
+	type line struct {
+		text string
+		err  error
+	}
+	lines := make(chan line, 1)
+	go func() {
+		text, err := reader.ReadString('\n')
+		lines <- line{text, err}
+	}()
+	var l line
+	select {
+	case l = <-lines:
+	case <-rootCtx.Done():
+		fmt.Println()
+		return "", context.Cause(rootCtx)
+	}
+	if l.err != nil && !errors.Is(l.err, io.EOF) {
+		return "", l.err
+	}
+	text := strings.TrimSpace(l.text)
 	if text == "" {
 		return fallback, nil
 	}
@@ -2,11 +2,13 @@ package main
 
 import (
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 )
 
 func TestExplainZoneCreatePermissionError_APIEnv(t *testing.T) {
+	useTestConfig(t, "")
 	t.Setenv("CF_API_TOKEN", "test-token")
 	t.Setenv("CLOUDFLARE_API_TOKEN", "")
 
@@ -34,6 +36,7 @@ func TestExplainZoneCreatePermissionError_APIEnv(t *testing.T) {
 }
 
 func TestExplainZoneCreatePermissionError_Wrangler(t *testing.T) {
+	useTestConfig(t, "")
 	t.Setenv("CF_API_TOKEN", "")
 	t.Setenv("CLOUDFLARE_API_TOKEN", "")
 
@@ -69,3 +72,69 @@ func TestExplainZoneCreatePermissionError_UnrelatedError(t *testing.T) {
 		t.Fatalf("expected original error to be returned")
 	}
 }
+
+func TestParseGlobalFlags(t *testing.T) {
+	t.Cleanup(func() { outputFormat = outputPlain })
+
+	rest, err := parseGlobalFlags([]string{"zones", "list", "--output", "CSV"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(rest, []string{"zones", "list"}) {
+		t.Fatalf("unexpected args: %v", rest)
+	}
+	if outputFormat != outputCSV {
+		t.Fatalf("expected csv output, got %s", outputFormat)
+	}
+
+	if _, err := parseGlobalFlags([]string{"zones", "list", "--json"}); err != nil || outputFormat != outputJSON {
+		t.Fatalf("expected --json to select json output, got %s (err=%v)", outputFormat, err)
+	}
+
+	if _, err := parseGlobalFlags([]string{"--output=xml"}); err == nil {
+		t.Fatalf("expected error for unknown output format")
+	}
+}
+
+func TestResolveCredentials_LegacyKeyPrecedence(t *testing.T) {
+	useTestConfig(t, "")
+	useFakeKeychain(t, fakeKeychain{})
+	t.Cleanup(func() { authMode = "" })
+	t.Setenv("CF_AUTH_MODE", "")
+	t.Setenv("CF_API_KEY", "legacy-key")
+	t.Setenv("CF_API_EMAIL", "me@example.com")
+	t.Setenv("CF_API_TOKEN", "")
+	t.Setenv("CLOUDFLARE_API_TOKEN", "")
+
+	creds, err := resolveCredentials()
+	if err != nil || creds.APIKey != "legacy-key" || creds.Email != "me@example.com" {
+		t.Fatalf("expected legacy key credentials, got %+v (err=%v)", creds, err)
+	}
+
+	t.Setenv("CF_API_TOKEN", "token")
+	creds, err = resolveCredentials()
+	if err != nil || creds.Token != "token" || creds.APIKey != "" {
+		t.Fatalf("expected token to win in auto mode, got %+v (err=%v)", creds, err)
+	}
+
+	authMode = "key"
+	creds, err = resolveCredentials()
+	if err != nil || creds.APIKey != "legacy-key" {
+		t.Fatalf("expected --auth-mode key to force legacy key, got %+v (err=%v)", creds, err)
+	}
+
+	t.Setenv("CF_API_EMAIL", "")
+	if _, err := resolveCredentials(); err == nil {
+		t.Fatalf("expected error when key mode lacks an email")
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	positional, flags := splitArgs([]string{"example.com", "--ssl", "strict", "http3", "--all"})
+	if !reflect.DeepEqual(positional, []string{"example.com", "http3"}) {
+		t.Fatalf("unexpected positional args: %v", positional)
+	}
+	if flags["ssl"] != "strict" || flags["all"] != "true" {
+		t.Fatalf("unexpected flags: %v", flags)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type accountRole struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type accountMember struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	User   struct {
+		ID        string `json:"id,omitempty"`
+		Email     string `json:"email"`
+		FirstName string `json:"first_name,omitempty"`
+		LastName  string `json:"last_name,omitempty"`
+		TwoFactor bool   `json:"two_factor_authentication_enabled"`
+	} `json:"user"`
+	Roles []accountRole `json:"roles"`
+}
+
+func (m accountMember) roleNames() string {
+	names := make([]string, len(m.Roles))
+	for i, r := range m.Roles {
+		names[i] = r.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func runMembers(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	base := "/accounts/" + accountID
+
+	switch action {
+	case "list":
+		members, err := listAll[accountMember](base+"/members", 50, 0)
+		if err != nil {
+			return err
+		}
+		if parseBoolWithDefault(flags["pending"], false) {
+			pending := members[:0]
+			for _, m := range members {
+				if m.Status == "pending" {
+					pending = append(pending, m)
+				}
+			}
+			members = pending
+		}
+		t := table{Headers: []string{"ID", "EMAIL", "STATUS", "2FA", "ROLES"}}
+		for _, m := range members {
+			t.Rows = append(t.Rows, []string{m.ID, m.User.Email, m.Status, fmt.Sprint(m.User.TwoFactor), m.roleNames()})
+		}
+		return printList(members, t, func() {
+			for _, m := range members {
+				fmt.Printf("%s\t%s\t%s\t%s\n", m.ID, m.User.Email, m.Status, m.roleNames())
+			}
+		})
+	case "roles":
+		roles, err := listAll[accountRole](base+"/roles", 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "DESCRIPTION"}}
+		for _, r := range roles {
+			t.Rows = append(t.Rows, []string{r.ID, r.Name, r.Description})
+		}
+		return printList(roles, t, func() {
+			for _, r := range roles {
+				fmt.Printf("%s\t%s\n", r.ID, r.Name)
+			}
+		})
+	case "invite":
+		if len(positional) == 0 || flags["roles"] == "" {
+			return usageErrorf("usage: cf members invite <email> --roles <name-or-id,...>")
+		}
+		roleIDs, err := resolveRoles(base, flags["roles"])
+		if err != nil {
+			return err
+		}
+		resp, err := requestCF(http.MethodPost, base+"/members", map[string]any{
+			"email":  positional[0],
+			"roles":  roleIDs,
+			"status": "pending",
+		})
+		if err != nil {
+			return err
+		}
+		var m accountMember
+		if err := json.Unmarshal(resp.Result, &m); err != nil {
+			return err
+		}
+		return printResult(m, func() {
+			fmt.Printf("Invitation sent: %s (id=%s) roles=%s\n", m.User.Email, m.ID, m.roleNames())
+		})
+	case "update-role":
+		if len(positional) == 0 || flags["roles"] == "" {
+			return usageErrorf("usage: cf members update-role <email|id> --roles <name-or-id,...>")
+		}
+		m, err := findMember(base, positional[0])
+		if err != nil {
+			return err
+		}
+		roleIDs, err := resolveRoles(base, flags["roles"])
+		if err != nil {
+			return err
+		}
+		roles := make([]map[string]string, len(roleIDs))
+		for i, id := range roleIDs {
+			roles[i] = map[string]string{"id": id}
+		}
+		resp, err := requestCF(http.MethodPut, base+"/members/"+m.ID, map[string]any{"roles": roles})
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, m); err != nil {
+			return err
+		}
+		return printResult(m, func() {
+			fmt.Printf("Roles updated: %s roles=%s\n", m.User.Email, m.roleNames())
+		})
+	case "remove":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf members remove <email|id> [--force]")
+		}
+		if err := requireConfirmable(flags, "members remove"); err != nil {
+			return err
+		}
+		m, err := findMember(base, positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirm(flags, "members remove", fmt.Sprintf("Remove %s (%s) from the account?", m.User.Email, m.roleNames()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing removed.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, base+"/members/"+m.ID, nil); err != nil {
+			return err
+		}
+		return printResult(m, func() {
+			fmt.Printf("Member removed: %s\n", m.User.Email)
+		})
+	}
+	return usageErrorf("usage: cf members list|roles|invite|update-role|remove")
+}
+
+func findMember(base, ref string) (*accountMember, error) {
+	members, err := listAll[accountMember](base+"/members", 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		if m.ID == ref || strings.EqualFold(m.User.Email, ref) {
+			return &m, nil
+		}
+	}
+	return nil, notFoundErrorf("member %q not found. run: cf members list", ref)
+}
+
+// resolveRoles maps role names (case-insensitive) or IDs to role IDs.
+func resolveRoles(base, list string) ([]string, error) {
+	roles, err := listAll[accountRole](base+"/roles", 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	return matchRoles(roles, splitList(list))
+}
+
+func matchRoles(roles []accountRole, refs []string) ([]string, error) {
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		found := ""
+		for _, r := range roles {
+			if r.ID == ref || strings.EqualFold(r.Name, ref) {
+				found = r.ID
+				break
+			}
+		}
+		if found == "" {
+			return nil, fmt.Errorf("unknown role %q. run: cf members roles", ref)
+		}
+		ids = append(ids, found)
+	}
+	return ids, nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchRoles(t *testing.T) {
+	roles := []accountRole{
+		{ID: "r1", Name: "Administrator"},
+		{ID: "r2", Name: "DNS"},
+		{ID: "r3", Name: "Billing"},
+	}
+	got, err := matchRoles(roles, []string{"dns", "r3"})
+	if err != nil || !reflect.DeepEqual(got, []string{"r2", "r3"}) {
+		t.Fatalf("unexpected roles: %v (err=%v)", got, err)
+	}
+	if _, err := matchRoles(roles, []string{"Super Admin"}); err == nil {
+		t.Fatalf("expected error for unknown role")
+	}
+}
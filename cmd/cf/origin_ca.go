@@ -0,0 +1,194 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+)
+
+// originCAValidities are the validity periods (in days) the API accepts.
+var originCAValidities = []int{7, 30, 90, 365, 730, 1095, 5475}
+
+type originCert struct {
+	ID              string   `json:"id"`
+	Certificate     string   `json:"certificate,omitempty"`
+	PrivateKey      string   `json:"private_key,omitempty"`
+	Hostnames       []string `json:"hostnames"`
+	ExpiresOn       string   `json:"expires_on"`
+	RequestType     string   `json:"request_type"`
+	RequestValidity int      `json:"requested_validity"`
+}
+
+func runOriginCA(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf origin-ca list|create|revoke")
+	}
+	positional, flags := splitArgs(args[1:])
+
+	switch args[0] {
+	case "list":
+		zoneName := zoneOrDefault(flags["zone"])
+		if zoneName == "" {
+			return usageErrorf("missing required flag for origin-ca list: --zone")
+		}
+		z, err := requireZone(zoneName)
+		if err != nil {
+			return err
+		}
+		certs, err := listAll[originCert]("/certificates?zone_id="+url.QueryEscape(z.ID), 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "TYPE", "EXPIRES", "HOSTNAMES"}}
+		for _, c := range certs {
+			t.Rows = append(t.Rows, []string{c.ID, c.RequestType, c.ExpiresOn, fmt.Sprint(c.Hostnames)})
+		}
+		return printList(certs, t, func() {
+			if len(certs) == 0 {
+				fmt.Printf("No Origin CA certificates for %s.\n", z.Name)
+				return
+			}
+			for _, c := range certs {
+				fmt.Printf("%s\t%s\t%v\n", c.ID, c.ExpiresOn, c.Hostnames)
+			}
+		})
+	case "create":
+		return createOriginCert(flags)
+	case "revoke":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf origin-ca revoke <certificate-id> [--force]")
+		}
+		id := positional[0]
+		ok, err := confirm(flags, "origin-ca revoke", fmt.Sprintf("Revoke Origin CA certificate %s? Origins still using it will fail TLS to Cloudflare.", id))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing revoked.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, "/certificates/"+url.PathEscape(id), nil); err != nil {
+			return err
+		}
+		return printResult(map[string]string{"id": id}, func() {
+			fmt.Printf("Certificate revoked: %s\n", id)
+		})
+	}
+	return errors.New("unknown origin-ca command. run: cf help")
+}
+
+// createOriginCert generates the key locally, so the private key never
+// leaves this machine, and asks Cloudflare to sign a CSR for it.
+func createOriginCert(flags map[string]string) error {
+	hostnames := splitList(flags["hostnames"])
+	if len(hostnames) == 0 {
+		zoneName := zoneOrDefault(flags["zone"])
+		if zoneName == "" {
+			return usageErrorf("missing required flag for origin-ca create: --hostnames or --zone")
+		}
+		hostnames = []string{zoneName, "*." + zoneName}
+	}
+	validity, err := parseIntWithDefault(flags["validity"], 5475)
+	if err != nil || !slices.Contains(originCAValidities, validity) {
+		return usageErrorf("invalid --validity: want one of %v days", originCAValidities)
+	}
+	keyType := flags["key-type"]
+	if keyType == "" {
+		keyType = "rsa"
+	}
+
+	csrPEM, keyPEM, err := originCSR(hostnames, keyType)
+	if err != nil {
+		return err
+	}
+
+	resp, err := requestCF(http.MethodPost, "/certificates", map[string]any{
+		"hostnames":          hostnames,
+		"requested_validity": validity,
+		"request_type":       "origin-" + keyType,
+		"csr":                string(csrPEM),
+	})
+	if err != nil {
+		return err
+	}
+	var c originCert
+	if err := json.Unmarshal(resp.Result, &c); err != nil {
+		return err
+	}
+	c.PrivateKey = string(keyPEM)
+
+	certFile, keyFile := flags["cert-file"], flags["key-file"]
+	if certFile != "" {
+		if err := os.WriteFile(certFile, []byte(c.Certificate), 0o644); err != nil {
+			return err
+		}
+		infof("Certificate written to %s\n", certFile)
+	}
+	if keyFile != "" {
+		if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+			return err
+		}
+		infof("Private key written to %s\n", keyFile)
+	}
+
+	return printResult(c, func() {
+		fmt.Printf("Origin CA certificate issued: %s (expires %s)\n", c.ID, c.ExpiresOn)
+		if certFile == "" {
+			fmt.Print(c.Certificate)
+		}
+		if keyFile == "" {
+			fmt.Print(c.PrivateKey)
+		}
+		fmt.Println("Set the zone SSL mode to strict to have Cloudflare validate it: cf zones settings set <zone> --ssl strict")
+	})
+}
+
+// originCSR returns a PEM CSR and PEM private key for an rsa (2048-bit) or
+// ecc (P-256) key.
+func originCSR(hostnames []string, keyType string) ([]byte, []byte, error) {
+	var key any
+	var keyDER []byte
+	var keyBlock string
+	switch keyType {
+	case "rsa":
+		k, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, keyDER, keyBlock = k, x509.MarshalPKCS1PrivateKey(k), "RSA PRIVATE KEY"
+	case "ecc":
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, keyDER, keyBlock = k, der, "EC PRIVATE KEY"
+	default:
+		return nil, nil, usageErrorf("invalid --key-type %q (want rsa or ecc)", keyType)
+	}
+
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: hostnames[0]},
+		DNSNames: hostnames,
+	}, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: keyBlock, Bytes: keyDER})
+	return csrPEM, keyPEM, nil
+}
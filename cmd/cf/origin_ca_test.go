@@ -0,0 +1,35 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"reflect"
+	"testing"
+)
+
+func TestOriginCSR(t *testing.T) {
+	hostnames := []string{"example.com", "*.example.com"}
+	for _, keyType := range []string{"rsa", "ecc"} {
+		csrPEM, keyPEM, err := originCSR(hostnames, keyType)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", keyType, err)
+		}
+		block, _ := pem.Decode(csrPEM)
+		if block == nil || block.Type != "CERTIFICATE REQUEST" {
+			t.Fatalf("%s: expected a PEM CSR", keyType)
+		}
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil || csr.CheckSignature() != nil {
+			t.Fatalf("%s: invalid CSR: %v", keyType, err)
+		}
+		if !reflect.DeepEqual(csr.DNSNames, hostnames) {
+			t.Fatalf("%s: unexpected SANs %v", keyType, csr.DNSNames)
+		}
+		if b, _ := pem.Decode(keyPEM); b == nil {
+			t.Fatalf("%s: expected a PEM key", keyType)
+		}
+	}
+	if _, _, err := originCSR(hostnames, "dsa"); err == nil {
+		t.Fatalf("expected error for unsupported key type")
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputPlain = "plain"
+	outputTable = "table"
+	outputCSV   = "csv"
+	outputJSON  = "json"
+)
+
+// outputFormat is set by the global --output (or --json) flag. Commands hand
+// their results to printResult/printList instead of printing directly so all
+// formats stay in sync.
+var outputFormat = outputPlain
+
+// table is the column view of a list result used by the table and csv
+// renderers.
+type table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+func setOutputFormat(v string) error {
+	switch strings.ToLower(v) {
+	case outputPlain, outputTable, outputCSV, outputJSON:
+		outputFormat = strings.ToLower(v)
+		return nil
+	}
+	return usageErrorf("invalid --output %q: use plain, table, csv or json", v)
+}
+
+func jsonOutput() bool {
+	return outputFormat == outputJSON
+}
+
+// machineOutput reports whether stdout is reserved for a parseable document.
+func machineOutput() bool {
+	return outputFormat == outputJSON || outputFormat == outputCSV
+}
+
+// printResult writes v as JSON in json mode, otherwise it calls human to
+// render the default text output. Use printList for list results.
+func printResult(v any, human func()) error {
+	if jsonOutput() {
+		return writeJSON(v)
+	}
+	human()
+	return nil
+}
+
+// printList renders a list result in the selected format: v for json, t for
+// table and csv, and human for plain.
+func printList(v any, t table, human func()) error {
+	switch outputFormat {
+	case outputJSON:
+		return writeJSON(v)
+	case outputCSV:
+		w := csv.NewWriter(os.Stdout)
+		if err := w.Write(t.Headers); err != nil {
+			return err
+		}
+		if err := w.WriteAll(t.Rows); err != nil {
+			return err
+		}
+		return w.Error()
+	case outputTable:
+		return writeTable(t)
+	}
+	human()
+	return nil
+}
+
+// writeTable renders t as aligned columns on stdout.
+func writeTable(t table) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(t.Headers, "\t"))
+	for _, row := range t.Rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func writeJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// infof prints progress and status messages. In json and csv modes they go
+// to stderr so stdout only ever carries the document.
+func infof(format string, args ...any) {
+	w := os.Stdout
+	if machineOutput() {
+		w = os.Stderr
+	}
+	fmt.Fprintf(w, format, args...)
+}
+
+// colorStatus colors s green (ok), yellow (pending, warn) or red (fail) on a
+// terminal, unless NO_COLOR is set.
+func colorStatus(status, s string) string {
+	if os.Getenv("NO_COLOR") != "" || !isTerminal(os.Stdout) {
+		return s
+	}
+	code := map[string]string{"ok": "32", "pending": "33", "warn": "33", "fail": "31"}[status]
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
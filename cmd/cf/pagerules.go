@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+)
+
+var (
+	pageRuleCacheLevels = []string{"bypass", "basic", "simplified", "aggressive", "cache_everything"}
+	pageRuleSSLModes    = []string{"off", "flexible", "full", "strict"}
+)
+
+type pageRule struct {
+	ID      string `json:"id"`
+	Targets []struct {
+		Target     string `json:"target"`
+		Constraint struct {
+			Operator string `json:"operator"`
+			Value    string `json:"value"`
+		} `json:"constraint"`
+	} `json:"targets"`
+	Actions    []pageRuleAction `json:"actions"`
+	Priority   int              `json:"priority"`
+	Status     string           `json:"status"`
+	ModifiedOn string           `json:"modified_on,omitempty"`
+}
+
+type pageRuleAction struct {
+	ID    string `json:"id"`
+	Value any    `json:"value,omitempty"`
+}
+
+func (r pageRule) pattern() string {
+	if len(r.Targets) == 0 {
+		return ""
+	}
+	return r.Targets[0].Constraint.Value
+}
+
+func (r pageRule) actionSummary() string {
+	parts := make([]string, 0, len(r.Actions))
+	for _, a := range r.Actions {
+		switch v := a.Value.(type) {
+		case nil:
+			parts = append(parts, a.ID)
+		case map[string]any:
+			parts = append(parts, fmt.Sprintf("%s=%v %v", a.ID, v["status_code"], v["url"]))
+		default:
+			parts = append(parts, fmt.Sprintf("%s=%v", a.ID, v))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runPageRules(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for pagerules: --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	path := "/zones/" + z.ID + "/pagerules"
+
+	switch action {
+	case "list":
+		rules, err := listPageRules(path)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "PRIORITY", "STATUS", "URL", "ACTIONS"}}
+		for _, r := range rules {
+			t.Rows = append(t.Rows, []string{r.ID, fmt.Sprint(r.Priority), r.Status, r.pattern(), r.actionSummary()})
+		}
+		return printList(rules, t, func() {
+			if len(rules) == 0 {
+				fmt.Printf("No page rules on %s.\n", z.Name)
+				return
+			}
+			for _, r := range rules {
+				fmt.Printf("%s\t%d\t%s\t%s\t%s\n", r.ID, r.Priority, r.Status, r.pattern(), r.actionSummary())
+			}
+		})
+	case "add":
+		body, err := pageRuleBody(flags)
+		if err != nil {
+			return err
+		}
+		resp, err := requestCF(http.MethodPost, path, body)
+		if err != nil {
+			return err
+		}
+		var r pageRule
+		if err := json.Unmarshal(resp.Result, &r); err != nil {
+			return err
+		}
+		return printResult(r, func() {
+			fmt.Printf("Page rule added: %s -> %s (id=%s)\n", r.pattern(), r.actionSummary(), r.ID)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf pagerules delete <rule-id> --zone <zone>")
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+positional[0], nil); err != nil {
+			return err
+		}
+		return printResult(map[string]string{"id": positional[0]}, func() {
+			fmt.Printf("Page rule deleted: %s\n", positional[0])
+		})
+	case "export":
+		rules, err := listPageRules(path)
+		if err != nil {
+			return err
+		}
+		return exportPageRules(z.Name, rules, flags["file"])
+	}
+	return usageErrorf("usage: cf pagerules list|add|delete|export --zone <zone>")
+}
+
+func listPageRules(path string) ([]pageRule, error) {
+	resp, err := requestCF(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var rules []pageRule
+	if err := json.Unmarshal(resp.Result, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// pageRuleBody maps the common page rule actions onto flags.
+func pageRuleBody(flags map[string]string) (map[string]any, error) {
+	if flags["url"] == "" {
+		return nil, usageErrorf("missing required flag for pagerules add: --url")
+	}
+
+	var actions []pageRuleAction
+	if v := flags["forward"]; v != "" {
+		status, err := parseIntWithDefault(flags["status"], 301)
+		if err != nil || (status != 301 && status != 302) {
+			return nil, usageErrorf("invalid --status: forwarding URLs support 301 or 302")
+		}
+		actions = append(actions, pageRuleAction{ID: "forwarding_url", Value: map[string]any{"url": v, "status_code": status}})
+	}
+	if v := flags["cache-level"]; v != "" {
+		if !slices.Contains(pageRuleCacheLevels, v) {
+			return nil, usageErrorf("invalid --cache-level %q (want one of: %s)", v, strings.Join(pageRuleCacheLevels, ", "))
+		}
+		actions = append(actions, pageRuleAction{ID: "cache_level", Value: v})
+	}
+	if v := flags["ssl"]; v != "" {
+		if !slices.Contains(pageRuleSSLModes, v) {
+			return nil, usageErrorf("invalid --ssl %q (want one of: %s)", v, strings.Join(pageRuleSSLModes, ", "))
+		}
+		actions = append(actions, pageRuleAction{ID: "ssl", Value: v})
+	}
+	if parseBoolWithDefault(flags["always-use-https"], false) {
+		actions = append(actions, pageRuleAction{ID: "always_use_https"})
+	}
+	if len(actions) == 0 {
+		return nil, errors.New("no action given: pass --forward, --cache-level, --ssl or --always-use-https")
+	}
+	for _, a := range actions {
+		if (a.ID == "forwarding_url" || a.ID == "always_use_https") && len(actions) > 1 {
+			return nil, fmt.Errorf("%s cannot be combined with other page rule actions", a.ID)
+		}
+	}
+
+	priority, err := parseIntWithDefault(flags["priority"], 1)
+	if err != nil {
+		return nil, usageErrorf("invalid --priority: %w", err)
+	}
+	status := "active"
+	if parseBoolWithDefault(flags["disabled"], false) {
+		status = "disabled"
+	}
+
+	return map[string]any{
+		"targets": []map[string]any{{
+			"target":     "url",
+			"constraint": map[string]string{"operator": "matches", "value": flags["url"]},
+		}},
+		"actions":  actions,
+		"priority": priority,
+		"status":   status,
+	}, nil
+}
+
+// pageRuleReplacement names the modern feature each page rule action maps to,
+// for planning a migration off page rules.
+var pageRuleReplacement = map[string]string{
+	"forwarding_url":           "Single Redirects (cf redirects rules)",
+	"always_use_https":         "Always Use HTTPS zone setting or a Single Redirect",
+	"ssl":                      "Configuration Rules",
+	"cache_level":              "Cache Rules",
+	"edge_cache_ttl":           "Cache Rules",
+	"browser_cache_ttl":        "Cache Rules",
+	"bypass_cache_on_cookie":   "Cache Rules",
+	"cache_key_fields":         "Cache Rules",
+	"host_header_override":     "Origin Rules",
+	"resolve_override":         "Origin Rules",
+	"security_level":           "Configuration Rules",
+	"browser_check":            "Configuration Rules",
+	"rocket_loader":            "Configuration Rules",
+	"automatic_https_rewrites": "Configuration Rules",
+	"disable_apps":             "Configuration Rules",
+	"disable_performance":      "Configuration Rules",
+	"disable_security":         "WAF custom rules (skip action)",
+	"email_obfuscation":        "Configuration Rules",
+}
+
+type pageRuleExport struct {
+	Zone  string               `json:"zone"`
+	Rules []pageRuleExportItem `json:"rules"`
+}
+
+type pageRuleExportItem struct {
+	pageRule
+	ReplaceWith []string `json:"replace_with"`
+}
+
+func exportPageRules(zoneName string, rules []pageRule, file string) error {
+	out := pageRuleExport{Zone: zoneName, Rules: []pageRuleExportItem{}}
+	for _, r := range rules {
+		item := pageRuleExportItem{pageRule: r}
+		for _, a := range r.Actions {
+			repl := pageRuleReplacement[a.ID]
+			if repl == "" {
+				repl = "see Cloudflare page rules migration guide"
+			}
+			if !slices.Contains(item.ReplaceWith, repl) {
+				item.ReplaceWith = append(item.ReplaceWith, repl)
+			}
+		}
+		out.Rules = append(out.Rules, item)
+	}
+
+	if file == "" {
+		return writeJSON(out)
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	infof("Exported %d page rules from %s to %s\n", len(rules), zoneName, file)
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestPageRuleBody(t *testing.T) {
+	body, err := pageRuleBody(map[string]string{"url": "example.com/static/*", "cache-level": "cache_everything", "ssl": "strict"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actions := body["actions"].([]pageRuleAction)
+	if len(actions) != 2 || actions[0].ID != "cache_level" || actions[1].Value != "strict" {
+		t.Fatalf("unexpected actions: %+v", actions)
+	}
+	if body["status"] != "active" || body["priority"] != 1 {
+		t.Fatalf("unexpected defaults: %v", body)
+	}
+
+	bad := []map[string]string{
+		{"cache-level": "basic"},
+		{"url": "example.com/*"},
+		{"url": "example.com/*", "cache-level": "everything"},
+		{"url": "example.com/*", "forward": "https://example.org/$1", "ssl": "full"},
+		{"url": "example.com/*", "forward": "https://example.org/$1", "status": "308"},
+	}
+	for _, flags := range bad {
+		if _, err := pageRuleBody(flags); err == nil {
+			t.Fatalf("expected error for %v", flags)
+		}
+	}
+}
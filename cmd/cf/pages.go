@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type pagesProject struct {
+	Name             string           `json:"name"`
+	Subdomain        string           `json:"subdomain,omitempty"`
+	Domains          []string         `json:"domains,omitempty"`
+	ProductionBranch string           `json:"production_branch,omitempty"`
+	CreatedOn        string           `json:"created_on,omitempty"`
+	LatestDeployment *pagesDeployment `json:"latest_deployment,omitempty"`
+}
+
+type pagesDeployment struct {
+	ID          string `json:"id"`
+	ShortID     string `json:"short_id,omitempty"`
+	Environment string `json:"environment"`
+	URL         string `json:"url"`
+	CreatedOn   string `json:"created_on"`
+	LatestStage struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	} `json:"latest_stage"`
+	DeploymentTrigger struct {
+		Metadata struct {
+			Branch        string `json:"branch"`
+			CommitHash    string `json:"commit_hash"`
+			CommitMessage string `json:"commit_message"`
+		} `json:"metadata"`
+	} `json:"deployment_trigger"`
+}
+
+func (d pagesDeployment) succeeded() bool {
+	return d.LatestStage.Name == "deploy" && d.LatestStage.Status == "success"
+}
+
+func runPages(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf pages list|deployments|retry|rollback")
+	}
+	positional, flags := splitArgs(args[1:])
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	path := "/accounts/" + accountID + "/pages/projects"
+
+	if args[0] == "list" {
+		projects, err := listAll[pagesProject](path, 10, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"NAME", "SUBDOMAIN", "BRANCH", "LATEST"}}
+		for _, p := range projects {
+			latest := ""
+			if p.LatestDeployment != nil {
+				latest = p.LatestDeployment.CreatedOn
+			}
+			t.Rows = append(t.Rows, []string{p.Name, p.Subdomain, p.ProductionBranch, latest})
+		}
+		return printList(projects, t, func() {
+			for _, p := range projects {
+				fmt.Printf("%s\t%s\n", p.Name, p.Subdomain)
+			}
+		})
+	}
+
+	if len(positional) == 0 {
+		return usageErrorf("usage: cf pages %s <project>", args[0])
+	}
+	project := positional[0]
+	deploymentsPath := path + "/" + url.PathEscape(project) + "/deployments"
+
+	switch args[0] {
+	case "deployments":
+		limit, err := parseIntWithDefault(flags["limit"], 20)
+		if err != nil {
+			return usageErrorf("invalid --limit: %w", err)
+		}
+		query := ""
+		if env := flags["env"]; env != "" {
+			query = "?env=" + url.QueryEscape(env)
+		}
+		deployments, err := listAll[pagesDeployment](deploymentsPath+query, 25, limit)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "ENVIRONMENT", "BRANCH", "COMMIT", "STATUS", "CREATED", "URL"}}
+		for _, d := range deployments {
+			m := d.DeploymentTrigger.Metadata
+			t.Rows = append(t.Rows, []string{d.ID, d.Environment, m.Branch, shortHash(m.CommitHash), d.LatestStage.Name + ":" + d.LatestStage.Status, d.CreatedOn, d.URL})
+		}
+		return printList(deployments, t, func() {
+			for _, d := range deployments {
+				m := d.DeploymentTrigger.Metadata
+				fmt.Printf("%s\t%s\t%s@%s\t%s:%s\t%s\n", d.ID, d.Environment, m.Branch, shortHash(m.CommitHash), d.LatestStage.Name, d.LatestStage.Status, d.CreatedOn)
+			}
+		})
+	case "retry", "rollback":
+		id := ""
+		if len(positional) > 1 {
+			id = positional[1]
+		} else {
+			deployments, err := listAll[pagesDeployment](deploymentsPath+"?env=production", 25, 25)
+			if err != nil {
+				return err
+			}
+			d, err := pickPagesDeployment(deployments, args[0])
+			if err != nil {
+				return err
+			}
+			id = d.ID
+			infof("Using deployment %s (%s, %s)\n", d.ID, shortHash(d.DeploymentTrigger.Metadata.CommitHash), d.CreatedOn)
+		}
+
+		resp, err := requestCF(http.MethodPost, deploymentsPath+"/"+url.PathEscape(id)+"/"+args[0], nil)
+		if err != nil {
+			return err
+		}
+		var d pagesDeployment
+		if err := json.Unmarshal(resp.Result, &d); err != nil {
+			return err
+		}
+		return printResult(d, func() {
+			if args[0] == "retry" {
+				fmt.Printf("Retrying deployment %s: new deployment %s\n", id, d.ID)
+			} else {
+				fmt.Printf("Rolled back %s to deployment %s\n", project, id)
+			}
+			if d.URL != "" {
+				fmt.Println(d.URL)
+			}
+		})
+	}
+	return errors.New("unknown pages command. run: cf help")
+}
+
+// pickPagesDeployment chooses the default target from newest-first production
+// deployments: retry uses the latest one, rollback the newest successful
+// deployment before it.
+func pickPagesDeployment(deployments []pagesDeployment, action string) (*pagesDeployment, error) {
+	if len(deployments) == 0 {
+		return nil, errors.New("no production deployments found")
+	}
+	if action == "retry" {
+		return &deployments[0], nil
+	}
+	for i := 1; i < len(deployments); i++ {
+		if deployments[i].succeeded() {
+			return &deployments[i], nil
+		}
+	}
+	return nil, errors.New("no earlier successful production deployment to roll back to; pass a deployment ID")
+}
+
+func shortHash(h string) string {
+	if len(h) > 7 {
+		return h[:7]
+	}
+	return h
+}
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestPickPagesDeployment(t *testing.T) {
+	mk := func(id, status string) pagesDeployment {
+		var d pagesDeployment
+		d.ID = id
+		d.LatestStage.Name = "deploy"
+		d.LatestStage.Status = status
+		return d
+	}
+	deployments := []pagesDeployment{mk("c", "success"), mk("b", "failure"), mk("a", "success")}
+
+	d, err := pickPagesDeployment(deployments, "rollback")
+	if err != nil || d.ID != "a" {
+		t.Fatalf("expected rollback to pick a, got %v (err=%v)", d, err)
+	}
+	d, err = pickPagesDeployment(deployments, "retry")
+	if err != nil || d.ID != "c" {
+		t.Fatalf("expected retry to pick c, got %v (err=%v)", d, err)
+	}
+	if _, err := pickPagesDeployment(deployments[:2], "rollback"); err == nil {
+		t.Fatalf("expected error when no earlier successful deployment exists")
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+)
+
+type probeHop struct {
+	URL      string `json:"url"`
+	Status   int    `json:"status"`
+	Location string `json:"location,omitempty"`
+}
+
+type probeTLS struct {
+	Version   string    `json:"version"`
+	Cipher    string    `json:"cipher"`
+	ALPN      string    `json:"alpn,omitempty"`
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dns_names,omitempty"`
+	NotAfter  time.Time `json:"not_after"`
+	ExpiresIn string    `json:"expires_in"`
+}
+
+type probeTimings struct {
+	DNS     time.Duration `json:"dns_ns"`
+	Connect time.Duration `json:"connect_ns"`
+	TLS     time.Duration `json:"tls_ns"`
+	TTFB    time.Duration `json:"ttfb_ns"`
+	Total   time.Duration `json:"total_ns"`
+}
+
+type probeResult struct {
+	URL           string       `json:"url"`
+	FinalURL      string       `json:"final_url"`
+	Status        int          `json:"status"`
+	Redirects     []probeHop   `json:"redirects"`
+	ViaCloudflare bool         `json:"via_cloudflare"`
+	Server        string       `json:"server,omitempty"`
+	CFRay         string       `json:"cf_ray,omitempty"`
+	Colo          string       `json:"colo,omitempty"`
+	CacheStatus   string       `json:"cache_status,omitempty"`
+	TLS           *probeTLS    `json:"tls,omitempty"`
+	Timings       probeTimings `json:"timings"`
+}
+
+func runProbe(args []string) error {
+	positional, flags := splitArgs(args)
+	if len(positional) != 1 {
+		return usageErrorf("usage: cf probe <url> [--no-follow] [--timeout 15s] [--require-cloudflare]")
+	}
+	target := positional[0]
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+	timeout, err := parseDurationWithDefault(flags["timeout"], 15*time.Second)
+	if err != nil {
+		return usageErrorf("invalid --timeout: %w", err)
+	}
+
+	result, err := probe(outboundClient(timeout), target, !parseBoolWithDefault(flags["no-follow"], false))
+	if err != nil {
+		return err
+	}
+	if err := printResult(result, func() { printProbe(result) }); err != nil {
+		return err
+	}
+	if parseBoolWithDefault(flags["require-cloudflare"], false) && !result.ViaCloudflare {
+		return fmt.Errorf("%s is not served through Cloudflare", result.FinalURL)
+	}
+	return nil
+}
+
+// probe fetches target, recording each redirect, and reports how the final
+// response was served. Timings are for the final request.
+func probe(client *http.Client, target string, follow bool) (*probeResult, error) {
+	result := &probeResult{URL: target, Redirects: []probeHop{}}
+	url := target
+	for hops := 0; ; hops++ {
+		if hops > 10 {
+			return nil, errors.New("stopped after 10 redirects")
+		}
+		resp, timings, err := probeOnce(client, url)
+		if err != nil {
+			return nil, fmt.Errorf("probe %s: %w", url, err)
+		}
+		location := resp.Header.Get("Location")
+		if follow && resp.StatusCode >= 300 && resp.StatusCode < 400 && location != "" {
+			next, err := resp.Request.URL.Parse(location)
+			if err != nil {
+				return nil, fmt.Errorf("bad redirect from %s: %w", url, err)
+			}
+			result.Redirects = append(result.Redirects, probeHop{URL: url, Status: resp.StatusCode, Location: next.String()})
+			url = next.String()
+			continue
+		}
+
+		result.FinalURL, result.Status, result.Timings = url, resp.StatusCode, timings
+		result.Server = resp.Header.Get("Server")
+		result.CFRay = resp.Header.Get("Cf-Ray")
+		result.CacheStatus = resp.Header.Get("Cf-Cache-Status")
+		result.ViaCloudflare = result.CFRay != "" || strings.EqualFold(result.Server, "cloudflare")
+		// The ray ID ends with the IATA code of the data center that answered.
+		if i := strings.LastIndex(result.CFRay, "-"); i >= 0 {
+			result.Colo = result.CFRay[i+1:]
+		}
+		if st := resp.TLS; st != nil && len(st.PeerCertificates) > 0 {
+			cert := st.PeerCertificates[0]
+			result.TLS = &probeTLS{
+				Version:   tls.VersionName(st.Version),
+				Cipher:    tls.CipherSuiteName(st.CipherSuite),
+				ALPN:      st.NegotiatedProtocol,
+				Subject:   cert.Subject.CommonName,
+				Issuer:    cert.Issuer.String(),
+				DNSNames:  cert.DNSNames,
+				NotAfter:  cert.NotAfter,
+				ExpiresIn: fmt.Sprintf("%d days", int(time.Until(cert.NotAfter).Hours()/24)),
+			}
+		}
+		return result, nil
+	}
+}
+
+// probeClock records phase timings from httptrace callbacks, which run on
+// the transport's dial goroutines and can fire after Do returns (e.g. an
+// abandoned happy-eyeballs dial).
+type probeClock struct {
+	mu                            sync.Mutex
+	t                             probeTimings
+	dnsStart, connStart, tlsStart time.Time
+}
+
+func (c *probeClock) do(f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f()
+}
+
+func (c *probeClock) timings() probeTimings {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+// probeOnce sends one GET without following redirects and times its phases.
+func probeOnce(client *http.Client, url string) (*http.Response, probeTimings, error) {
+	clock := &probeClock{}
+	start := time.Now()
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { clock.do(func() { clock.dnsStart = time.Now() }) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { clock.do(func() { clock.t.DNS = time.Since(clock.dnsStart) }) },
+		ConnectStart:         func(string, string) { clock.do(func() { clock.connStart = time.Now() }) },
+		ConnectDone:          func(string, string, error) { clock.do(func() { clock.t.Connect = time.Since(clock.connStart) }) },
+		TLSHandshakeStart:    func() { clock.do(func() { clock.tlsStart = time.Now() }) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { clock.do(func() { clock.t.TLS = time.Since(clock.tlsStart) }) },
+		GotFirstResponseByte: func() { clock.do(func() { clock.t.TTFB = time.Since(start) }) },
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(rootCtx, trace), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, probeTimings{}, err
+	}
+	req.Header.Set("User-Agent", "cf-probe")
+
+	c := *client
+	c.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, clock.timings(), err
+	}
+	resp.Body.Close()
+	t := clock.timings()
+	t.Total = time.Since(start)
+	return resp, t, nil
+}
+
+func printProbe(r *probeResult) {
+	for _, h := range r.Redirects {
+		fmt.Printf("%d %s -> %s\n", h.Status, h.URL, h.Location)
+	}
+	fmt.Printf("%d %s\n", r.Status, r.FinalURL)
+	if r.ViaCloudflare {
+		fmt.Printf("Served via Cloudflare: yes (cf-ray %s", r.CFRay)
+		if r.Colo != "" {
+			fmt.Printf(", data center %s", r.Colo)
+		}
+		fmt.Println(")")
+		fmt.Printf("Cache: %s\n", orNone(r.CacheStatus))
+	} else {
+		fmt.Printf("Served via Cloudflare: no (server %q); check the record is proxied and DNS has propagated\n", r.Server)
+	}
+	if r.TLS != nil {
+		fmt.Printf("TLS: %s %s", r.TLS.Version, r.TLS.Cipher)
+		if r.TLS.ALPN != "" {
+			fmt.Printf(" (%s)", r.TLS.ALPN)
+		}
+		fmt.Println()
+		fmt.Printf("Certificate: %s, issued by %s, expires %s (%s)\n", r.TLS.Subject, r.TLS.Issuer, r.TLS.NotAfter.Format(time.DateOnly), r.TLS.ExpiresIn)
+	}
+	ms := func(d time.Duration) string { return d.Round(time.Millisecond).String() }
+	fmt.Printf("Timing: dns %s, connect %s, tls %s, first byte %s, total %s\n", ms(r.Timings.DNS), ms(r.Timings.Connect), ms(r.Timings.TLS), ms(r.Timings.TTFB), ms(r.Timings.Total))
+}
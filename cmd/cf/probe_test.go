@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProbe(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Server", "cloudflare")
+		w.Header().Set("Cf-Ray", "8a1b2c3d4e5f6789-LHR")
+		w.Header().Set("Cf-Cache-Status", "HIT")
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+
+	r, err := probe(srv.Client(), srv.URL+"/old", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Redirects) != 1 || r.Redirects[0].Status != 301 || r.Redirects[0].Location != srv.URL+"/new" {
+		t.Fatalf("unexpected redirects: %+v", r.Redirects)
+	}
+	if r.FinalURL != srv.URL+"/new" || r.Status != 200 {
+		t.Fatalf("unexpected final response: %s %d", r.FinalURL, r.Status)
+	}
+	if !r.ViaCloudflare || r.Colo != "LHR" || r.CacheStatus != "HIT" {
+		t.Fatalf("unexpected Cloudflare details: %+v", r)
+	}
+	if r.TLS == nil || !strings.HasPrefix(r.TLS.Version, "TLS") || r.TLS.Cipher == "" {
+		t.Fatalf("unexpected TLS details: %+v", r.TLS)
+	}
+
+	r, err = probe(srv.Client(), srv.URL+"/old", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Redirects) != 0 || r.Status != 301 || r.ViaCloudflare {
+		t.Fatalf("--no-follow should stop at the redirect: %+v", r)
+	}
+}
+
+func TestProbeRedirectLoop(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path, http.StatusFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	if _, err := probe(srv.Client(), srv.URL+"/loop", true); err == nil || !strings.Contains(err.Error(), "10 redirects") {
+		t.Fatalf("expected redirect limit error, got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type queue struct {
+	ID             string          `json:"queue_id"`
+	Name           string          `json:"queue_name"`
+	CreatedOn      string          `json:"created_on,omitempty"`
+	ModifiedOn     string          `json:"modified_on,omitempty"`
+	ProducersCount int             `json:"producers_total_count"`
+	ConsumersCount int             `json:"consumers_total_count"`
+	Consumers      []queueConsumer `json:"consumers,omitempty"`
+}
+
+type queueConsumer struct {
+	ID              string `json:"consumer_id,omitempty"`
+	Script          string `json:"script,omitempty"`
+	ScriptName      string `json:"script_name,omitempty"`
+	Type            string `json:"type,omitempty"`
+	DeadLetterQueue string `json:"dead_letter_queue,omitempty"`
+	Settings        struct {
+		BatchSize     int `json:"batch_size,omitempty"`
+		MaxRetries    int `json:"max_retries,omitempty"`
+		MaxWaitTimeMs int `json:"max_wait_time_ms,omitempty"`
+	} `json:"settings"`
+}
+
+// script returns the consumer's Worker, which the API reports under either
+// field depending on the endpoint.
+func (c queueConsumer) script() string {
+	if c.Script != "" {
+		return c.Script
+	}
+	return c.ScriptName
+}
+
+func runQueues(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	path := "/accounts/" + accountID + "/queues"
+	if action == "consumers" {
+		return runQueueConsumers(path, args)
+	}
+	positional, flags := splitArgs(args)
+
+	switch action {
+	case "list":
+		queues, err := listAll[queue](path, 100, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "PRODUCERS", "CONSUMERS", "CREATED"}}
+		for _, q := range queues {
+			t.Rows = append(t.Rows, []string{q.ID, q.Name, strconv.Itoa(q.ProducersCount), strconv.Itoa(q.ConsumersCount), q.CreatedOn})
+		}
+		return printList(queues, t, func() {
+			if len(queues) == 0 {
+				fmt.Println("No queues.")
+				return
+			}
+			for _, q := range queues {
+				fmt.Printf("%s\t%s\tproducers=%d consumers=%d\n", q.ID, q.Name, q.ProducersCount, q.ConsumersCount)
+			}
+		})
+	case "create":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf queues create <name>")
+		}
+		resp, err := requestCF(http.MethodPost, path, map[string]string{"queue_name": positional[0]})
+		if err != nil {
+			return err
+		}
+		var q queue
+		if err := json.Unmarshal(resp.Result, &q); err != nil {
+			return err
+		}
+		return printResult(q, func() {
+			fmt.Printf("Queue created: %s (id=%s)\n", q.Name, q.ID)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf queues delete <name|id> [--force]")
+		}
+		if err := requireConfirmable(flags, "queues delete"); err != nil {
+			return err
+		}
+		q, err := findQueue(path, positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirm(flags, "queues delete", fmt.Sprintf("Delete queue %s and any undelivered messages?", q.Name))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+q.ID, nil); err != nil {
+			return err
+		}
+		return printResult(q, func() {
+			fmt.Printf("Queue deleted: %s\n", q.Name)
+		})
+	}
+	return usageErrorf("usage: cf queues list|create|delete|consumers")
+}
+
+func runQueueConsumers(path string, args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	flags := parseFlags(args)
+	if flags["queue"] == "" {
+		return usageErrorf("missing required flag for queues consumers: --queue")
+	}
+	q, err := findQueue(path, flags["queue"])
+	if err != nil {
+		return err
+	}
+	path += "/" + q.ID + "/consumers"
+
+	switch action {
+	case "list":
+		resp, err := requestCF(http.MethodGet, path, nil)
+		if err != nil {
+			return err
+		}
+		var consumers []queueConsumer
+		if err := json.Unmarshal(resp.Result, &consumers); err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "SCRIPT", "BATCH_SIZE", "MAX_RETRIES", "MAX_WAIT_MS", "DEAD_LETTER_QUEUE"}}
+		for _, c := range consumers {
+			t.Rows = append(t.Rows, []string{c.ID, c.script(), strconv.Itoa(c.Settings.BatchSize), strconv.Itoa(c.Settings.MaxRetries), strconv.Itoa(c.Settings.MaxWaitTimeMs), c.DeadLetterQueue})
+		}
+		return printList(consumers, t, func() {
+			if len(consumers) == 0 {
+				fmt.Printf("No consumers on %s.\n", q.Name)
+				return
+			}
+			for _, c := range consumers {
+				fmt.Printf("%s\t%s\tbatch=%d retries=%d\n", c.ID, c.script(), c.Settings.BatchSize, c.Settings.MaxRetries)
+			}
+		})
+	case "add":
+		if flags["script"] == "" {
+			return usageErrorf("usage: cf queues consumers add --queue <queue> --script <worker> [--batch-size 10] [--max-retries 3] [--max-wait 5s] [--dead-letter-queue <queue>]")
+		}
+		c, err := queueConsumerFromFlags(flags)
+		if err != nil {
+			return err
+		}
+		resp, err := requestCF(http.MethodPost, path, c)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, &c); err != nil {
+			return err
+		}
+		return printResult(c, func() {
+			fmt.Printf("Consumer added: %s now consumes %s (id=%s)\n", c.script(), q.Name, c.ID)
+		})
+	case "remove":
+		if flags["script"] == "" {
+			return usageErrorf("usage: cf queues consumers remove --queue <queue> --script <worker>")
+		}
+		resp, err := requestCF(http.MethodGet, path, nil)
+		if err != nil {
+			return err
+		}
+		var consumers []queueConsumer
+		if err := json.Unmarshal(resp.Result, &consumers); err != nil {
+			return err
+		}
+		for _, c := range consumers {
+			if c.script() != flags["script"] && c.ID != flags["script"] {
+				continue
+			}
+			if _, err := requestCF(http.MethodDelete, path+"/"+c.ID, nil); err != nil {
+				return err
+			}
+			return printResult(c, func() {
+				fmt.Printf("Consumer removed: %s no longer consumes %s\n", c.script(), q.Name)
+			})
+		}
+		return fmt.Errorf("%s is not a consumer of %s. run: cf queues consumers list --queue %s", flags["script"], q.Name, q.Name)
+	}
+	return usageErrorf("usage: cf queues consumers list|add|remove --queue <queue>")
+}
+
+func queueConsumerFromFlags(flags map[string]string) (queueConsumer, error) {
+	c := queueConsumer{ScriptName: flags["script"], Type: "worker", DeadLetterQueue: flags["dead-letter-queue"]}
+	var err error
+	if c.Settings.BatchSize, err = parseIntWithDefault(flags["batch-size"], 0); err != nil {
+		return c, usageErrorf("invalid --batch-size: %w", err)
+	}
+	if c.Settings.MaxRetries, err = parseIntWithDefault(flags["max-retries"], 0); err != nil {
+		return c, usageErrorf("invalid --max-retries: %w", err)
+	}
+	wait, err := parseDurationWithDefault(flags["max-wait"], 0)
+	if err != nil {
+		return c, usageErrorf("invalid --max-wait: %w", err)
+	}
+	c.Settings.MaxWaitTimeMs = int(wait / time.Millisecond)
+	return c, nil
+}
+
+func findQueue(path, ref string) (*queue, error) {
+	queues, err := listAll[queue](path, 100, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range queues {
+		if q.ID == ref || q.Name == ref {
+			return &q, nil
+		}
+	}
+	return nil, notFoundErrorf("queue %q not found. run: cf queues list", ref)
+}
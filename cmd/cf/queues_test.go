@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const queuesPath = "/accounts/acc1/queues"
+
+func TestQueuesConsumersAdd(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", queuesPath, []queue{{ID: "q1", Name: "jobs"}})
+	srv.Reply("POST", queuesPath+"/q1/consumers", queueConsumer{ID: "c1", ScriptName: "worker"})
+
+	out, err := captureStdout(t, func() error {
+		return runQueues([]string{"consumers", "add", "--queue", "jobs", "--script", "worker", "--batch-size", "50", "--max-wait", "2s", "--dead-letter-queue", "dlq"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	posts := srv.Calls("POST", queuesPath+"/q1/consumers")
+	if len(posts) != 1 {
+		t.Fatalf("expected one POST, got %d", len(posts))
+	}
+	want := `{"script_name":"worker","type":"worker","dead_letter_queue":"dlq","settings":{"batch_size":50,"max_wait_time_ms":2000}}`
+	if got := strings.TrimSpace(string(posts[0].Body)); got != want {
+		t.Fatalf("unexpected consumer:\n got %s\nwant %s", got, want)
+	}
+	if out != "Consumer added: worker now consumes jobs (id=c1)\n" {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	if err := runQueues([]string{"consumers", "add", "--queue", "jobs", "--script", "w", "--max-retries", "many"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error for invalid --max-retries, got %v", err)
+	}
+	if n := len(srv.Calls("POST", queuesPath+"/q1/consumers")); n != 1 {
+		t.Fatalf("expected no request for invalid flags, got %d POSTs", n)
+	}
+}
+
+func TestQueuesConsumersRemove(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", queuesPath, []queue{{ID: "q1", Name: "jobs"}})
+	srv.Reply("GET", queuesPath+"/q1/consumers", []queueConsumer{{ID: "c1", Script: "worker"}})
+	srv.Reply("DELETE", queuesPath+"/q1/consumers/c1", nil)
+
+	out, err := captureStdout(t, func() error {
+		return runQueues([]string{"consumers", "remove", "--queue", "q1", "--script", "worker"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(srv.Calls("DELETE", queuesPath+"/q1/consumers/c1")); n != 1 || out != "Consumer removed: worker no longer consumes jobs\n" {
+		t.Fatalf("expected one DELETE, got %d:\n%s", n, out)
+	}
+
+	if err := runQueues([]string{"consumers", "list", "--queue", "mail"}); exitCode(err) != exitNotFound {
+		t.Fatalf("expected not found for an unknown queue, got %v", err)
+	}
+}
+
+func TestQueuesCreate(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("POST", queuesPath, queue{ID: "q2", Name: "emails"})
+
+	out, err := captureStdout(t, func() error { return runQueues([]string{"create", "emails"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body map[string]string
+	if posts := srv.Calls("POST", queuesPath); len(posts) != 1 {
+		t.Fatalf("expected one POST, got %d", len(posts))
+	} else if err := posts[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["queue_name"] != "emails" || out != "Queue created: emails (id=q2)\n" {
+		t.Fatalf("unexpected body %v:\n%s", body, out)
+	}
+
+	srv.Fail("POST", queuesPath, 400, 11009, "A queue with this name already exists")
+	err = runQueues([]string{"create", "emails"})
+	if exitCode(err) != exitAPI || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+}
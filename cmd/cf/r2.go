@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type r2Bucket struct {
+	Name         string `json:"name"`
+	CreationDate string `json:"creation_date,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+// r2Usage mirrors GET /r2/buckets/:name/usage; the API reports sizes as
+// strings.
+type r2Usage struct {
+	Bucket       string `json:"bucket"`
+	PayloadSize  string `json:"payloadSize"`
+	MetadataSize string `json:"metadataSize"`
+	ObjectCount  string `json:"objectCount"`
+	UploadCount  string `json:"uploadCount"`
+	End          string `json:"end,omitempty"`
+}
+
+func runR2(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf r2 list|create|delete|usage")
+	}
+	positional, flags := splitArgs(args[1:])
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	path := "/accounts/" + accountID + "/r2/buckets"
+
+	switch args[0] {
+	case "list":
+		buckets, err := listR2Buckets(path)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"NAME", "LOCATION", "CREATED"}}
+		for _, b := range buckets {
+			t.Rows = append(t.Rows, []string{b.Name, b.Location, b.CreationDate})
+		}
+		return printList(buckets, t, func() {
+			if len(buckets) == 0 {
+				fmt.Println("No R2 buckets found.")
+				return
+			}
+			for _, b := range buckets {
+				fmt.Printf("%s\t%s\n", b.Name, b.Location)
+			}
+		})
+	case "create":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf r2 create <bucket> [--location <wnam|enam|weur|eeur|apac|oc>]")
+		}
+		body := map[string]string{"name": positional[0]}
+		if v := flags["location"]; v != "" {
+			body["locationHint"] = v
+		}
+		resp, err := requestCF(http.MethodPost, path, body)
+		if err != nil {
+			return err
+		}
+		b := r2Bucket{Name: positional[0]}
+		if err := json.Unmarshal(resp.Result, &b); err != nil {
+			return err
+		}
+		return printResult(b, func() {
+			fmt.Printf("Bucket created: %s\n", b.Name)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf r2 delete <bucket> [--force]")
+		}
+		name := positional[0]
+		ok, err := confirm(flags, "r2 delete", fmt.Sprintf("Delete R2 bucket %s?", name))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+url.PathEscape(name), nil); err != nil {
+			return err
+		}
+		return printResult(r2Bucket{Name: name}, func() {
+			fmt.Printf("Bucket deleted: %s\n", name)
+		})
+	case "usage":
+		names := positional
+		if len(names) == 0 {
+			buckets, err := listR2Buckets(path)
+			if err != nil {
+				return err
+			}
+			for _, b := range buckets {
+				names = append(names, b.Name)
+			}
+		}
+		var usage []r2Usage
+		for _, name := range names {
+			resp, err := requestCF(http.MethodGet, path+"/"+url.PathEscape(name)+"/usage", nil)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			u := r2Usage{Bucket: name}
+			if err := json.Unmarshal(resp.Result, &u); err != nil {
+				return err
+			}
+			usage = append(usage, u)
+		}
+		t := table{Headers: []string{"BUCKET", "OBJECTS", "SIZE", "METADATA", "UPLOADS"}}
+		for _, u := range usage {
+			t.Rows = append(t.Rows, []string{u.Bucket, u.ObjectCount, humanBytes(u.PayloadSize), humanBytes(u.MetadataSize), u.UploadCount})
+		}
+		return printList(usage, t, func() {
+			for _, u := range usage {
+				fmt.Printf("%s\t%s objects\t%s\n", u.Bucket, u.ObjectCount, humanBytes(u.PayloadSize))
+			}
+		})
+	}
+	return errors.New("unknown r2 command. run: cf help")
+}
+
+// listR2Buckets follows the cursor pagination of GET /r2/buckets.
+func listR2Buckets(path string) ([]r2Bucket, error) {
+	var out []r2Bucket
+	cursor := ""
+	for {
+		query := url.Values{"per_page": {"1000"}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		resp, err := requestCF(http.MethodGet, path+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Buckets []r2Bucket `json:"buckets"`
+		}
+		if err := json.Unmarshal(resp.Result, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Buckets...)
+		if resp.ResultInfo == nil || resp.ResultInfo.Cursor == "" || len(page.Buckets) == 0 {
+			return out, nil
+		}
+		cursor = resp.ResultInfo.Cursor
+	}
+}
+
+// humanBytes formats a decimal byte count such as "1536" as "1.5 KiB".
+func humanBytes(s string) string {
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	i := 0
+	for n >= 1024 && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f B", n)
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
@@ -0,0 +1,18 @@
+package main
+
+import "testing"
+
+func TestHumanBytes(t *testing.T) {
+	cases := map[string]string{
+		"0":          "0 B",
+		"1023":       "1023 B",
+		"1536":       "1.5 KiB",
+		"5368709120": "5.0 GiB",
+		"n/a":        "n/a",
+	}
+	for in, want := range cases {
+		if got := humanBytes(in); got != want {
+			t.Fatalf("humanBytes(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
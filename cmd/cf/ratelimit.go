@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var rateLimitActions = []string{"block", "challenge", "js_challenge", "managed_challenge", "log"}
+
+func runRateLimit(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for ratelimit: --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "list":
+		rs, err := getPhaseRuleset("/zones/"+z.ID, phaseRateLimit)
+		if err != nil {
+			return err
+		}
+		return printRateLimitRules(z.Name, rs)
+	case "add":
+		rule, err := rateLimitRule(flags)
+		if err != nil {
+			return err
+		}
+		rs, err := addPhaseRule("/zones/"+z.ID, phaseRateLimit, rule)
+		if err != nil {
+			return err
+		}
+		added := lastRule(rs)
+		return printResult(added, func() {
+			fmt.Printf("Rate limit added: %d requests / %ds -> %s for %ds (id=%s)\n",
+				added.Ratelimit.RequestsPerPeriod, added.Ratelimit.Period, added.Action, added.Ratelimit.MitigationTimeout, added.ID)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf ratelimit delete <rule-id|description> --zone <zone>")
+		}
+		rs, err := getPhaseRuleset("/zones/"+z.ID, phaseRateLimit)
+		if err != nil {
+			return err
+		}
+		rule, err := findRule(rs, positional[0])
+		if err != nil {
+			return err
+		}
+		if err := deletePhaseRule("/zones/"+z.ID, rs, rule.ID); err != nil {
+			return err
+		}
+		return printResult(rule, func() {
+			fmt.Printf("Rate limit deleted: %s\n", rule.ID)
+		})
+	}
+	return usageErrorf("usage: cf ratelimit list|add|delete --zone <zone>")
+}
+
+// rateLimitRule builds a rule from flags. Requests are counted per client IP
+// and data center unless --characteristics says otherwise.
+func rateLimitRule(flags map[string]string) (rulesetRule, error) {
+	requests, err := parseIntWithDefault(flags["requests"], 0)
+	if err != nil || requests <= 0 {
+		return rulesetRule{}, usageErrorf("missing or invalid --requests: number of requests allowed per period")
+	}
+	period, err := parseIntWithDefault(flags["period"], 60)
+	if err != nil {
+		return rulesetRule{}, usageErrorf("invalid --period: %w", err)
+	}
+	timeout, err := parseIntWithDefault(flags["timeout"], period)
+	if err != nil {
+		return rulesetRule{}, usageErrorf("invalid --timeout: %w", err)
+	}
+	action := flags["action"]
+	if action == "" {
+		action = "block"
+	}
+	if !slices.Contains(rateLimitActions, action) {
+		return rulesetRule{}, usageErrorf("invalid --action %q (want one of: %s)", action, strings.Join(rateLimitActions, ", "))
+	}
+	expression := flags["expression"]
+	if expression == "" {
+		expression = "true"
+	}
+	characteristics := splitList(flags["characteristics"])
+	if len(characteristics) == 0 {
+		characteristics = []string{"ip.src", "cf.colo.id"}
+	} else if !slices.Contains(characteristics, "cf.colo.id") {
+		// The API requires cf.colo.id on every rate limiting rule.
+		characteristics = append(characteristics, "cf.colo.id")
+	}
+
+	enabled := true
+	return rulesetRule{
+		Action:      action,
+		Expression:  expression,
+		Description: flags["description"],
+		Enabled:     &enabled,
+		Ratelimit: &rateLimit{
+			Characteristics:   characteristics,
+			Period:            period,
+			RequestsPerPeriod: requests,
+			MitigationTimeout: timeout,
+		},
+	}, nil
+}
+
+func printRateLimitRules(zoneName string, rs *ruleset) error {
+	rules := []rulesetRule{}
+	if rs != nil {
+		rules = rs.Rules
+	}
+	t := table{Headers: []string{"ID", "ENABLED", "ACTION", "REQUESTS", "PERIOD", "TIMEOUT", "EXPRESSION", "DESCRIPTION"}}
+	for _, r := range rules {
+		var requests, period, timeout string
+		if r.Ratelimit != nil {
+			requests = fmt.Sprint(r.Ratelimit.RequestsPerPeriod)
+			period = fmt.Sprint(r.Ratelimit.Period)
+			timeout = fmt.Sprint(r.Ratelimit.MitigationTimeout)
+		}
+		t.Rows = append(t.Rows, []string{r.ID, fmt.Sprint(r.enabled()), r.Action, requests, period, timeout, r.Expression, r.Description})
+	}
+	return printList(rules, t, func() {
+		if len(rules) == 0 {
+			fmt.Printf("No rate limiting rules on %s.\n", zoneName)
+			return
+		}
+		for _, row := range t.Rows {
+			fmt.Printf("%s\t%s req/%ss -> %s for %ss\t%s\n", row[0], row[3], row[4], row[2], row[5], row[6])
+		}
+	})
+}
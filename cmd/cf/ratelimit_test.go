@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const rateLimitEntrypoint = "/zones/z1/rulesets/phases/http_ratelimit/entrypoint"
+
+func TestRateLimitAddAndList(t *testing.T) {
+	srv := useFakeAPI(t)
+	existing := rulesetRule{ID: "r1", Action: "log", Expression: "true", Ratelimit: &rateLimit{Period: 10, RequestsPerPeriod: 100, MitigationTimeout: 10}}
+	srv.Reply("GET", rateLimitEntrypoint, ruleset{ID: "rs1", Rules: []rulesetRule{existing}})
+	srv.Reply("POST", "/zones/z1/rulesets/rs1/rules", ruleset{ID: "rs1", Rules: []rulesetRule{existing, {
+		ID: "r2", Action: "managed_challenge", Expression: `http.request.uri.path eq "/login"`,
+		Ratelimit: &rateLimit{Period: 60, RequestsPerPeriod: 5, MitigationTimeout: 600},
+	}}})
+
+	out, err := captureStdout(t, func() error {
+		return runRateLimit([]string{"add", "--zone", "example.com", "--requests", "5", "--timeout", "600", "--action", "managed_challenge", "--expression", `http.request.uri.path eq "/login"`})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rule rulesetRule
+	if posts := srv.Calls("POST", "/zones/z1/rulesets/rs1/rules"); len(posts) != 1 {
+		t.Fatalf("expected the rule to be appended, got %d POSTs", len(posts))
+	} else if err := posts[0].Decode(&rule); err != nil {
+		t.Fatal(err)
+	}
+	rl := rule.Ratelimit
+	if rule.Action != "managed_challenge" || rl == nil || rl.RequestsPerPeriod != 5 || rl.Period != 60 || rl.MitigationTimeout != 600 || strings.Join(rl.Characteristics, ",") != "ip.src,cf.colo.id" {
+		t.Fatalf("unexpected rule %+v %+v", rule, rl)
+	}
+	if !strings.Contains(out, "Rate limit added: 5 requests / 60s -> managed_challenge for 600s (id=r2)") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	out, err = captureStdout(t, func() error { return runRateLimit([]string{"--zone", "example.com"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "r1\t100 req/10s -> log for 10s\ttrue\n" {
+		t.Fatalf("unexpected list output:\n%s", out)
+	}
+}
+
+func TestRateLimitDelete(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", rateLimitEntrypoint, ruleset{ID: "rs1", Rules: []rulesetRule{{ID: "r1", Action: "block", Expression: "true", Description: "api"}}})
+	srv.Reply("DELETE", "/zones/z1/rulesets/rs1/rules/r1", ruleset{ID: "rs1"})
+
+	out, err := captureStdout(t, func() error { return runRateLimit([]string{"delete", "api", "--zone", "example.com"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(srv.Calls("DELETE", "/zones/z1/rulesets/rs1/rules/r1")); n != 1 || out != "Rate limit deleted: r1\n" {
+		t.Fatalf("expected one DELETE, got %d:\n%s", n, out)
+	}
+}
+
+func TestRateLimitErrors(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Fail("GET", rateLimitEntrypoint, 404, 10003, "Entry point not found")
+	srv.Fail("PUT", rateLimitEntrypoint, 400, 20120, "ratelimit period not allowed on this plan")
+
+	if err := runRateLimit([]string{"add", "--zone", "example.com"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error without --requests, got %v", err)
+	}
+	err := runRateLimit([]string{"add", "--zone", "example.com", "--requests", "5", "--period", "10"})
+	if exitCode(err) != exitAPI || !strings.Contains(err.Error(), "not allowed on this plan") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+	if n := len(srv.Calls("PUT", rateLimitEntrypoint)); n != 1 {
+		t.Fatalf("expected the entry point to be created on first use, got %d PUTs", n)
+	}
+}
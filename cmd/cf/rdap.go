@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// rdapBaseURL redirects to the RDAP server of the domain's registry, so one
+// endpoint works for every TLD that publishes RDAP.
+var rdapBaseURL = "https://rdap.org/domain/"
+
+// rdapDomain is the subset of an RDAP domain response the CLI uses.
+type rdapDomain struct {
+	Name        string       `json:"ldhName"`
+	Status      []string     `json:"status,omitempty"`
+	Events      []rdapEvent  `json:"events,omitempty"`
+	Entities    []rdapEntity `json:"entities,omitempty"`
+	Nameservers []struct {
+		Name string `json:"ldhName"`
+	} `json:"nameservers,omitempty"`
+	SecureDNS struct {
+		DelegationSigned bool `json:"delegationSigned"`
+	} `json:"secureDNS"`
+}
+
+type rdapEvent struct {
+	Action string `json:"eventAction"`
+	Date   string `json:"eventDate"`
+}
+
+type rdapEntity struct {
+	Roles      []string     `json:"roles"`
+	VCardArray []any        `json:"vcardArray,omitempty"`
+	Entities   []rdapEntity `json:"entities,omitempty"`
+}
+
+// lookupRDAP fetches the registry's record for domain. It is a variable so
+// tests can avoid the network.
+var lookupRDAP = func(domain string) (*rdapDomain, error) {
+	ctx, cancel := context.WithTimeout(rootCtx, 15*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rdapBaseURL+url.PathEscape(strings.ToLower(domain)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+
+	client := httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("RDAP lookup for %s failed: %w", domain, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, notFoundErrorf("RDAP has no record of %s (unregistered, or the TLD does not publish RDAP)", domain)
+	case resp.StatusCode >= 300:
+		return nil, fmt.Errorf("RDAP lookup for %s failed: HTTP %d", domain, resp.StatusCode)
+	}
+
+	var d rdapDomain
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, fmt.Errorf("RDAP lookup for %s: %w", domain, err)
+	}
+	return &d, nil
+}
+
+// registrar returns the registrar's name, or "" if the record has none.
+func (d *rdapDomain) registrar() string {
+	for _, e := range d.Entities {
+		for _, role := range e.Roles {
+			if role == "registrar" {
+				return vcardName(e.VCardArray)
+			}
+		}
+	}
+	return ""
+}
+
+func (d *rdapDomain) nameservers() []string {
+	out := make([]string, 0, len(d.Nameservers))
+	for _, ns := range d.Nameservers {
+		out = append(out, ns.Name)
+	}
+	return normalizeNameservers(out)
+}
+
+// event returns the date of the first event with action, such as
+// "registration" or "expiration", or "" if there is none.
+func (d *rdapDomain) event(action string) string {
+	for _, e := range d.Events {
+		if e.Action == action {
+			return e.Date
+		}
+	}
+	return ""
+}
+
+// locked reports whether the registry refuses transfers; registrars set
+// "client transfer prohibited" when the domain is locked.
+func (d *rdapDomain) locked() bool {
+	for _, s := range d.Status {
+		if strings.HasSuffix(strings.ToLower(s), "transfer prohibited") {
+			return true
+		}
+	}
+	return false
+}
+
+// domainWhois is what cf domains whois reports about a domain.
+type domainWhois struct {
+	Domain       string   `json:"domain"`
+	Registrar    string   `json:"registrar"`
+	Registered   string   `json:"registered,omitempty"`
+	Expires      string   `json:"expires,omitempty"`
+	Nameservers  []string `json:"nameservers"`
+	OnCloudflare bool     `json:"on_cloudflare"`
+	Locked       bool     `json:"locked"`
+	DNSSEC       bool     `json:"dnssec"`
+	Statuses     []string `json:"statuses"`
+}
+
+func whoisDomain(domain string) (*domainWhois, error) {
+	d, err := lookupRDAP(domain)
+	if err != nil {
+		return nil, err
+	}
+	w := &domainWhois{
+		Domain:      strings.ToLower(domain),
+		Registrar:   d.registrar(),
+		Registered:  d.event("registration"),
+		Expires:     d.event("expiration"),
+		Nameservers: d.nameservers(),
+		Locked:      d.locked(),
+		DNSSEC:      d.SecureDNS.DelegationSigned,
+		Statuses:    append([]string{}, d.Status...),
+	}
+	w.OnCloudflare = len(w.Nameservers) > 0
+	for _, ns := range w.Nameservers {
+		w.OnCloudflare = w.OnCloudflare && strings.HasSuffix(ns, ".ns.cloudflare.com")
+	}
+	return w, nil
+}
+
+func printDomainWhois(w *domainWhois) {
+	fmt.Println(w.Domain)
+	fmt.Printf("  registrar:   %s\n", orNone(w.Registrar))
+	if w.Registered != "" {
+		fmt.Printf("  registered:  %s\n", w.Registered)
+	}
+	if w.Expires != "" {
+		fmt.Printf("  expires:     %s\n", w.Expires)
+	}
+	fmt.Printf("  nameservers: %s\n", orNone(strings.Join(w.Nameservers, ", ")))
+	fmt.Printf("  cloudflare:  %t\n", w.OnCloudflare)
+	fmt.Printf("  locked:      %t\n", w.Locked)
+	fmt.Printf("  dnssec:      %t\n", w.DNSSEC)
+	if len(w.Statuses) > 0 {
+		fmt.Printf("  statuses:    %s\n", strings.Join(w.Statuses, ", "))
+	}
+}
+
+// vcardName reads the "fn" property from a jCard: ["vcard", [[name, params,
+// type, value], ...]].
+func vcardName(card []any) string {
+	if len(card) < 2 {
+		return ""
+	}
+	props, _ := card[1].([]any)
+	for _, p := range props {
+		fields, _ := p.([]any)
+		if len(fields) >= 4 && fields[0] == "fn" {
+			name, _ := fields[3].(string)
+			return name
+		}
+	}
+	return ""
+}
+
+// registrarGuide says where a registrar keeps its nameserver settings.
+type registrarGuide struct {
+	Name  string
+	URL   string
+	Steps string
+}
+
+// registrarGuides is matched by lowercase substring of the RDAP registrar
+// name.
+var registrarGuides = []struct {
+	match string
+	guide registrarGuide
+}{
+	{"godaddy", registrarGuide{"GoDaddy", "https://dcc.godaddy.com/control/portfolio", "Domain Portfolio > the domain > DNS > Nameservers > Change Nameservers > I'll use my own nameservers"}},
+	{"namecheap", registrarGuide{"Namecheap", "https://ap.www.namecheap.com/domains/list/", "Domain List > Manage > Nameservers > Custom DNS"}},
+	{"squarespace", registrarGuide{"Squarespace Domains", "https://account.squarespace.com/domains", "Domains > the domain > DNS > Domain Nameservers > Use Custom Nameservers"}},
+	{"google", registrarGuide{"Google Domains (now Squarespace)", "https://account.squarespace.com/domains", "Domains > the domain > DNS > Domain Nameservers > Use Custom Nameservers"}},
+	{"porkbun", registrarGuide{"Porkbun", "https://porkbun.com/account/domainsSpeedy", "Domain Management > the domain > Details > Authoritative Nameservers > Edit"}},
+	{"name.com", registrarGuide{"Name.com", "https://www.name.com/account/domain", "My Domains > the domain > Manage Nameservers"}},
+	{"gandi", registrarGuide{"Gandi", "https://admin.gandi.net/domain/", "Domain > the domain > Nameservers > Change > External nameservers"}},
+	{"hover", registrarGuide{"Hover", "https://www.hover.com/control_panel", "the domain > DNS/Nameservers > Edit nameservers"}},
+	{"dynadot", registrarGuide{"Dynadot", "https://www.dynadot.com/account/domain/name/list.html", "My Domains > Manage Domains > the domain > DNS Settings > Name Servers"}},
+	{"ionos", registrarGuide{"IONOS", "https://my.ionos.com/domains", "Domains & SSL > the domain > Nameservers > Use custom nameservers"}},
+	{"1&1", registrarGuide{"IONOS", "https://my.ionos.com/domains", "Domains & SSL > the domain > Nameservers > Use custom nameservers"}},
+	{"ovh", registrarGuide{"OVHcloud", "https://www.ovh.com/manager/", "Web Cloud > Domain names > the domain > DNS servers > Modify DNS servers"}},
+	{"network solutions", registrarGuide{"Network Solutions", "https://www.networksolutions.com/my-account/domain-center", "My Domain Names > the domain > Change Where Domain Points > Advanced DNS"}},
+	{"cloudflare", registrarGuide{"Cloudflare Registrar", "https://dash.cloudflare.com/?to=/:account/domains", "nothing to do: Cloudflare Registrar domains always use Cloudflare's nameservers"}},
+}
+
+func findRegistrarGuide(registrar string) (registrarGuide, bool) {
+	lower := strings.ToLower(registrar)
+	for _, g := range registrarGuides {
+		if strings.Contains(lower, g.match) {
+			return g.guide, true
+		}
+	}
+	return registrarGuide{}, false
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const rdapSample = `{
+  "objectClassName": "domain",
+  "ldhName": "EXAMPLE.COM",
+  "status": ["client transfer prohibited"],
+  "events": [{"eventAction": "expiration", "eventDate": "2027-08-13T04:00:00Z"}],
+  "entities": [{
+    "roles": ["registrar"],
+    "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "NameCheap, Inc."]]]
+  }],
+  "nameservers": [{"ldhName": "DNS1.REGISTRAR-SERVERS.COM"}, {"ldhName": "DNS2.REGISTRAR-SERVERS.COM"}]
+}`
+
+func TestLookupRDAP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domain/example.com" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(rdapSample))
+	}))
+	defer srv.Close()
+	orig := rdapBaseURL
+	rdapBaseURL = srv.URL + "/domain/"
+	t.Cleanup(func() { rdapBaseURL = orig })
+
+	d, err := lookupRDAP("Example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.registrar() != "NameCheap, Inc." {
+		t.Fatalf("unexpected registrar %q", d.registrar())
+	}
+	if ns := d.nameservers(); len(ns) != 2 || ns[0] != "dns1.registrar-servers.com" {
+		t.Fatalf("unexpected nameservers %v", ns)
+	}
+	if g, ok := findRegistrarGuide(d.registrar()); !ok || g.Name != "Namecheap" {
+		t.Fatalf("expected the Namecheap guide, got %+v (ok=%t)", g, ok)
+	}
+
+	if _, err := lookupRDAP("unregistered.example"); exitCode(err) != exitNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestDomainsWhois(t *testing.T) {
+	orig := lookupRDAP
+	t.Cleanup(func() { lookupRDAP = orig })
+	lookupRDAP = func(string) (*rdapDomain, error) {
+		var d rdapDomain
+		err := json.Unmarshal([]byte(rdapSample), &d)
+		d.SecureDNS.DelegationSigned = true
+		return &d, err
+	}
+
+	out, err := captureStdout(t, func() error { return runDomains([]string{"whois", "example.com"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"registrar:   NameCheap, Inc.",
+		"expires:     2027-08-13T04:00:00Z",
+		"nameservers: dns1.registrar-servers.com, dns2.registrar-servers.com",
+		"cloudflare:  false",
+		"locked:      true",
+		"dnssec:      true",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	if err := runDomains([]string{"lookup", "example.com"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
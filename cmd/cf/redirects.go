@@ -0,0 +1,461 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ruleList struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Kind        string `json:"kind"`
+	NumItems    int    `json:"num_items"`
+}
+
+type bulkRedirect struct {
+	SourceURL           string `json:"source_url"`
+	TargetURL           string `json:"target_url"`
+	StatusCode          int    `json:"status_code,omitempty"`
+	PreserveQueryString bool   `json:"preserve_query_string,omitempty"`
+}
+
+type ruleListItem struct {
+	ID       string        `json:"id,omitempty"`
+	Redirect *bulkRedirect `json:"redirect"`
+}
+
+func runRedirects(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf redirects lists|items|import|enable|rules")
+	}
+	if args[0] == "rules" {
+		return runSingleRedirects(args[1:])
+	}
+
+	positional, flags := splitArgs(args[1:])
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	listsPath := "/accounts/" + accountID + "/rules/lists"
+
+	switch args[0] {
+	case "lists":
+		if len(positional) > 0 && positional[0] == "create" {
+			if len(positional) < 2 {
+				return usageErrorf("usage: cf redirects lists create <name> [--description <text>]")
+			}
+			l, err := createRedirectList(listsPath, positional[1], flags["description"])
+			if err != nil {
+				return err
+			}
+			return printResult(l, func() {
+				fmt.Printf("Redirect list created: %s (id=%s)\n", l.Name, l.ID)
+			})
+		}
+		lists, err := listRedirectLists(listsPath)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "ITEMS", "DESCRIPTION"}}
+		for _, l := range lists {
+			t.Rows = append(t.Rows, []string{l.ID, l.Name, strconv.Itoa(l.NumItems), l.Description})
+		}
+		return printList(lists, t, func() {
+			if len(lists) == 0 {
+				fmt.Println("No redirect lists found.")
+				return
+			}
+			for _, l := range lists {
+				fmt.Printf("%s\t%s\t%d items\n", l.ID, l.Name, l.NumItems)
+			}
+		})
+	case "items":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf redirects items <list>")
+		}
+		l, err := findRedirectList(listsPath, positional[0])
+		if err != nil {
+			return err
+		}
+		items, err := listRedirectItems(listsPath + "/" + l.ID + "/items")
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"SOURCE", "TARGET", "STATUS"}}
+		for _, it := range items {
+			t.Rows = append(t.Rows, []string{it.Redirect.SourceURL, it.Redirect.TargetURL, strconv.Itoa(it.Redirect.StatusCode)})
+		}
+		return printList(items, t, func() {
+			for _, it := range items {
+				fmt.Printf("%s -> %s (%d)\n", it.Redirect.SourceURL, it.Redirect.TargetURL, it.Redirect.StatusCode)
+			}
+		})
+	case "import":
+		if len(positional) == 0 || flags["file"] == "" {
+			return usageErrorf("usage: cf redirects import <list> --file <redirects.csv> [--status 301] [--replace]")
+		}
+		status, err := parseIntWithDefault(flags["status"], 301)
+		if err != nil {
+			return usageErrorf("invalid --status: %w", err)
+		}
+		f, err := os.Open(flags["file"])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		redirects, err := parseRedirectsCSV(f, status)
+		if err != nil {
+			return fmt.Errorf("could not parse %s: %w", flags["file"], err)
+		}
+		return importRedirects(listsPath, positional[0], redirects, parseBoolWithDefault(flags["replace"], false))
+	case "enable":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf redirects enable <list>")
+		}
+		l, err := findRedirectList(listsPath, positional[0])
+		if err != nil {
+			return err
+		}
+		return enableBulkRedirects(accountID, l)
+	}
+	return errors.New("unknown redirects command. run: cf help")
+}
+
+func listRedirectLists(listsPath string) ([]ruleList, error) {
+	resp, err := requestCF(http.MethodGet, listsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	var all []ruleList
+	if err := json.Unmarshal(resp.Result, &all); err != nil {
+		return nil, err
+	}
+	lists := []ruleList{}
+	for _, l := range all {
+		if l.Kind == "redirect" {
+			lists = append(lists, l)
+		}
+	}
+	return lists, nil
+}
+
+func findRedirectList(listsPath, ref string) (*ruleList, error) {
+	lists, err := listRedirectLists(listsPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lists {
+		if l.ID == ref || l.Name == ref {
+			return &l, nil
+		}
+	}
+	return nil, notFoundErrorf("redirect list %q not found. run: cf redirects lists", ref)
+}
+
+func createRedirectList(listsPath, name, description string) (*ruleList, error) {
+	resp, err := requestCF(http.MethodPost, listsPath, map[string]string{"name": name, "kind": "redirect", "description": description})
+	if err != nil {
+		return nil, err
+	}
+	var l ruleList
+	if err := json.Unmarshal(resp.Result, &l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// listRedirectItems follows the before/after cursors of the list items API.
+func listRedirectItems(itemsPath string) ([]ruleListItem, error) {
+	var out []ruleListItem
+	cursor := ""
+	for {
+		path := itemsPath
+		if cursor != "" {
+			path += "?cursor=" + url.QueryEscape(cursor)
+		}
+		resp, err := requestCF(http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []ruleListItem
+		if err := json.Unmarshal(resp.Result, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page...)
+		if resp.ResultInfo == nil || resp.ResultInfo.Cursors.After == "" || len(page) == 0 {
+			return out, nil
+		}
+		cursor = resp.ResultInfo.Cursors.After
+	}
+}
+
+// parseRedirectsCSV reads source,target[,status] rows. A header row is
+// skipped when its first cell is "source" or "source_url".
+func parseRedirectsCSV(r io.Reader, defaultStatus int) ([]bulkRedirect, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []bulkRedirect
+	for i, row := range rows {
+		if i == 0 && len(row) > 0 {
+			if h := strings.ToLower(strings.TrimSpace(row[0])); h == "source" || h == "source_url" {
+				continue
+			}
+		}
+		if len(row) < 2 || strings.TrimSpace(row[0]) == "" || strings.TrimSpace(row[1]) == "" {
+			return nil, fmt.Errorf("line %d: expected source,target[,status]", i+1)
+		}
+		status := defaultStatus
+		if len(row) > 2 && strings.TrimSpace(row[2]) != "" {
+			status, err = strconv.Atoi(strings.TrimSpace(row[2]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid status %q", i+1, row[2])
+			}
+		}
+		out = append(out, bulkRedirect{
+			SourceURL:  normalizeRedirectSource(row[0]),
+			TargetURL:  strings.TrimSpace(row[1]),
+			StatusCode: status,
+		})
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no redirects found")
+	}
+	return out, nil
+}
+
+// normalizeRedirectSource drops the scheme so a single entry matches both
+// http and https requests.
+func normalizeRedirectSource(s string) string {
+	s = strings.TrimSpace(s)
+	for _, scheme := range []string{"https://", "http://"} {
+		if len(s) > len(scheme) && strings.EqualFold(s[:len(scheme)], scheme) {
+			return s[len(scheme):]
+		}
+	}
+	return s
+}
+
+// importRedirects creates the list if needed, then appends (or with replace,
+// overwrites) its items and waits for the bulk operation to finish.
+func importRedirects(listsPath, name string, redirects []bulkRedirect, replace bool) error {
+	l, err := findRedirectList(listsPath, name)
+	if err != nil {
+		if l, err = createRedirectList(listsPath, name, ""); err != nil {
+			return err
+		}
+		infof("Created redirect list %s.\n", l.Name)
+	}
+
+	items := make([]ruleListItem, len(redirects))
+	for i := range redirects {
+		items[i] = ruleListItem{Redirect: &redirects[i]}
+	}
+	method := http.MethodPost
+	if replace {
+		method = http.MethodPut
+	}
+	resp, err := requestCF(method, listsPath+"/"+l.ID+"/items", items)
+	if err != nil {
+		return err
+	}
+	var op struct {
+		OperationID string `json:"operation_id"`
+	}
+	if err := json.Unmarshal(resp.Result, &op); err != nil {
+		return err
+	}
+	if err := waitListOperation(listsPath, op.OperationID); err != nil {
+		return err
+	}
+
+	result := map[string]any{"list": l.Name, "id": l.ID, "imported": len(redirects), "replaced": replace}
+	return printResult(result, func() {
+		fmt.Printf("Imported %d redirects into %s.\n", len(redirects), l.Name)
+		fmt.Printf("Redirects take effect once the list is enabled: cf redirects enable %s\n", l.Name)
+	})
+}
+
+func waitListOperation(listsPath, operationID string) error {
+	if operationID == "" {
+		return nil
+	}
+	for i := 0; i < 60; i++ {
+		resp, err := requestCF(http.MethodGet, listsPath+"/bulk_operations/"+operationID, nil)
+		if err != nil {
+			return err
+		}
+		var op struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		}
+		if err := json.Unmarshal(resp.Result, &op); err != nil {
+			return err
+		}
+		switch op.Status {
+		case "completed":
+			return nil
+		case "failed":
+			return fmt.Errorf("list update failed: %s", op.Error)
+		}
+		if err := sleep(time.Second); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("list update %s still pending; check again later", operationID)
+}
+
+// enableBulkRedirects adds the account-level rule that applies a redirect
+// list, unless one already references it.
+func enableBulkRedirects(accountID string, l *ruleList) error {
+	base := "/accounts/" + accountID
+	expression := "http.request.full_uri in $" + l.Name
+
+	rs, err := getPhaseRuleset(base, phaseBulkRedirect)
+	if err != nil {
+		return err
+	}
+	if rs != nil {
+		for _, r := range rs.Rules {
+			if r.Expression == expression {
+				return printResult(r, func() {
+					fmt.Printf("Redirect list %s is already enabled (rule %s).\n", l.Name, r.ID)
+				})
+			}
+		}
+	}
+
+	params, _ := json.Marshal(map[string]any{
+		"from_list": map[string]string{"name": l.Name, "key": "http.request.full_uri"},
+	})
+	enabled := true
+	rs, err = addPhaseRule(base, phaseBulkRedirect, rulesetRule{
+		Action:           "redirect",
+		ActionParameters: params,
+		Expression:       expression,
+		Description:      "cf: " + l.Name,
+		Enabled:          &enabled,
+	})
+	if err != nil {
+		return err
+	}
+	added := lastRule(rs)
+	return printResult(added, func() {
+		fmt.Printf("Redirect list %s enabled (rule %s).\n", l.Name, added.ID)
+	})
+}
+
+func runSingleRedirects(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for redirects rules: --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	base := "/zones/" + z.ID
+
+	switch action {
+	case "list":
+		rs, err := getPhaseRuleset(base, phaseSingleRedirect)
+		if err != nil {
+			return err
+		}
+		return printRules(z.Name, rs)
+	case "add":
+		rule, err := singleRedirectRule(flags)
+		if err != nil {
+			return err
+		}
+		rs, err := addPhaseRule(base, phaseSingleRedirect, rule)
+		if err != nil {
+			return err
+		}
+		added := lastRule(rs)
+		return printResult(added, func() {
+			fmt.Printf("Redirect added: %s -> %s (id=%s)\n", added.Expression, flags["to"], added.ID)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf redirects rules delete <rule-id|description> --zone <zone>")
+		}
+		rs, err := getPhaseRuleset(base, phaseSingleRedirect)
+		if err != nil {
+			return err
+		}
+		rule, err := findRule(rs, positional[0])
+		if err != nil {
+			return err
+		}
+		if err := deletePhaseRule(base, rs, rule.ID); err != nil {
+			return err
+		}
+		return printResult(rule, func() {
+			fmt.Printf("Redirect deleted: %s\n", rule.ID)
+		})
+	}
+	return usageErrorf("usage: cf redirects rules list|add|delete --zone <zone>")
+}
+
+// singleRedirectRule builds a dynamic redirect. --from is a path ("/old") or
+// a full URL; --expression can be used instead for anything more complex.
+func singleRedirectRule(flags map[string]string) (rulesetRule, error) {
+	if flags["to"] == "" || (flags["from"] == "" && flags["expression"] == "") {
+		return rulesetRule{}, usageErrorf("missing required flags for redirects rules add: --to and --from or --expression")
+	}
+	status, err := parseIntWithDefault(flags["status"], 301)
+	if err != nil {
+		return rulesetRule{}, usageErrorf("invalid --status: %w", err)
+	}
+
+	expression := flags["expression"]
+	if expression == "" {
+		from := flags["from"]
+		field := "http.request.uri.path"
+		if strings.Contains(from, "://") {
+			field = "http.request.full_uri"
+		}
+		expression = fmt.Sprintf("%s eq %q", field, from)
+	}
+
+	params, err := json.Marshal(map[string]any{
+		"from_value": map[string]any{
+			"status_code":           status,
+			"target_url":            map[string]string{"value": flags["to"]},
+			"preserve_query_string": parseBoolWithDefault(flags["preserve-query"], false),
+		},
+	})
+	if err != nil {
+		return rulesetRule{}, err
+	}
+	enabled := true
+	return rulesetRule{
+		Action:           "redirect",
+		ActionParameters: params,
+		Expression:       expression,
+		Description:      flags["description"],
+		Enabled:          &enabled,
+	}, nil
+}
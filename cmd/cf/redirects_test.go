@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseRedirectsCSV(t *testing.T) {
+	in := "source,target,status\nhttps://example.com/old,https://example.com/new,\nexample.com/a,https://example.com/b,302\n"
+	got, err := parseRedirectsCSV(strings.NewReader(in), 301)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 redirects, got %d", len(got))
+	}
+	if got[0].SourceURL != "example.com/old" || got[0].StatusCode != 301 {
+		t.Fatalf("unexpected first redirect: %+v", got[0])
+	}
+	if got[1].StatusCode != 302 {
+		t.Fatalf("expected per-row status, got %+v", got[1])
+	}
+
+	if _, err := parseRedirectsCSV(strings.NewReader("only-one-column\n"), 301); err == nil {
+		t.Fatalf("expected error for missing target")
+	}
+}
+
+func TestSingleRedirectRule(t *testing.T) {
+	rule, err := singleRedirectRule(map[string]string{"from": "/docs", "to": "https://docs.example.com/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Expression != `http.request.uri.path eq "/docs"` {
+		t.Fatalf("unexpected expression: %s", rule.Expression)
+	}
+	var params struct {
+		FromValue struct {
+			StatusCode int `json:"status_code"`
+			TargetURL  struct {
+				Value string `json:"value"`
+			} `json:"target_url"`
+		} `json:"from_value"`
+	}
+	if err := json.Unmarshal(rule.ActionParameters, &params); err != nil {
+		t.Fatalf("bad action parameters: %v", err)
+	}
+	if params.FromValue.StatusCode != 301 || params.FromValue.TargetURL.Value != "https://docs.example.com/" {
+		t.Fatalf("unexpected action parameters: %s", rule.ActionParameters)
+	}
+
+	if _, err := singleRedirectRule(map[string]string{"from": "/docs"}); err == nil {
+		t.Fatalf("expected error without --to")
+	}
+}
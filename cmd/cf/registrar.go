@@ -0,0 +1,248 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+func runRegistrar(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf registrar list|get|update|transfer. run: cf registrar --help")
+	}
+	switch args[0] {
+	case "list":
+		limit, err := parseIntWithDefault(parseFlags(args[1:])["limit"], 0)
+		if err != nil {
+			return usageErrorf("invalid --limit: %w", err)
+		}
+		return listRegistrarDomains(limit)
+	case "transfer":
+		return runRegistrarTransfer(args[1:])
+	case "get":
+		positional, _ := splitArgs(args[1:])
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf registrar get <domain>")
+		}
+		return showRegistrarDomain(positional[0])
+	case "update":
+		positional, flags := splitArgs(args[1:])
+		all := parseBoolWithDefault(flags["all"], false)
+		if len(positional) == 0 && !all {
+			return usageErrorf("usage: cf registrar update <domain>... | --all")
+		}
+		if len(positional) > 0 && all {
+			return usageErrorf("pass domains or --all, not both")
+		}
+		concurrency, err := parseIntWithDefault(flags["concurrency"], defaultBulkConcurrency)
+		if err != nil {
+			return usageErrorf("invalid --concurrency: %w", err)
+		}
+		return updateRegistrarDomains(positional, all, flags, concurrency)
+	}
+	return usageErrorf("unknown registrar command %q. run: cf registrar --help", args[0])
+}
+
+func runDomains(args []string) error {
+	if len(args) < 2 {
+		return usageErrorf("usage: cf domains check|whois <domain>")
+	}
+	switch args[0] {
+	case "check":
+		a, err := checkDomainAvailability(args[1])
+		if err != nil {
+			return err
+		}
+		return printResult(a, func() { printDomainAvailability(a) })
+	case "whois":
+		w, err := whoisDomain(args[1])
+		if err != nil {
+			return err
+		}
+		return printResult(w, func() { printDomainWhois(w) })
+	}
+	return usageErrorf("unknown domains command %q. run: cf domains check|whois <domain>", args[0])
+}
+
+func getRegistrarDomain(domain string) (*registrarDomain, error) {
+	var d registrarDomain
+	if err := getRegistrarDomainInto(domain, &d); err != nil {
+		return nil, err
+	}
+	if d.Name == "" {
+		d.Name = domain
+	}
+	return &d, nil
+}
+
+func getRegistrarDomainInto(domain string, out any) error {
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	return c.GetRegistrarDomain(rootCtx, accountID, domain, out)
+}
+
+func showRegistrarDomain(domain string) error {
+	d, err := getRegistrarDomain(domain)
+	if err != nil {
+		return err
+	}
+
+	return printResult(d, func() {
+		fmt.Printf("%s\n", d.Name)
+		fmt.Printf("  auto_renew: %t\n", d.AutoRenew)
+		fmt.Printf("  locked:     %t\n", d.Locked)
+		fmt.Printf("  privacy:    %t\n", d.Privacy)
+		if d.ExpiresAt != "" {
+			fmt.Printf("  expires_at: %s\n", d.ExpiresAt)
+		}
+		if d.CurrentRegistrar != "" {
+			fmt.Printf("  registrar:  %s\n", d.CurrentRegistrar)
+		}
+		if d.RegistryStatuses != "" {
+			fmt.Printf("  statuses:   %s\n", d.RegistryStatuses)
+		}
+	})
+}
+
+// updateRegistrarDomains wraps PUT /accounts/:id/registrar/domains/:domain
+// for each domain, or every Registrar domain with all. Only the fields passed
+// as flags are sent.
+func updateRegistrarDomains(domains []string, all bool, flags map[string]string, concurrency int) error {
+	body := map[string]bool{}
+	for flag, field := range map[string]string{"auto-renew": "auto_renew", "locked": "locked", "privacy": "privacy"} {
+		v, ok := flags[flag]
+		if !ok {
+			continue
+		}
+		b, err := parseOnOff(v)
+		if err != nil {
+			return fmt.Errorf("--%s: %w", flag, err)
+		}
+		body[field] = b
+	}
+	if len(body) == 0 {
+		return errors.New("nothing to update: pass --auto-renew, --locked and/or --privacy")
+	}
+
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	if all {
+		list, err := c.ListRegistrarDomains(rootCtx, accountID, 0)
+		if err != nil {
+			return err
+		}
+		for _, d := range list {
+			domains = append(domains, d.Name)
+		}
+		if len(domains) == 0 {
+			return errors.New("no domains in Cloudflare Registrar for this account")
+		}
+	}
+
+	updated := make([]*registrarDomain, len(domains))
+	errs := runBatch("Updating domains", len(domains), concurrency, func(i int) error {
+		if _, err := c.UpdateRegistrarDomain(rootCtx, accountID, domains[i], body); err != nil {
+			return fmt.Errorf("%s: %w", domains[i], err)
+		}
+		// The update response does not reliably echo the settings, so read
+		// them back.
+		d, err := getRegistrarDomain(domains[i])
+		if err != nil {
+			return fmt.Errorf("%s: %w", domains[i], err)
+		}
+		updated[i] = d
+		return nil
+	})
+	failed := countFailed(errs)
+	if failed == len(domains) {
+		return errors.Join(errs...)
+	}
+
+	changed := make([]string, 0, len(body))
+	for field, v := range body {
+		changed = append(changed, fmt.Sprintf("%s=%t", field, v))
+	}
+	sort.Strings(changed)
+	var out any = updated[0]
+	if len(domains) > 1 {
+		out = slices.DeleteFunc(updated, func(d *registrarDomain) bool { return d == nil })
+	}
+	if err := printResult(out, func() {
+		for _, d := range updated {
+			if d == nil {
+				continue
+			}
+			fmt.Printf("Registrar domain updated: %s (%s)\n", d.Name, strings.Join(changed, ", "))
+			fmt.Printf("%s  auto_renew=%t  locked=%t  privacy=%t\n", d.Name, d.AutoRenew, d.Locked, d.Privacy)
+		}
+	}); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d domains failed: %w", failed, len(domains), errors.Join(errs...))
+	}
+	return nil
+}
+
+type domainAvailability struct {
+	Name         string      `json:"name"`
+	Available    bool        `json:"available"`
+	CanRegister  bool        `json:"can_register"`
+	SupportedTLD bool        `json:"supported_tld"`
+	Fees         *domainFees `json:"fees,omitempty"`
+}
+
+type domainFees struct {
+	RegistrationFee float64 `json:"registration_fee"`
+	RenewalFee      float64 `json:"renewal_fee"`
+	TransferFee     float64 `json:"transfer_fee,omitempty"`
+}
+
+// checkDomainAvailability asks Cloudflare Registrar about a domain the
+// account does not necessarily own. Pricing is only present when the API
+// includes fees in its response.
+func checkDomainAvailability(domain string) (*domainAvailability, error) {
+	var a domainAvailability
+	if err := getRegistrarDomainInto(domain, &a); err != nil {
+		return nil, err
+	}
+	if a.Name == "" {
+		a.Name = domain
+	}
+	return &a, nil
+}
+
+func printDomainAvailability(a *domainAvailability) {
+	switch {
+	case !a.SupportedTLD:
+		fmt.Printf("%s: TLD not supported by Cloudflare Registrar\n", a.Name)
+	case a.Available && a.CanRegister:
+		fmt.Printf("%s: available to register through Cloudflare\n", a.Name)
+	case a.Available:
+		fmt.Printf("%s: available, but cannot be registered through Cloudflare for this account\n", a.Name)
+	default:
+		fmt.Printf("%s: not available (already registered)\n", a.Name)
+	}
+
+	if a.Fees != nil {
+		fmt.Printf("  registration: $%.2f/yr  renewal: $%.2f/yr\n", a.Fees.RegistrationFee, a.Fees.RenewalFee)
+	} else if a.SupportedTLD {
+		fmt.Println("  pricing: not returned by the API; Cloudflare charges registry cost, shown in the dashboard")
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cf/pkg/cfapi"
+)
+
+const defaultMaxRetries = cfapi.DefaultMaxRetries
+
+// maxRetries is set by the global --retries flag; -1 means fall back to
+// CF_MAX_RETRIES or the default.
+var maxRetries = -1
+
+// maxRPS is set by the global --max-rps flag; -1 means fall back to
+// CF_MAX_RPS or Cloudflare's limit.
+var maxRPS float64 = -1
+
+// limiter is shared by every client in a run so concurrent bulk requests
+// are paced together. limiterRPS is the rate it was built for.
+var (
+	limiterMu  sync.Mutex
+	limiter    *cfapi.RateLimiter
+	limiterRPS float64
+)
+
+// sleep is used by commands that poll; tests replace it. It returns early
+// with an error when the run is cancelled.
+var sleep = func(d time.Duration) error { return sleepContext(rootCtx, d) }
+
+func resolveMaxRetries() int {
+	if maxRetries >= 0 {
+		return maxRetries
+	}
+	if v := strings.TrimSpace(os.Getenv("CF_MAX_RETRIES")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return defaultMaxRetries
+}
+
+func setMaxRetries(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return usageErrorf("invalid --retries %q: must be a non-negative integer", v)
+	}
+	maxRetries = n
+	return nil
+}
+
+// resolveMaxRPS returns the request rate to keep under; 0 means unlimited.
+func resolveMaxRPS() float64 {
+	if maxRPS >= 0 {
+		return maxRPS
+	}
+	if v := strings.TrimSpace(os.Getenv("CF_MAX_RPS")); v != "" {
+		if n, err := strconv.ParseFloat(v, 64); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return cfapi.DefaultRateLimit
+}
+
+func setMaxRPS(v string) error {
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || n < 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+		return usageErrorf("invalid --max-rps %q: must be a non-negative number (0 disables pacing)", v)
+	}
+	maxRPS = n
+	return nil
+}
+
+// apiLimiter returns the run's shared limiter, or nil when pacing is off.
+// It allows a second's worth of requests at once.
+func apiLimiter() *cfapi.RateLimiter {
+	rps := resolveMaxRPS()
+	if rps == 0 {
+		return nil
+	}
+	limiterMu.Lock()
+	defer limiterMu.Unlock()
+	if limiter == nil || limiterRPS != rps {
+		limiter, limiterRPS = cfapi.NewRateLimiter(rps, int(math.Ceil(rps))), rps
+	}
+	return limiter
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestMaxRPSFlag(t *testing.T) {
+	t.Cleanup(func() { maxRPS, limiter = -1, nil })
+	t.Setenv("CF_MAX_RPS", "")
+
+	if _, err := parseGlobalFlags([]string{"dns", "sync", "--max-rps", "2.5"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if maxRPS != 2.5 {
+		t.Fatalf("maxRPS = %v, want 2.5", maxRPS)
+	}
+	if a, b := apiLimiter(), apiLimiter(); a == nil || a != b {
+		t.Fatal("expected one limiter shared by every client")
+	}
+
+	maxRPS = 0
+	if apiLimiter() != nil {
+		t.Fatal("expected --max-rps 0 to turn pacing off")
+	}
+	for _, v := range []string{"-1", "fast", "Inf"} {
+		if err := setMaxRPS(v); exitCode(err) != exitUsage {
+			t.Fatalf("expected usage error for %q, got %v", v, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+const (
+	phaseFirewallCustom  = "http_request_firewall_custom"
+	phaseRateLimit       = "http_ratelimit"
+	phaseSingleRedirect  = "http_request_dynamic_redirect"
+	phaseBulkRedirect    = "http_request_redirect"
+	phaseURLRewrite      = "http_request_transform"
+	phaseRequestHeaders  = "http_request_late_transform"
+	phaseResponseHeaders = "http_response_headers_transform"
+)
+
+type ruleset struct {
+	ID    string        `json:"id"`
+	Name  string        `json:"name,omitempty"`
+	Kind  string        `json:"kind,omitempty"`
+	Phase string        `json:"phase,omitempty"`
+	Rules []rulesetRule `json:"rules"`
+}
+
+type rulesetRule struct {
+	ID               string          `json:"id,omitempty"`
+	Action           string          `json:"action"`
+	ActionParameters json.RawMessage `json:"action_parameters,omitempty"`
+	Expression       string          `json:"expression"`
+	Description      string          `json:"description,omitempty"`
+	Enabled          *bool           `json:"enabled,omitempty"`
+	Ratelimit        *rateLimit      `json:"ratelimit,omitempty"`
+	LastUpdated      string          `json:"last_updated,omitempty"`
+}
+
+type rateLimit struct {
+	Characteristics    []string `json:"characteristics"`
+	Period             int      `json:"period"`
+	RequestsPerPeriod  int      `json:"requests_per_period"`
+	MitigationTimeout  int      `json:"mitigation_timeout"`
+	CountingExpression string   `json:"counting_expression,omitempty"`
+}
+
+func (r rulesetRule) enabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// Ruleset helpers take a base path of "/zones/<id>" or "/accounts/<id>".
+func phaseEntrypointPath(base, phase string) string {
+	return base + "/rulesets/phases/" + phase + "/entrypoint"
+}
+
+// getPhaseRuleset returns the entry point ruleset for a phase, or nil when none
+// exists yet.
+func getPhaseRuleset(base, phase string) (*ruleset, error) {
+	resp, err := requestCF(http.MethodGet, phaseEntrypointPath(base, phase), nil)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rs ruleset
+	if err := json.Unmarshal(resp.Result, &rs); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// addPhaseRule appends a rule to the phase entry point, creating the entry
+// point ruleset on first use. It returns the updated ruleset.
+func addPhaseRule(base, phase string, rule rulesetRule) (*ruleset, error) {
+	rs, err := getPhaseRuleset(base, phase)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp apiResponse
+	if rs == nil {
+		resp, err = requestCF(http.MethodPut, phaseEntrypointPath(base, phase), map[string]any{"rules": []rulesetRule{rule}})
+	} else {
+		resp, err = requestCF(http.MethodPost, base+"/rulesets/"+rs.ID+"/rules", rule)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var out ruleset
+	if err := json.Unmarshal(resp.Result, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func updatePhaseRule(base string, rs *ruleset, rule rulesetRule) error {
+	_, err := requestCF(http.MethodPatch, base+"/rulesets/"+rs.ID+"/rules/"+url.PathEscape(rule.ID), rule)
+	return err
+}
+
+func deletePhaseRule(base string, rs *ruleset, ruleID string) error {
+	_, err := requestCF(http.MethodDelete, base+"/rulesets/"+rs.ID+"/rules/"+url.PathEscape(ruleID), nil)
+	return err
+}
+
+// findRule matches a rule by ID or exact description.
+func findRule(rs *ruleset, ref string) (*rulesetRule, error) {
+	if rs != nil {
+		for i, r := range rs.Rules {
+			if r.ID == ref || (r.Description != "" && r.Description == ref) {
+				return &rs.Rules[i], nil
+			}
+		}
+	}
+	return nil, notFoundErrorf("rule %q not found", ref)
+}
+
+// lastRule returns the rule the API appended last, which is the newly added
+// one after addPhaseRule.
+func lastRule(rs *ruleset) rulesetRule {
+	if rs == nil || len(rs.Rules) == 0 {
+		return rulesetRule{}
+	}
+	return rs.Rules[len(rs.Rules)-1]
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestFindRule(t *testing.T) {
+	rs := &ruleset{Rules: []rulesetRule{
+		{ID: "r1", Description: "block bad asn"},
+		{ID: "r2"},
+	}}
+	if r, err := findRule(rs, "r2"); err != nil || r.ID != "r2" {
+		t.Fatalf("expected lookup by id, got %v (err=%v)", r, err)
+	}
+	if r, err := findRule(rs, "block bad asn"); err != nil || r.ID != "r1" {
+		t.Fatalf("expected lookup by description, got %v (err=%v)", r, err)
+	}
+	if _, err := findRule(rs, ""); err == nil {
+		t.Fatalf("empty ref must not match rules without a description")
+	}
+	if _, err := findRule(nil, "r1"); err == nil {
+		t.Fatalf("expected error for missing ruleset")
+	}
+}
+
+func TestRateLimitRule(t *testing.T) {
+	rule, err := rateLimitRule(map[string]string{"requests": "20", "characteristics": "ip.src"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rl := rule.Ratelimit
+	if rule.Action != "block" || rule.Expression != "true" || rl.Period != 60 || rl.MitigationTimeout != 60 || rl.RequestsPerPeriod != 20 {
+		t.Fatalf("unexpected defaults: %+v %+v", rule, rl)
+	}
+	if len(rl.Characteristics) != 2 || rl.Characteristics[1] != "cf.colo.id" {
+		t.Fatalf("expected cf.colo.id to be added: %v", rl.Characteristics)
+	}
+
+	if _, err := rateLimitRule(map[string]string{}); err == nil {
+		t.Fatalf("expected error without --requests")
+	}
+	if _, err := rateLimitRule(map[string]string{"requests": "5", "action": "skip"}); err == nil {
+		t.Fatalf("expected error for unsupported action")
+	}
+}
+
+func TestTransformRules(t *testing.T) {
+	rule, err := headerTransformRule(map[string]string{"set": "Strict-Transport-Security: max-age=31536000; X-Frame-Options: DENY", "remove": "Server"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"headers":{"Server":{"operation":"remove"},"Strict-Transport-Security":{"operation":"set","value":"max-age=31536000"},"X-Frame-Options":{"operation":"set","value":"DENY"}}}`
+	if string(rule.ActionParameters) != want || rule.Expression != "true" || rule.Action != "rewrite" {
+		t.Fatalf("unexpected header rule: %s %s", rule.Expression, rule.ActionParameters)
+	}
+
+	rule, err = urlRewriteRule(map[string]string{"expression": `starts_with(http.request.uri.path, "/api/")`, "path-expression": `regex_replace(http.request.uri.path, "^/api", "")`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(rule.ActionParameters) != `{"uri":{"path":{"expression":"regex_replace(http.request.uri.path, \"^/api\", \"\")"}}}` {
+		t.Fatalf("unexpected rewrite rule: %s", rule.ActionParameters)
+	}
+
+	if _, err := urlRewriteRule(map[string]string{"path": "/a", "path-expression": "x"}); err == nil {
+		t.Fatalf("expected error for conflicting path flags")
+	}
+	if _, err := headerTransformRule(map[string]string{"set": "no-colon"}); err == nil {
+		t.Fatalf("expected error for malformed --set")
+	}
+}
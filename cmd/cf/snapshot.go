@@ -0,0 +1,279 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// snapshotPhases are the zone rulesets a snapshot records.
+var snapshotPhases = []string{phaseFirewallCustom, phaseRateLimit, phaseSingleRedirect, phaseURLRewrite, phaseRequestHeaders, phaseResponseHeaders}
+
+// accountSnapshot is the file cf snapshot save writes. Zone is set when the
+// snapshot covers one zone; otherwise it covers every zone in the account,
+// and zones added since show up as drift.
+type accountSnapshot struct {
+	CreatedAt time.Time   `json:"created_at"`
+	AccountID string      `json:"account_id"`
+	Zone      string      `json:"zone,omitempty"`
+	Zones     []zoneState `json:"zones"`
+}
+
+// zoneState is everything a snapshot records about one zone. Skipped lists
+// the sections the token could not read; they are left out of diffs.
+type zoneState struct {
+	Name      string                     `json:"name"`
+	ID        string                     `json:"id"`
+	Status    string                     `json:"status"`
+	Paused    bool                       `json:"paused"`
+	Settings  map[string]json.RawMessage `json:"settings"`
+	Records   []dnsRecord                `json:"records"`
+	Rulesets  map[string][]rulesetRule   `json:"rulesets"`
+	PageRules []pageRule                 `json:"page_rules"`
+	Skipped   []string                   `json:"skipped,omitempty"`
+}
+
+// snapshotDrift is one difference between a snapshot and live state.
+type snapshotDrift struct {
+	Key    string `json:"key"`
+	Change string `json:"change"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+func runSnapshot(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf snapshot save|diff")
+	}
+	flags := parseFlags(args[1:])
+	switch args[0] {
+	case "save":
+		return saveSnapshot(zoneOrDefault(flags["zone"]), flags["out"])
+	case "diff":
+		if flags["file"] == "" {
+			return usageErrorf("missing required flag for snapshot diff: --file")
+		}
+		return diffSnapshot(flags["file"])
+	}
+	return usageErrorf("unknown snapshot command %q. run: cf snapshot --help", args[0])
+}
+
+func saveSnapshot(zoneName, file string) error {
+	snap, err := takeSnapshot(zoneName)
+	if err != nil {
+		return err
+	}
+	for _, z := range snap.Zones {
+		if len(z.Skipped) > 0 {
+			infof("Warning: %s: could not read %s; they are not in the snapshot\n", z.Name, strings.Join(z.Skipped, ", "))
+		}
+	}
+	if file == "" || file == "-" {
+		return writeJSON(snap)
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	infof("Saved a snapshot of %d zone(s) to %s\n", len(snap.Zones), file)
+	return nil
+}
+
+// diffSnapshot compares the snapshot in file with live state. Drift is an
+// error, so a cron job or CI step fails when something changed.
+func diffSnapshot(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var saved accountSnapshot
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("could not parse %s: %w", file, err)
+	}
+	live, err := takeSnapshot(saved.Zone)
+	if err != nil {
+		return err
+	}
+
+	drift := diffAccountSnapshots(&saved, live)
+	err = printList(drift, table{Headers: []string{"CHANGE", "KEY", "FROM", "TO"}, Rows: driftRows(drift)}, func() {
+		if len(drift) == 0 {
+			fmt.Printf("No drift since the snapshot of %s.\n", saved.CreatedAt.Local().Format(time.DateTime))
+			return
+		}
+		fmt.Printf("%d change(s) since the snapshot of %s:\n", len(drift), saved.CreatedAt.Local().Format(time.DateTime))
+		for _, d := range drift {
+			switch d.Change {
+			case "added":
+				fmt.Printf("  + %s: %s\n", d.Key, d.To)
+			case "removed":
+				fmt.Printf("  - %s: was %s\n", d.Key, d.From)
+			default:
+				fmt.Printf("  ~ %s: %s -> %s\n", d.Key, colorStatus("warn", d.From), colorStatus("ok", d.To))
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if len(drift) > 0 {
+		return driftErrorf("drift detected: %d change(s) since %s", len(drift), file)
+	}
+	return nil
+}
+
+func driftRows(drift []snapshotDrift) [][]string {
+	rows := make([][]string, len(drift))
+	for i, d := range drift {
+		rows[i] = []string{d.Change, d.Key, d.From, d.To}
+	}
+	return rows
+}
+
+// takeSnapshot reads zoneName, or every zone in the account when it is empty.
+func takeSnapshot(zoneName string) (*accountSnapshot, error) {
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return nil, err
+	}
+	var zones []zone
+	if zoneName != "" {
+		z, err := requireZone(zoneName)
+		if err != nil {
+			return nil, err
+		}
+		zones = []zone{*z}
+	} else {
+		c, err := apiClient()
+		if err != nil {
+			return nil, err
+		}
+		if zones, err = c.ListZones(rootCtx, accountID, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	snap := &accountSnapshot{CreatedAt: time.Now().UTC().Truncate(time.Second), AccountID: accountID, Zone: zoneName, Zones: make([]zoneState, len(zones))}
+	errs := runBatch("Reading zones", len(zones), defaultBulkConcurrency, func(i int) error {
+		state, err := readZoneState(zones[i])
+		if err != nil {
+			return fmt.Errorf("%s: %w", zones[i].Name, err)
+		}
+		snap.Zones[i] = *state
+		return nil
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// readZoneState fails when the zone's records cannot be read; settings and
+// rules the token has no access to are marked skipped instead.
+func readZoneState(z zone) (*zoneState, error) {
+	s := &zoneState{Name: z.Name, ID: z.ID, Status: z.Status, Paused: z.Paused, Settings: map[string]json.RawMessage{}, Rulesets: map[string][]rulesetRule{}}
+	var err error
+	if s.Records, err = listDNSRecords(z.ID); err != nil {
+		return nil, err
+	}
+	if settings, err := listZoneSettings(z.ID); err != nil {
+		s.Skipped = append(s.Skipped, "settings")
+	} else {
+		for _, st := range settings {
+			s.Settings[st.ID] = st.Value
+		}
+	}
+	for _, phase := range snapshotPhases {
+		rs, err := getPhaseRuleset("/zones/"+z.ID, phase)
+		switch {
+		case err != nil:
+			s.Skipped = append(s.Skipped, phase)
+		case rs != nil:
+			s.Rulesets[phase] = rs.Rules
+		}
+	}
+	if s.PageRules, err = listPageRules("/zones/" + z.ID + "/pagerules"); err != nil {
+		s.Skipped = append(s.Skipped, "page_rules")
+	}
+	return s, nil
+}
+
+// diffAccountSnapshots lists what changed from saved to live, leaving out
+// sections either side could not read.
+func diffAccountSnapshots(saved, live *accountSnapshot) []snapshotDrift {
+	skipped := map[string][]string{}
+	for _, z := range append(slices.Clone(saved.Zones), live.Zones...) {
+		skipped[z.Name] = append(skipped[z.Name], z.Skipped...)
+	}
+	flatten := func(snap *accountSnapshot) watchSnapshot {
+		out := watchSnapshot{}
+		for _, z := range snap.Zones {
+			for k, v := range flattenZoneState(z, skipped[z.Name]) {
+				out[z.Name+" "+k] = v
+			}
+		}
+		return out
+	}
+
+	drift := []snapshotDrift{}
+	for _, e := range diffSnapshots(flatten(saved), flatten(live), time.Time{}) {
+		d := snapshotDrift{Key: e.Key, From: e.From, To: e.To, Change: "changed"}
+		switch {
+		case e.From == "":
+			d.Change = "added"
+		case e.To == "":
+			d.Change = "removed"
+		}
+		drift = append(drift, d)
+	}
+	return drift
+}
+
+// flattenZoneState keys everything in a zone so two states can be compared
+// with diffSnapshots.
+func flattenZoneState(z zoneState, skipped []string) watchSnapshot {
+	s := watchSnapshot{"status": z.Status, "paused": fmt.Sprint(z.Paused)}
+	for _, r := range z.Records {
+		v := dnsRecordSummary(r)
+		if r.Comment != "" {
+			v += fmt.Sprintf(" comment=%q", r.Comment)
+		}
+		if len(r.Tags) > 0 {
+			v += " tags=" + strings.Join(sortedTags(r.Tags), ",")
+		}
+		s["dns "+dnsRecordKey(r)] = v
+	}
+	if !slices.Contains(skipped, "settings") {
+		for id, v := range z.Settings {
+			s["setting "+id] = settingValueString(v)
+		}
+	}
+	for phase, rules := range z.Rulesets {
+		if slices.Contains(skipped, phase) {
+			continue
+		}
+		for _, r := range rules {
+			v := fmt.Sprintf("%q: %s if %s", r.Description, r.Action, r.Expression)
+			if len(r.ActionParameters) > 0 {
+				v += " " + string(r.ActionParameters)
+			}
+			if !r.enabled() {
+				v += " (disabled)"
+			}
+			s[fmt.Sprintf("rule %s [%s]", phase, r.ID)] = v
+		}
+	}
+	if !slices.Contains(skipped, "page_rules") {
+		for _, r := range z.PageRules {
+			s[fmt.Sprintf("page rule %s [%s]", r.pattern(), r.ID)] = fmt.Sprintf("%s priority=%d status=%s", r.actionSummary(), r.Priority, r.Status)
+		}
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotSaveAndDiff(t *testing.T) {
+	srv := useFakeAPI(t)
+	records := []dnsRecord{
+		{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Proxied: true},
+		{ID: "r2", Type: "TXT", Name: "example.com", Content: "v=spf1 -all", TTL: 300},
+	}
+	settings := []zoneSetting{{ID: "ssl", Value: json.RawMessage(`"strict"`)}}
+	srv.Reply("GET", "/zones/z1/dns_records", &records)
+	srv.Reply("GET", "/zones/z1/settings", &settings)
+	srv.Reply("GET", "/zones/z1/pagerules", []pageRule{})
+	srv.Fail("GET", "/zones/z1/rulesets/phases/http_request_firewall_custom/entrypoint", 403, 10000, "Authentication error")
+
+	file := filepath.Join(t.TempDir(), "snapshot.json")
+	if _, err := captureStdout(t, func() error {
+		return runSnapshot([]string{"save", "--zone", "example.com", "--out", file})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error { return runSnapshot([]string{"diff", "--file", file}) })
+	if err != nil || !strings.Contains(out, "No drift") {
+		t.Fatalf("expected no drift, got err=%v:\n%s", err, out)
+	}
+
+	records = []dnsRecord{
+		{ID: "r1", Type: "A", Name: "www.example.com", Content: "198.51.100.9", TTL: 1, Proxied: true},
+		{ID: "r3", Type: "A", Name: "rogue.example.com", Content: "203.0.113.7", TTL: 1},
+	}
+	settings[0].Value = json.RawMessage(`"flexible"`)
+	out, err = captureStdout(t, func() error { return runSnapshot([]string{"diff", "--file", file}) })
+	if err == nil || !strings.Contains(err.Error(), "drift detected: 4 change(s)") {
+		t.Fatalf("expected drift to be an error, got %v", err)
+	}
+	if code := exitCode(err); code != exitDrift {
+		t.Fatalf("expected drift to exit %d, got %d", exitDrift, code)
+	}
+	for _, want := range []string{
+		"~ example.com dns A www.example.com [r1]: 192.0.2.1 (proxied) ttl auto -> 198.51.100.9 (proxied) ttl auto",
+		"+ example.com dns A rogue.example.com [r3]: 203.0.113.7 ttl auto",
+		"- example.com dns TXT example.com [r2]: was v=spf1 -all ttl 300",
+		"~ example.com setting ssl: strict -> flexible",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type spectrumApp struct {
+	ID       string `json:"id,omitempty"`
+	Protocol string `json:"protocol"`
+	DNS      struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	} `json:"dns"`
+	OriginDirect []string `json:"origin_direct,omitempty"`
+	OriginDNS    *struct {
+		Name string `json:"name"`
+	} `json:"origin_dns,omitempty"`
+	OriginPort    any    `json:"origin_port,omitempty"`
+	ProxyProtocol string `json:"proxy_protocol,omitempty"`
+	TLS           string `json:"tls,omitempty"`
+	TrafficType   string `json:"traffic_type,omitempty"`
+	CreatedOn     string `json:"created_on,omitempty"`
+}
+
+// origin renders where the app forwards to.
+func (a spectrumApp) origin() string {
+	if len(a.OriginDirect) > 0 {
+		return strings.Join(a.OriginDirect, ",")
+	}
+	if a.OriginDNS != nil {
+		return fmt.Sprintf("%s:%v", a.OriginDNS.Name, a.OriginPort)
+	}
+	return ""
+}
+
+func runSpectrum(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for spectrum: --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	path := "/zones/" + z.ID + "/spectrum/apps"
+
+	switch action {
+	case "list":
+		apps, err := listAll[spectrumApp](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "DNS", "PROTOCOL", "ORIGIN", "PROXY_PROTOCOL", "TLS"}}
+		for _, a := range apps {
+			t.Rows = append(t.Rows, []string{a.ID, a.DNS.Name, a.Protocol, a.origin(), a.ProxyProtocol, a.TLS})
+		}
+		return printList(apps, t, func() {
+			if len(apps) == 0 {
+				fmt.Printf("No Spectrum applications on %s.\n", z.Name)
+				return
+			}
+			for _, a := range apps {
+				fmt.Printf("%s\t%s %s -> %s\n", a.ID, a.Protocol, a.DNS.Name, a.origin())
+			}
+		})
+	case "create":
+		if flags["dns"] == "" || flags["port"] == "" || flags["origin"] == "" {
+			return usageErrorf("usage: cf spectrum create --zone <zone> --dns <hostname> --port <port|start-end> --origin <ip[:port]|host[:port]> [--protocol tcp|udp] [--proxy-protocol off|v1|v2|simple] [--tls off|flexible|full|strict]")
+		}
+		app, err := spectrumAppFromFlags(z.Name, flags)
+		if err != nil {
+			return err
+		}
+		resp, err := requestCF(http.MethodPost, path, app)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, &app); err != nil {
+			return err
+		}
+		return printResult(app, func() {
+			fmt.Printf("Spectrum application created: %s %s -> %s (id=%s)\n", app.Protocol, app.DNS.Name, app.origin(), app.ID)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf spectrum delete <id|hostname> --zone <zone> [--force]")
+		}
+		if err := requireConfirmable(flags, "spectrum delete"); err != nil {
+			return err
+		}
+		apps, err := listAll[spectrumApp](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		var app *spectrumApp
+		for i, a := range apps {
+			if a.ID == positional[0] || strings.EqualFold(a.DNS.Name, positional[0]) {
+				app = &apps[i]
+				break
+			}
+		}
+		if app == nil {
+			return notFoundErrorf("Spectrum application %q not found. run: cf spectrum list --zone %s", positional[0], z.Name)
+		}
+		ok, err := confirm(flags, "spectrum delete", fmt.Sprintf("Delete Spectrum application %s (%s)?", app.DNS.Name, app.Protocol))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+app.ID, nil); err != nil {
+			return err
+		}
+		return printResult(app, func() {
+			fmt.Printf("Spectrum application deleted: %s\n", app.DNS.Name)
+		})
+	}
+	return usageErrorf("usage: cf spectrum list|create|delete --zone <zone>")
+}
+
+func spectrumAppFromFlags(zoneName string, flags map[string]string) (spectrumApp, error) {
+	var app spectrumApp
+	proto := strings.ToLower(flags["protocol"])
+	if proto == "" {
+		proto = "tcp"
+	}
+	if proto != "tcp" && proto != "udp" {
+		return app, usageErrorf("invalid --protocol %q (want tcp or udp)", proto)
+	}
+	port := flags["port"]
+	if err := validatePortRange(port); err != nil {
+		return app, usageErrorf("invalid --port: %w", err)
+	}
+	app.Protocol = proto + "/" + port
+	app.DNS.Type = "CNAME"
+	app.DNS.Name = qualifyRecordName(flags["dns"], zoneName)
+	app.ProxyProtocol = flags["proxy-protocol"]
+	app.TLS = flags["tls"]
+	if app.TLS != "" && proto == "udp" {
+		return app, errors.New("--tls only applies to tcp applications")
+	}
+
+	host, originPort := flags["origin"], port
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		host, originPort = h, p
+	} else if strings.Count(host, ":") == 1 {
+		// SplitHostPort rejects port ranges such as host:1000-2000.
+		host, originPort, _ = strings.Cut(host, ":")
+	}
+	if err := validatePortRange(originPort); err != nil {
+		return app, fmt.Errorf("invalid origin port: %w", err)
+	}
+	if strings.Contains(originPort, "-") != strings.Contains(port, "-") {
+		return app, errors.New("edge and origin must both be single ports or both be ranges")
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		app.OriginDirect = []string{proto + "://" + net.JoinHostPort(ip.String(), originPort)}
+		return app, nil
+	}
+	app.OriginDNS = &struct {
+		Name string `json:"name"`
+	}{Name: host}
+	if n, err := strconv.Atoi(originPort); err == nil {
+		app.OriginPort = n
+	} else {
+		app.OriginPort = originPort
+	}
+	return app, nil
+}
+
+func validatePortRange(v string) error {
+	lo, hi, isRange := strings.Cut(v, "-")
+	a, err := strconv.Atoi(lo)
+	if err != nil || a < 1 || a > 65535 {
+		return fmt.Errorf("%q is not a port or port range", v)
+	}
+	if !isRange {
+		return nil
+	}
+	b, err := strconv.Atoi(hi)
+	if err != nil || b < a || b > 65535 {
+		return fmt.Errorf("%q is not a valid port range", v)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"cf/internal/cftest"
+)
+
+const spectrumPath = "/zones/z1/spectrum/apps"
+
+func TestSpectrumCreate(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Handle("POST", spectrumPath, func(r cftest.Request) cftest.Response {
+		var app spectrumApp
+		r.Decode(&app)
+		app.ID = "app1"
+		return cftest.Response{Result: app}
+	})
+
+	cases := []struct {
+		args []string
+		body string
+		out  string
+	}{
+		{
+			[]string{"--dns", "ssh", "--port", "22", "--origin", "192.0.2.10:2222"},
+			`{"protocol":"tcp/22","dns":{"type":"CNAME","name":"ssh.example.com"},"origin_direct":["tcp://192.0.2.10:2222"]}`,
+			"Spectrum application created: tcp/22 ssh.example.com -> tcp://192.0.2.10:2222 (id=app1)\n",
+		},
+		{
+			[]string{"--dns", "game.example.com", "--protocol", "udp", "--port", "27015-27020", "--origin", "origin.example.net"},
+			`{"protocol":"udp/27015-27020","dns":{"type":"CNAME","name":"game.example.com"},"origin_dns":{"name":"origin.example.net"},"origin_port":"27015-27020"}`,
+			"Spectrum application created: udp/27015-27020 game.example.com -> origin.example.net:27015-27020 (id=app1)\n",
+		},
+	}
+	for i, tc := range cases {
+		out, err := captureStdout(t, func() error {
+			return runSpectrum(append([]string{"create", "--zone", "example.com"}, tc.args...))
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		posts := srv.Calls("POST", spectrumPath)
+		if len(posts) != i+1 {
+			t.Fatalf("expected %d POSTs, got %d", i+1, len(posts))
+		}
+		if got := strings.TrimSpace(string(posts[i].Body)); got != tc.body {
+			t.Fatalf("unexpected app:\n got %s\nwant %s", got, tc.body)
+		}
+		if out != tc.out {
+			t.Fatalf("unexpected output:\n%s", out)
+		}
+	}
+}
+
+func TestSpectrumCreateRejectsBadFlags(t *testing.T) {
+	srv := useFakeAPI(t)
+	bad := [][]string{
+		{"--dns", "a", "--port", "70000", "--origin", "192.0.2.1"},
+		{"--dns", "a", "--port", "22", "--origin", "192.0.2.1:1000-2000"},
+		{"--dns", "a", "--port", "22", "--protocol", "udp", "--tls", "full", "--origin", "192.0.2.1"},
+		{"--dns", "a", "--port", "22", "--protocol", "sctp", "--origin", "192.0.2.1"},
+		{"--dns", "a", "--port", "22"},
+	}
+	for _, args := range bad {
+		if err := runSpectrum(append([]string{"create", "--zone", "example.com"}, args...)); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+	if n := len(srv.Calls("POST", spectrumPath)); n != 0 {
+		t.Fatalf("expected no request for invalid flags, got %d POSTs", n)
+	}
+}
+
+func TestSpectrumDelete(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", spectrumPath, []map[string]any{{"id": "app1", "protocol": "tcp/22", "dns": map[string]string{"name": "ssh.example.com"}}})
+	srv.Fail("DELETE", spectrumPath+"/app1", 400, 11000, "Application is locked")
+
+	err := runSpectrum([]string{"delete", "SSH.example.com", "--zone", "example.com", "--force"})
+	if exitCode(err) != exitAPI || !strings.Contains(err.Error(), "Application is locked") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+	if n := len(srv.Calls("DELETE", spectrumPath+"/app1")); n != 1 {
+		t.Fatalf("expected one DELETE, got %d", n)
+	}
+
+	srv.Reply("DELETE", spectrumPath+"/app1", map[string]string{"id": "app1"})
+	out, err := captureStdout(t, func() error {
+		return runSpectrum([]string{"delete", "app1", "--zone", "example.com", "--force"})
+	})
+	if err != nil || out != "Spectrum application deleted: ssh.example.com\n" {
+		t.Fatalf("unexpected result err=%v:\n%s", err, out)
+	}
+	if err := runSpectrum([]string{"delete", "rdp.example.com", "--zone", "example.com", "--force"}); exitCode(err) != exitNotFound {
+		t.Fatalf("expected not found for an unknown app, got %v", err)
+	}
+}
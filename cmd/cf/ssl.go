@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var (
+	sslModes               = []string{"off", "flexible", "full", "strict"}
+	certificateAuthorities = []string{"lets_encrypt", "google", "ssl_com"}
+	certValidationMethods  = []string{"txt", "http", "email"}
+	advancedCertValidities = []int{14, 30, 90, 365}
+)
+
+type certificatePack struct {
+	ID                   string   `json:"id"`
+	Type                 string   `json:"type"`
+	Hosts                []string `json:"hosts"`
+	Status               string   `json:"status"`
+	CertificateAuthority string   `json:"certificate_authority,omitempty"`
+	ValidityDays         int      `json:"validity_days,omitempty"`
+	ValidationMethod     string   `json:"validation_method,omitempty"`
+	Certificates         []struct {
+		ID        string `json:"id"`
+		Status    string `json:"status"`
+		ExpiresOn string `json:"expires_on"`
+		Issuer    string `json:"issuer"`
+	} `json:"certificates,omitempty"`
+}
+
+type sslVerification struct {
+	CertificateStatus  string `json:"certificate_status"`
+	Hostname           string `json:"hostname,omitempty"`
+	VerificationType   string `json:"verification_type,omitempty"`
+	VerificationStatus bool   `json:"verification_status"`
+	BrandCheck         bool   `json:"brand_check"`
+	CertPackUUID       string `json:"cert_pack_uuid,omitempty"`
+	SignatureAlgorithm string `json:"signature,omitempty"`
+	ValidationMethod   string `json:"validation_method,omitempty"`
+	VerificationInfo   any    `json:"verification_info,omitempty"`
+}
+
+type sslStatus struct {
+	Zone              string            `json:"zone"`
+	Mode              string            `json:"mode"`
+	UniversalSSL      bool              `json:"universal_ssl"`
+	Verification      []sslVerification `json:"verification"`
+	CertificatePacks  []certificatePack `json:"certificate_packs"`
+	VerificationError string            `json:"verification_error,omitempty"`
+}
+
+func runSSL(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf ssl status|mode|order|total-tls --zone <zone>")
+	}
+	sub := args[0]
+	positional, flags := splitArgs(args[1:])
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for ssl %s: --zone", sub)
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "status":
+		return showSSLStatus(z)
+	case "mode":
+		action := "get"
+		if len(positional) > 0 {
+			action = positional[0]
+		}
+		path := "/zones/" + z.ID + "/settings/ssl"
+		var resp apiResponse
+		switch action {
+		case "get":
+			resp, err = requestCF(http.MethodGet, path, nil)
+		case "set":
+			if len(positional) < 2 || !slices.Contains(sslModes, positional[1]) {
+				return usageErrorf("usage: cf ssl mode set <%s> --zone <zone>", strings.Join(sslModes, "|"))
+			}
+			resp, err = setSSLMode(z, positional[1])
+		default:
+			return usageErrorf("usage: cf ssl mode get|set --zone <zone>")
+		}
+		if err != nil {
+			return err
+		}
+		var setting zoneSetting
+		if err := json.Unmarshal(resp.Result, &setting); err != nil {
+			return err
+		}
+		return printResult(setting, func() {
+			fmt.Printf("SSL/TLS encryption mode for %s: %s\n", z.Name, settingValueString(setting.Value))
+		})
+	case "order":
+		return orderCertificatePack(z, flags)
+	case "total-tls":
+		action := "status"
+		if len(positional) > 0 {
+			action = positional[0]
+		}
+		return runTotalTLS(z, action, flags)
+	}
+	return usageErrorf("usage: cf ssl status|mode|order|total-tls --zone <zone>")
+}
+
+func setSSLMode(z *zone, mode string) (apiResponse, error) {
+	if !slices.Contains(sslModes, mode) {
+		return apiResponse{}, usageErrorf("invalid SSL/TLS mode %q (want one of: %s)", mode, strings.Join(sslModes, ", "))
+	}
+	return requestCF(http.MethodPatch, "/zones/"+z.ID+"/settings/ssl", map[string]string{"value": mode})
+}
+
+// advancedCertOrder builds the body for ordering an advanced certificate
+// pack. Hostnames default to the zone apex and wildcard.
+func advancedCertOrder(zoneName string, flags map[string]string) (map[string]any, error) {
+	hosts := splitList(flags["hostnames"])
+	if len(hosts) == 0 {
+		hosts = []string{zoneName, "*." + zoneName}
+	}
+	ca := flags["ca"]
+	if ca == "" {
+		ca = "lets_encrypt"
+	}
+	if !slices.Contains(certificateAuthorities, ca) {
+		return nil, usageErrorf("invalid --ca %q (want one of: %s)", ca, strings.Join(certificateAuthorities, ", "))
+	}
+	method := flags["validation"]
+	if method == "" {
+		method = "txt"
+	}
+	if !slices.Contains(certValidationMethods, method) {
+		return nil, usageErrorf("invalid --validation %q (want one of: %s)", method, strings.Join(certValidationMethods, ", "))
+	}
+	validity, err := parseIntWithDefault(flags["validity"], 90)
+	if err != nil || !slices.Contains(advancedCertValidities, validity) {
+		return nil, usageErrorf("invalid --validity: want one of %v days", advancedCertValidities)
+	}
+	if ca == "lets_encrypt" && validity == 365 {
+		return nil, errors.New("lets_encrypt certificates are valid for at most 90 days")
+	}
+	return map[string]any{
+		"type":                  "advanced",
+		"hosts":                 hosts,
+		"certificate_authority": ca,
+		"validation_method":     method,
+		"validity_days":         validity,
+		"cloudflare_branding":   false,
+	}, nil
+}
+
+func orderCertificatePack(z *zone, flags map[string]string) error {
+	body, err := advancedCertOrder(z.Name, flags)
+	if err != nil {
+		return err
+	}
+	resp, err := requestCF(http.MethodPost, "/zones/"+z.ID+"/ssl/certificate_packs/order", body)
+	if err != nil {
+		return err
+	}
+	var p certificatePack
+	if err := json.Unmarshal(resp.Result, &p); err != nil {
+		return err
+	}
+	return printResult(p, func() {
+		fmt.Printf("Certificate pack ordered: %s (%s) for %s\n", p.ID, p.Status, strings.Join(p.Hosts, ", "))
+		fmt.Printf("Track validation with: cf ssl status --zone %s\n", z.Name)
+	})
+}
+
+type totalTLS struct {
+	Enabled              bool   `json:"enabled"`
+	CertificateAuthority string `json:"certificate_authority,omitempty"`
+	ValidityPeriod       int    `json:"validity_period,omitempty"`
+}
+
+// runTotalTLS reports or toggles Total TLS, which issues a certificate for
+// every proxied hostname (requires Advanced Certificate Manager).
+func runTotalTLS(z *zone, action string, flags map[string]string) error {
+	path := "/zones/" + z.ID + "/acm/total_tls"
+	var resp apiResponse
+	var err error
+	switch action {
+	case "status":
+		resp, err = requestCF(http.MethodGet, path, nil)
+	case "enable", "disable":
+		body := map[string]any{"enabled": action == "enable"}
+		if ca := flags["ca"]; ca != "" {
+			if !slices.Contains(certificateAuthorities, ca) {
+				return usageErrorf("invalid --ca %q (want one of: %s)", ca, strings.Join(certificateAuthorities, ", "))
+			}
+			body["certificate_authority"] = ca
+		}
+		resp, err = requestCF(http.MethodPost, path, body)
+	default:
+		return usageErrorf("usage: cf ssl total-tls enable|disable|status --zone <zone> [--ca <ca>]")
+	}
+	if err != nil {
+		return err
+	}
+	var t totalTLS
+	if err := json.Unmarshal(resp.Result, &t); err != nil {
+		return err
+	}
+	return printResult(t, func() {
+		state := "disabled"
+		if t.Enabled {
+			state = "enabled"
+		}
+		fmt.Printf("Total TLS for %s: %s", z.Name, state)
+		if t.CertificateAuthority != "" {
+			fmt.Printf(" (CA: %s)", t.CertificateAuthority)
+		}
+		fmt.Println()
+	})
+}
+
+func showSSLStatus(z *zone) error {
+	st := sslStatus{Zone: z.Name}
+	base := "/zones/" + z.ID
+
+	resp, err := requestCF(http.MethodGet, base+"/settings/ssl", nil)
+	if err != nil {
+		return err
+	}
+	var mode zoneSetting
+	if err := json.Unmarshal(resp.Result, &mode); err != nil {
+		return err
+	}
+	st.Mode = settingValueString(mode.Value)
+
+	resp, err = requestCF(http.MethodGet, base+"/ssl/universal/settings", nil)
+	if err != nil {
+		return err
+	}
+	var universal struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.Unmarshal(resp.Result, &universal); err != nil {
+		return err
+	}
+	st.UniversalSSL = universal.Enabled
+
+	packs, err := listAll[certificatePack](base+"/ssl/certificate_packs?status=all", 50, 0)
+	if err != nil {
+		return err
+	}
+	st.CertificatePacks = packs
+
+	// Verification is unavailable for zones that are not active yet; report
+	// that instead of failing the whole command.
+	resp, err = requestCF(http.MethodGet, base+"/ssl/verification", nil)
+	if err != nil {
+		st.VerificationError = err.Error()
+	} else if err := json.Unmarshal(resp.Result, &st.Verification); err != nil {
+		return err
+	}
+
+	return printResult(st, func() { printSSLStatus(&st) })
+}
+
+func printSSLStatus(st *sslStatus) {
+	fmt.Printf("SSL for %s\n", st.Zone)
+	fmt.Printf("  mode:          %s\n", st.Mode)
+	fmt.Printf("  universal SSL: %t\n", st.UniversalSSL)
+
+	if len(st.CertificatePacks) == 0 {
+		fmt.Println("  no certificate packs yet (universal SSL is issued after the zone is active)")
+	}
+	for _, p := range st.CertificatePacks {
+		fmt.Printf("  pack %s (%s): %s\n", p.ID, p.Type, p.Status)
+		fmt.Printf("    hosts: %s\n", strings.Join(p.Hosts, ", "))
+		if p.CertificateAuthority != "" {
+			fmt.Printf("    CA:    %s\n", p.CertificateAuthority)
+		}
+		for _, c := range p.Certificates {
+			fmt.Printf("    cert %s: %s, expires %s\n", c.ID, c.Status, c.ExpiresOn)
+		}
+	}
+
+	if st.VerificationError != "" {
+		fmt.Printf("  verification: unavailable (%s)\n", st.VerificationError)
+		return
+	}
+	for _, v := range st.Verification {
+		state := "verified"
+		if !v.VerificationStatus {
+			state = "pending"
+		}
+		fmt.Printf("  verification %s: certificate %s, %s", v.Hostname, v.CertificateStatus, state)
+		if v.ValidationMethod != "" {
+			fmt.Printf(" (%s)", v.ValidationMethod)
+		}
+		fmt.Println()
+		if !v.VerificationStatus && v.VerificationInfo != nil {
+			info, _ := json.Marshal(v.VerificationInfo)
+			fmt.Printf("    action needed: %s\n", info)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAdvancedCertOrder(t *testing.T) {
+	body, err := advancedCertOrder("example.com", map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(body["hosts"], []string{"example.com", "*.example.com"}) || body["certificate_authority"] != "lets_encrypt" || body["validity_days"] != 90 {
+		t.Fatalf("unexpected defaults: %v", body)
+	}
+
+	body, err = advancedCertOrder("example.com", map[string]string{"hostnames": "a.example.com, *.a.example.com", "ca": "google", "validity": "365"})
+	if err != nil || !reflect.DeepEqual(body["hosts"], []string{"a.example.com", "*.a.example.com"}) {
+		t.Fatalf("unexpected body %v (err=%v)", body, err)
+	}
+
+	bad := []map[string]string{
+		{"ca": "digicert"},
+		{"validation": "cname"},
+		{"validity": "60"},
+		{"validity": "365"},
+	}
+	for _, flags := range bad {
+		if _, err := advancedCertOrder("example.com", flags); err == nil {
+			t.Fatalf("expected error for %v", flags)
+		}
+	}
+}
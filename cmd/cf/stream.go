@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// streamBasicUploadLimit is the largest file the plain multipart upload
+// accepts; bigger files need tus or a direct upload URL.
+const streamBasicUploadLimit = 200 << 20
+
+type streamVideo struct {
+	UID  string `json:"uid"`
+	Meta struct {
+		Name string `json:"name,omitempty"`
+	} `json:"meta"`
+	Status struct {
+		State string `json:"state"`
+	} `json:"status"`
+	Duration  float64 `json:"duration"`
+	Size      int64   `json:"size"`
+	Created   string  `json:"created,omitempty"`
+	Thumbnail string  `json:"thumbnail,omitempty"`
+	Playback  struct {
+		HLS  string `json:"hls,omitempty"`
+		Dash string `json:"dash,omitempty"`
+	} `json:"playback"`
+}
+
+func runStream(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	path := "/accounts/" + accountID + "/stream"
+
+	switch action {
+	case "list":
+		resp, err := requestCF(http.MethodGet, path, nil)
+		if err != nil {
+			return err
+		}
+		var videos []streamVideo
+		if err := json.Unmarshal(resp.Result, &videos); err != nil {
+			return err
+		}
+		t := table{Headers: []string{"UID", "NAME", "STATE", "DURATION", "SIZE", "CREATED"}}
+		for _, v := range videos {
+			t.Rows = append(t.Rows, []string{v.UID, v.Meta.Name, v.Status.State, strconv.FormatFloat(v.Duration, 'f', 1, 64), strconv.FormatInt(v.Size, 10), v.Created})
+		}
+		return printList(videos, t, func() {
+			if len(videos) == 0 {
+				fmt.Println("No Stream videos.")
+				return
+			}
+			for _, v := range videos {
+				fmt.Printf("%s\t%s\t%s\t%.0fs\n", v.UID, v.Meta.Name, v.Status.State, v.Duration)
+			}
+		})
+	case "upload":
+		var resp apiResponse
+		switch {
+		case parseBoolWithDefault(flags["direct"], false):
+			maxDuration, err := parseIntWithDefault(flags["max-duration"], 3600)
+			if err != nil {
+				return usageErrorf("invalid --max-duration: %w", err)
+			}
+			resp, err = requestCF(http.MethodPost, path+"/direct_upload", map[string]any{"maxDurationSeconds": maxDuration})
+			if err != nil {
+				return err
+			}
+			var u directUpload
+			if err := json.Unmarshal(resp.Result, &u); err != nil {
+				return err
+			}
+			return printResult(u, func() {
+				fmt.Printf("One-time upload URL for video %s (POST a multipart \"file\" field to it):\n", u.UID)
+				fmt.Printf("  %s\n", u.UploadURL)
+			})
+		case flags["url"] != "":
+			name := flags["name"]
+			if name == "" {
+				name = filepath.Base(flags["url"])
+			}
+			resp, err = requestCF(http.MethodPost, path+"/copy", map[string]any{"url": flags["url"], "meta": map[string]string{"name": name}})
+		case len(positional) > 0:
+			info, serr := os.Stat(positional[0])
+			if serr != nil {
+				return serr
+			}
+			if info.Size() > streamBasicUploadLimit {
+				return fmt.Errorf("%s is %s; files over 200 MiB need --direct (or a tus client)", positional[0], humanBytes(strconv.FormatInt(info.Size(), 10)))
+			}
+			data, rerr := os.ReadFile(positional[0])
+			if rerr != nil {
+				return rerr
+			}
+			body, contentType, ferr := multipartForm(nil, "file", positional[0], data)
+			if ferr != nil {
+				return ferr
+			}
+			resp, err = requestCFRaw(http.MethodPost, path, contentType, body)
+		default:
+			return usageErrorf("usage: cf stream upload (<file> | --url <url> | --direct) [--name <name>]")
+		}
+		if err != nil {
+			return err
+		}
+		var v streamVideo
+		if err := json.Unmarshal(resp.Result, &v); err != nil {
+			return err
+		}
+		return printResult(v, func() {
+			fmt.Printf("Video uploaded: %s (uid=%s, state=%s)\n", v.Meta.Name, v.UID, v.Status.State)
+			if v.Playback.HLS != "" {
+				fmt.Printf("  HLS: %s\n", v.Playback.HLS)
+			}
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf stream delete <uid> [--force]")
+		}
+		ok, err := confirm(flags, "stream delete", fmt.Sprintf("Delete video %s?", positional[0]))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+positional[0], nil); err != nil {
+			return err
+		}
+		return printResult(map[string]string{"uid": positional[0]}, func() {
+			fmt.Printf("Video deleted: %s\n", positional[0])
+		})
+	}
+	return usageErrorf("usage: cf stream list|upload|delete")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStreamUpload(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("POST", "/accounts/acc1/stream/copy", map[string]any{
+		"uid": "v1", "meta": map[string]string{"name": "intro.mp4"}, "status": map[string]string{"state": "queued"},
+	})
+	srv.Reply("POST", "/accounts/acc1/stream", map[string]any{
+		"uid": "v2", "meta": map[string]string{"name": "clip.mp4"}, "status": map[string]string{"state": "ready"},
+		"playback": map[string]string{"hls": "https://videodelivery.test/v2/manifest/video.m3u8"},
+	})
+
+	out, err := captureStdout(t, func() error {
+		return runStream([]string{"upload", "--url", "https://media.example.com/videos/intro.mp4"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body struct {
+		URL  string            `json:"url"`
+		Meta map[string]string `json:"meta"`
+	}
+	if posts := srv.Calls("POST", "/accounts/acc1/stream/copy"); len(posts) != 1 {
+		t.Fatalf("expected one copy request, got %d", len(posts))
+	} else if err := posts[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.URL != "https://media.example.com/videos/intro.mp4" || body.Meta["name"] != "intro.mp4" {
+		t.Fatalf("unexpected body %+v", body)
+	}
+	if out != "Video uploaded: intro.mp4 (uid=v1, state=queued)\n" {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	file := filepath.Join(t.TempDir(), "clip.mp4")
+	if err := os.WriteFile(file, []byte("not really a video"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = captureStdout(t, func() error { return runStream([]string{"upload", file}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	posts := srv.Calls("POST", "/accounts/acc1/stream")
+	if len(posts) != 1 || !strings.HasPrefix(posts[0].Header.Get("Content-Type"), "multipart/form-data") {
+		t.Fatalf("expected one multipart upload, got %d", len(posts))
+	}
+	if b := string(posts[0].Body); !strings.Contains(b, `name="file"; filename="clip.mp4"`) || !strings.Contains(b, "not really a video") {
+		t.Fatalf("unexpected multipart body:\n%s", b)
+	}
+	if !strings.Contains(out, "HLS: https://videodelivery.test/v2/manifest/video.m3u8") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestStreamDirectUploadAndDelete(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("POST", "/accounts/acc1/stream/direct_upload", map[string]string{"uid": "v3", "uploadURL": "https://upload.videodelivery.test/v3"})
+	srv.Reply("DELETE", "/accounts/acc1/stream/v3", nil)
+
+	out, err := captureStdout(t, func() error { return runStream([]string{"upload", "--direct", "--max-duration", "600"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body map[string]int
+	if posts := srv.Calls("POST", "/accounts/acc1/stream/direct_upload"); len(posts) != 1 {
+		t.Fatalf("expected one direct upload request, got %d", len(posts))
+	} else if err := posts[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["maxDurationSeconds"] != 600 || !strings.Contains(out, "https://upload.videodelivery.test/v3") {
+		t.Fatalf("unexpected body %v:\n%s", body, out)
+	}
+
+	out, err = captureStdout(t, func() error { return runStream([]string{"delete", "v3", "--force"}) })
+	if err != nil || out != "Video deleted: v3\n" {
+		t.Fatalf("unexpected result err=%v:\n%s", err, out)
+	}
+	if n := len(srv.Calls("DELETE", "/accounts/acc1/stream/v3")); n != 1 {
+		t.Fatalf("expected one DELETE, got %d", n)
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Fail("GET", "/accounts/acc1/stream", 400, 10011, "Stream is not enabled for this account")
+
+	if err := runStream([]string{"upload"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error without a source, got %v", err)
+	}
+	err := runStream([]string{"list"})
+	if exitCode(err) != exitAPI || !strings.Contains(err.Error(), "Stream is not enabled") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+	if len(srv.Calls("POST", "/accounts/acc1/stream")) != 0 {
+		t.Fatal("expected no upload without a source")
+	}
+}
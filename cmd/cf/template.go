@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var templateVarPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// templateVars collects every --set KEY=value in args. The flag may be
+// repeated, which parseFlags cannot express.
+func templateVars(args []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for i := 0; i < len(args); i++ {
+		var v string
+		switch {
+		case strings.HasPrefix(args[i], "--set="):
+			v = strings.TrimPrefix(args[i], "--set=")
+		case args[i] == "--set" && i+1 < len(args):
+			i++
+			v = args[i]
+		default:
+			continue
+		}
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, usageErrorf("invalid --set %q: want KEY=value", v)
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+	return vars, nil
+}
+
+// expandVars replaces ${NAME} with the --set value for NAME, falling back to
+// the environment, so one file can serve staging and production. $${ is a
+// literal ${. A variable that is set nowhere is an error rather than an
+// empty string that would end up in a record.
+func expandVars(data []byte, vars map[string]string) ([]byte, error) {
+	missing := map[string]bool{}
+	out := templateVarPattern.ReplaceAllStringFunc(string(data), func(m string) string {
+		if m == "$${" {
+			return "${"
+		}
+		name := m[2 : len(m)-1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		missing[name] = true
+		return m
+	})
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for n := range missing {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("undefined variable(s) %s: pass --set NAME=value or export them", strings.Join(names, ", "))
+	}
+	return []byte(out), nil
+}
+
+// readTemplateFile reads path with its variables expanded.
+func readTemplateFile(path string, vars map[string]string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err = expandVars(data, vars)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return data, nil
+}
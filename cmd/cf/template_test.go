@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandVars(t *testing.T) {
+	t.Setenv("CF_TEST_ZONE", "staging.example.com")
+	t.Setenv("CF_TEST_IP", "192.0.2.1")
+
+	got, err := expandVars([]byte("zone: ${CF_TEST_ZONE}\ncontent: ${CF_TEST_IP}\nliteral: $${CF_TEST_IP}\n"), map[string]string{"CF_TEST_IP": "198.51.100.2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "zone: staging.example.com\ncontent: 198.51.100.2\nliteral: ${CF_TEST_IP}\n"
+	if string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	if _, err := expandVars([]byte("${CF_TEST_NOPE_B} ${CF_TEST_NOPE_A}"), nil); err == nil || !strings.Contains(err.Error(), "CF_TEST_NOPE_A, CF_TEST_NOPE_B") {
+		t.Fatalf("expected an undefined variable error, got %v", err)
+	}
+}
+
+func TestTemplateVars(t *testing.T) {
+	vars, err := templateVars([]string{"--file", "x.yaml", "--set", "ZONE=a.example", "--set=IP=192.0.2.1=x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["ZONE"] != "a.example" || vars["IP"] != "192.0.2.1=x" || len(vars) != 2 {
+		t.Fatalf("unexpected vars %v", vars)
+	}
+	if _, err := templateVars([]string{"--set", "novalue"}); err == nil {
+		t.Fatal("expected an error for --set without =")
+	}
+}
+
+func TestDNSSyncExpandsVars(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{})
+	srv.Reply("POST", "/zones/z1/dns_records", dnsRecord{ID: "r1"})
+
+	file := filepath.Join(t.TempDir(), "records.yaml")
+	if err := os.WriteFile(file, []byte("zone: ${ZONE}\nrecords:\n  - {type: A, name: www, content: \"${IP}\"}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := captureStdout(t, func() error {
+		return runDNS([]string{"sync", "--file", file, "--set", "ZONE=example.com", "--set", "IP=192.0.2.7", "--yes"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body dnsRecord
+	if calls := srv.Calls("POST", "/zones/z1/dns_records"); len(calls) != 1 {
+		t.Fatalf("expected one record to be created, got %d", len(calls))
+	} else if err := calls[0].Decode(&body); err != nil || body.Content != "192.0.2.7" {
+		t.Fatalf("unexpected body %+v (err=%v)", body, err)
+	}
+}
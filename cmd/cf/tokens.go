@@ -0,0 +1,281 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	scopeAccount = "com.cloudflare.api.account"
+	scopeZone    = "com.cloudflare.api.account.zone"
+)
+
+type tokenTemplate struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+// tokenTemplates are the permission bundles `tokens create --template`
+// offers. Zone-scoped groups are limited to --zone when given.
+var tokenTemplates = []tokenTemplate{
+	{"dns-edit", "Edit DNS records", []string{"Zone Read", "DNS Write"}},
+	{"dns-read", "Read DNS records", []string{"Zone Read", "DNS Read"}},
+	{"cache-purge", "Purge cache", []string{"Zone Read", "Cache Purge"}},
+	{"zone-settings", "Edit zone settings and SSL", []string{"Zone Read", "Zone Settings Write", "SSL and Certificates Write"}},
+	{"workers-deploy", "Deploy Workers and routes", []string{"Account Settings Read", "Workers Scripts Write", "Workers Routes Write"}},
+	{"cf", "Everything the cf wizard, zones and dns commands need", []string{"Account Settings Read", "Zone Write", "DNS Write", "Cache Purge", "Zone Settings Write"}},
+}
+
+type apiToken struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Status     string        `json:"status"`
+	IssuedOn   string        `json:"issued_on,omitempty"`
+	ModifiedOn string        `json:"modified_on,omitempty"`
+	ExpiresOn  string        `json:"expires_on,omitempty"`
+	LastUsedOn string        `json:"last_used_on,omitempty"`
+	Policies   []tokenPolicy `json:"policies,omitempty"`
+
+	// Value is only returned when a token is created.
+	Value string `json:"value,omitempty"`
+}
+
+type permissionGroup struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+func runTokens(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+
+	switch action {
+	case "list":
+		tokens, err := listAll[apiToken]("/user/tokens", 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "STATUS", "EXPIRES_ON", "LAST_USED_ON"}}
+		for _, tk := range tokens {
+			t.Rows = append(t.Rows, []string{tk.ID, tk.Name, tk.Status, tk.ExpiresOn, tk.LastUsedOn})
+		}
+		return printList(tokens, t, func() {
+			for _, tk := range tokens {
+				fmt.Printf("%s\t%s\t%s\tlast used %s\n", tk.ID, tk.Name, tk.Status, orDash(tk.LastUsedOn))
+			}
+		})
+	case "templates":
+		t := table{Headers: []string{"TEMPLATE", "DESCRIPTION", "PERMISSIONS"}}
+		for _, tpl := range tokenTemplates {
+			t.Rows = append(t.Rows, []string{tpl.Name, tpl.Description, strings.Join(tpl.Permissions, ", ")})
+		}
+		return printList(tokenTemplates, t, func() {
+			for _, tpl := range tokenTemplates {
+				fmt.Printf("%-15s %s (%s)\n", tpl.Name, tpl.Description, strings.Join(tpl.Permissions, ", "))
+			}
+		})
+	case "create":
+		return createToken(positional, flags)
+	case "roll":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf tokens roll <name|id> [--force]")
+		}
+		tk, err := findToken(positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirmTokenChange("roll", tk, flags)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Token unchanged.")
+			return nil
+		}
+		resp, err := requestCF(http.MethodPut, "/user/tokens/"+tk.ID+"/value", map[string]any{})
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, &tk.Value); err != nil {
+			return err
+		}
+		return printResult(tk, func() {
+			fmt.Printf("Token rolled: %s (id=%s). The old secret no longer works.\n", tk.Name, tk.ID)
+			printTokenValue(tk.Value)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf tokens delete <name|id> [--force]")
+		}
+		tk, err := findToken(positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirmTokenChange("delete", tk, flags)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, "/user/tokens/"+tk.ID, nil); err != nil {
+			return err
+		}
+		return printResult(tk, func() {
+			fmt.Printf("Token deleted: %s (id=%s)\n", tk.Name, tk.ID)
+		})
+	}
+	return usageErrorf("usage: cf tokens list|templates|create|roll|delete")
+}
+
+func createToken(positional []string, flags map[string]string) error {
+	if len(positional) == 0 || (flags["template"] == "" && flags["permissions"] == "") {
+		return usageErrorf("usage: cf tokens create <name> (--template <name> | --permissions <a,b>) [--zone <zone,...>] [--expires 90d]")
+	}
+	names := splitList(flags["permissions"])
+	if v := flags["template"]; v != "" {
+		i := slices.IndexFunc(tokenTemplates, func(t tokenTemplate) bool { return t.Name == v })
+		if i < 0 {
+			return fmt.Errorf("unknown template %q. run: cf tokens templates", v)
+		}
+		names = append(slices.Clone(tokenTemplates[i].Permissions), names...)
+	}
+
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	var zoneIDs []string
+	for _, name := range splitList(flags["zone"]) {
+		z, err := requireZone(name)
+		if err != nil {
+			return err
+		}
+		zoneIDs = append(zoneIDs, z.ID)
+	}
+
+	resp, err := requestCF(http.MethodGet, "/user/tokens/permission_groups", nil)
+	if err != nil {
+		return err
+	}
+	var groups []permissionGroup
+	if err := json.Unmarshal(resp.Result, &groups); err != nil {
+		return err
+	}
+	policies, err := tokenPoliciesFor(groups, names, accountID, zoneIDs)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]any{"name": positional[0], "policies": policies}
+	if v := flags["expires"]; v != "" {
+		d, err := parseDurationWithDefault(v, 0)
+		if err != nil || d <= 0 {
+			return usageErrorf("invalid --expires %q (example: 30d, 720h)", v)
+		}
+		body["expires_on"] = time.Now().UTC().Add(d).Format(time.RFC3339)
+	}
+
+	resp, err = requestCF(http.MethodPost, "/user/tokens", body)
+	if err != nil {
+		return err
+	}
+	var tk apiToken
+	if err := json.Unmarshal(resp.Result, &tk); err != nil {
+		return err
+	}
+	return printResult(tk, func() {
+		fmt.Printf("Token created: %s (id=%s) with %s\n", tk.Name, tk.ID, strings.Join(names, ", "))
+		printTokenValue(tk.Value)
+		fmt.Println("To use it with cf: cf login --token <value>")
+	})
+}
+
+// tokenPoliciesFor resolves permission group names and builds one policy per
+// scope: account-level groups apply to the account, zone-level groups to the
+// given zones or every zone in the account.
+func tokenPoliciesFor(groups []permissionGroup, names []string, accountID string, zoneIDs []string) ([]map[string]any, error) {
+	var accountGroups, zoneGroups []map[string]string
+	seen := map[string]bool{}
+	for _, name := range names {
+		i := slices.IndexFunc(groups, func(g permissionGroup) bool { return strings.EqualFold(g.Name, name) || g.ID == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown permission group %q", name)
+		}
+		g := groups[i]
+		if seen[g.ID] {
+			continue
+		}
+		seen[g.ID] = true
+		if slices.Contains(g.Scopes, scopeZone) {
+			zoneGroups = append(zoneGroups, map[string]string{"id": g.ID})
+		} else {
+			accountGroups = append(accountGroups, map[string]string{"id": g.ID})
+		}
+	}
+
+	var policies []map[string]any
+	if len(accountGroups) > 0 {
+		policies = append(policies, map[string]any{
+			"effect":            "allow",
+			"permission_groups": accountGroups,
+			"resources":         map[string]any{scopeAccount + "." + accountID: "*"},
+		})
+	}
+	if len(zoneGroups) > 0 {
+		resources := map[string]any{}
+		if len(zoneIDs) == 0 {
+			resources[scopeAccount+"."+accountID] = map[string]string{scopeZone + ".*": "*"}
+		}
+		for _, id := range zoneIDs {
+			resources[scopeZone+"."+id] = "*"
+		}
+		policies = append(policies, map[string]any{
+			"effect":            "allow",
+			"permission_groups": zoneGroups,
+			"resources":         resources,
+		})
+	}
+	return policies, nil
+}
+
+func findToken(ref string) (*apiToken, error) {
+	tokens, err := listAll[apiToken]("/user/tokens", 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, tk := range tokens {
+		if tk.ID == ref || tk.Name == ref {
+			return &tk, nil
+		}
+	}
+	return nil, notFoundErrorf("token %q not found. run: cf tokens list", ref)
+}
+
+// confirmTokenChange asks before rolling or deleting a token; either breaks
+// every client still using the current secret.
+func confirmTokenChange(verb string, tk *apiToken, flags map[string]string) (bool, error) {
+	return confirm(flags, "tokens "+verb, fmt.Sprintf("%s token %s? Anything using it will stop working.", strings.ToUpper(verb[:1])+verb[1:], tk.Name))
+}
+
+func printTokenValue(v string) {
+	fmt.Println("Token value (shown only once, store it now):")
+	fmt.Printf("  %s\n", v)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var testPermissionGroups = []permissionGroup{
+	{ID: "g-zone-read", Name: "Zone Read", Scopes: []string{scopeZone}},
+	{ID: "g-dns-write", Name: "DNS Write", Scopes: []string{scopeZone}},
+	{ID: "g-acct", Name: "Account Settings Read", Scopes: []string{scopeAccount}},
+}
+
+func TestTokensCreate(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/user/tokens/permission_groups", testPermissionGroups)
+	srv.Reply("POST", "/user/tokens", apiToken{ID: "tk1", Name: "ci", Value: "secret-value"})
+
+	out, err := captureStdout(t, func() error {
+		return runTokens([]string{"create", "ci", "--permissions", "zone read,DNS Write,Zone Read", "--zone", "example.com"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body struct {
+		Name     string          `json:"name"`
+		Policies json.RawMessage `json:"policies"`
+	}
+	if posts := srv.Calls("POST", "/user/tokens"); len(posts) != 1 {
+		t.Fatalf("expected one POST, got %d", len(posts))
+	} else if err := posts[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"effect":"allow","permission_groups":[{"id":"g-zone-read"},{"id":"g-dns-write"}],"resources":{"com.cloudflare.api.account.zone.z1":"*"}}]`
+	if body.Name != "ci" || string(body.Policies) != want {
+		t.Fatalf("unexpected policies:\n got %s\nwant %s", body.Policies, want)
+	}
+	if !strings.Contains(out, "Token created: ci (id=tk1)") || !strings.Contains(out, "  secret-value\n") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestTokensCreateAllZones(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/user/tokens/permission_groups", testPermissionGroups)
+	srv.Reply("POST", "/user/tokens", apiToken{ID: "tk2", Name: "ops"})
+
+	if _, err := captureStdout(t, func() error {
+		return runTokens([]string{"create", "ops", "--permissions", "Account Settings Read,DNS Write", "--expires", "30d"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body struct {
+		Policies  []map[string]json.RawMessage `json:"policies"`
+		ExpiresOn string                       `json:"expires_on"`
+	}
+	if err := srv.Calls("POST", "/user/tokens")[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Policies) != 2 || body.ExpiresOn == "" {
+		t.Fatalf("expected account and zone policies with an expiry, got %+v", body)
+	}
+	if got := string(body.Policies[1]["resources"]); got != `{"com.cloudflare.api.account.acc1":{"com.cloudflare.api.account.zone.*":"*"}}` {
+		t.Fatalf("expected all zones in the account, got %s", got)
+	}
+}
+
+func TestTokensCreateErrors(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/user/tokens/permission_groups", testPermissionGroups)
+
+	err := runTokens([]string{"create", "ci", "--permissions", "Nope"})
+	if err == nil || !strings.Contains(err.Error(), `unknown permission group "Nope"`) {
+		t.Fatalf("expected error for unknown permission group, got %v", err)
+	}
+	if n := len(srv.Calls("POST", "/user/tokens")); n != 0 {
+		t.Fatalf("expected no token to be created, got %d POSTs", n)
+	}
+
+	srv.Fail("POST", "/user/tokens", 400, 1001, "Token name already in use")
+	err = runTokens([]string{"create", "ci", "--template", "dns-read"})
+	if err == nil || !strings.Contains(err.Error(), `unknown permission group "DNS Read"`) {
+		t.Fatalf("expected the template's missing group to be reported, got %v", err)
+	}
+	err = runTokens([]string{"create", "ci", "--permissions", "DNS Write"})
+	if exitCode(err) != exitAPI || !strings.Contains(err.Error(), "Token name already in use") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+}
+
+func TestTokensRoll(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/user/tokens", []apiToken{{ID: "tk1", Name: "ci"}})
+	srv.Reply("PUT", "/user/tokens/tk1/value", "new-secret")
+
+	out, err := captureStdout(t, func() error { return runTokens([]string{"roll", "ci", "--force"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(srv.Calls("PUT", "/user/tokens/tk1/value")); n != 1 {
+		t.Fatalf("expected one PUT, got %d", n)
+	}
+	if !strings.Contains(out, "Token rolled: ci (id=tk1)") || !strings.Contains(out, "  new-secret\n") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if err := runTokens([]string{"roll", "deploy", "--force"}); exitCode(err) != exitNotFound {
+		t.Fatalf("expected not found for an unknown token, got %v", err)
+	}
+}
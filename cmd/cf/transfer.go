@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const transferDashboardURL = "https://dash.cloudflare.com/?to=/:account/domains/transfer"
+
+// transferIn mirrors the transfer_in object on a Registrar domain. Each step
+// is reported as e.g. "needed", "ok", "pending" or "rejected".
+type transferIn struct {
+	UnlockDomain      string `json:"unlock_domain,omitempty"`
+	DisablePrivacy    string `json:"disable_privacy,omitempty"`
+	EnterAuthCode     string `json:"enter_auth_code,omitempty"`
+	ApproveTransfer   string `json:"approve_transfer,omitempty"`
+	AcceptFOA         string `json:"accept_foa,omitempty"`
+	CanCancelTransfer bool   `json:"can_cancel_transfer,omitempty"`
+}
+
+type transferStatus struct {
+	Domain           string      `json:"domain"`
+	CurrentRegistrar string      `json:"current_registrar,omitempty"`
+	RegistryStatuses string      `json:"registry_statuses,omitempty"`
+	TransferIn       *transferIn `json:"transfer_in,omitempty"`
+	Complete         bool        `json:"complete"`
+}
+
+func runRegistrarTransfer(args []string) error {
+	positional, flags := splitArgs(args)
+	if len(positional) > 0 && positional[0] == "status" {
+		if len(positional) != 2 {
+			return usageErrorf("usage: cf registrar transfer status <domain> [--wait] [--interval 30s] [--timeout 30m]")
+		}
+		interval, err := parseDurationWithDefault(flags["interval"], 30*time.Second)
+		if err != nil {
+			return usageErrorf("invalid --interval: %w", err)
+		}
+		timeout, err := parseDurationWithDefault(flags["timeout"], 30*time.Minute)
+		if err != nil {
+			return usageErrorf("invalid --timeout: %w", err)
+		}
+		return showTransferStatus(positional[1], parseBoolWithDefault(flags["wait"], false), interval, timeout)
+	}
+	if len(positional) == 1 {
+		return runTransferWizard(positional[0])
+	}
+	return usageErrorf("usage: cf registrar transfer <domain> | cf registrar transfer status <domain> [--wait]")
+}
+
+// runTransferWizard walks through the prerequisites the API can check and
+// hands off to the dashboard for the steps it cannot: Cloudflare's public API
+// has no endpoint to submit an auth code or start a transfer.
+func runTransferWizard(domain string) error {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Printf("Transferring %s into Cloudflare Registrar\n\n", domain)
+
+	fmt.Println("Step 1: domain must be an active zone on Cloudflare")
+	z, err := getZoneByName(domain)
+	if err != nil {
+		return err
+	}
+	switch {
+	case z == nil:
+		return fmt.Errorf("%s is not a zone in this account yet. run: cf zones add %s, update nameservers, then retry", domain, domain)
+	case z.Status != "active":
+		return fmt.Errorf("zone %s is %s; transfers need an active zone. run: cf zones check --zone %s", domain, z.Status, domain)
+	}
+	fmt.Printf("  OK: zone %s is active\n", z.Name)
+
+	fmt.Println("Step 2: domain must be unlocked at the current registrar")
+	status, err := fetchTransferStatus(domain)
+	if err != nil {
+		fmt.Printf("  Could not read registry status: %v\n", err)
+	} else if strings.Contains(status.RegistryStatuses, "clientTransferProhibited") {
+		fmt.Printf("  LOCKED: registry status is %q. Unlock the domain at %s first.\n", status.RegistryStatuses, registrarLabel(status.CurrentRegistrar))
+		ok, err := promptYesNo(reader, "Have you unlocked it now?", false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("transfer paused: unlock the domain at your current registrar and rerun")
+		}
+	} else {
+		fmt.Println("  OK: no transfer lock reported")
+	}
+
+	fmt.Println("Step 3: get the authorization (EPP) code from the current registrar")
+	if _, err := prompt(reader, "Press Enter once you have the auth code ready", ""); err != nil {
+		return err
+	}
+
+	fmt.Println("Step 4: enter the auth code and confirm payment in the Cloudflare dashboard")
+	fmt.Println(transferDashboardURL)
+	openNow, err := promptYesNo(reader, "Open the dashboard URL in your browser now?", true)
+	if err != nil {
+		return err
+	}
+	if openNow {
+		if err := openURL(transferDashboardURL); err != nil {
+			fmt.Printf("Could not open browser automatically: %v\n", err)
+		}
+	}
+
+	fmt.Printf("\nTrack progress with: cf registrar transfer status %s --wait\n", domain)
+	return nil
+}
+
+func fetchTransferStatus(domain string) (*transferStatus, error) {
+	var raw struct {
+		registrarDomain
+		TransferIn *transferIn `json:"transfer_in"`
+	}
+	if err := getRegistrarDomainInto(domain, &raw); err != nil {
+		return nil, err
+	}
+
+	s := &transferStatus{
+		Domain:           domain,
+		CurrentRegistrar: raw.CurrentRegistrar,
+		RegistryStatuses: raw.RegistryStatuses,
+		TransferIn:       raw.TransferIn,
+	}
+	s.Complete = transferComplete(s)
+	return s, nil
+}
+
+// transferComplete treats a transfer as done once Cloudflare is the current
+// registrar, or every reported transfer step is ok.
+func transferComplete(s *transferStatus) bool {
+	if strings.Contains(strings.ToLower(s.CurrentRegistrar), "cloudflare") {
+		return true
+	}
+	t := s.TransferIn
+	if t == nil {
+		return false
+	}
+	for _, step := range []string{t.UnlockDomain, t.DisablePrivacy, t.EnterAuthCode, t.ApproveTransfer, t.AcceptFOA} {
+		if step != "" && step != "ok" {
+			return false
+		}
+	}
+	return t.ApproveTransfer == "ok"
+}
+
+func showTransferStatus(domain string, wait bool, interval, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	last := ""
+	for {
+		s, err := fetchTransferStatus(domain)
+		if err != nil {
+			return err
+		}
+		if !wait || s.Complete {
+			return printResult(s, func() { printTransferStatus(s) })
+		}
+
+		if summary := transferSummary(s); summary != last && !machineOutput() {
+			printTransferStatus(s)
+			last = summary
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("transfer of %s not complete after %s", domain, timeout)
+		}
+		if err := sleep(interval); err != nil {
+			return err
+		}
+	}
+}
+
+func transferSummary(s *transferStatus) string {
+	if s.TransferIn == nil {
+		return s.CurrentRegistrar
+	}
+	t := s.TransferIn
+	return strings.Join([]string{t.UnlockDomain, t.DisablePrivacy, t.EnterAuthCode, t.ApproveTransfer, t.AcceptFOA}, "|")
+}
+
+func printTransferStatus(s *transferStatus) {
+	fmt.Printf("%s  registrar=%s\n", s.Domain, registrarLabel(s.CurrentRegistrar))
+	if t := s.TransferIn; t != nil {
+		for _, step := range []struct{ name, value string }{
+			{"unlock domain", t.UnlockDomain},
+			{"disable privacy", t.DisablePrivacy},
+			{"enter auth code", t.EnterAuthCode},
+			{"approve transfer", t.ApproveTransfer},
+			{"accept FOA", t.AcceptFOA},
+		} {
+			if step.value != "" {
+				fmt.Printf("  %-17s %s\n", step.name+":", step.value)
+			}
+		}
+	}
+	if s.Complete {
+		fmt.Println("Transfer complete.")
+	} else if s.TransferIn == nil {
+		fmt.Println("No transfer in progress.")
+	}
+}
+
+func registrarLabel(name string) string {
+	if name == "" {
+		return "your current registrar"
+	}
+	return name
+}
@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestTransferComplete(t *testing.T) {
+	cases := []struct {
+		name   string
+		status transferStatus
+		want   bool
+	}{
+		{"cloudflare registrar", transferStatus{CurrentRegistrar: "Cloudflare, Inc."}, true},
+		{"no transfer", transferStatus{CurrentRegistrar: "Example Registrar"}, false},
+		{"pending approval", transferStatus{TransferIn: &transferIn{UnlockDomain: "ok", EnterAuthCode: "ok", ApproveTransfer: "pending"}}, false},
+		{"all ok", transferStatus{TransferIn: &transferIn{UnlockDomain: "ok", EnterAuthCode: "ok", ApproveTransfer: "ok", AcceptFOA: "ok"}}, true},
+	}
+	for _, c := range cases {
+		if got := transferComplete(&c.status); got != c.want {
+			t.Fatalf("%s: transferComplete = %t, want %t", c.name, got, c.want)
+		}
+	}
+}
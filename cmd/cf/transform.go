@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func runTransform(args []string) error {
+	if len(args) == 0 || (args[0] != "rewrite" && args[0] != "headers") {
+		return usageErrorf("usage: cf transform rewrite|headers list|add|delete --zone <zone>")
+	}
+	kind := args[0]
+	args = args[1:]
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+
+	phase := phaseURLRewrite
+	if kind == "headers" {
+		phase = phaseResponseHeaders
+		if parseBoolWithDefault(flags["request"], false) {
+			phase = phaseRequestHeaders
+		}
+	}
+
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for transform %s: --zone", kind)
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	base := "/zones/" + z.ID
+
+	switch action {
+	case "list":
+		rs, err := getPhaseRuleset(base, phase)
+		if err != nil {
+			return err
+		}
+		return printRules(z.Name, rs)
+	case "add":
+		var rule rulesetRule
+		if kind == "rewrite" {
+			rule, err = urlRewriteRule(flags)
+		} else {
+			rule, err = headerTransformRule(flags)
+		}
+		if err != nil {
+			return err
+		}
+		rs, err := addPhaseRule(base, phase, rule)
+		if err != nil {
+			return err
+		}
+		added := lastRule(rs)
+		return printResult(added, func() {
+			fmt.Printf("Transform rule added to %s: %s (id=%s)\n", phase, added.Expression, added.ID)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf transform %s delete <rule-id|description> --zone <zone>", kind)
+		}
+		rs, err := getPhaseRuleset(base, phase)
+		if err != nil {
+			return err
+		}
+		rule, err := findRule(rs, positional[0])
+		if err != nil {
+			return err
+		}
+		if err := deletePhaseRule(base, rs, rule.ID); err != nil {
+			return err
+		}
+		return printResult(rule, func() {
+			fmt.Printf("Transform rule deleted: %s\n", rule.ID)
+		})
+	}
+	return usageErrorf("usage: cf transform %s list|add|delete --zone <zone>", kind)
+}
+
+// urlRewriteRule rewrites the path and/or query. Static values use --path and
+// --query; --path-expression and --query-expression take a rules language
+// expression, e.g. regex_replace(http.request.uri.path, "^/api", "").
+func urlRewriteRule(flags map[string]string) (rulesetRule, error) {
+	uri := map[string]any{}
+	for _, part := range []string{"path", "query"} {
+		if v := flags[part]; v != "" {
+			uri[part] = map[string]string{"value": v}
+		}
+		if v := flags[part+"-expression"]; v != "" {
+			if _, ok := uri[part]; ok {
+				return rulesetRule{}, fmt.Errorf("--%s and --%s-expression are mutually exclusive", part, part)
+			}
+			uri[part] = map[string]string{"expression": v}
+		}
+	}
+	if len(uri) == 0 {
+		return rulesetRule{}, usageErrorf("missing rewrite: pass --path, --path-expression, --query or --query-expression")
+	}
+	return transformRule(flags, map[string]any{"uri": uri})
+}
+
+// headerTransformRule sets or removes headers. --set takes "Name: value"
+// pairs separated by ";" and --remove a comma-separated list of names.
+func headerTransformRule(flags map[string]string) (rulesetRule, error) {
+	headers := map[string]map[string]string{}
+	for _, pair := range strings.Split(flags["set"], ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return rulesetRule{}, usageErrorf("invalid --set %q: want \"Name: value\"", pair)
+		}
+		headers[name] = map[string]string{"operation": "set", "value": strings.TrimSpace(value)}
+	}
+	for _, name := range splitList(flags["remove"]) {
+		headers[name] = map[string]string{"operation": "remove"}
+	}
+	if len(headers) == 0 {
+		return rulesetRule{}, usageErrorf("missing header change: pass --set \"Name: value\" or --remove Name")
+	}
+	return transformRule(flags, map[string]any{"headers": headers})
+}
+
+func transformRule(flags map[string]string, params map[string]any) (rulesetRule, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return rulesetRule{}, err
+	}
+	expression := flags["expression"]
+	if expression == "" {
+		expression = "true"
+	}
+	enabled := true
+	return rulesetRule{
+		Action:           "rewrite",
+		ActionParameters: raw,
+		Expression:       expression,
+		Description:      flags["description"],
+		Enabled:          &enabled,
+	}, nil
+}
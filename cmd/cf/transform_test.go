@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTransformRewriteAdd(t *testing.T) {
+	srv := useFakeAPI(t)
+	const entrypoint = "/zones/z1/rulesets/phases/http_request_transform/entrypoint"
+	srv.Reply("GET", entrypoint, ruleset{ID: "rs1"})
+	srv.Reply("POST", "/zones/z1/rulesets/rs1/rules", ruleset{ID: "rs1", Rules: []rulesetRule{{ID: "r1", Action: "rewrite", Expression: `starts_with(http.request.uri.path, "/old/")`}}})
+
+	out, err := captureStdout(t, func() error {
+		return runTransform([]string{"rewrite", "add", "--zone", "example.com", "--expression", `starts_with(http.request.uri.path, "/old/")`, "--path", "/new/"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rule rulesetRule
+	if posts := srv.Calls("POST", "/zones/z1/rulesets/rs1/rules"); len(posts) != 1 {
+		t.Fatalf("expected the rule to be appended, got %d POSTs", len(posts))
+	} else if err := posts[0].Decode(&rule); err != nil {
+		t.Fatal(err)
+	}
+	if rule.Action != "rewrite" || string(rule.ActionParameters) != `{"uri":{"path":{"value":"/new/"}}}` {
+		t.Fatalf("unexpected rule %s %s", rule.Action, rule.ActionParameters)
+	}
+	if !strings.Contains(out, "Transform rule added to http_request_transform: ") || !strings.Contains(out, "(id=r1)") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestTransformHeadersAddAndDelete(t *testing.T) {
+	srv := useFakeAPI(t)
+	const request = "/zones/z1/rulesets/phases/http_request_late_transform/entrypoint"
+	const response = "/zones/z1/rulesets/phases/http_response_headers_transform/entrypoint"
+	srv.Fail("GET", request, 404, 10003, "Entry point not found")
+	srv.Reply("PUT", request, ruleset{ID: "rs1", Rules: []rulesetRule{{ID: "r1", Action: "rewrite", Expression: "true"}}})
+	srv.Reply("GET", response, ruleset{ID: "rs2", Rules: []rulesetRule{{ID: "r9", Action: "rewrite", Expression: "true", Description: "hsts"}}})
+	srv.Reply("DELETE", "/zones/z1/rulesets/rs2/rules/r9", ruleset{ID: "rs2"})
+
+	if _, err := captureStdout(t, func() error {
+		return runTransform([]string{"headers", "add", "--zone", "example.com", "--request", "--set", "X-Origin: edge", "--remove", "Cookie"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	puts := srv.Calls("PUT", request)
+	if len(puts) != 1 {
+		t.Fatalf("expected --request to target the request header phase, got %d PUTs", len(puts))
+	}
+	var body struct {
+		Rules []struct {
+			ActionParameters json.RawMessage `json:"action_parameters"`
+		} `json:"rules"`
+	}
+	if err := puts[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"headers":{"Cookie":{"operation":"remove"},"X-Origin":{"operation":"set","value":"edge"}}}`
+	if len(body.Rules) != 1 || string(body.Rules[0].ActionParameters) != want {
+		t.Fatalf("unexpected rules %s", puts[0].Body)
+	}
+
+	out, err := captureStdout(t, func() error { return runTransform([]string{"headers", "delete", "hsts", "--zone", "example.com"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(srv.Calls("DELETE", "/zones/z1/rulesets/rs2/rules/r9")); n != 1 || out != "Transform rule deleted: r9\n" {
+		t.Fatalf("expected one DELETE, got %d:\n%s", n, out)
+	}
+}
+
+func TestTransformErrors(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Fail("GET", "/zones/z1/rulesets/phases/http_request_transform/entrypoint", 403, 10000, "Authentication error")
+
+	if err := runTransform([]string{"headers", "add", "--zone", "example.com"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error without a header change, got %v", err)
+	}
+	if len(srv.Calls("GET", "/zones/z1/rulesets/phases/*/entrypoint")) != 0 {
+		t.Fatal("expected no ruleset lookup before validation")
+	}
+	err := runTransform([]string{"rewrite", "list", "--zone", "example.com"})
+	if exitCode(err) != exitAuth || !strings.Contains(err.Error(), "Authentication error") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+}
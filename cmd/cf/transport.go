@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// proxyFlag is set by the global --proxy flag. It overrides HTTPS_PROXY,
+// HTTP_PROXY and NO_PROXY for every request.
+var proxyFlag string
+
+// outboundTransport carries every request cf makes once setupHTTP has run;
+// nil means http.DefaultTransport.
+var outboundTransport *http.Transport
+
+func setProxy(v string) error {
+	if _, err := parseProxyURL(v); err != nil {
+		return err
+	}
+	proxyFlag = v
+	return nil
+}
+
+// parseProxyURL accepts a proxy such as http://proxy:3128 or a bare
+// host:port, which means an HTTP proxy.
+func parseProxyURL(v string) (*url.URL, error) {
+	if !strings.Contains(v, "://") {
+		v = "http://" + v
+	}
+	u, err := url.Parse(v)
+	if err != nil || u.Host == "" {
+		return nil, usageErrorf("invalid --proxy %q: use a URL such as http://proxy.example:3128", v)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, usageErrorf("invalid --proxy %q: scheme must be http, https or socks5", v)
+	}
+	return u, nil
+}
+
+// setupHTTP builds the transport for the run from --proxy (or the proxy
+// environment variables) and CF_CA_BUNDLE.
+func setupHTTP() error {
+	t, err := newTransport(proxyFlag, strings.TrimSpace(os.Getenv("CF_CA_BUNDLE")))
+	if err != nil {
+		return err
+	}
+	outboundTransport = t
+	if httpClient == nil {
+		httpClient = &http.Client{Transport: t}
+	}
+	return nil
+}
+
+// newTransport is http.DefaultTransport with proxy set, or the environment's
+// proxy when it is empty, and the certificates in caBundle trusted in
+// addition to the system roots, for TLS-intercepting proxies.
+func newTransport(proxy, caBundle string) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	if proxy != "" {
+		u, err := parseProxyURL(proxy)
+		if err != nil {
+			return nil, err
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+	if caBundle != "" {
+		pem, err := os.ReadFile(caBundle)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CF_CA_BUNDLE: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CF_CA_BUNDLE %s holds no PEM certificates", caBundle)
+		}
+		t.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	return t, nil
+}
+
+// outboundClient returns a client on the run's transport for requests that
+// are not API calls, such as probes and public IP lookups.
+func outboundClient(timeout time.Duration) *http.Client {
+	c := &http.Client{Timeout: timeout}
+	if outboundTransport != nil {
+		c.Transport = outboundTransport
+	}
+	return c
+}
+
+// outboundTLSConfig is the TLS config for connections made outside an
+// http.Client, with the CF_CA_BUNDLE roots.
+func outboundTLSConfig(serverName string) *tls.Config {
+	cfg := &tls.Config{}
+	if outboundTransport != nil && outboundTransport.TLSClientConfig != nil {
+		cfg = outboundTransport.TLSClientConfig.Clone()
+	}
+	cfg.ServerName = serverName
+	return cfg
+}
+
+// dialOutbound opens a TCP connection to addr for target, tunnelling
+// through the run's HTTP proxy with CONNECT when one applies.
+func dialOutbound(ctx context.Context, target *url.URL, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var proxy *url.URL
+	if outboundTransport != nil && outboundTransport.Proxy != nil {
+		// Proxy selection goes by the equivalent http(s) URL.
+		probe := *target
+		switch target.Scheme {
+		case "wss":
+			probe.Scheme = "https"
+		case "ws":
+			probe.Scheme = "http"
+		}
+		var err error
+		if proxy, err = outboundTransport.Proxy(&http.Request{URL: &probe}); err != nil {
+			return nil, err
+		}
+	}
+	if proxy == nil {
+		return dialer.DialContext(ctx, "tcp", addr)
+	}
+	if proxy.Scheme != "http" && proxy.Scheme != "https" {
+		return nil, fmt.Errorf("proxy %s: only http and https proxies can tunnel this connection", proxy.Redacted())
+	}
+
+	proxyAddr := proxy.Host
+	if proxy.Port() == "" {
+		proxyAddr = net.JoinHostPort(proxy.Hostname(), map[string]string{"http": "80", "https": "443"}[proxy.Scheme])
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+	if proxy.Scheme == "https" {
+		conn = tls.Client(conn, outboundTLSConfig(proxy.Hostname()))
+	}
+
+	req := &http.Request{Method: http.MethodConnect, URL: &url.URL{Opaque: addr}, Host: addr, Header: http.Header{}}
+	if u := proxy.User; u != nil {
+		pass, _ := u.Password()
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(u.Username()+":"+pass)))
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s refused CONNECT to %s: %s", proxy.Redacted(), addr, resp.Status)
+	}
+	if br.Buffered() > 0 {
+		conn.Close()
+		return nil, errors.New("proxy sent data before the tunnel was established")
+	}
+	return conn, nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestCABundleIsTrusted(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	plain, err := newTransport("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&http.Client{Transport: plain}).Get(srv.URL); err == nil {
+		t.Fatal("expected the test certificate to be rejected without a bundle")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := newTransport("", bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("expected the bundle to be trusted, got %v", err)
+	}
+	resp.Body.Close()
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(empty, []byte("not a certificate"), 0o600)
+	if _, err := newTransport("", empty); err == nil {
+		t.Fatal("expected an error for a bundle without certificates")
+	}
+}
+
+func TestProxyFlagRoutesRequests(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.URL.String())
+		mu.Unlock()
+		io.WriteString(w, "via proxy")
+	}))
+	defer proxy.Close()
+
+	tr, err := newTransport(proxy.Listener.Addr().String(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: tr}).Get("http://api.example.invalid/ips")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "via proxy" || len(seen) != 1 || seen[0] != "http://api.example.invalid/ips" {
+		t.Fatalf("expected the request to go through the proxy, got %q, %v", body, seen)
+	}
+
+	for _, v := range []string{"ftp://proxy:21", "http://"} {
+		if err := setProxy(v); exitCode(err) != exitUsage {
+			t.Fatalf("expected usage error for %q, got %v", v, err)
+		}
+	}
+}
+
+func TestDialOutboundTunnelsThroughProxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello")
+	}))
+	defer target.Close()
+
+	var auth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		auth = r.Header.Get("Proxy-Authorization")
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() { io.Copy(upstream, conn); upstream.Close() }()
+		io.Copy(conn, upstream)
+		conn.Close()
+	}))
+	defer proxy.Close()
+
+	orig := outboundTransport
+	t.Cleanup(func() { outboundTransport = orig })
+	tr, err := newTransport("http://user:secret@"+proxy.Listener.Addr().String(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outboundTransport = tr
+
+	u, _ := url.Parse("ws://" + target.Listener.Addr().String() + "/tail")
+	conn, err := dialOutbound(context.Background(), u, u.Host)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+	req, _ := http.NewRequest(http.MethodGet, "http://"+u.Host+"/", nil)
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "hello" {
+		t.Fatalf("expected the target's response through the tunnel, got %q", body)
+	}
+	if auth != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Fatalf("expected proxy credentials to be sent, got %q", auth)
+	}
+}
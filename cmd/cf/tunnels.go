@@ -0,0 +1,354 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type tunnel struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Status      string `json:"status,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	ConfigSrc   string `json:"config_src,omitempty"`
+	Connections []struct {
+		ColoName string `json:"colo_name"`
+	} `json:"connections,omitempty"`
+	Token string `json:"token,omitempty"`
+}
+
+type tunnelIngressRule struct {
+	Hostname string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Path     string `json:"path,omitempty" yaml:"path,omitempty"`
+	Service  string `json:"service" yaml:"service"`
+}
+
+type tunnelConfig struct {
+	Ingress []tunnelIngressRule `json:"ingress" yaml:"ingress"`
+}
+
+// catchAllService is appended when an ingress list lacks a final rule without
+// a hostname; cloudflared rejects configs without one.
+const catchAllService = "http_status:404"
+
+func runTunnels(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf tunnels list|create|delete|token|config")
+	}
+	positional, flags := splitArgs(args[1:])
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	path := "/accounts/" + accountID + "/cfd_tunnel"
+
+	switch args[0] {
+	case "list":
+		tunnels, err := listAll[tunnel](path+"?is_deleted=false", 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "STATUS", "CONNECTIONS", "CREATED"}}
+		for _, tn := range tunnels {
+			t.Rows = append(t.Rows, []string{tn.ID, tn.Name, tn.Status, fmt.Sprint(len(tn.Connections)), tn.CreatedAt})
+		}
+		return printList(tunnels, t, func() {
+			if len(tunnels) == 0 {
+				fmt.Println("No tunnels found.")
+				return
+			}
+			for _, tn := range tunnels {
+				fmt.Printf("%s\t%s\t%s\n", tn.ID, tn.Name, tn.Status)
+			}
+		})
+	case "create":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf tunnels create <name>")
+		}
+		return createTunnel(path, positional[0])
+	case "route":
+		if len(positional) < 3 || positional[0] != "dns" {
+			return usageErrorf("usage: cf tunnels route dns <tunnel> <hostname> [--zone <zone>]")
+		}
+		tn, err := findTunnel(path, positional[1])
+		if err != nil {
+			return err
+		}
+		return routeTunnelDNS(tn, positional[2], flags["zone"])
+	}
+
+	if len(positional) == 0 {
+		return usageErrorf("usage: cf tunnels %s <tunnel>", args[0])
+	}
+	tn, err := findTunnel(path, positional[0])
+	if err != nil {
+		return err
+	}
+	tunnelPath := path + "/" + tn.ID
+
+	switch args[0] {
+	case "delete":
+		ok, err := confirm(flags, "tunnels delete", fmt.Sprintf("Delete tunnel %s (id=%s)?", tn.Name, tn.ID))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, tunnelPath, nil); err != nil {
+			return err
+		}
+		return printResult(tn, func() {
+			fmt.Printf("Tunnel deleted: %s (id=%s)\n", tn.Name, tn.ID)
+		})
+	case "token":
+		token, err := tunnelToken(tunnelPath)
+		if err != nil {
+			return err
+		}
+		tn.Token = token
+		return printResult(tn, func() { fmt.Println(token) })
+	case "config":
+		return runTunnelConfig(tunnelPath, tn, positional[1:], flags)
+	}
+	return errors.New("unknown tunnels command. run: cf help")
+}
+
+func createTunnel(path, name string) error {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+	resp, err := requestCF(http.MethodPost, path, map[string]string{
+		"name":          name,
+		"config_src":    "cloudflare",
+		"tunnel_secret": base64.StdEncoding.EncodeToString(secret),
+	})
+	if err != nil {
+		return err
+	}
+	var tn tunnel
+	if err := json.Unmarshal(resp.Result, &tn); err != nil {
+		return err
+	}
+	if tn.Token == "" {
+		if tn.Token, err = tunnelToken(path + "/" + tn.ID); err != nil {
+			return err
+		}
+	}
+
+	return printResult(tn, func() {
+		fmt.Printf("Tunnel created: %s (id=%s)\n", tn.Name, tn.ID)
+		fmt.Println("Run the connector with:")
+		fmt.Printf("  cloudflared tunnel run --token %s\n", tn.Token)
+		fmt.Printf("Then route a hostname to it: cf tunnels route dns %s <hostname>\n", tn.Name)
+	})
+}
+
+func tunnelToken(tunnelPath string) (string, error) {
+	resp, err := requestCF(http.MethodGet, tunnelPath+"/token", nil)
+	if err != nil {
+		return "", err
+	}
+	var token string
+	if err := json.Unmarshal(resp.Result, &token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// findTunnel matches a tunnel by ID or name.
+func findTunnel(path, ref string) (*tunnel, error) {
+	tunnels, err := listAll[tunnel](path+"?is_deleted=false", 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, tn := range tunnels {
+		if tn.ID == ref || tn.Name == ref {
+			return &tn, nil
+		}
+	}
+	return nil, notFoundErrorf("tunnel %q not found. run: cf tunnels list", ref)
+}
+
+func runTunnelConfig(tunnelPath string, tn *tunnel, args []string, flags map[string]string) error {
+	action := "get"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	configPath := tunnelPath + "/configurations"
+
+	var cfg tunnelConfig
+	switch action {
+	case "get":
+		c, err := getTunnelConfig(configPath)
+		if err != nil {
+			return err
+		}
+		cfg = *c
+	case "set":
+		if flags["file"] == "" {
+			return usageErrorf("missing required flag for tunnels config set: --file")
+		}
+		c, err := readTunnelConfig(flags["file"])
+		if err != nil {
+			return err
+		}
+		cfg = *c
+	case "add", "remove":
+		if flags["hostname"] == "" {
+			return usageErrorf("missing required flag for tunnels config %s: --hostname", action)
+		}
+		if action == "add" && flags["service"] == "" {
+			return usageErrorf("missing required flag for tunnels config add: --service")
+		}
+		c, err := getTunnelConfig(configPath)
+		if err != nil {
+			return err
+		}
+		cfg = *c
+		rule := tunnelIngressRule{Hostname: flags["hostname"], Path: flags["path"], Service: flags["service"]}
+		if action == "add" {
+			cfg.Ingress = addIngressRule(cfg.Ingress, rule)
+		} else {
+			cfg.Ingress = removeIngressRule(cfg.Ingress, rule)
+		}
+	default:
+		return usageErrorf("usage: cf tunnels config <tunnel> get|set|add|remove")
+	}
+
+	if action != "get" {
+		cfg.Ingress = ensureCatchAll(cfg.Ingress)
+		if _, err := requestCF(http.MethodPut, configPath, map[string]any{"config": cfg}); err != nil {
+			return err
+		}
+		infof("Tunnel %s configuration updated.\n", tn.Name)
+	}
+
+	t := table{Headers: []string{"HOSTNAME", "PATH", "SERVICE"}}
+	for _, r := range cfg.Ingress {
+		t.Rows = append(t.Rows, []string{r.Hostname, r.Path, r.Service})
+	}
+	return printList(cfg, t, func() {
+		for _, r := range cfg.Ingress {
+			host := r.Hostname
+			if host == "" {
+				host = "*"
+			}
+			fmt.Printf("%s%s -> %s\n", host, r.Path, r.Service)
+		}
+	})
+}
+
+func getTunnelConfig(configPath string) (*tunnelConfig, error) {
+	resp, err := requestCF(http.MethodGet, configPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Config *tunnelConfig `json:"config"`
+	}
+	if err := json.Unmarshal(resp.Result, &out); err != nil {
+		return nil, err
+	}
+	if out.Config == nil {
+		return &tunnelConfig{}, nil
+	}
+	return out.Config, nil
+}
+
+// readTunnelConfig accepts the cloudflared config.yml format (or JSON) and
+// keeps only the ingress rules.
+func readTunnelConfig(path string) (*tunnelConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg tunnelConfig
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	if len(cfg.Ingress) == 0 {
+		return nil, fmt.Errorf("%s has no ingress rules", path)
+	}
+	return &cfg, nil
+}
+
+// addIngressRule replaces a rule for the same hostname and path, or inserts
+// it ahead of the catch-all.
+func addIngressRule(rules []tunnelIngressRule, rule tunnelIngressRule) []tunnelIngressRule {
+	for i, r := range rules {
+		if strings.EqualFold(r.Hostname, rule.Hostname) && r.Path == rule.Path {
+			rules[i] = rule
+			return rules
+		}
+	}
+	out := make([]tunnelIngressRule, 0, len(rules)+1)
+	inserted := false
+	for _, r := range rules {
+		if r.Hostname == "" && !inserted {
+			out = append(out, rule)
+			inserted = true
+		}
+		out = append(out, r)
+	}
+	if !inserted {
+		out = append(out, rule)
+	}
+	return out
+}
+
+func removeIngressRule(rules []tunnelIngressRule, rule tunnelIngressRule) []tunnelIngressRule {
+	out := rules[:0]
+	for _, r := range rules {
+		if strings.EqualFold(r.Hostname, rule.Hostname) && (rule.Path == "" || r.Path == rule.Path) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func ensureCatchAll(rules []tunnelIngressRule) []tunnelIngressRule {
+	if n := len(rules); n > 0 && rules[n-1].Hostname == "" && rules[n-1].Path == "" {
+		return rules
+	}
+	return append(rules, tunnelIngressRule{Service: catchAllService})
+}
+
+// routeTunnelDNS points hostname at a tunnel with a proxied CNAME to
+// <tunnel-id>.cfargotunnel.com.
+func routeTunnelDNS(tn *tunnel, hostname, zoneName string) error {
+	zoneName = zoneOrDefault(zoneName)
+	if zoneName == "" {
+		z, err := zoneForHostname(hostname)
+		if err != nil {
+			return err
+		}
+		zoneName = z.Name
+	}
+
+	r, err := addDNSRecord(zoneName, dnsRecord{Type: "CNAME", Name: qualifyRecordName(hostname, zoneName), Content: tn.ID + ".cfargotunnel.com", TTL: 1, Proxied: true})
+	if err != nil {
+		return err
+	}
+	return printResult(r, func() {
+		fmt.Printf("%s now routes to tunnel %s. Add an ingress rule if you have not yet:\n", r.Name, tn.Name)
+		fmt.Printf("  cf tunnels config %s add --hostname %s --service http://localhost:8080\n", tn.Name, r.Name)
+	})
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIngressRules(t *testing.T) {
+	rules := []tunnelIngressRule{
+		{Hostname: "a.example.com", Service: "http://localhost:3000"},
+		{Service: catchAllService},
+	}
+
+	rules = addIngressRule(rules, tunnelIngressRule{Hostname: "b.example.com", Service: "http://localhost:4000"})
+	want := []tunnelIngressRule{
+		{Hostname: "a.example.com", Service: "http://localhost:3000"},
+		{Hostname: "b.example.com", Service: "http://localhost:4000"},
+		{Service: catchAllService},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("add inserted in wrong place: %+v", rules)
+	}
+
+	rules = addIngressRule(rules, tunnelIngressRule{Hostname: "A.example.com", Service: "http://localhost:3001"})
+	if len(rules) != 3 || rules[0].Service != "http://localhost:3001" {
+		t.Fatalf("expected existing hostname to be replaced: %+v", rules)
+	}
+
+	rules = removeIngressRule(rules, tunnelIngressRule{Hostname: "a.example.com"})
+	if len(rules) != 2 || rules[0].Hostname != "b.example.com" {
+		t.Fatalf("unexpected rules after remove: %+v", rules)
+	}
+
+	got := ensureCatchAll([]tunnelIngressRule{{Hostname: "a.example.com", Service: "http://localhost:3000"}})
+	if len(got) != 2 || got[1].Service != catchAllService {
+		t.Fatalf("expected catch-all to be appended: %+v", got)
+	}
+	if got := ensureCatchAll(want); len(got) != 3 {
+		t.Fatalf("did not expect a second catch-all: %+v", got)
+	}
+}
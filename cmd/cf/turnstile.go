@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var turnstileModes = []string{"managed", "non-interactive", "invisible"}
+
+type turnstileWidget struct {
+	Sitekey    string   `json:"sitekey"`
+	Secret     string   `json:"secret,omitempty"`
+	Name       string   `json:"name"`
+	Domains    []string `json:"domains"`
+	Mode       string   `json:"mode"`
+	CreatedOn  string   `json:"created_on,omitempty"`
+	ModifiedOn string   `json:"modified_on,omitempty"`
+}
+
+func runTurnstile(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	path := "/accounts/" + accountID + "/challenges/widgets"
+
+	switch action {
+	case "list":
+		widgets, err := listAll[turnstileWidget](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"SITEKEY", "NAME", "MODE", "DOMAINS"}}
+		for _, w := range widgets {
+			t.Rows = append(t.Rows, []string{w.Sitekey, w.Name, w.Mode, strings.Join(w.Domains, ",")})
+		}
+		return printList(widgets, t, func() {
+			if len(widgets) == 0 {
+				fmt.Println("No Turnstile widgets.")
+				return
+			}
+			for _, w := range widgets {
+				fmt.Printf("%s\t%s\t%s\t%s\n", w.Sitekey, w.Name, w.Mode, strings.Join(w.Domains, ", "))
+			}
+		})
+	case "create":
+		if len(positional) == 0 || flags["domains"] == "" {
+			return usageErrorf("usage: cf turnstile create <name> --domains <a,b> [--mode managed|non-interactive|invisible]")
+		}
+		mode := flags["mode"]
+		if mode == "" {
+			mode = "managed"
+		}
+		if !slices.Contains(turnstileModes, mode) {
+			return usageErrorf("invalid --mode %q (want one of: %s)", mode, strings.Join(turnstileModes, ", "))
+		}
+		resp, err := requestCF(http.MethodPost, path, map[string]any{
+			"name":    positional[0],
+			"domains": splitList(flags["domains"]),
+			"mode":    mode,
+		})
+		if err != nil {
+			return err
+		}
+		var w turnstileWidget
+		if err := json.Unmarshal(resp.Result, &w); err != nil {
+			return err
+		}
+		return printResult(w, func() {
+			fmt.Printf("Turnstile widget created: %s (%s) for %s\n", w.Name, w.Mode, strings.Join(w.Domains, ", "))
+			fmt.Printf("  Sitekey: %s\n", w.Sitekey)
+			fmt.Printf("  Secret:  %s\n", w.Secret)
+		})
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf turnstile delete <sitekey|name> [--force]")
+		}
+		if err := requireConfirmable(flags, "turnstile delete"); err != nil {
+			return err
+		}
+		w, err := findTurnstileWidget(path, positional[0])
+		if err != nil {
+			return err
+		}
+		ok, err := confirm(flags, "turnstile delete", fmt.Sprintf("Delete Turnstile widget %s (%s)?", w.Name, w.Sitekey))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted. Nothing deleted.")
+			return nil
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+w.Sitekey, nil); err != nil {
+			return err
+		}
+		return printResult(w, func() {
+			fmt.Printf("Turnstile widget deleted: %s (%s)\n", w.Name, w.Sitekey)
+		})
+	case "rotate-secret":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf turnstile rotate-secret <sitekey|name> [--invalidate-immediately]")
+		}
+		w, err := findTurnstileWidget(path, positional[0])
+		if err != nil {
+			return err
+		}
+		immediately := parseBoolWithDefault(flags["invalidate-immediately"], false)
+		resp, err := requestCF(http.MethodPost, path+"/"+w.Sitekey+"/rotate_secret", map[string]bool{
+			"invalidate_immediately": immediately,
+		})
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, w); err != nil {
+			return err
+		}
+		return printResult(w, func() {
+			fmt.Printf("Secret rotated for %s (%s)\n", w.Name, w.Sitekey)
+			fmt.Printf("  New secret: %s\n", w.Secret)
+			if immediately {
+				fmt.Println("The old secret stopped working immediately.")
+			} else {
+				fmt.Println("The old secret keeps working for two hours; update your server before then.")
+			}
+		})
+	}
+	return usageErrorf("usage: cf turnstile list|create|delete|rotate-secret")
+}
+
+func findTurnstileWidget(path, ref string) (*turnstileWidget, error) {
+	widgets, err := listAll[turnstileWidget](path, 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range widgets {
+		if w.Sitekey == ref || w.Name == ref {
+			return &w, nil
+		}
+	}
+	return nil, notFoundErrorf("Turnstile widget %q not found. run: cf turnstile list", ref)
+}
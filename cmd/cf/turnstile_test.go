@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const turnstilePath = "/accounts/acc1/challenges/widgets"
+
+func TestTurnstileCreate(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("POST", turnstilePath, turnstileWidget{Sitekey: "0xAAA", Secret: "0xSECRET", Name: "signup", Domains: []string{"example.com", "www.example.com"}, Mode: "invisible"})
+
+	out, err := captureStdout(t, func() error {
+		return runTurnstile([]string{"create", "signup", "--domains", "example.com,www.example.com", "--mode", "invisible"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body turnstileWidget
+	if posts := srv.Calls("POST", turnstilePath); len(posts) != 1 {
+		t.Fatalf("expected one POST, got %d", len(posts))
+	} else if err := posts[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Name != "signup" || body.Mode != "invisible" || strings.Join(body.Domains, ",") != "example.com,www.example.com" {
+		t.Fatalf("unexpected body %+v", body)
+	}
+	if !strings.Contains(out, "Sitekey: 0xAAA") || !strings.Contains(out, "Secret:  0xSECRET") {
+		t.Fatalf("expected the keys in the output:\n%s", out)
+	}
+
+	if err := runTurnstile([]string{"create", "signup", "--domains", "example.com", "--mode", "loud"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error for an unknown mode, got %v", err)
+	}
+	if n := len(srv.Calls("POST", turnstilePath)); n != 1 {
+		t.Fatalf("expected no request for an invalid mode, got %d POSTs", n)
+	}
+}
+
+func TestTurnstileRotateSecret(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", turnstilePath, []turnstileWidget{{Sitekey: "0xAAA", Name: "signup", Mode: "managed"}})
+	srv.Reply("POST", turnstilePath+"/0xAAA/rotate_secret", turnstileWidget{Sitekey: "0xAAA", Secret: "0xNEW", Name: "signup", Mode: "managed"})
+
+	out, err := captureStdout(t, func() error { return runTurnstile([]string{"rotate-secret", "signup", "--invalidate-immediately"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body map[string]bool
+	if posts := srv.Calls("POST", turnstilePath+"/0xAAA/rotate_secret"); len(posts) != 1 {
+		t.Fatalf("expected one rotate request, got %d", len(posts))
+	} else if err := posts[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if !body["invalidate_immediately"] || !strings.Contains(out, "New secret: 0xNEW") || !strings.Contains(out, "stopped working immediately") {
+		t.Fatalf("unexpected body %v:\n%s", body, out)
+	}
+
+	if err := runTurnstile([]string{"rotate-secret", "login"}); exitCode(err) != exitNotFound {
+		t.Fatalf("expected not found for an unknown widget, got %v", err)
+	}
+}
+
+func TestTurnstileDelete(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", turnstilePath, []turnstileWidget{{Sitekey: "0xAAA", Name: "signup"}})
+	srv.Fail("DELETE", turnstilePath+"/0xAAA", 400, 10400, "Widget is locked")
+
+	err := runTurnstile([]string{"delete", "signup", "--force"})
+	if exitCode(err) != exitAPI || !strings.Contains(err.Error(), "Widget is locked") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+	if n := len(srv.Calls("DELETE", turnstilePath+"/0xAAA")); n != 1 {
+		t.Fatalf("expected one DELETE, got %d", n)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var wafActions = []string{"block", "challenge", "js_challenge", "managed_challenge", "log", "skip"}
+
+func runWAF(args []string) error {
+	if len(args) == 0 || args[0] != "rules" {
+		return usageErrorf("usage: cf waf rules list|add|enable|disable|delete --zone <zone>")
+	}
+	args = args[1:]
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for waf rules: --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	if action == "add" {
+		if flags["expression"] == "" || flags["action"] == "" {
+			return usageErrorf("missing required flags for waf rules add: --expression --action")
+		}
+		if !slices.Contains(wafActions, flags["action"]) {
+			return usageErrorf("invalid --action %q (want one of: %s)", flags["action"], strings.Join(wafActions, ", "))
+		}
+		enabled := parseBoolWithDefault(flags["enabled"], true)
+		rule := rulesetRule{
+			Action:      flags["action"],
+			Expression:  flags["expression"],
+			Description: flags["description"],
+			Enabled:     &enabled,
+		}
+		rs, err := addPhaseRule("/zones/"+z.ID, phaseFirewallCustom, rule)
+		if err != nil {
+			return err
+		}
+		added := lastRule(rs)
+		return printResult(added, func() {
+			fmt.Printf("WAF rule added: %s %s (id=%s)\n", added.Action, added.Expression, added.ID)
+		})
+	}
+
+	rs, err := getPhaseRuleset("/zones/"+z.ID, phaseFirewallCustom)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "list":
+		return printRules(z.Name, rs)
+	case "enable", "disable", "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf waf rules %s <rule-id|description> --zone <zone>", action)
+		}
+		rule, err := findRule(rs, positional[0])
+		if err != nil {
+			return err
+		}
+		if action == "delete" {
+			if err := deletePhaseRule("/zones/"+z.ID, rs, rule.ID); err != nil {
+				return err
+			}
+			return printResult(rule, func() {
+				fmt.Printf("WAF rule deleted: %s\n", rule.ID)
+			})
+		}
+		enabled := action == "enable"
+		rule.Enabled = &enabled
+		if err := updatePhaseRule("/zones/"+z.ID, rs, *rule); err != nil {
+			return err
+		}
+		return printResult(rule, func() {
+			fmt.Printf("WAF rule %sd: %s\n", action, rule.ID)
+		})
+	}
+	return usageErrorf("usage: cf waf rules list|add|enable|disable|delete --zone <zone>")
+}
+
+func printRules(zoneName string, rs *ruleset) error {
+	rules := []rulesetRule{}
+	if rs != nil {
+		rules = rs.Rules
+	}
+	t := table{Headers: []string{"ID", "ENABLED", "ACTION", "DESCRIPTION", "EXPRESSION"}}
+	for _, r := range rules {
+		t.Rows = append(t.Rows, []string{r.ID, fmt.Sprint(r.enabled()), r.Action, r.Description, r.Expression})
+	}
+	return printList(rules, t, func() {
+		if len(rules) == 0 {
+			fmt.Printf("No rules on %s.\n", zoneName)
+			return
+		}
+		for _, r := range rules {
+			state := "on "
+			if !r.enabled() {
+				state = "off"
+			}
+			fmt.Printf("%s\t%s\t%s\t%s\n", r.ID, state, r.Action, r.Expression)
+			if r.Description != "" {
+				fmt.Printf("\t# %s\n", r.Description)
+			}
+		}
+	})
+}
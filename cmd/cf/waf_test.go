@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const wafEntrypoint = "/zones/z1/rulesets/phases/http_request_firewall_custom/entrypoint"
+
+func TestWAFRulesAddCreatesEntrypoint(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Fail("GET", wafEntrypoint, 404, 10003, "Entry point not found")
+	srv.Reply("PUT", wafEntrypoint, ruleset{ID: "rs1", Rules: []rulesetRule{
+		{ID: "r1", Action: "block", Expression: `ip.src.country eq "XX"`},
+	}})
+
+	out, err := captureStdout(t, func() error {
+		return runWAF([]string{"rules", "add", "--zone", "example.com", "--expression", `ip.src.country eq "XX"`, "--action", "block", "--description", "geo"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	puts := srv.Calls("PUT", wafEntrypoint)
+	if len(puts) != 1 {
+		t.Fatalf("expected the entry point to be created, got %d PUTs", len(puts))
+	}
+	var body struct {
+		Rules []rulesetRule `json:"rules"`
+	}
+	if err := puts[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Rules) != 1 || body.Rules[0].Action != "block" || body.Rules[0].Description != "geo" || !body.Rules[0].enabled() {
+		t.Fatalf("unexpected rules %+v", body.Rules)
+	}
+	if !strings.Contains(out, `WAF rule added: block ip.src.country eq "XX" (id=r1)`) {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestWAFRulesAddAppendsAndToggles(t *testing.T) {
+	srv := useFakeAPI(t)
+	existing := ruleset{ID: "rs1", Rules: []rulesetRule{{ID: "r1", Action: "log", Expression: "true", Description: "audit"}}}
+	srv.Reply("GET", wafEntrypoint, existing)
+	srv.Reply("POST", "/zones/z1/rulesets/rs1/rules", ruleset{ID: "rs1", Rules: append(existing.Rules, rulesetRule{ID: "r2", Action: "skip", Expression: "true"})})
+	srv.Reply("PATCH", "/zones/z1/rulesets/rs1/rules/r1", existing)
+
+	if _, err := captureStdout(t, func() error {
+		return runWAF([]string{"rules", "add", "--zone", "example.com", "--expression", "true", "--action", "skip", "--enabled", "false"})
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var added rulesetRule
+	if posts := srv.Calls("POST", "/zones/z1/rulesets/rs1/rules"); len(posts) != 1 {
+		t.Fatalf("expected the rule to be appended, got %d POSTs", len(posts))
+	} else if err := posts[0].Decode(&added); err != nil {
+		t.Fatal(err)
+	}
+	if added.Action != "skip" || added.enabled() {
+		t.Fatalf("unexpected rule %+v", added)
+	}
+
+	out, err := captureStdout(t, func() error { return runWAF([]string{"rules", "disable", "audit", "--zone", "example.com"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var patched rulesetRule
+	if patches := srv.Calls("PATCH", "/zones/z1/rulesets/rs1/rules/r1"); len(patches) != 1 {
+		t.Fatalf("expected one PATCH, got %d", len(patches))
+	} else if err := patches[0].Decode(&patched); err != nil {
+		t.Fatal(err)
+	}
+	if patched.enabled() || out != "WAF rule disabled: r1\n" {
+		t.Fatalf("expected the rule to be disabled, got %+v:\n%s", patched, out)
+	}
+
+	if err := runWAF([]string{"rules", "delete", "nosuch", "--zone", "example.com"}); exitCode(err) != exitNotFound {
+		t.Fatalf("expected not found for an unknown rule, got %v", err)
+	}
+}
+
+func TestWAFRulesErrors(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Fail("GET", wafEntrypoint, 403, 10000, "Authentication error")
+
+	if err := runWAF([]string{"rules", "add", "--zone", "example.com", "--expression", "true", "--action", "deny"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected a usage error for an unknown action, got %v", err)
+	}
+	if n := len(srv.Calls("GET", wafEntrypoint)); n != 0 {
+		t.Fatalf("expected no ruleset lookup before validation, got %d", n)
+	}
+	if err := runWAF([]string{"rules", "list", "--zone", "example.com"}); err == nil || !strings.Contains(err.Error(), "Authentication error") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var queueingMethods = []string{"fifo", "random", "passthrough", "reject"}
+
+type waitingRoom struct {
+	ID                string `json:"id,omitempty"`
+	Name              string `json:"name,omitempty"`
+	Description       string `json:"description,omitempty"`
+	Host              string `json:"host,omitempty"`
+	Path              string `json:"path,omitempty"`
+	TotalActiveUsers  int    `json:"total_active_users,omitempty"`
+	NewUsersPerMinute int    `json:"new_users_per_minute,omitempty"`
+	QueueingMethod    string `json:"queueing_method,omitempty"`
+	SessionDuration   int    `json:"session_duration,omitempty"`
+	Suspended         bool   `json:"suspended"`
+}
+
+type waitingRoomStatus struct {
+	Status                    string `json:"status"`
+	EventID                   string `json:"event_id,omitempty"`
+	EstimatedQueuedUsers      int    `json:"estimated_queued_users"`
+	EstimatedTotalActiveUsers int    `json:"estimated_total_active_users"`
+	MaxEstimatedTimeMinutes   int    `json:"max_estimated_time_minutes"`
+}
+
+func runWaitingRoom(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	positional, flags := splitArgs(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for waiting-room: --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	path := "/zones/" + z.ID + "/waiting_rooms"
+
+	switch action {
+	case "list":
+		rooms, err := listAll[waitingRoom](path, 50, 0)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "NAME", "HOST", "PATH", "ACTIVE_USERS", "NEW_PER_MIN", "METHOD", "SUSPENDED"}}
+		for _, r := range rooms {
+			t.Rows = append(t.Rows, []string{r.ID, r.Name, r.Host, r.Path, strconv.Itoa(r.TotalActiveUsers), strconv.Itoa(r.NewUsersPerMinute), r.QueueingMethod, strconv.FormatBool(r.Suspended)})
+		}
+		return printList(rooms, t, func() {
+			if len(rooms) == 0 {
+				fmt.Printf("No waiting rooms on %s.\n", z.Name)
+				return
+			}
+			for _, r := range rooms {
+				fmt.Printf("%s\t%s\t%s%s\tactive=%d new/min=%d %s\n", r.ID, r.Name, r.Host, r.Path, r.TotalActiveUsers, r.NewUsersPerMinute, r.QueueingMethod)
+			}
+		})
+	case "create", "update":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf waiting-room %s <name> --zone <zone> --host <host> [--path /] --total-active-users <n> --new-users-per-minute <n> [--queueing-method fifo]", action)
+		}
+		room, err := waitingRoomFromFlags(flags)
+		if err != nil {
+			return err
+		}
+		var resp apiResponse
+		if action == "create" {
+			room.Name = positional[0]
+			if room.Host == "" || room.TotalActiveUsers == 0 || room.NewUsersPerMinute == 0 {
+				return errors.New("waiting-room create needs --host, --total-active-users and --new-users-per-minute")
+			}
+			if room.Path == "" {
+				room.Path = "/"
+			}
+			resp, err = requestCF(http.MethodPost, path, room)
+		} else {
+			existing, ferr := findWaitingRoom(path, positional[0])
+			if ferr != nil {
+				return ferr
+			}
+			body, merr := waitingRoomPatch(room, flags)
+			if merr != nil {
+				return merr
+			}
+			if len(body) == 0 {
+				return errors.New("nothing to update: pass at least one setting flag")
+			}
+			resp, err = requestCF(http.MethodPatch, path+"/"+existing.ID, body)
+		}
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(resp.Result, &room); err != nil {
+			return err
+		}
+		return printResult(room, func() {
+			fmt.Printf("Waiting room %sd: %s (id=%s) %s%s active=%d new/min=%d %s\n", action, room.Name, room.ID, room.Host, room.Path, room.TotalActiveUsers, room.NewUsersPerMinute, room.QueueingMethod)
+		})
+	case "status":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf waiting-room status <name|id> --zone <zone>")
+		}
+		room, err := findWaitingRoom(path, positional[0])
+		if err != nil {
+			return err
+		}
+		resp, err := requestCF(http.MethodGet, path+"/"+room.ID+"/status", nil)
+		if err != nil {
+			return err
+		}
+		var s waitingRoomStatus
+		if err := json.Unmarshal(resp.Result, &s); err != nil {
+			return err
+		}
+		return printResult(s, func() {
+			fmt.Printf("Waiting room %s: %s\n", room.Name, s.Status)
+			fmt.Printf("  Queued users:        %d\n", s.EstimatedQueuedUsers)
+			fmt.Printf("  Active users:        %d of %d\n", s.EstimatedTotalActiveUsers, room.TotalActiveUsers)
+			fmt.Printf("  Max estimated wait:  %d min\n", s.MaxEstimatedTimeMinutes)
+			if s.EventID != "" {
+				fmt.Printf("  Event: %s\n", s.EventID)
+			}
+		})
+	}
+	return usageErrorf("usage: cf waiting-room list|create|update|status --zone <zone>")
+}
+
+func waitingRoomFromFlags(flags map[string]string) (waitingRoom, error) {
+	room := waitingRoom{
+		Description:    flags["description"],
+		Host:           flags["host"],
+		Path:           flags["path"],
+		QueueingMethod: flags["queueing-method"],
+	}
+	if room.QueueingMethod != "" && !slices.Contains(queueingMethods, room.QueueingMethod) {
+		return room, usageErrorf("invalid --queueing-method %q (want one of: %s)", room.QueueingMethod, strings.Join(queueingMethods, ", "))
+	}
+	var err error
+	if room.TotalActiveUsers, err = parseIntWithDefault(flags["total-active-users"], 0); err != nil {
+		return room, usageErrorf("invalid --total-active-users: %w", err)
+	}
+	if room.NewUsersPerMinute, err = parseIntWithDefault(flags["new-users-per-minute"], 0); err != nil {
+		return room, usageErrorf("invalid --new-users-per-minute: %w", err)
+	}
+	if room.SessionDuration, err = parseIntWithDefault(flags["session-duration"], 0); err != nil {
+		return room, usageErrorf("invalid --session-duration: %w", err)
+	}
+	return room, nil
+}
+
+// waitingRoomPatch turns the flags that were given into a PATCH body, so an
+// update leaves everything else as it is. suspended is handled here because
+// its zero value is meaningful.
+func waitingRoomPatch(room waitingRoom, flags map[string]string) (map[string]any, error) {
+	data, err := json.Marshal(room)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]any{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	delete(body, "suspended")
+	if v := flags["suspended"]; v != "" {
+		on, err := parseOnOff(v)
+		if err != nil {
+			return nil, usageErrorf("invalid --suspended: %w", err)
+		}
+		body["suspended"] = on
+	}
+	return body, nil
+}
+
+func findWaitingRoom(path, ref string) (*waitingRoom, error) {
+	rooms, err := listAll[waitingRoom](path, 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rooms {
+		if r.ID == ref || r.Name == ref {
+			return &r, nil
+		}
+	}
+	return nil, notFoundErrorf("waiting room %q not found. run: cf waiting-room list --zone <zone>", ref)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWaitingRoomPatch(t *testing.T) {
+	room, err := waitingRoomFromFlags(map[string]string{"new-users-per-minute": "300"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, err := waitingRoomPatch(room, map[string]string{"suspended": "off"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]any{"new_users_per_minute": float64(300), "suspended": false}
+	if !reflect.DeepEqual(body, want) {
+		t.Fatalf("unexpected patch: %v", body)
+	}
+
+	if _, err := waitingRoomFromFlags(map[string]string{"queueing-method": "lifo"}); err == nil {
+		t.Fatalf("expected error for unknown queueing method")
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cf/pkg/cfapi"
+)
+
+// watchEvent is one change seen between polls. From is empty for the first
+// poll and for things that appeared; To is empty for things that went away.
+type watchEvent struct {
+	Time  time.Time `json:"time"`
+	Key   string    `json:"key"`
+	From  string    `json:"from,omitempty"`
+	To    string    `json:"to,omitempty"`
+	First bool      `json:"initial,omitempty"`
+}
+
+// watchSnapshot maps what is being watched, such as "status" or a DNS
+// record, to its current value.
+type watchSnapshot map[string]string
+
+func runWatch(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf watch zone <zone> | cf watch dns --zone <zone> [--name <name>]")
+	}
+	positional, flags := splitArgs(args[1:])
+	interval, err := parseDurationWithDefault(flags["interval"], 30*time.Second)
+	if err != nil || interval <= 0 {
+		return usageErrorf("invalid --interval %q: want a duration such as 30s or 5m", flags["interval"])
+	}
+
+	switch args[0] {
+	case "zone":
+		zoneName := zoneOrDefault("")
+		if len(positional) > 0 {
+			zoneName = positional[0]
+		}
+		if zoneName == "" {
+			return usageErrorf("usage: cf watch zone <zone> [--interval 30s] [--until-active]")
+		}
+		untilActive := parseBoolWithDefault(flags["until-active"], false)
+		infof("Watching zone %s every %s (Ctrl-C to stop)...\n", zoneName, interval)
+		return watch(interval, func() (watchSnapshot, bool, error) {
+			s, err := zoneSnapshot(zoneName)
+			return s, untilActive && s["status"] == "active", err
+		})
+	case "dns":
+		zoneName := zoneOrDefault(flags["zone"])
+		if zoneName == "" {
+			return usageErrorf("missing required flag for watch dns: --zone")
+		}
+		z, err := requireZone(zoneName)
+		if err != nil {
+			return err
+		}
+		filter := cfapi.DNSRecordFilter{Type: strings.ToUpper(flags["type"])}
+		if flags["name"] != "" {
+			filter.Name = qualifyRecordName(flags["name"], z.Name)
+		}
+		what := "DNS records in " + z.Name
+		if filter.Name != "" {
+			what = filter.Name
+		}
+		infof("Watching %s every %s (Ctrl-C to stop)...\n", what, interval)
+		return watch(interval, func() (watchSnapshot, bool, error) {
+			s, err := dnsSnapshot(z.ID, filter)
+			return s, false, err
+		})
+	}
+	return usageErrorf("unknown watch target %q. run: cf watch --help", args[0])
+}
+
+// watch polls until poll reports done or the user interrupts, printing what
+// changed each time. API errors other than auth and not-found are reported
+// and retried on the next poll, so a network blip does not end a long watch.
+func watch(interval time.Duration, poll func() (watchSnapshot, bool, error)) error {
+	var last watchSnapshot
+	for {
+		current, done, err := poll()
+		switch {
+		case err != nil && (exitCode(err) == exitAuth || exitCode(err) == exitNotFound):
+			return err
+		case err != nil:
+			infof("%s poll failed: %v\n", time.Now().Format(time.TimeOnly), err)
+		default:
+			for _, e := range diffSnapshots(last, current, time.Now()) {
+				if err := printWatchEvent(e); err != nil {
+					return err
+				}
+			}
+			last = current
+		}
+		if done {
+			return nil
+		}
+		if err := sleep(interval); err != nil {
+			if errors.Is(err, errInterrupted) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// diffSnapshots lists the keys whose values differ between before and after,
+// in key order. A nil before means the first poll, which reports everything.
+func diffSnapshots(before, after watchSnapshot, now time.Time) []watchEvent {
+	keys := map[string]bool{}
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var events []watchEvent
+	for _, k := range sorted {
+		from, to := before[k], after[k]
+		if before != nil && from == to {
+			continue
+		}
+		events = append(events, watchEvent{Time: now, Key: k, From: from, To: to, First: before == nil})
+	}
+	return events
+}
+
+func printWatchEvent(e watchEvent) error {
+	if machineOutput() {
+		// One compact document per line, so the stream can be piped to jq.
+		return json.NewEncoder(os.Stdout).Encode(e)
+	}
+	ts := e.Time.Format(time.TimeOnly)
+	switch {
+	case e.First:
+		fmt.Printf("%s %s: %s\n", ts, e.Key, orNone(e.To))
+	case e.From == "":
+		fmt.Printf("%s %s: added %s\n", ts, e.Key, e.To)
+	case e.To == "":
+		fmt.Printf("%s %s: removed (was %s)\n", ts, e.Key, e.From)
+	default:
+		fmt.Printf("%s %s: %s -> %s\n", ts, e.Key, colorStatus("warn", e.From), colorStatus("ok", e.To))
+	}
+	return nil
+}
+
+// zoneSnapshot records a zone's status and its nameservers, both as
+// Cloudflare assigned them and as live DNS currently delegates.
+func zoneSnapshot(zoneName string) (watchSnapshot, error) {
+	z, err := requireFreshZone(zoneName)
+	if err != nil {
+		return nil, err
+	}
+	s := watchSnapshot{"status": z.Status, "paused": strconv.FormatBool(z.Paused)}
+	if z.Type == "partial" {
+		return s, nil
+	}
+	s["assigned nameservers"] = strings.Join(normalizeNameservers(z.NameServers), ", ")
+	ctx, cancel := context.WithTimeout(rootCtx, 10*time.Second)
+	defer cancel()
+	// A failed lookup fails the poll rather than reading as an empty
+	// delegation, which would be reported as a change and back.
+	delegated, err := delegatedNameservers(ctx, z.Name)
+	if err != nil {
+		return nil, err
+	}
+	s["delegated nameservers"] = strings.Join(delegated, ", ")
+	return s, nil
+}
+
+// dnsSnapshot keys each matching record by type, name and ID, so two
+// records with the same name are told apart.
+func dnsSnapshot(zoneID string, filter cfapi.DNSRecordFilter) (watchSnapshot, error) {
+	c, err := apiClient()
+	if err != nil {
+		return nil, err
+	}
+	records, err := c.ListDNSRecords(rootCtx, zoneID, filter, 0)
+	if err != nil {
+		return nil, err
+	}
+	s := watchSnapshot{}
+	for _, r := range records {
+		s[dnsRecordKey(r)] = dnsRecordSummary(r)
+	}
+	return s, nil
+}
+
+func dnsRecordKey(r dnsRecord) string {
+	return fmt.Sprintf("%s %s [%s]", r.Type, r.Name, r.ID)
+}
+
+// dnsRecordSummary is the part of a record worth reporting a change in.
+func dnsRecordSummary(r dnsRecord) string {
+	value := r.Content
+	if r.Priority != nil {
+		value = fmt.Sprintf("%d %s", *r.Priority, value)
+	}
+	if r.Proxied {
+		value += " (proxied)"
+	}
+	ttl := "auto"
+	if r.TTL > 1 {
+		ttl = strconv.Itoa(r.TTL)
+	}
+	return value + " ttl " + ttl
+}
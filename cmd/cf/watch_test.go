@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"cf/internal/cftest"
+)
+
+func TestWatchZoneUntilActive(t *testing.T) {
+	srv := useFakeAPI(t)
+	polls := 0
+	srv.Handle("GET", "/zones?name=example.com", func(cftest.Request) cftest.Response {
+		polls++
+		status := "pending"
+		if polls >= 3 {
+			status = "active"
+		}
+		return cftest.Response{Result: []zone{{ID: "z1", Name: "example.com", Status: status, NameServers: []string{"ada.ns.cloudflare.com"}}}}
+	})
+
+	origSleep, origNS := sleep, lookupNS
+	t.Cleanup(func() { sleep, lookupNS = origSleep, origNS })
+	sleep = func(time.Duration) error { return nil }
+	lookupNS = func(context.Context, string) ([]*net.NS, error) {
+		if polls < 2 {
+			return []*net.NS{{Host: "ns1.old-host.example."}}, nil
+		}
+		return []*net.NS{{Host: "ada.ns.cloudflare.com."}}, nil
+	}
+
+	out, err := captureStdout(t, func() error {
+		return runWatch([]string{"zone", "example.com", "--interval", "1s", "--until-active"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"status: pending\n",
+		"delegated nameservers: ns1.old-host.example\n",
+		"delegated nameservers: ns1.old-host.example -> ada.ns.cloudflare.com\n",
+		"status: pending -> active\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if polls != 3 {
+		t.Fatalf("expected watching to stop once active, got %d polls", polls)
+	}
+}
+
+func TestWatchZoneIgnoresFailedNameserverLookup(t *testing.T) {
+	useFakeAPI(t)
+
+	origSleep, origNS := sleep, lookupNS
+	t.Cleanup(func() { sleep, lookupNS = origSleep, origNS })
+	sleeps := 0
+	sleep = func(time.Duration) error {
+		if sleeps++; sleeps == 3 {
+			return errInterrupted
+		}
+		return nil
+	}
+	lookups := 0
+	lookupNS = func(context.Context, string) ([]*net.NS, error) {
+		if lookups++; lookups == 2 {
+			return nil, &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}
+		}
+		return []*net.NS{{Host: "ada.ns.cloudflare.com."}}, nil
+	}
+
+	out, err := captureStdout(t, func() error {
+		return runWatch([]string{"zone", "example.com", "--interval", "1s"})
+	})
+	if err != nil {
+		t.Fatalf("Ctrl-C should end the watch cleanly, got %v", err)
+	}
+	if lookups != 3 {
+		t.Fatalf("expected 3 nameserver lookups, got %d", lookups)
+	}
+	if n := strings.Count(out, "delegated nameservers:"); n != 1 {
+		t.Fatalf("a failed lookup should not be reported as a change:\n%s", out)
+	}
+}
+
+func TestWatchDNS(t *testing.T) {
+	srv := useFakeAPI(t)
+	polls := 0
+	srv.Handle("GET", "/zones/z1/dns_records?name=www.example.com", func(cftest.Request) cftest.Response {
+		polls++
+		records := []dnsRecord{{ID: "r1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1}}
+		switch polls {
+		case 2:
+			records[0].Content, records[0].Proxied = "192.0.2.9", true
+		case 3:
+			records = nil
+		}
+		return cftest.Response{Result: records}
+	})
+
+	origSleep := sleep
+	t.Cleanup(func() { sleep = origSleep })
+	sleeps := 0
+	sleep = func(time.Duration) error {
+		if sleeps++; sleeps == 3 {
+			return errInterrupted
+		}
+		return nil
+	}
+
+	out, err := captureStdout(t, func() error {
+		return runWatch([]string{"dns", "--zone", "example.com", "--name", "www"})
+	})
+	if err != nil {
+		t.Fatalf("Ctrl-C should end the watch cleanly, got %v", err)
+	}
+	for _, want := range []string{
+		"A www.example.com [r1]: 192.0.2.1 ttl auto\n",
+		"A www.example.com [r1]: 192.0.2.1 ttl auto -> 192.0.2.9 (proxied) ttl auto\n",
+		"A www.example.com [r1]: removed (was 192.0.2.9 (proxied) ttl auto)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
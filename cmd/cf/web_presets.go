@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// hostingPreset is a website host whose DNS setup the wizard can create in
+// one step: records for the apex (flattened by Cloudflare when it is a
+// CNAME) and for www.
+type hostingPreset struct {
+	Key  string
+	Name string
+	// TargetPrompt asks for the host-side name or address the records point
+	// at; empty means the preset needs none.
+	TargetPrompt string
+	Records      func(zone, target string) ([]dnsRecord, error)
+	Note         string
+}
+
+var hostingPresets = []hostingPreset{
+	{
+		Key:          "github-pages",
+		Name:         "GitHub Pages",
+		TargetPrompt: "GitHub user or organization (the part before .github.io)",
+		Records: func(_, target string) ([]dnsRecord, error) {
+			var out []dnsRecord
+			for i := 0; i < 4; i++ {
+				out = append(out, dnsRecord{Type: "A", Name: "@", Content: fmt.Sprintf("185.199.%d.153", 108+i)})
+			}
+			for i := 0; i < 4; i++ {
+				out = append(out, dnsRecord{Type: "AAAA", Name: "@", Content: fmt.Sprintf("2606:50c0:800%d::153", i)})
+			}
+			return append(out, dnsRecord{Type: "CNAME", Name: "www", Content: withSuffix(target, ".github.io")}), nil
+		},
+		Note: "Set the custom domain in the repository's Settings > Pages, then tick Enforce HTTPS once the certificate is issued.",
+	},
+	{
+		Key:          "netlify",
+		Name:         "Netlify",
+		TargetPrompt: "Netlify site name (the part before .netlify.app)",
+		Records: func(_, target string) ([]dnsRecord, error) {
+			return []dnsRecord{
+				{Type: "A", Name: "@", Content: "75.2.60.5"},
+				{Type: "CNAME", Name: "www", Content: withSuffix(target, ".netlify.app")},
+			}, nil
+		},
+		Note: "Add the domain under Site configuration > Domain management in Netlify.",
+	},
+	{
+		Key:  "vercel",
+		Name: "Vercel",
+		Records: func(string, string) ([]dnsRecord, error) {
+			return []dnsRecord{
+				{Type: "A", Name: "@", Content: "76.76.21.21"},
+				{Type: "CNAME", Name: "www", Content: "cname.vercel-dns.com"},
+			}, nil
+		},
+		Note: "Add both the apex and www domains to the project under Settings > Domains in Vercel.",
+	},
+	{
+		Key:          "cloudflare-pages",
+		Name:         "Cloudflare Pages",
+		TargetPrompt: "Pages project name (the part before .pages.dev)",
+		Records: func(_, target string) ([]dnsRecord, error) {
+			host := withSuffix(target, ".pages.dev")
+			return []dnsRecord{
+				{Type: "CNAME", Name: "@", Content: host, Proxied: true},
+				{Type: "CNAME", Name: "www", Content: host, Proxied: true},
+			}, nil
+		},
+		Note: "Add the domains under the project's Custom domains tab too, or Pages will not serve them.",
+	},
+	{
+		Key:          "ip",
+		Name:         "Point at an IP",
+		TargetPrompt: "Server IP address",
+		Records: func(zone, target string) ([]dnsRecord, error) {
+			ip := net.ParseIP(target)
+			if ip == nil {
+				return nil, fmt.Errorf("%q is not an IP address", target)
+			}
+			typeName := "AAAA"
+			if ip.To4() != nil {
+				typeName = "A"
+			}
+			return []dnsRecord{
+				{Type: typeName, Name: "@", Content: target, Proxied: true},
+				{Type: "CNAME", Name: "www", Content: zone, Proxied: true},
+			}, nil
+		},
+	},
+}
+
+func findHostingPreset(key string) (*hostingPreset, error) {
+	for i, p := range hostingPresets {
+		if p.Key == strings.ToLower(key) {
+			return &hostingPresets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown hosting preset %q (want one of: %s)", key, strings.Join(hostingPresetKeys(), ", "))
+}
+
+func hostingPresetKeys() []string {
+	keys := make([]string, 0, len(hostingPresets))
+	for _, p := range hostingPresets {
+		keys = append(keys, p.Key)
+	}
+	return keys
+}
+
+// withSuffix appends suffix unless name already ends with it.
+func withSuffix(name, suffix string) string {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+	if strings.HasSuffix(name, suffix) {
+		return name
+	}
+	return name + suffix
+}
+
+// wizardHostingPreset creates a preset's records in zoneName, first offering
+// to remove apex and www records they would clash with. Failures are
+// reported and skipped so one bad record does not end the wizard.
+func wizardHostingPreset(reader *bufio.Reader, zoneName string, p *hostingPreset) error {
+	target := ""
+	if p.TargetPrompt != "" {
+		var err error
+		if target, err = prompt(reader, p.TargetPrompt, ""); err != nil {
+			return err
+		}
+		if target == "" {
+			fmt.Println("Skipped: no target given.")
+			return nil
+		}
+	}
+	records, err := p.Records(zoneName, target)
+	if err != nil {
+		fmt.Printf("Skipped: %v\n", err)
+		return nil
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	live, err := listDNSRecords(z.ID)
+	if err != nil {
+		return err
+	}
+	clashes := hostingClashes(z.Name, live)
+	if len(clashes) > 0 {
+		fmt.Printf("%s already has these web records:\n", z.Name)
+		for _, r := range clashes {
+			fmt.Printf("  %s %s -> %s\n", r.Type, r.Name, r.Content)
+		}
+		replace, err := promptYesNo(reader, fmt.Sprintf("Delete them before adding the %s records?", p.Name), true)
+		if err != nil {
+			return err
+		}
+		if !replace {
+			clashes = nil
+		}
+	}
+
+	if err := applyHostingRecords(z, p, records, clashes); err != nil {
+		fmt.Println(err)
+	}
+	return nil
+}
+
+// applyHostingRecords deletes the records in remove, then creates records.
+// Failures are printed as they happen and counted in the returned error.
+func applyHostingRecords(z *zone, p *hostingPreset, records, remove []dnsRecord) error {
+	failed := 0
+	for _, r := range remove {
+		if err := deleteDNSRecord(z.ID, r); err != nil {
+			fmt.Printf("Could not delete %s %s: %v\n", r.Type, r.Name, err)
+			failed++
+		}
+	}
+	for _, r := range records {
+		r.TTL = 1
+		if _, err := addDNSRecord(z.Name, r); err != nil {
+			fmt.Printf("Could not add %s %s -> %s: %v\n", r.Type, r.Name, r.Content, err)
+			failed++
+		}
+	}
+	if p.Note != "" {
+		fmt.Println("Next: " + p.Note)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %s record changes failed", failed, len(remove)+len(records), p.Name)
+	}
+	return nil
+}
+
+// hostingClashes returns the A, AAAA and CNAME records at the apex and www,
+// which a hosting preset replaces.
+func hostingClashes(zoneName string, live []dnsRecord) []dnsRecord {
+	var out []dnsRecord
+	for _, r := range live {
+		switch r.Type {
+		case "A", "AAAA", "CNAME":
+		default:
+			continue
+		}
+		if strings.EqualFold(r.Name, zoneName) || strings.EqualFold(r.Name, "www."+zoneName) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
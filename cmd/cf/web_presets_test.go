@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"cf/internal/cftest"
+)
+
+func TestHostingPresetRecords(t *testing.T) {
+	tests := []struct {
+		key, target string
+		want        []string
+	}{
+		{"netlify", "my-site", []string{"A @ 75.2.60.5", "CNAME www my-site.netlify.app"}},
+		{"vercel", "", []string{"A @ 76.76.21.21", "CNAME www cname.vercel-dns.com"}},
+		{"cloudflare-pages", "blog.pages.dev", []string{"CNAME @ blog.pages.dev", "CNAME www blog.pages.dev"}},
+		{"ip", "2001:db8::1", []string{"AAAA @ 2001:db8::1", "CNAME www example.com"}},
+	}
+	for _, tt := range tests {
+		p, err := findHostingPreset(tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := p.Records("example.com", tt.target)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.key, err)
+		}
+		var got []string
+		for _, r := range records {
+			got = append(got, r.Type+" "+r.Name+" "+r.Content)
+		}
+		if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+			t.Fatalf("%s: got %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	p, _ := findHostingPreset("github-pages")
+	records, _ := p.Records("example.com", "octocat")
+	if len(records) != 9 || records[8].Content != "octocat.github.io" {
+		t.Fatalf("unexpected GitHub Pages records: %v", records)
+	}
+	if _, err := p.Records("example.com", ""); err != nil {
+		t.Fatal(err)
+	}
+	ip, _ := findHostingPreset("ip")
+	if _, err := ip.Records("example.com", "not-an-ip"); err == nil {
+		t.Fatal("expected an error for a bad IP")
+	}
+	if _, err := findHostingPreset("geocities"); err == nil {
+		t.Fatal("expected an error for an unknown preset")
+	}
+}
+
+func TestWizardHostingPresetReplacesClashes(t *testing.T) {
+	srv := useFakeAPI(t)
+	live := []dnsRecord{
+		{ID: "old-a", Type: "A", Name: "example.com", Content: "192.0.2.1"},
+		{ID: "mx", Type: "MX", Name: "example.com", Content: "mx.example.net"},
+	}
+	srv.Handle("GET", "/zones/z1/dns_records", func(cftest.Request) cftest.Response {
+		return cftest.Response{Result: live}
+	})
+	srv.Handle("DELETE", "/zones/z1/dns_records/*", func(r cftest.Request) cftest.Response {
+		id := r.Path[strings.LastIndex(r.Path, "/")+1:]
+		for i, rec := range live {
+			if rec.ID == id {
+				live = append(live[:i], live[i+1:]...)
+				break
+			}
+		}
+		return cftest.Response{Result: map[string]string{"id": id}}
+	})
+	srv.Handle("POST", "/zones/z1/dns_records", func(r cftest.Request) cftest.Response {
+		var rec dnsRecord
+		r.Decode(&rec)
+		rec.ID = "new"
+		return cftest.Response{Result: rec}
+	})
+
+	p, _ := findHostingPreset("cloudflare-pages")
+	reader := bufio.NewReader(strings.NewReader("blog\ny\n"))
+	out, err := captureStdout(t, func() error { return wizardHostingPreset(reader, "example.com", p) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(srv.Calls("DELETE", "/zones/z1/dns_records/old-a")); n != 1 {
+		t.Fatalf("expected the apex A record to be deleted once, got %d\n%s", n, out)
+	}
+	if n := len(srv.Calls("DELETE", "/zones/z1/dns_records/mx")); n != 0 {
+		t.Fatal("the MX record should be left alone")
+	}
+	posts := srv.Calls("POST", "/zones/z1/dns_records")
+	if len(posts) != 2 {
+		t.Fatalf("expected two records created, got %d\n%s", len(posts), out)
+	}
+	var body map[string]any
+	posts[0].Decode(&body)
+	if body["type"] != "CNAME" || body["content"] != "blog.pages.dev" || body["proxied"] != true {
+		t.Fatalf("unexpected request body: %v", body)
+	}
+	if !strings.Contains(out, "Custom domains") {
+		t.Fatalf("missing follow-up note: %q", out)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestWSAcceptKey(t *testing.T) {
+	// Example from RFC 6455 section 1.3.
+	if got := wsAcceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected accept key: %s", got)
+	}
+}
+
+func TestWSFrames(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	w := &wsConn{conn: client, br: bufio.NewReader(client)}
+	r := &wsConn{conn: server, br: bufio.NewReader(server)}
+
+	long := strings.Repeat("x", 70000)
+	go func() {
+		w.writeText([]byte("hello"))
+		w.writeFrame(wsOpPong, nil)
+		w.writeText([]byte(long))
+		w.writeFrame(wsOpClose, nil)
+	}()
+
+	for _, want := range []string{"hello", long} {
+		got, err := r.readMessage()
+		if err != nil || string(got) != want {
+			t.Fatalf("readMessage = %d bytes, %v; want %d bytes", len(got), err, len(want))
+		}
+	}
+	go io.Copy(io.Discard, client)
+	if _, err := r.readMessage(); err != io.EOF {
+		t.Fatalf("expected io.EOF after close frame, got %v", err)
+	}
+}
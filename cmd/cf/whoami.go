@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type tokenPolicy struct {
+	ID               string         `json:"id"`
+	Effect           string         `json:"effect"`
+	Resources        map[string]any `json:"resources"`
+	PermissionGroups []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"permission_groups"`
+}
+
+type whoAmI struct {
+	AuthSource  string             `json:"auth_source"`
+	Email       string             `json:"email,omitempty"`
+	Token       *tokenVerification `json:"token,omitempty"`
+	TokenError  string             `json:"token_error,omitempty"`
+	Policies    []tokenPolicy      `json:"policies,omitempty"`
+	PolicyError string             `json:"policy_error,omitempty"`
+	AccountID   string             `json:"account_id,omitempty"`
+	AccountErr  string             `json:"account_error,omitempty"`
+	Memberships []membership       `json:"memberships,omitempty"`
+	MemberErr   string             `json:"memberships_error,omitempty"`
+}
+
+// runWhoAmI reports what the permission-error explainer only shows on
+// failure: where credentials come from, what they can do, and which account
+// commands will run against. Individual lookups that fail are reported
+// rather than aborting the whole report.
+func runWhoAmI() error {
+	creds, err := resolveCredentials()
+	if err != nil {
+		return err
+	}
+
+	out := whoAmI{AuthSource: authSource}
+
+	if creds.APIKey != "" {
+		// Global API keys have no verify endpoint; /user succeeds only with a
+		// valid key and email pair.
+		if _, err := requestCFWithCredentials(creds, http.MethodGet, "/user", nil); err != nil {
+			out.TokenError = err.Error()
+		} else {
+			out.Email = creds.Email
+		}
+	} else if v, err := verifyToken(creds.Token); err != nil {
+		// Wrangler OAuth tokens are not API tokens and cannot be verified.
+		out.TokenError = err.Error()
+	} else {
+		out.Token = v
+		if policies, err := tokenPolicies(creds.Token, v.ID); err != nil {
+			out.PolicyError = err.Error()
+		} else {
+			out.Policies = policies
+		}
+	}
+
+	if memberships, err := listMemberships(creds); err != nil {
+		out.MemberErr = err.Error()
+	} else {
+		out.Memberships = memberships
+	}
+
+	if accountID, err := configuredAccountID(); err != nil {
+		out.AccountErr = err.Error()
+	} else {
+		out.AccountID = accountID
+	}
+
+	return printResult(out, func() { printWhoAmI(out) })
+}
+
+// tokenPolicies reads the token's own policies. This needs the token to
+// carry "API Tokens Read", which many scoped tokens do not.
+func tokenPolicies(token, tokenID string) ([]tokenPolicy, error) {
+	resp, err := requestCFWithToken(token, http.MethodGet, "/user/tokens/"+tokenID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var details struct {
+		Policies []tokenPolicy `json:"policies"`
+	}
+	if err := json.Unmarshal(resp.Result, &details); err != nil {
+		return nil, err
+	}
+	return details.Policies, nil
+}
+
+func printWhoAmI(out whoAmI) {
+	fmt.Printf("Auth source: %s\n", out.AuthSource)
+
+	if out.Email != "" {
+		fmt.Printf("Legacy API key: valid for %s (full account access; prefer a scoped API token)\n", out.Email)
+	} else if out.Token != nil {
+		fmt.Printf("Token: id=%s  status=%s", out.Token.ID, out.Token.Status)
+		if out.Token.ExpiresOn != "" {
+			fmt.Printf("  expires_on=%s", out.Token.ExpiresOn)
+		}
+		fmt.Println()
+	} else {
+		fmt.Printf("Token: could not verify (%s)\n", out.TokenError)
+	}
+
+	switch {
+	case out.PolicyError != "":
+		fmt.Printf("Permissions: unavailable (%s)\n", out.PolicyError)
+		fmt.Println("  Grant the token \"API Tokens Read\" to inspect its permissions here.")
+	case out.Token != nil:
+		fmt.Println("Permissions:")
+		for _, p := range out.Policies {
+			names := make([]string, 0, len(p.PermissionGroups))
+			for _, g := range p.PermissionGroups {
+				names = append(names, g.Name)
+			}
+			resources := make([]string, 0, len(p.Resources))
+			for r := range p.Resources {
+				resources = append(resources, r)
+			}
+			sort.Strings(resources)
+			fmt.Printf("  %s: %s\n", p.Effect, strings.Join(names, ", "))
+			fmt.Printf("    on: %s\n", strings.Join(resources, ", "))
+		}
+	}
+
+	if out.AccountErr != "" {
+		fmt.Printf("Account: unresolved (%s)\n", out.AccountErr)
+	} else {
+		fmt.Printf("Account: %s\n", out.AccountID)
+	}
+
+	if out.MemberErr != "" {
+		fmt.Printf("Memberships: unavailable (%s)\n", out.MemberErr)
+		return
+	}
+	fmt.Println("Memberships:")
+	for _, m := range out.Memberships {
+		marker := " "
+		if m.Account.ID == out.AccountID {
+			marker = "*"
+		}
+		fmt.Printf("  %s %s (%s)  roles=%s  status=%s\n", marker, m.Account.Name, m.Account.ID, strings.Join(m.Roles, ","), m.Status)
+	}
+}
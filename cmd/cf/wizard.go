@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func printWizardHelp() {
+	fmt.Print(`cf wizard: guided flow to add a domain to Cloudflare
+
+What it does:
+  1. Ask for the domain name
+  2. If not registered, check availability/pricing and show dashboard registration URL
+     (and optionally open browser)
+  3. Add the domain as a Cloudflare zone, then show the current registrar
+     (looked up with RDAP) and where it keeps its nameserver settings
+     With --wait (or if you say yes when asked) it polls until the zone is
+     active, every --interval (default 1m) for up to --timeout (default 2h)
+  4. Optionally create the MX and SPF records for a mail provider
+     (google, microsoft, fastmail), warning about MX and SPF records it
+     replaces; otherwise offer email forwarding with Email Routing
+  5. Optionally create the DNS records for a website host in one step:
+     github-pages, netlify, vercel, cloudflare-pages, or ip (your own server)
+  6. Optionally set the SSL/TLS encryption mode
+  7. Optionally add further DNS records interactively
+  8. Verify the result: nameserver delegation and the new records as
+     public DNS (1.1.1.1) sees them, HTTPS on the configured hosts, and the
+     SSL certificate, marking each check ok, pending (still propagating)
+     or FAIL
+
+Unattended:
+  cf wizard --non-interactive --answers onboard.yaml runs the same steps
+  from a YAML file; flags such as --domain and --website override it.
+  Steps without answers are skipped and the first failure stops the run.
+
+    domain: example.com
+    zone: {type: full, scan: true, wait: false}
+    website: {preset: github-pages, target: octocat, replace: true}
+    ssl: strict
+    email: {provider: google-workspace}   # or forward_from/forward_to
+    records:
+      - {type: TXT, name: "@", content: "site-verification=abc"}
+
+What it does not do:
+  - It does not fully automate purchasing/registering a new domain via API.
+    Domain purchase still happens in Cloudflare Dashboard.
+`)
+}
+
+// wizardOptions come from cf wizard's flags.
+type wizardOptions struct {
+	wait     bool
+	interval time.Duration
+	timeout  time.Duration
+}
+
+func parseWizardOptions(flags map[string]string) (wizardOptions, error) {
+	opts := wizardOptions{wait: parseBoolWithDefault(flags["wait"], false)}
+	var err error
+	if opts.interval, err = parseDurationWithDefault(flags["interval"], time.Minute); err != nil {
+		return opts, usageErrorf("invalid --interval: %w", err)
+	}
+	if opts.timeout, err = parseDurationWithDefault(flags["timeout"], 2*time.Hour); err != nil {
+		return opts, usageErrorf("invalid --timeout: %w", err)
+	}
+	return opts, nil
+}
+
+func runWizard(opts wizardOptions) error {
+	reader := bufio.NewReader(os.Stdin)
+	domain, err := prompt(reader, "Domain you want to onboard (example.com)", "")
+	if err != nil {
+		return err
+	}
+	if domain == "" {
+		return usageErrorf("domain is required")
+	}
+
+	alreadyRegistered, err := promptYesNo(reader, "Is this domain already registered somewhere?", true)
+	if err != nil {
+		return err
+	}
+
+	if !alreadyRegistered {
+		if a, err := checkDomainAvailability(domain); err != nil {
+			fmt.Printf("Could not check availability through Cloudflare Registrar: %v\n", err)
+		} else {
+			printDomainAvailability(a)
+			if !a.SupportedTLD {
+				fmt.Println("Cloudflare Registrar cannot register this TLD; register it elsewhere and continue with zone setup.")
+			}
+		}
+
+		dashboardURL := "https://dash.cloudflare.com/?to=/:account/domains"
+		fmt.Println("\nManual step required: register domain in Cloudflare Dashboard:")
+		fmt.Println(dashboardURL)
+
+		openNow, err := promptYesNo(reader, "Open the dashboard URL in your browser now?", true)
+		if err != nil {
+			return err
+		}
+		if openNow {
+			if err := openURL(dashboardURL); err != nil {
+				fmt.Printf("Could not open browser automatically: %v\n", err)
+			} else {
+				fmt.Println("Opened browser tab.")
+			}
+		}
+
+		if _, err := prompt(reader, "Press Enter when registration is complete and you want to continue", ""); err != nil {
+			return err
+		}
+	}
+
+	addZoneNow, err := promptYesNo(reader, fmt.Sprintf("Add %s as a zone in Cloudflare now?", domain), true)
+	if err != nil {
+		return err
+	}
+	if addZoneNow {
+		scan, err := promptYesNo(reader, "Scan the domain's current DNS and import the records found?", true)
+		if err != nil {
+			return err
+		}
+		z, err := addZone(domain, "full", scan)
+		if err != nil {
+			return err
+		}
+		if z != nil && z.Status != "active" && z.Type != "partial" {
+			wizardNameserverGuidance(z)
+			wait := opts.wait
+			if !wait {
+				if wait, err = promptYesNo(reader, "Wait here until the zone is active?", false); err != nil {
+					return err
+				}
+			}
+			if wait {
+				if err := waitForZoneActive(z, opts.interval, opts.timeout); err != nil {
+					fmt.Printf("%v\nContinuing; rerun cf zones check --zone %s later.\n", err, z.Name)
+				}
+			}
+		}
+	}
+
+	hostedMail, err := wizardMailProvider(reader, domain)
+	if err != nil {
+		fmt.Printf("Mail provider setup failed: %v\n", err)
+	}
+	if !hostedMail {
+		if err := wizardEmailForwarding(reader, domain); err != nil {
+			fmt.Printf("Email forwarding setup failed: %v\n", err)
+		}
+	}
+
+	if err := wizardWebsite(reader, domain); err != nil {
+		return err
+	}
+
+	if err := wizardSSLMode(reader, domain); err != nil {
+		fmt.Printf("SSL/TLS mode not changed: %v\n", err)
+	}
+
+	var addedNames []string
+	for {
+		addRecord, err := promptYesNo(reader, "Add a DNS record now?", true)
+		if err != nil {
+			return err
+		}
+		if !addRecord {
+			break
+		}
+
+		zoneName, err := prompt(reader, "Zone name", domain)
+		if err != nil {
+			return err
+		}
+		typeName, err := prompt(reader, "Record type", "A")
+		if err != nil {
+			return err
+		}
+		name, err := prompt(reader, "Record name", "@")
+		if err != nil {
+			return err
+		}
+		content, err := prompt(reader, "Record content (IP or hostname)", "")
+		if err != nil {
+			return err
+		}
+		ttlRaw, err := prompt(reader, "TTL (1 means auto)", "1")
+		if err != nil {
+			return err
+		}
+		ttl, err := strconv.Atoi(ttlRaw)
+		if err != nil {
+			return fmt.Errorf("invalid TTL: %w", err)
+		}
+		proxied, err := promptYesNo(reader, "Proxied through Cloudflare (orange cloud)?", false)
+		if err != nil {
+			return err
+		}
+
+		if _, err := addDNSRecord(zoneName, dnsRecord{Type: strings.ToUpper(typeName), Name: name, Content: content, TTL: ttl, Proxied: proxied}); err != nil {
+			return err
+		}
+		if strings.EqualFold(zoneName, domain) {
+			addedNames = append(addedNames, name)
+		}
+	}
+
+	if z, err := getZoneByName(domain); err == nil && z != nil {
+		runSetupVerification(z, addedNames)
+	}
+	fmt.Println("\nWizard complete.")
+	return nil
+}
+
+// wizardWebsite offers the hosting presets until one is chosen or the user
+// skips.
+func wizardWebsite(reader *bufio.Reader, domain string) error {
+	for {
+		key, err := prompt(reader, fmt.Sprintf("Website host to set up DNS for (%s; Enter to skip)", strings.Join(hostingPresetKeys(), ", ")), "")
+		if err != nil || key == "" {
+			return err
+		}
+		p, err := findHostingPreset(key)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		return wizardHostingPreset(reader, domain, p)
+	}
+}
+
+func wizardSSLMode(reader *bufio.Reader, domain string) error {
+	mode, err := prompt(reader, fmt.Sprintf("SSL/TLS encryption mode (%s; Enter to keep the current one)", strings.Join(sslModes, ", ")), "")
+	if err != nil || mode == "" {
+		return err
+	}
+	z, err := requireZone(domain)
+	if err != nil {
+		return err
+	}
+	if _, err := setSSLMode(z, mode); err != nil {
+		return err
+	}
+	fmt.Printf("SSL/TLS encryption mode for %s: %s\n", z.Name, mode)
+	return nil
+}
+
+// wizardNameserverGuidance explains how to point the domain at Cloudflare,
+// with registrar-specific steps when RDAP names the registrar.
+func wizardNameserverGuidance(z *zone) {
+	fmt.Println("\nNext: point the domain at Cloudflare's nameservers.")
+	registrar, dnssec := "", false
+	if w, err := whoisDomain(z.Name); err != nil {
+		fmt.Printf("Could not look up the current registrar: %v\n", err)
+	} else {
+		registrar, dnssec = w.Registrar, w.DNSSEC
+		if registrar != "" {
+			fmt.Printf("Registrar (RDAP): %s\n", registrar)
+		}
+		if len(w.Nameservers) > 0 {
+			fmt.Printf("Current nameservers: %s\n", strings.Join(w.Nameservers, ", "))
+		}
+	}
+	fmt.Printf("Set the nameservers to exactly: %s\n", strings.Join(z.NameServers, ", "))
+	if g, ok := findRegistrarGuide(registrar); ok {
+		fmt.Printf("At %s: %s\n  %s\n", g.Name, g.Steps, g.URL)
+	} else {
+		fmt.Println("At your registrar, open the domain's settings, find \"Nameservers\" and choose custom nameservers.")
+	}
+	if dnssec {
+		fmt.Println("DNSSEC is on for this domain: turn it off at the registrar first, or the domain will stop resolving after the switch.")
+	}
+	fmt.Println("Remove any other nameservers, and turn DNSSEC off at the registrar until the zone is active.")
+}
+
+// waitForZoneActive polls until Cloudflare marks the zone active. It asks for
+// an activation check as soon as live DNS shows the new nameservers, since
+// the check endpoint is rate limited.
+func waitForZoneActive(z *zone, interval, timeout time.Duration) error {
+	fmt.Printf("Waiting for %s to become active (checking every %s, up to %s; Ctrl-C to stop)...\n", z.Name, interval, timeout)
+	deadline := time.Now().Add(timeout)
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return err
+	}
+	assigned := normalizeNameservers(z.NameServers)
+	requested := false
+	last := ""
+	for {
+		current, err := fetchZoneByName(accountID, z.Name)
+		if err != nil {
+			return err
+		}
+		if current != nil && current.Status == "active" {
+			fmt.Printf("Zone %s is active.\n", z.Name)
+			return nil
+		}
+
+		// A failed lookup says nothing about the delegation; skip this round
+		// rather than report the nameservers as gone.
+		ctx, cancel := context.WithTimeout(rootCtx, 10*time.Second)
+		delegated, err := delegatedNameservers(ctx, z.Name)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  %v\n", err)
+		} else if summary := strings.Join(delegated, ", "); summary != last {
+			fmt.Printf("  nameservers in live DNS: %s\n", orNone(summary))
+			last = summary
+		}
+		if ok, _, _ := compareNameservers(assigned, delegated); ok && err == nil && !requested {
+			if _, err := requestCF(http.MethodPut, "/zones/"+z.ID+"/activation_check", nil); err == nil {
+				fmt.Println("  delegation looks right; asked Cloudflare to re-check activation")
+			}
+			requested = true
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("zone %s not active after %s", z.Name, timeout)
+		}
+		if err := sleep(interval); err != nil {
+			return err
+		}
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// wizardAnswers drives cf wizard --non-interactive. Flags override the
+// answers file; steps without answers are skipped.
+type wizardAnswers struct {
+	Domain string `yaml:"domain"`
+	Zone   struct {
+		Type string `yaml:"type"`
+		Scan *bool  `yaml:"scan"`
+		Wait bool   `yaml:"wait"`
+	} `yaml:"zone"`
+	Website struct {
+		Preset  string `yaml:"preset"`
+		Target  string `yaml:"target"`
+		Replace bool   `yaml:"replace"`
+	} `yaml:"website"`
+	SSL   string `yaml:"ssl"`
+	Email struct {
+		Provider    string `yaml:"provider"`
+		ForwardFrom string `yaml:"forward_from"`
+		ForwardTo   string `yaml:"forward_to"`
+	} `yaml:"email"`
+	Records []dnsSyncRecord `yaml:"records"`
+}
+
+// readWizardAnswers loads path, rejecting unknown keys so a typo does not
+// silently skip a step.
+func readWizardAnswers(path string) (*wizardAnswers, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var a wizardAnswers
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&a); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return &a, nil
+}
+
+// wizardAnswersFrom reads --answers, if given, and applies the answer flags
+// on top.
+func wizardAnswersFrom(flags map[string]string) (*wizardAnswers, error) {
+	a := &wizardAnswers{}
+	if path := flags["answers"]; path != "" {
+		var err error
+		if a, err = readWizardAnswers(path); err != nil {
+			return nil, err
+		}
+	}
+	set := func(dst *string, flag string) {
+		if v := flags[flag]; v != "" {
+			*dst = v
+		}
+	}
+	set(&a.Domain, "domain")
+	set(&a.Zone.Type, "zone-type")
+	set(&a.Website.Preset, "website")
+	set(&a.Website.Target, "website-target")
+	set(&a.SSL, "ssl")
+	set(&a.Email.Provider, "mail-provider")
+	set(&a.Email.ForwardFrom, "forward-from")
+	set(&a.Email.ForwardTo, "forward-to")
+	if v := flags["scan"]; v != "" {
+		scan := parseBoolWithDefault(v, true)
+		a.Zone.Scan = &scan
+	}
+	a.Website.Replace = parseBoolWithDefault(flags["replace"], a.Website.Replace)
+	return a, a.validate()
+}
+
+func (a *wizardAnswers) validate() error {
+	if a.Domain == "" {
+		return usageErrorf("the wizard needs a domain when not run interactively: set domain in --answers or pass --domain")
+	}
+	switch a.Zone.Type {
+	case "":
+		a.Zone.Type = "full"
+	case "full", "partial":
+	default:
+		return usageErrorf("invalid zone type %q (want full or partial)", a.Zone.Type)
+	}
+	if a.Website.Preset != "" {
+		p, err := findHostingPreset(a.Website.Preset)
+		if err != nil {
+			return usageErrorf("%w", err)
+		}
+		if p.TargetPrompt != "" && a.Website.Target == "" {
+			return usageErrorf("the %s preset needs a target: %s", p.Key, p.TargetPrompt)
+		}
+	}
+	if a.SSL != "" && !slices.Contains(sslModes, a.SSL) {
+		return usageErrorf("invalid SSL/TLS mode %q (want one of: %s)", a.SSL, strings.Join(sslModes, ", "))
+	}
+	if a.Email.Provider != "" {
+		if a.Email.ForwardTo != "" {
+			return usageErrorf("choose either a mail provider or email forwarding, not both")
+		}
+		if _, err := findEmailProvider(a.Email.Provider); err != nil {
+			return usageErrorf("%w", err)
+		}
+	}
+	return nil
+}
+
+// runWizardUnattended runs the wizard's steps from answers, stopping at the
+// first step that fails.
+func runWizardUnattended(a *wizardAnswers, opts wizardOptions) error {
+	scan := a.Zone.Scan == nil || *a.Zone.Scan
+	z, err := addZone(a.Domain, a.Zone.Type, scan)
+	if err != nil {
+		return fmt.Errorf("zone: %w", err)
+	}
+	if z.ID == "" {
+		// Dry run: nothing was created, so the later steps have no zone.
+		return nil
+	}
+	if z.Status != "active" && z.Type != "partial" && (opts.wait || a.Zone.Wait) {
+		if err := waitForZoneActive(z, opts.interval, opts.timeout); err != nil {
+			return err
+		}
+	}
+
+	if a.SSL != "" {
+		if _, err := setSSLMode(z, a.SSL); err != nil {
+			return fmt.Errorf("ssl: %w", err)
+		}
+		infof("SSL/TLS encryption mode for %s: %s\n", z.Name, a.SSL)
+	}
+
+	if a.Website.Preset != "" {
+		if err := unattendedHostingPreset(z, a); err != nil {
+			return fmt.Errorf("website: %w", err)
+		}
+	}
+
+	switch {
+	case a.Email.Provider != "":
+		p, _ := findEmailProvider(a.Email.Provider)
+		if _, err := applyMailPreset(nil, z, p, map[string]string{"yes": "true"}); err != nil {
+			return fmt.Errorf("email: %w", err)
+		}
+	case a.Email.ForwardTo != "":
+		from := a.Email.ForwardFrom
+		if from == "" {
+			from = "hello@" + z.Name
+		}
+		if err := setUpEmailForwarding(z, from, a.Email.ForwardTo); err != nil {
+			return fmt.Errorf("email: %w", err)
+		}
+	}
+
+	if len(a.Records) > 0 {
+		records, err := desiredDNSRecords(z.Name, a.Records)
+		if err != nil {
+			return fmt.Errorf("records: %w", err)
+		}
+		for _, r := range records {
+			if _, err := addDNSRecord(z.Name, r); err != nil {
+				return fmt.Errorf("records: %s %s: %w", r.Type, r.Name, err)
+			}
+		}
+	}
+
+	extra := make([]string, len(a.Records))
+	for i, r := range a.Records {
+		extra[i] = r.Name
+	}
+	runSetupVerification(z, extra)
+	infof("\nWizard complete.\n")
+	return nil
+}
+
+// unattendedHostingPreset applies the website preset, refusing to touch
+// existing apex and www records unless the answers allow replacing them.
+func unattendedHostingPreset(z *zone, a *wizardAnswers) error {
+	p, _ := findHostingPreset(a.Website.Preset)
+	records, err := p.Records(z.Name, a.Website.Target)
+	if err != nil {
+		return err
+	}
+	live, err := listDNSRecords(z.ID)
+	if err != nil {
+		return err
+	}
+	clashes := hostingClashes(z.Name, live)
+	if len(clashes) > 0 && !a.Website.Replace {
+		names := make([]string, len(clashes))
+		for i, r := range clashes {
+			names[i] = r.Type + " " + r.Name
+		}
+		return errors.New("existing records would clash (" + strings.Join(names, ", ") + "); set website.replace: true or pass --replace to delete them")
+	}
+	return applyHostingRecords(z, p, records, clashes)
+}
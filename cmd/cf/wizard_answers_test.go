@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestWizardAnswersFrom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "onboard.yaml")
+	os.WriteFile(path, []byte(`domain: example.com
+zone: {scan: false}
+website: {preset: netlify, target: my-site}
+ssl: full
+email: {provider: google-workspace}
+`), 0o600)
+
+	a, err := wizardAnswersFrom(map[string]string{"answers": path, "ssl": "strict"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Domain != "example.com" || a.Zone.Type != "full" || *a.Zone.Scan || a.Website.Target != "my-site" || a.SSL != "strict" {
+		t.Fatalf("unexpected answers: %+v", a)
+	}
+
+	for _, flags := range []map[string]string{
+		{},
+		{"domain": "example.com", "ssl": "maybe"},
+		{"domain": "example.com", "website": "netlify"},
+		{"domain": "example.com", "mail-provider": "fastmail", "forward-to": "me@example.net"},
+	} {
+		if _, err := wizardAnswersFrom(flags); exitCode(err) != exitUsage {
+			t.Fatalf("%v: expected a usage error, got %v", flags, err)
+		}
+	}
+
+	os.WriteFile(path, []byte("domian: example.com\n"), 0o600)
+	if _, err := wizardAnswersFrom(map[string]string{"answers": path}); err == nil || !strings.Contains(err.Error(), "domian") {
+		t.Fatalf("expected an unknown-field error, got %v", err)
+	}
+}
+
+func TestRunWizardUnattended(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("POST", "/zones", zone{ID: "z1", Name: "example.com", Status: "pending", Type: "full"})
+	srv.Reply("PATCH", "/zones/z1/settings/ssl", map[string]string{"id": "ssl", "value": "strict"})
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{})
+	srv.Reply("POST", "/zones/z1/dns_records", dnsRecord{ID: "r1"})
+	srv.Reply("GET", "/zones/z1/ssl/certificate_packs", []certificatePack{})
+	useFakeVerification(t, nil, nil, 0)
+
+	a := &wizardAnswers{Domain: "example.com", SSL: "strict"}
+	a.Website.Preset = "vercel"
+	a.Email.Provider = "fastmail"
+	a.Records = []dnsSyncRecord{{Type: "TXT", Name: "@", Content: "site-verification=abc"}}
+	if err := a.validate(); err != nil {
+		t.Fatal(err)
+	}
+	out, err := captureStdout(t, func() error { return runWizardUnattended(a, wizardOptions{}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+
+	if n := len(srv.Calls("PATCH", "/zones/z1/settings/ssl")); n != 1 {
+		t.Fatalf("expected the SSL mode to be set once, got %d", n)
+	}
+	var contents []string
+	for _, r := range srv.Calls("POST", "/zones/z1/dns_records") {
+		var body dnsRecord
+		r.Decode(&body)
+		contents = append(contents, body.Type+" "+body.Content)
+	}
+	// The mail records are created concurrently.
+	sort.Strings(contents)
+	want := "A 76.76.21.21, CNAME cname.vercel-dns.com, MX in1-smtp.messagingengine.com, MX in2-smtp.messagingengine.com, TXT site-verification=abc, TXT v=spf1 include:spf.messagingengine.com ~all"
+	if strings.Join(contents, ", ") != want {
+		t.Fatalf("unexpected records created:\n%s", strings.Join(contents, "\n"))
+	}
+}
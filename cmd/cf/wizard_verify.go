@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// setupCheck is one line of the wizard's verification report. Status is
+// "ok", "pending" (expected to clear as DNS and certificates propagate) or
+// "fail".
+type setupCheck struct {
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type setupReport struct {
+	Zone   string       `json:"zone"`
+	Checks []setupCheck `json:"checks"`
+}
+
+// lookupPublic returns the answers Cloudflare's public resolver gives for
+// host, so the report shows what the rest of the internet sees rather than a
+// local cache. A and AAAA lookups follow CNAMEs.
+var lookupPublic = func(ctx context.Context, typeName, host string) ([]string, error) {
+	return lookupVia(ctx, "1.1.1.1", typeName, host)
+}
+
+// probeHTTPS fetches url and returns the response status. It is a variable
+// so tests can avoid the network.
+var probeHTTPS = func(ctx context.Context, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := outboundClient(15 * time.Second).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// verifySetup checks what the wizard configured: nameserver delegation, the
+// records at the apex, www and extraNames as public DNS sees them, HTTPS on
+// the hosts that have address records, and the zone's certificate.
+func verifySetup(z *zone, extraNames []string) (*setupReport, error) {
+	report := &setupReport{Zone: z.Name}
+	add := func(check, status, detail string) {
+		report.Checks = append(report.Checks, setupCheck{check, status, detail})
+	}
+	ctx, cancel := context.WithTimeout(rootCtx, time.Minute)
+	defer cancel()
+
+	if z.Type == "partial" {
+		found, _ := lookupTXT(ctx, partialVerificationName(z.Name))
+		if z.Status == "active" || (z.VerificationKey != "" && slices.Contains(found, z.VerificationKey)) {
+			add("Ownership TXT record", "ok", partialVerificationName(z.Name))
+		} else {
+			add("Ownership TXT record", "pending", "not visible yet at "+partialVerificationName(z.Name))
+		}
+	} else {
+		delegated, err := delegatedNameservers(ctx, z.Name)
+		if err != nil {
+			add("Nameserver delegation", "pending", err.Error())
+		} else if ok, _, _ := compareNameservers(normalizeNameservers(z.NameServers), delegated); ok {
+			add("Nameserver delegation", "ok", strings.Join(delegated, ", "))
+		} else {
+			add("Nameserver delegation", "pending", "delegated to "+orNone(strings.Join(delegated, ", "))+", want "+strings.Join(z.NameServers, ", "))
+		}
+	}
+
+	live, err := listDNSRecords(z.ID)
+	if err != nil {
+		return nil, err
+	}
+	var extra []string
+	for _, n := range extraNames {
+		extra = append(extra, strings.ToLower(qualifyRecordName(n, z.Name)))
+	}
+	names := append([]string{z.Name, "www." + z.Name}, extra...)
+	var webHosts []string
+	for _, r := range live {
+		if !slices.Contains(names, strings.ToLower(r.Name)) {
+			continue
+		}
+		switch r.Type {
+		case "A", "AAAA", "CNAME":
+			if !slices.Contains(webHosts, r.Name) {
+				webHosts = append(webHosts, r.Name)
+			}
+		case "MX":
+		case "TXT":
+			if !isSPF(r) && !slices.Contains(extra, strings.ToLower(r.Name)) {
+				continue
+			}
+		default:
+			continue
+		}
+		status, detail := checkPublicRecord(ctx, r)
+		add(fmt.Sprintf("DNS %s %s -> %s", r.Type, r.Name, r.Content), status, detail)
+	}
+
+	for _, host := range webHosts {
+		code, err := probeHTTPS(ctx, "https://"+host+"/")
+		switch {
+		case err != nil:
+			add("HTTPS "+host, "pending", "not reachable yet: "+err.Error())
+		case code >= 400:
+			add("HTTPS "+host, "fail", fmt.Sprintf("HTTP %d", code))
+		default:
+			add("HTTPS "+host, "ok", fmt.Sprintf("HTTP %d", code))
+		}
+	}
+
+	packs, err := listAll[certificatePack]("/zones/"+z.ID+"/ssl/certificate_packs?status=all", 50, 0)
+	switch {
+	case err != nil:
+		add("SSL certificate", "fail", err.Error())
+	case len(packs) == 0:
+		add("SSL certificate", "pending", "none issued yet; Universal SSL is issued once the zone is active")
+	default:
+		status, detail := "pending", ""
+		for _, p := range packs {
+			if p.Status == "active" {
+				status, detail = "ok", "active for "+strings.Join(p.Hosts, ", ")
+				break
+			}
+			detail = p.Status + " for " + strings.Join(p.Hosts, ", ")
+		}
+		add("SSL certificate", status, detail)
+	}
+	return report, nil
+}
+
+// checkPublicRecord compares r with public DNS. Proxied and CNAME records
+// answer with Cloudflare's addresses, so those only need to resolve.
+func checkPublicRecord(ctx context.Context, r dnsRecord) (string, string) {
+	typeName := r.Type
+	want := strings.ToLower(strings.TrimSuffix(unquoteTXT(r.Content), "."))
+	if r.Proxied || r.Type == "CNAME" {
+		typeName, want = "A", ""
+	}
+	found, err := lookupPublic(ctx, typeName, r.Name)
+	if err != nil && len(found) == 0 {
+		return "pending", "not visible in public DNS yet"
+	}
+	for _, f := range found {
+		if want == "" || strings.EqualFold(strings.TrimSuffix(unquoteTXT(f), "."), want) {
+			return "ok", "resolves to " + strings.Join(found, ", ")
+		}
+	}
+	return "pending", "public DNS still answers " + orNone(strings.Join(found, ", "))
+}
+
+func runSetupVerification(z *zone, extraNames []string) {
+	infof("\nVerifying the setup (public DNS, HTTPS, SSL)...\n")
+	report, err := verifySetup(z, extraNames)
+	if err != nil {
+		fmt.Printf("Verification failed: %v\n", err)
+		return
+	}
+	if err := printResult(report, func() { printSetupReport(report) }); err != nil {
+		fmt.Println(err)
+	}
+}
+
+func printSetupReport(r *setupReport) {
+	pending := 0
+	for _, c := range r.Checks {
+		label := map[string]string{"ok": "  ok   ", "pending": "pending", "fail": " FAIL  "}[c.Status]
+		fmt.Printf("[%s] %s", colorStatus(c.Status, label), c.Check)
+		if c.Detail != "" {
+			fmt.Printf(": %s", c.Detail)
+		}
+		fmt.Println()
+		if c.Status == "pending" {
+			pending++
+		}
+	}
+	if pending > 0 {
+		fmt.Printf("%d check(s) still propagating; DNS changes can take up to 48 hours. Recheck with: cf zones check --zone %s\n", pending, r.Zone)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+// useFakeVerification stubs the public DNS and HTTPS probes the
+// verification report makes.
+func useFakeVerification(t *testing.T, ns []string, public map[string][]string, status int) {
+	t.Helper()
+	origNS, origPublic, origProbe := lookupNS, lookupPublic, probeHTTPS
+	t.Cleanup(func() { lookupNS, lookupPublic, probeHTTPS = origNS, origPublic, origProbe })
+	lookupNS = func(context.Context, string) ([]*net.NS, error) {
+		out := make([]*net.NS, len(ns))
+		for i, h := range ns {
+			out[i] = &net.NS{Host: h}
+		}
+		return out, nil
+	}
+	lookupPublic = func(_ context.Context, typeName, host string) ([]string, error) {
+		if found, ok := public[typeName+" "+host]; ok {
+			return found, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	probeHTTPS = func(_ context.Context, url string) (int, error) {
+		if status == 0 {
+			return 0, errors.New("connection refused")
+		}
+		return status, nil
+	}
+}
+
+func TestVerifySetup(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{
+		{ID: "1", Type: "A", Name: "example.com", Content: "192.0.2.1"},
+		{ID: "2", Type: "CNAME", Name: "www.example.com", Content: "example.com", Proxied: true},
+		{ID: "3", Type: "MX", Name: "example.com", Content: "smtp.google.com"},
+		{ID: "4", Type: "TXT", Name: "example.com", Content: "google-site-verification=abc"},
+		{ID: "5", Type: "A", Name: "other.example.com", Content: "192.0.2.9"},
+	})
+	srv.Reply("GET", "/zones/z1/ssl/certificate_packs", []certificatePack{{ID: "p1", Status: "pending_validation", Hosts: []string{"example.com", "*.example.com"}}})
+	useFakeVerification(t, []string{"old.registrar-dns.net"}, map[string][]string{
+		"A example.com":     {"192.0.2.1"},
+		"A www.example.com": {"104.16.0.1"},
+		"MX example.com":    {"mx.old-host.net"},
+	}, 200)
+
+	z := &zone{ID: "z1", Name: "example.com", Status: "pending", NameServers: []string{"ada.ns.cloudflare.com", "bob.ns.cloudflare.com"}}
+	report, err := verifySetup(z, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, c := range report.Checks {
+		got = append(got, c.Status+" "+c.Check)
+	}
+	want := []string{
+		"pending Nameserver delegation",
+		"ok DNS A example.com -> 192.0.2.1",
+		"ok DNS CNAME www.example.com -> example.com",
+		"pending DNS MX example.com -> smtp.google.com",
+		"ok HTTPS example.com",
+		"ok HTTPS www.example.com",
+		"pending SSL certificate",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected checks:\n%s", strings.Join(got, "\n"))
+	}
+
+	out, _ := captureStdout(t, func() error { printSetupReport(report); return nil })
+	if !strings.Contains(out, "[pending] Nameserver delegation") || !strings.Contains(out, "3 check(s) still propagating") {
+		t.Fatalf("unexpected report:\n%s", out)
+	}
+}
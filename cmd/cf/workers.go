@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type workerScript struct {
+	ID         string `json:"id"`
+	CreatedOn  string `json:"created_on,omitempty"`
+	ModifiedOn string `json:"modified_on,omitempty"`
+	UsageModel string `json:"usage_model,omitempty"`
+	Etag       string `json:"etag,omitempty"`
+}
+
+func runWorkers(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf workers list|deploy|delete|routes|cron|tail")
+	}
+	positional, flags := splitArgs(args[1:])
+	switch args[0] {
+	case "list":
+		return listWorkers()
+	case "deploy":
+		if len(positional) == 0 || flags["file"] == "" {
+			return usageErrorf("usage: cf workers deploy <name> --file <worker.js> [--compatibility-date <YYYY-MM-DD>]")
+		}
+		return deployWorker(positional[0], flags["file"], flags["compatibility-date"])
+	case "delete":
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf workers delete <name> [--force]")
+		}
+		return deleteWorker(positional[0], flags)
+	case "routes":
+		return runWorkerRoutes(args[1:])
+	case "cron":
+		return runWorkerCron(args[1:])
+	case "tail":
+		return runWorkerTail(args[1:])
+	}
+	return errors.New("unknown workers command. run: cf help")
+}
+
+func workersPath(name string) (string, error) {
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return "", err
+	}
+	path := "/accounts/" + accountID + "/workers/scripts"
+	if name != "" {
+		path += "/" + url.PathEscape(name)
+	}
+	return path, nil
+}
+
+func listWorkers() error {
+	path, err := workersPath("")
+	if err != nil {
+		return err
+	}
+	resp, err := requestCF(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+
+	var scripts []workerScript
+	if err := json.Unmarshal(resp.Result, &scripts); err != nil {
+		return err
+	}
+
+	t := table{Headers: []string{"NAME", "MODIFIED", "USAGE MODEL"}}
+	for _, s := range scripts {
+		t.Rows = append(t.Rows, []string{s.ID, s.ModifiedOn, s.UsageModel})
+	}
+	return printList(scripts, t, func() {
+		if len(scripts) == 0 {
+			fmt.Println("No Worker scripts found.")
+			return
+		}
+		for _, s := range scripts {
+			fmt.Printf("%s\t%s\n", s.ID, s.ModifiedOn)
+		}
+	})
+}
+
+var esModuleExport = regexp.MustCompile(`(?m)^\s*export\s+default\b`)
+
+// workerUpload builds the multipart body for PUT /workers/scripts/:name.
+// Scripts with an `export default` are uploaded as ES modules; anything else
+// uses the service-worker format.
+func workerUpload(filename string, source []byte, compatibilityDate string) ([]byte, string, error) {
+	if compatibilityDate == "" {
+		compatibilityDate = time.Now().UTC().Format("2006-01-02")
+	}
+	partName := filepath.Base(filename)
+	metadata := map[string]any{"compatibility_date": compatibilityDate}
+	contentType := "application/javascript"
+	if esModuleExport.Match(source) || strings.HasSuffix(partName, ".mjs") {
+		metadata["main_module"] = partName
+		contentType = "application/javascript+module"
+	} else {
+		metadata["body_part"] = partName
+	}
+
+	meta, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="metadata"; filename="metadata.json"`)
+	h.Set("Content-Type", "application/json")
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return nil, "", err
+	}
+	part.Write(meta)
+
+	h = textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, partName, partName))
+	h.Set("Content-Type", contentType)
+	part, err = w.CreatePart(h)
+	if err != nil {
+		return nil, "", err
+	}
+	part.Write(source)
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
+
+func deployWorker(name, file, compatibilityDate string) error {
+	source, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	body, contentType, err := workerUpload(file, source, compatibilityDate)
+	if err != nil {
+		return err
+	}
+
+	path, err := workersPath(name)
+	if err != nil {
+		return err
+	}
+	resp, err := requestCFRaw(http.MethodPut, path, contentType, body)
+	if err != nil {
+		return err
+	}
+
+	var s workerScript
+	if err := json.Unmarshal(resp.Result, &s); err != nil {
+		return err
+	}
+	if s.ID == "" {
+		s.ID = name
+	}
+	return printResult(s, func() {
+		fmt.Printf("Worker deployed: %s (%d bytes)\n", s.ID, len(source))
+	})
+}
+
+func deleteWorker(name string, flags map[string]string) error {
+	ok, err := confirm(flags, "workers delete", fmt.Sprintf("Delete Worker %s?", name))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted. Nothing deleted.")
+		return nil
+	}
+
+	path, err := workersPath(name)
+	if err != nil {
+		return err
+	}
+	if _, err := requestCF(http.MethodDelete, path, nil); err != nil {
+		return err
+	}
+	return printResult(workerScript{ID: name}, func() {
+		fmt.Printf("Worker deleted: %s\n", name)
+	})
+}
+
+type workerRoute struct {
+	ID      string `json:"id"`
+	Pattern string `json:"pattern"`
+	Script  string `json:"script,omitempty"`
+}
+
+func runWorkerRoutes(args []string) error {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "--") {
+		action, args = args[0], args[1:]
+	}
+	flags := parseFlags(args)
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for workers routes: --zone")
+	}
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	path := "/zones/" + z.ID + "/workers/routes"
+
+	switch action {
+	case "list":
+		routes, err := listWorkerRoutes(z.ID)
+		if err != nil {
+			return err
+		}
+		t := table{Headers: []string{"ID", "PATTERN", "SCRIPT"}}
+		for _, r := range routes {
+			t.Rows = append(t.Rows, []string{r.ID, r.Pattern, r.Script})
+		}
+		return printList(routes, t, func() {
+			if len(routes) == 0 {
+				fmt.Printf("No Worker routes on %s.\n", z.Name)
+				return
+			}
+			for _, r := range routes {
+				script := r.Script
+				if script == "" {
+					script = "(disabled)"
+				}
+				fmt.Printf("%s\t%s -> %s\n", r.ID, r.Pattern, script)
+			}
+		})
+	case "add":
+		if flags["pattern"] == "" || flags["script"] == "" {
+			return usageErrorf("missing required flags for workers routes add: --pattern --script")
+		}
+		resp, err := requestCF(http.MethodPost, path, map[string]string{"pattern": flags["pattern"], "script": flags["script"]})
+		if err != nil {
+			return err
+		}
+		r := workerRoute{Pattern: flags["pattern"], Script: flags["script"]}
+		if err := json.Unmarshal(resp.Result, &r); err != nil {
+			return err
+		}
+		return printResult(r, func() {
+			fmt.Printf("Route added: %s -> %s (id=%s)\n", r.Pattern, r.Script, r.ID)
+		})
+	case "delete":
+		id := flags["id"]
+		if id == "" && flags["pattern"] == "" {
+			return usageErrorf("missing required flag for workers routes delete: --id or --pattern")
+		}
+		if id == "" {
+			routes, err := listWorkerRoutes(z.ID)
+			if err != nil {
+				return err
+			}
+			for _, r := range routes {
+				if r.Pattern == flags["pattern"] {
+					id = r.ID
+					break
+				}
+			}
+			if id == "" {
+				return fmt.Errorf("no route with pattern %q on %s", flags["pattern"], z.Name)
+			}
+		}
+		if _, err := requestCF(http.MethodDelete, path+"/"+url.PathEscape(id), nil); err != nil {
+			return err
+		}
+		return printResult(workerRoute{ID: id, Pattern: flags["pattern"]}, func() {
+			fmt.Printf("Route deleted: %s\n", id)
+		})
+	}
+	return usageErrorf("usage: cf workers routes list|add|delete --zone <zone>")
+}
+
+func listWorkerRoutes(zoneID string) ([]workerRoute, error) {
+	resp, err := requestCF(http.MethodGet, "/zones/"+zoneID+"/workers/routes", nil)
+	if err != nil {
+		return nil, err
+	}
+	var routes []workerRoute
+	if err := json.Unmarshal(resp.Result, &routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
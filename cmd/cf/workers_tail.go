@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+type workerTail struct {
+	ID        string `json:"id"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// tailEvent is the subset of a trace-v1 message cf prints.
+type tailEvent struct {
+	Outcome        string `json:"outcome"`
+	ScriptName     string `json:"scriptName"`
+	EventTimestamp int64  `json:"eventTimestamp"`
+	Event          *struct {
+		Request *struct {
+			URL    string `json:"url"`
+			Method string `json:"method"`
+		} `json:"request"`
+		Response *struct {
+			Status int `json:"status"`
+		} `json:"response"`
+		Cron string `json:"cron"`
+	} `json:"event"`
+	Logs []struct {
+		Level     string `json:"level"`
+		Message   []any  `json:"message"`
+		Timestamp int64  `json:"timestamp"`
+	} `json:"logs"`
+	Exceptions []struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+	} `json:"exceptions"`
+}
+
+// tailOutcomes maps --status values to the outcomes the tail filter
+// understands.
+var tailOutcomes = map[string][]string{
+	"ok":       {"ok"},
+	"error":    {"exception", "exceededCpu", "exceededMemory", "unknown"},
+	"canceled": {"canceled"},
+}
+
+func runWorkerTail(args []string) error {
+	positional, flags := splitArgs(args)
+	if len(positional) == 0 {
+		return usageErrorf("usage: cf workers tail <script> [--status ok|error|canceled] [--method GET,POST] [--search <text>] [--ip <a,b>] [--sampling-rate 1]")
+	}
+	filters, err := tailFilters(flags)
+	if err != nil {
+		return err
+	}
+	path, err := workersPath(positional[0])
+	if err != nil {
+		return err
+	}
+
+	resp, err := requestCF(http.MethodPost, path+"/tails", map[string]any{})
+	if err != nil {
+		return err
+	}
+	var tail workerTail
+	if err := json.Unmarshal(resp.Result, &tail); err != nil {
+		return err
+	}
+	// Tails expire on their own, but deleting frees the slot right away.
+	defer requestCF(http.MethodDelete, path+"/tails/"+tail.ID, nil)
+
+	ws, err := dialWebSocket(tail.URL, "trace-v1")
+	if err != nil {
+		return err
+	}
+	defer ws.close()
+
+	msg, err := json.Marshal(map[string]any{"filters": filters, "debug": false})
+	if err != nil {
+		return err
+	}
+	if err := ws.writeText(msg); err != nil {
+		return err
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		<-interrupt
+		ws.close()
+	}()
+
+	infof("Tailing %s (Ctrl-C to stop)...\n", positional[0])
+	for {
+		data, err := ws.readMessage()
+		if err != nil {
+			if errors.Is(err, io.EOF) || strings.Contains(err.Error(), "use of closed network connection") {
+				return nil
+			}
+			return err
+		}
+		if jsonOutput() {
+			// One event per line so the stream can be piped into jq.
+			fmt.Println(strings.TrimSpace(string(data)))
+			continue
+		}
+		var ev tailEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			infof("unparsed tail message: %s\n", data)
+			continue
+		}
+		printTailEvent(ev)
+	}
+}
+
+func tailFilters(flags map[string]string) ([]map[string]any, error) {
+	var filters []map[string]any
+	if v := flags["status"]; v != "" {
+		var outcomes []string
+		for _, s := range splitList(v) {
+			o, ok := tailOutcomes[strings.ToLower(s)]
+			if !ok {
+				return nil, usageErrorf("invalid --status %q (want ok, error or canceled)", s)
+			}
+			outcomes = append(outcomes, o...)
+		}
+		filters = append(filters, map[string]any{"outcome": outcomes})
+	}
+	if v := flags["method"]; v != "" {
+		filters = append(filters, map[string]any{"method": splitList(strings.ToUpper(v))})
+	}
+	if v := flags["search"]; v != "" {
+		filters = append(filters, map[string]any{"query": v})
+	}
+	if v := flags["ip"]; v != "" {
+		filters = append(filters, map[string]any{"client_ip": splitList(v)})
+	}
+	if v := flags["sampling-rate"]; v != "" {
+		var rate float64
+		if _, err := fmt.Sscan(v, &rate); err != nil || rate <= 0 || rate > 1 {
+			return nil, usageErrorf("invalid --sampling-rate %q (want a number in (0, 1])", v)
+		}
+		filters = append(filters, map[string]any{"sampling_rate": rate})
+	}
+	return filters, nil
+}
+
+func printTailEvent(ev tailEvent) {
+	ts := time.UnixMilli(ev.EventTimestamp).Format("15:04:05")
+	switch {
+	case ev.Event != nil && ev.Event.Request != nil:
+		status := ""
+		if ev.Event.Response != nil {
+			status = fmt.Sprintf(" %d", ev.Event.Response.Status)
+		}
+		fmt.Printf("%s %s %s%s (%s)\n", ts, ev.Event.Request.Method, ev.Event.Request.URL, status, ev.Outcome)
+	case ev.Event != nil && ev.Event.Cron != "":
+		fmt.Printf("%s cron %q (%s)\n", ts, ev.Event.Cron, ev.Outcome)
+	default:
+		fmt.Printf("%s %s (%s)\n", ts, ev.ScriptName, ev.Outcome)
+	}
+	for _, l := range ev.Logs {
+		parts := make([]string, len(l.Message))
+		for i, m := range l.Message {
+			if s, ok := m.(string); ok {
+				parts[i] = s
+				continue
+			}
+			b, _ := json.Marshal(m)
+			parts[i] = string(b)
+		}
+		fmt.Printf("  [%s] %s\n", l.Level, strings.Join(parts, " "))
+	}
+	for _, e := range ev.Exceptions {
+		fmt.Printf("  [exception] %s: %s\n", e.Name, e.Message)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWorkerUpload(t *testing.T) {
+	cases := map[string]struct {
+		source  string
+		metaKey string
+		ctype   string
+	}{
+		"module":         {"export default { fetch() { return new Response('hi') } }", "main_module", "application/javascript+module"},
+		"service-worker": {"addEventListener('fetch', e => e.respondWith(new Response('hi')))", "body_part", "application/javascript"},
+	}
+	for name, tc := range cases {
+		body, contentType, err := workerUpload("dir/worker.js", []byte(tc.source), "2024-01-01")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		_, params, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			t.Fatalf("%s: bad content type %q", name, contentType)
+		}
+		r := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+
+		part, err := r.NextPart()
+		if err != nil || part.FormName() != "metadata" {
+			t.Fatalf("%s: expected metadata part first, got %v", name, err)
+		}
+		var meta map[string]string
+		if err := json.NewDecoder(part).Decode(&meta); err != nil {
+			t.Fatalf("%s: bad metadata: %v", name, err)
+		}
+		if meta[tc.metaKey] != "worker.js" || meta["compatibility_date"] != "2024-01-01" {
+			t.Fatalf("%s: unexpected metadata %v", name, meta)
+		}
+
+		part, err = r.NextPart()
+		if err != nil || part.FormName() != "worker.js" || part.Header.Get("Content-Type") != tc.ctype {
+			t.Fatalf("%s: unexpected script part: %v %v", name, err, part.Header)
+		}
+		src, _ := io.ReadAll(part)
+		if string(src) != tc.source {
+			t.Fatalf("%s: script body mismatch", name)
+		}
+	}
+}
+
+func TestWorkersTail(t *testing.T) {
+	srv := useFakeAPI(t)
+	filters := make(chan []byte, 1)
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", wsAcceptKey(r.Header.Get("Sec-WebSocket-Key")))
+		rw.Flush()
+		peer := &wsConn{conn: conn, br: rw.Reader}
+		msg, err := peer.readMessage()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		filters <- msg
+		peer.writeText([]byte(`{"outcome":"exception","scriptName":"api","eventTimestamp":0,"event":{"request":{"url":"https://example.com/x","method":"POST"},"response":{"status":500}},"exceptions":[{"name":"Error","message":"boom"}]}`))
+		peer.writeFrame(wsOpClose, nil)
+		peer.readMessage()
+	}))
+	defer ws.Close()
+
+	const script = "/accounts/acc1/workers/scripts/api"
+	srv.Reply("POST", script+"/tails", workerTail{ID: "tail1", URL: "ws" + strings.TrimPrefix(ws.URL, "http")})
+	srv.Reply("DELETE", script+"/tails/tail1", nil)
+
+	out, err := captureStdout(t, func() error {
+		return runWorkers([]string{"tail", "api", "--status", "error", "--method", "get,post", "--sampling-rate", "0.5"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"debug":false,"filters":[{"outcome":["exception","exceededCpu","exceededMemory","unknown"]},{"method":["GET","POST"]},{"sampling_rate":0.5}]}`
+	if got := string(<-filters); got != want {
+		t.Fatalf("unexpected filter message:\n got %s\nwant %s", got, want)
+	}
+	if !strings.Contains(out, "POST https://example.com/x 500 (exception)") || !strings.Contains(out, "  [exception] Error: boom") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if n := len(srv.Calls("DELETE", script+"/tails/tail1")); n != 1 {
+		t.Fatalf("expected the tail to be deleted, got %d DELETEs", n)
+	}
+}
+
+func TestWorkersTailErrors(t *testing.T) {
+	srv := useFakeAPI(t)
+	for _, flags := range [][]string{{"--status", "weird"}, {"--sampling-rate", "2"}} {
+		if err := runWorkers(append([]string{"tail", "api"}, flags...)); exitCode(err) != exitUsage {
+			t.Fatalf("expected a usage error for %v, got %v", flags, err)
+		}
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("expected no tail to be opened for invalid filters, got %d requests", n)
+	}
+
+	srv.Fail("POST", "/accounts/acc1/workers/scripts/api/tails", 400, 10057, "Too many tails attached")
+	err := runWorkers([]string{"tail", "api"})
+	if exitCode(err) != exitAPI || !strings.Contains(err.Error(), "Too many tails attached") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+}
+
+func TestValidateCron(t *testing.T) {
+	if err := validateCron("*/5 * * * *"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := validateCron("*/5 * * * *, 0 0 * * *"); err == nil {
+		t.Fatalf("expected error for two expressions in one argument")
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheTTL is set by the global --cache-ttl flag; -1 means fall back to
+// CF_CACHE_TTL. Zero keeps zone lookups cached for the run only.
+var cacheTTL time.Duration = -1
+
+// zoneCache holds zone lookups and zone lists, keyed by account ID, so a
+// command that resolves the same zone many times only asks once. With
+// --cache-ttl it is also kept on disk between runs. Misses are not cached,
+// so a zone created since is found.
+type zoneCache struct {
+	Zones map[string]cachedZone  `json:"zones"`
+	Lists map[string]cachedZones `json:"lists"`
+}
+
+type cachedZone struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Zone      zone      `json:"zone"`
+}
+
+type cachedZones struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Zones     []zone    `json:"zones"`
+}
+
+var (
+	zoneCacheMu     sync.Mutex
+	zoneCacheLoaded *zoneCache
+)
+
+func setCacheTTL(v string) error {
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return usageErrorf("invalid --cache-ttl %q: use a duration such as 5m (0 turns the disk cache off)", v)
+	}
+	cacheTTL = d
+	return nil
+}
+
+func resolveCacheTTL() time.Duration {
+	if cacheTTL >= 0 {
+		return cacheTTL
+	}
+	if v := strings.TrimSpace(os.Getenv("CF_CACHE_TTL")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// zoneCachePath is zones.json in the user cache directory, or CF_CACHE_DIR.
+func zoneCachePath() string {
+	dir := strings.TrimSpace(os.Getenv("CF_CACHE_DIR"))
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(base, "cf")
+	}
+	return filepath.Join(dir, "zones.json")
+}
+
+// loadZoneCache returns the run's cache, read from disk the first time when
+// --cache-ttl is set. The caller holds zoneCacheMu.
+func loadZoneCache() *zoneCache {
+	if zoneCacheLoaded != nil {
+		return zoneCacheLoaded
+	}
+	zc := &zoneCache{Zones: map[string]cachedZone{}, Lists: map[string]cachedZones{}}
+	if resolveCacheTTL() > 0 {
+		// An unreadable or corrupt cache is just empty.
+		if data, err := os.ReadFile(zoneCachePath()); err == nil {
+			_ = json.Unmarshal(data, zc)
+		}
+		if zc.Zones == nil {
+			zc.Zones = map[string]cachedZone{}
+		}
+		if zc.Lists == nil {
+			zc.Lists = map[string]cachedZones{}
+		}
+	}
+	zoneCacheLoaded = zc
+	return zc
+}
+
+// save writes the cache to disk when --cache-ttl is set. Failing to is not
+// worth failing the command over. The caller holds zoneCacheMu.
+func (zc *zoneCache) save() {
+	path := zoneCachePath()
+	if resolveCacheTTL() <= 0 || path == "" {
+		return
+	}
+	data, err := json.Marshal(zc)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o600)
+}
+
+// fresh reports whether something fetched at t may still be used. Entries
+// read in this run are always fresh; older ones only within the TTL.
+func fresh(t time.Time) bool {
+	return !t.Before(runStarted) || time.Since(t) < resolveCacheTTL()
+}
+
+// runStarted separates entries fetched by this run from ones loaded from
+// disk.
+var runStarted = time.Now()
+
+func zoneCacheKey(accountID, name string) string {
+	return accountID + "/" + strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// cachedZoneByName returns the cached zone, from a lookup or a cached list.
+func cachedZoneByName(accountID, name string) (*zone, bool) {
+	zoneCacheMu.Lock()
+	defer zoneCacheMu.Unlock()
+	zc := loadZoneCache()
+	if e, ok := zc.Zones[zoneCacheKey(accountID, name)]; ok && fresh(e.FetchedAt) {
+		z := e.Zone
+		return &z, true
+	}
+	if l, ok := zc.Lists[accountID]; ok && fresh(l.FetchedAt) {
+		for _, z := range l.Zones {
+			if strings.EqualFold(z.Name, strings.TrimSuffix(name, ".")) {
+				return &z, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func storeZone(accountID string, z zone) {
+	zoneCacheMu.Lock()
+	defer zoneCacheMu.Unlock()
+	zc := loadZoneCache()
+	zc.Zones[zoneCacheKey(accountID, z.Name)] = cachedZone{FetchedAt: time.Now(), Zone: z}
+	zc.save()
+}
+
+func cachedZoneList(accountID string) ([]zone, bool) {
+	zoneCacheMu.Lock()
+	defer zoneCacheMu.Unlock()
+	if l, ok := loadZoneCache().Lists[accountID]; ok && fresh(l.FetchedAt) {
+		return append([]zone(nil), l.Zones...), true
+	}
+	return nil, false
+}
+
+func storeZoneList(accountID string, zones []zone) {
+	zoneCacheMu.Lock()
+	defer zoneCacheMu.Unlock()
+	zc := loadZoneCache()
+	zc.Lists[accountID] = cachedZones{FetchedAt: time.Now(), Zones: append([]zone(nil), zones...)}
+	zc.save()
+}
+
+// forgetZones drops every cached zone, in memory and on disk, after a zone
+// is created, deleted or changed.
+func forgetZones() {
+	zoneCacheMu.Lock()
+	defer zoneCacheMu.Unlock()
+	zoneCacheLoaded = &zoneCache{Zones: map[string]cachedZone{}, Lists: map[string]cachedZones{}}
+	if path := zoneCachePath(); path != "" {
+		_ = os.Remove(path)
+	}
+}
+
+// listAccountZones returns every zone in the account, cached like
+// getZoneByName.
+func listAccountZones() ([]zone, error) {
+	accountID, err := resolveAccountID()
+	if err != nil {
+		return nil, err
+	}
+	if zones, ok := cachedZoneList(accountID); ok {
+		return zones, nil
+	}
+	c, err := apiClient()
+	if err != nil {
+		return nil, err
+	}
+	zones, err := c.ListZones(rootCtx, accountID, 0)
+	if err != nil {
+		return nil, err
+	}
+	storeZoneList(accountID, zones)
+	return zones, nil
+}
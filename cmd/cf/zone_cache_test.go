@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestZoneLookupsAreCachedPerRun(t *testing.T) {
+	srv := useFakeAPI(t)
+	for i := 0; i < 3; i++ {
+		if _, err := requireZone("example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(srv.Calls("GET", "/zones?name=example.com")); n != 1 {
+		t.Fatalf("expected one lookup, got %d", n)
+	}
+
+	// A miss is not cached, so a zone added since is found.
+	for i := 0; i < 2; i++ {
+		if z, err := getZoneByName("missing.example"); err != nil || z != nil {
+			t.Fatalf("expected no zone, got %+v (err=%v)", z, err)
+		}
+	}
+	if n := len(srv.Calls("GET", "/zones?name=missing.example")); n != 2 {
+		t.Fatalf("expected misses to be looked up again, got %d lookups", n)
+	}
+
+	srv.Reply("GET", "/zones?account.id=acc1", []zone{{ID: "z9", Name: "other.org"}})
+	if _, err := listAccountZones(); err != nil {
+		t.Fatal(err)
+	}
+	if z, err := requireZone("Other.org"); err != nil || z.ID != "z9" {
+		t.Fatalf("expected the zone from the cached list, got %+v (err=%v)", z, err)
+	}
+	if n := len(srv.Calls("GET", "/zones?name=other.org")); n != 0 {
+		t.Fatalf("expected no lookup for a listed zone, got %d", n)
+	}
+}
+
+func TestZoneCacheOnDisk(t *testing.T) {
+	srv := useFakeAPI(t)
+	t.Setenv("CF_CACHE_TTL", "5m")
+	origStarted := runStarted
+	t.Cleanup(func() { runStarted = origStarted })
+
+	if _, err := requireZone("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	// A later run reads the lookup back from disk.
+	zoneCacheLoaded, runStarted = nil, time.Now().Add(time.Second)
+	if _, err := requireZone("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Calls("GET", "/zones?name=example.com")); n != 1 {
+		t.Fatalf("expected the second run to use the disk cache, got %d lookups", n)
+	}
+
+	// Once the TTL has passed it is looked up again.
+	t.Setenv("CF_CACHE_TTL", "1ns")
+	zoneCacheLoaded = nil
+	if _, err := requireZone("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Calls("GET", "/zones?name=example.com")); n != 2 {
+		t.Fatalf("expected an expired entry to be refetched, got %d lookups", n)
+	}
+
+	// Changing a zone drops the cache. The pause itself looks the zone up
+	// fresh, and so does the next lookup.
+	t.Setenv("CF_CACHE_TTL", "5m")
+	srv.Reply("PATCH", "/zones/z1", zone{ID: "z1", Name: "example.com", Paused: true})
+	if _, err := captureStdout(t, func() error { return setZonePaused("example.com", true) }); err != nil {
+		t.Fatal(err)
+	}
+	zoneCacheLoaded = nil
+	if _, err := requireZone("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Calls("GET", "/zones?name=example.com")); n != 4 {
+		t.Fatalf("expected the zone to be looked up after pausing it, got %d lookups", n)
+	}
+}
+
+func TestCacheTTLFlag(t *testing.T) {
+	t.Cleanup(func() { cacheTTL = -1 })
+	if err := setCacheTTL("5m"); err != nil || cacheTTL != 5*time.Minute {
+		t.Fatalf("cacheTTL = %v (err=%v), want 5m", cacheTTL, err)
+	}
+	if err := setCacheTTL("soon"); exitCode(err) != exitUsage {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
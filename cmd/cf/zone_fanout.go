@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// zoneFanOutResult is what a fanned-out command reports for one zone.
+type zoneFanOutResult struct {
+	Zone   string `json:"zone"`
+	OK     bool   `json:"ok"`
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+
+	summary string
+}
+
+// fanOutRequested reports whether --zones or --all-zones was given.
+func fanOutRequested(flags map[string]string) bool {
+	return flags["zones"] != "" || parseBoolWithDefault(flags["all-zones"], false)
+}
+
+// fanOutZones lists the zones selected by --all-zones, or by the
+// comma-separated globs in --zones (e.g. "*.example-brand.com").
+func fanOutZones(flags map[string]string) ([]zone, error) {
+	all := parseBoolWithDefault(flags["all-zones"], false)
+	if all && flags["zones"] != "" {
+		return nil, usageErrorf("--zones and --all-zones cannot be combined")
+	}
+	if flags["zone"] != "" {
+		return nil, usageErrorf("--zone cannot be combined with --zones or --all-zones")
+	}
+	patterns := splitList(strings.ToLower(flags["zones"]))
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, usageErrorf("invalid --zones pattern %q: %w", p, err)
+		}
+	}
+
+	zones, err := listAccountZones()
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		if len(zones) == 0 {
+			return nil, errors.New("no zones found in this account")
+		}
+		return zones, nil
+	}
+
+	var matched []zone
+	for _, z := range zones {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, strings.ToLower(z.Name)); ok {
+				matched = append(matched, z)
+				break
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return nil, notFoundErrorf("no zones match --zones %q", flags["zones"])
+	}
+	return matched, nil
+}
+
+// runOnZones confirms, then calls do for every zone with up to concurrency
+// zones in flight and prints one result line per zone. do returns a short
+// human summary and the value reported in machine output.
+func runOnZones(flags map[string]string, command string, zones []zone, concurrency int, do func(z *zone) (string, any, error)) error {
+	if err := requireConfirmable(flags, command); err != nil {
+		return err
+	}
+	names := make([]string, len(zones))
+	for i, z := range zones {
+		names[i] = z.Name
+	}
+	infof("%s on %d zone(s): %s\n", command, len(zones), strings.Join(names, ", "))
+	ok, err := confirm(flags, command, fmt.Sprintf("Apply to %d zone(s)?", len(zones)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("aborted; nothing changed")
+	}
+
+	results := make([]zoneFanOutResult, len(zones))
+	errs := runBatch(command, len(zones), concurrency, func(i int) error {
+		summary, v, err := do(&zones[i])
+		if err != nil {
+			return err
+		}
+		results[i] = zoneFanOutResult{Zone: zones[i].Name, OK: true, Result: v, summary: summary}
+		return nil
+	})
+	for i, err := range errs {
+		if err != nil {
+			results[i] = zoneFanOutResult{Zone: zones[i].Name, Error: err.Error(), summary: err.Error()}
+		}
+	}
+
+	t := table{Headers: []string{"ZONE", "STATUS", "RESULT"}}
+	for _, r := range results {
+		t.Rows = append(t.Rows, []string{r.Zone, fanOutStatus(r.OK), r.summary})
+	}
+	if err := printList(results, t, func() {
+		for _, r := range results {
+			fmt.Printf("%s %s: %s\n", colorStatus(fanOutStatus(r.OK), fanOutStatus(r.OK)), r.Zone, r.summary)
+		}
+	}); err != nil {
+		return err
+	}
+	if failed := countFailed(errs); failed > 0 {
+		return fmt.Errorf("%s failed on %d of %d zones", command, failed, len(zones))
+	}
+	return nil
+}
+
+func fanOutStatus(ok bool) string {
+	if ok {
+		return "ok"
+	}
+	return "fail"
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"cf/internal/cftest"
+)
+
+func fanOutTestZones(srv *cftest.Server) {
+	srv.Reply("GET", "/zones?account.id=acc1", []zone{
+		{ID: "z1", Name: "a.brand.com"},
+		{ID: "z2", Name: "b.brand.com"},
+		{ID: "z3", Name: "other.org"},
+	})
+}
+
+func TestDNSAddZonesGlob(t *testing.T) {
+	srv := useFakeAPI(t)
+	fanOutTestZones(srv)
+	srv.Reply("GET", "/zones/z1/dns_records", []dnsRecord{})
+	srv.Reply("GET", "/zones/z2/dns_records", []dnsRecord{})
+	srv.Reply("POST", "/zones/z1/dns_records", dnsRecord{ID: "r1", Type: "A", Name: "www.a.brand.com", Content: "192.0.2.1"})
+	srv.Fail("POST", "/zones/z2/dns_records", 400, 81057, "Record already exists")
+
+	out, err := captureStdout(t, func() error {
+		return runDNS([]string{"add", "--zones", "*.brand.com", "--type", "A", "--name", "www", "--content", "192.0.2.1", "--yes"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "dns add failed on 1 of 2 zones") {
+		t.Fatalf("expected one zone to fail, got %v", err)
+	}
+	for _, want := range []string{"a.brand.com: created A www.a.brand.com -> 192.0.2.1 (id=r1)", "b.brand.com: ", "Record already exists"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if n := len(srv.Calls("POST", "/zones/z3/dns_records")); n != 0 {
+		t.Fatalf("expected other.org to be left alone, got %d creates", n)
+	}
+}
+
+func TestZoneSettingsSetAllZones(t *testing.T) {
+	srv := useFakeAPI(t)
+	fanOutTestZones(srv)
+	for _, id := range []string{"z1", "z2", "z3"} {
+		srv.Reply("PATCH", "/zones/"+id+"/settings", []zoneSetting{{ID: "ssl", Value: []byte(`"strict"`)}})
+	}
+
+	out, err := captureStdout(t, func() error {
+		return runZones([]string{"settings", "set", "--all-zones", "--ssl", "strict", "--yes"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	for _, id := range []string{"z1", "z2", "z3"} {
+		var body struct {
+			Items []map[string]any `json:"items"`
+		}
+		calls := srv.Calls("PATCH", "/zones/"+id+"/settings")
+		if len(calls) != 1 {
+			t.Fatalf("expected one settings update for %s, got %d", id, len(calls))
+		}
+		if err := calls[0].Decode(&body); err != nil || len(body.Items) != 1 || body.Items[0]["id"] != "ssl" {
+			t.Fatalf("unexpected body for %s: %+v (err=%v)", id, body, err)
+		}
+	}
+	if !strings.Contains(out, "other.org: ssl = strict") {
+		t.Fatalf("expected a result line per zone:\n%s", out)
+	}
+}
+
+func TestFanOutZonesNoMatch(t *testing.T) {
+	srv := useFakeAPI(t)
+	fanOutTestZones(srv)
+	_, err := fanOutZones(map[string]string{"zones": "*.nope.com"})
+	if exitCode(err) != exitNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := fanOutZones(map[string]string{"zones": "*.brand.com", "all-zones": "true"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
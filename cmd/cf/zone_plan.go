@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ratePlan is one entry from /zones/:id/available_plans. LegacyID is the
+// short name (free, pro, business, enterprise) users type.
+type ratePlan struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	LegacyID     string  `json:"legacy_id"`
+	Price        float64 `json:"price"`
+	Currency     string  `json:"currency"`
+	Frequency    string  `json:"frequency"`
+	CanSubscribe bool    `json:"can_subscribe"`
+	IsSubscribed bool    `json:"is_subscribed"`
+}
+
+func (p ratePlan) String() string {
+	return fmt.Sprintf("%s (%s)", p.Name, planPrice(p.Price, p.Currency, p.Frequency))
+}
+
+func planPrice(price float64, currency, frequency string) string {
+	if currency == "" {
+		currency = "USD"
+	}
+	if frequency == "" {
+		frequency = "monthly"
+	}
+	return fmt.Sprintf("%.2f %s/%s", price, currency, frequency)
+}
+
+type zonePlanInfo struct {
+	Zone      string     `json:"zone"`
+	Plan      string     `json:"plan"`
+	Available []ratePlan `json:"available_plans"`
+}
+
+// planChange is one zone's move from its current plan to a new one.
+type planChange struct {
+	Zone *zone    `json:"-"`
+	Name string   `json:"zone"`
+	From ratePlan `json:"from"`
+	To   ratePlan `json:"to"`
+	Err  string   `json:"error,omitempty"`
+}
+
+func runZonePlan(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf zones plan get|set <zone>... run: cf zones plan --help")
+	}
+	positional, flags := splitArgs(args[1:])
+	if len(positional) == 0 {
+		return usageErrorf("usage: cf zones plan %s <zone>...", args[0])
+	}
+	switch args[0] {
+	case "get":
+		return showZonePlan(positional[0])
+	case "set":
+		plan := strings.ToLower(flags["plan"])
+		if plan == "" {
+			return usageErrorf("missing required flag for zones plan set: --plan free|pro|business|enterprise")
+		}
+		concurrency, err := parseIntWithDefault(flags["concurrency"], defaultBulkConcurrency)
+		if err != nil {
+			return usageErrorf("invalid --concurrency: %w", err)
+		}
+		return setZonePlans(flags, positional, plan, flags["frequency"], concurrency)
+	}
+	return usageErrorf("unknown zones plan command %q. run: cf zones plan --help", args[0])
+}
+
+func availablePlans(zoneID string) ([]ratePlan, error) {
+	resp, err := requestCF(http.MethodGet, "/zones/"+zoneID+"/available_plans", nil)
+	if err != nil {
+		return nil, err
+	}
+	var plans []ratePlan
+	if err := json.Unmarshal(resp.Result, &plans); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func showZonePlan(zoneName string) error {
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	plans, err := availablePlans(z.ID)
+	if err != nil {
+		return err
+	}
+
+	info := zonePlanInfo{Zone: z.Name, Available: plans}
+	if z.Plan != nil {
+		info.Plan = z.Plan.Name
+	}
+	t := table{Headers: []string{"PLAN", "NAME", "PRICE", "CURRENT"}}
+	for _, p := range plans {
+		t.Rows = append(t.Rows, []string{p.LegacyID, p.Name, planPrice(p.Price, p.Currency, p.Frequency), fmt.Sprint(p.IsSubscribed)})
+	}
+	return printList(info, t, func() {
+		fmt.Printf("%s: %s\n", z.Name, info.Plan)
+		fmt.Println("Available plans:")
+		for _, p := range plans {
+			marker := " "
+			if p.IsSubscribed {
+				marker = "*"
+			}
+			fmt.Printf(" %s %-12s %-24s %s\n", marker, p.LegacyID, p.Name, planPrice(p.Price, p.Currency, p.Frequency))
+		}
+	})
+}
+
+// setZonePlans moves each zone to plan after showing what it will cost and
+// asking for confirmation.
+func setZonePlans(flags map[string]string, zoneNames []string, plan, frequency string, concurrency int) error {
+	if err := requireConfirmable(flags, "zones plan set"); err != nil {
+		return err
+	}
+
+	var changes []planChange
+	for _, name := range zoneNames {
+		z, err := requireZone(name)
+		if err != nil {
+			return err
+		}
+		plans, err := availablePlans(z.ID)
+		if err != nil {
+			return err
+		}
+		c := planChange{Zone: z, Name: z.Name}
+		for _, p := range plans {
+			if p.IsSubscribed {
+				c.From = p
+			}
+			if p.LegacyID == plan {
+				c.To = p
+			}
+		}
+		if c.To.ID == "" {
+			return usageErrorf("plan %q is not available for %s; run: cf zones plan get %s", plan, z.Name, z.Name)
+		}
+		if c.From.ID == c.To.ID {
+			infof("%s is already on %s; skipping.\n", z.Name, c.To.Name)
+			continue
+		}
+		if !c.To.CanSubscribe {
+			return fmt.Errorf("%s cannot subscribe to %s (check the account's billing setup in the dashboard)", z.Name, c.To.Name)
+		}
+		changes = append(changes, c)
+	}
+	if len(changes) == 0 {
+		return printResult(changes, func() {})
+	}
+
+	if !machineOutput() {
+		printPlanChanges(changes)
+	}
+	ok, err := confirm(flags, "zones plan set", "Change these plans?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted. No plans changed.")
+		return nil
+	}
+
+	errs := runBatch("Changing plans", len(changes), concurrency, func(i int) error {
+		return updateZoneSubscription(changes[i].Zone.ID, changes[i].To, frequency)
+	})
+	for i, err := range errs {
+		if err != nil {
+			changes[i].Err = err.Error()
+		}
+	}
+	if err := printResult(changes, func() {
+		for _, c := range changes {
+			if c.Err != "" {
+				fmt.Printf("FAIL %s: %s\n", c.Name, c.Err)
+			} else {
+				fmt.Printf("ok   %s: %s -> %s\n", c.Name, c.From.Name, c.To.Name)
+			}
+		}
+	}); err != nil {
+		return err
+	}
+	if failed := countFailed(errs); failed > 0 {
+		return fmt.Errorf("%d of %d plan changes failed", failed, len(changes))
+	}
+	return nil
+}
+
+func printPlanChanges(changes []planChange) {
+	fmt.Println("Plan changes:")
+	var total float64
+	for _, c := range changes {
+		delta := c.To.Price - c.From.Price
+		total += delta
+		fmt.Printf("  %s: %s -> %s, %+.2f\n", c.Name, c.From, c.To, delta)
+	}
+	fmt.Printf("Net change: %+.2f per billing period\n", total)
+	fmt.Println("Upgrades are billed to the account's payment method right away; downgrades take effect at the end of the billing period.")
+}
+
+// updateZoneSubscription switches a zone's rate plan, creating the
+// subscription if the zone has none yet.
+func updateZoneSubscription(zoneID string, p ratePlan, frequency string) error {
+	if frequency == "" {
+		frequency = p.Frequency
+	}
+	body := map[string]any{"rate_plan": map[string]string{"id": p.LegacyID}, "frequency": frequency}
+	path := "/zones/" + zoneID + "/subscription"
+	_, err := requestCF(http.MethodPut, path, body)
+	if isNotFound(err) {
+		_, err = requestCF(http.MethodPost, path, body)
+	}
+	forgetZones()
+	return err
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetZonePlans(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/available_plans", []ratePlan{
+		{ID: "p-free", Name: "Free Website", LegacyID: "free", Frequency: "monthly", CanSubscribe: true, IsSubscribed: true},
+		{ID: "p-pro", Name: "Pro Website", LegacyID: "pro", Price: 20, Currency: "USD", Frequency: "monthly", CanSubscribe: true},
+	})
+	srv.Fail("PUT", "/zones/z1/subscription", 404, 1207, "No subscription")
+	srv.Reply("POST", "/zones/z1/subscription", map[string]any{"id": "sub1"})
+
+	out, err := captureStdout(t, func() error {
+		return setZonePlans(map[string]string{"yes": "true"}, []string{"example.com"}, "pro", "", 1)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Free Website (0.00 USD/monthly) -> Pro Website (20.00 USD/monthly), +20.00") {
+		t.Fatalf("expected price summary, got:\n%s", out)
+	}
+	posts := srv.Calls("POST", "/zones/z1/subscription")
+	if len(posts) != 1 {
+		t.Fatalf("expected the subscription to be created after the 404, got %d POSTs", len(posts))
+	}
+	var body struct {
+		RatePlan struct {
+			ID string `json:"id"`
+		} `json:"rate_plan"`
+		Frequency string `json:"frequency"`
+	}
+	if err := posts[0].Decode(&body); err != nil || body.RatePlan.ID != "pro" || body.Frequency != "monthly" {
+		t.Fatalf("unexpected body %s (err=%v)", posts[0].Body, err)
+	}
+
+	if err := setZonePlans(map[string]string{"yes": "true"}, []string{"example.com"}, "enterprise", "", 1); exitCode(err) != exitUsage {
+		t.Fatalf("expected usage error for an unavailable plan, got %v", err)
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commonZoneSettings are shown by `zones settings get` when neither specific
+// settings nor --all are requested.
+var commonZoneSettings = []string{
+	"ssl",
+	"always_use_https",
+	"min_tls_version",
+	"tls_1_3",
+	"automatic_https_rewrites",
+	"brotli",
+	"http3",
+	"development_mode",
+	"security_level",
+	"cache_level",
+}
+
+type zoneSetting struct {
+	ID         string          `json:"id"`
+	Value      json.RawMessage `json:"value"`
+	Editable   bool            `json:"editable"`
+	ModifiedOn string          `json:"modified_on,omitempty"`
+
+	// TimeRemaining is only set for development_mode, in seconds.
+	TimeRemaining *int `json:"time_remaining,omitempty"`
+}
+
+func runZoneSettings(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf zones settings get|set <zone> ...")
+	}
+
+	positional, flags := splitArgs(args[1:])
+	if args[0] == "set" && fanOutRequested(flags) {
+		if len(positional) > 0 {
+			return usageErrorf("a zone name cannot be combined with --zones or --all-zones")
+		}
+		return setZoneSettingsZones(flags)
+	}
+	zoneName := ""
+	if len(positional) > 0 {
+		zoneName, positional = positional[0], positional[1:]
+	}
+	zoneName = zoneOrDefault(zoneName)
+	if zoneName == "" {
+		return usageErrorf("usage: cf zones settings %s <zone>", args[0])
+	}
+
+	switch args[0] {
+	case "get":
+		return getZoneSettings(zoneName, positional, parseBoolWithDefault(flags["all"], false))
+	case "set":
+		return setZoneSettings(zoneName, flags)
+	}
+	return usageErrorf("usage: cf zones settings get|set <zone> ...")
+}
+
+func listZoneSettings(zoneID string) ([]zoneSetting, error) {
+	resp, err := requestCF(http.MethodGet, "/zones/"+zoneID+"/settings", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []zoneSetting
+	if err := json.Unmarshal(resp.Result, &settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func getZoneSettings(zoneName string, names []string, all bool) error {
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	settings, err := listZoneSettings(z.ID)
+	if err != nil {
+		return err
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].ID < settings[j].ID })
+
+	if all {
+		return writeJSON(settings)
+	}
+
+	useDefaults := len(names) == 0
+	if useDefaults {
+		names = commonZoneSettings
+	}
+	byID := map[string]zoneSetting{}
+	for _, s := range settings {
+		byID[s.ID] = s
+	}
+
+	selected := make([]zoneSetting, 0, len(names))
+	for _, name := range names {
+		id := zoneSettingID(name)
+		s, ok := byID[id]
+		if !ok {
+			if useDefaults {
+				continue
+			}
+			return fmt.Errorf("unknown zone setting %q. run: cf zones settings get %s --all", name, zoneName)
+		}
+		selected = append(selected, s)
+	}
+
+	t := table{Headers: []string{"SETTING", "VALUE", "EDITABLE"}}
+	for _, s := range selected {
+		t.Rows = append(t.Rows, []string{s.ID, settingValueString(s.Value), strconv.FormatBool(s.Editable)})
+	}
+
+	return printList(selected, t, func() {
+		for _, s := range selected {
+			fmt.Printf("%s = %s\n", s.ID, settingValueString(s.Value))
+		}
+	})
+}
+
+func setZoneSettings(zoneName string, flags map[string]string) error {
+	items, err := zoneSettingItems(flags)
+	if err != nil {
+		return err
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	changed, err := patchZoneSettings(z.ID, items)
+	if err != nil {
+		return err
+	}
+
+	return printResult(changed, func() {
+		for _, s := range changed {
+			fmt.Printf("Zone setting updated: %s %s = %s\n", z.Name, s.ID, settingValueString(s.Value))
+		}
+	})
+}
+
+// setZoneSettingsZones applies the same settings to every zone selected by
+// --zones or --all-zones.
+func setZoneSettingsZones(flags map[string]string) error {
+	concurrency, err := parseIntWithDefault(flags["concurrency"], defaultBulkConcurrency)
+	if err != nil {
+		return usageErrorf("invalid --concurrency: %w", err)
+	}
+	settings := map[string]string{}
+	for k, v := range flags {
+		switch k {
+		case "zones", "all-zones", "concurrency", "yes":
+		default:
+			settings[k] = v
+		}
+	}
+	items, err := zoneSettingItems(settings)
+	if err != nil {
+		return err
+	}
+	zones, err := fanOutZones(flags)
+	if err != nil {
+		return err
+	}
+	return runOnZones(flags, "zones settings set", zones, concurrency, func(z *zone) (string, any, error) {
+		changed, err := patchZoneSettings(z.ID, items)
+		if err != nil {
+			return "", nil, err
+		}
+		parts := make([]string, len(changed))
+		for i, s := range changed {
+			parts[i] = s.ID + " = " + settingValueString(s.Value)
+		}
+		return strings.Join(parts, ", "), changed, nil
+	})
+}
+
+// zoneSettingItems turns --<setting> <value> flags into the items of a
+// settings PATCH, sorted by setting.
+func zoneSettingItems(flags map[string]string) ([]map[string]any, error) {
+	if len(flags) == 0 {
+		return nil, errors.New("no settings given. example: cf zones settings set example.com --ssl strict --always-use-https on")
+	}
+
+	keys := make([]string, 0, len(flags))
+	for k := range flags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]map[string]any, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, map[string]any{"id": zoneSettingID(k), "value": parseSettingValue(flags[k])})
+	}
+	return items, nil
+}
+
+// patchZoneSettings applies items and returns the settings they changed.
+func patchZoneSettings(zoneID string, items []map[string]any) ([]zoneSetting, error) {
+	resp, err := requestCF(http.MethodPatch, "/zones/"+zoneID+"/settings", map[string]any{"items": items})
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []zoneSetting
+	if err := json.Unmarshal(resp.Result, &updated); err != nil {
+		return nil, err
+	}
+
+	// The bulk endpoint echoes every setting; only report the ones we changed.
+	changed := make([]zoneSetting, 0, len(items))
+	for _, s := range updated {
+		for _, item := range items {
+			if item["id"] == s.ID {
+				changed = append(changed, s)
+			}
+		}
+	}
+	return changed, nil
+}
+
+// zoneSettingID maps a CLI flag name (always-use-https) to the API setting
+// ID (always_use_https).
+func zoneSettingID(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+}
+
+// parseSettingValue sends integers as numbers and JSON objects/arrays as-is;
+// everything else (on/off, strict, 1.2, ...) is a string.
+func parseSettingValue(v string) any {
+	v = strings.TrimSpace(v)
+	if n, err := strconv.Atoi(v); err == nil {
+		return n
+	}
+	if strings.HasPrefix(v, "{") || strings.HasPrefix(v, "[") {
+		if json.Valid([]byte(v)) {
+			return json.RawMessage(v)
+		}
+	}
+	return v
+}
+
+func settingValueString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// runDevMode toggles or reports development mode, which Cloudflare turns off
+// automatically three hours after it is enabled.
+func runDevMode(args []string) error {
+	positional, flags := splitArgs(args)
+	action := "status"
+	if len(positional) > 0 {
+		action = positional[0]
+	}
+	zoneName := zoneOrDefault(flags["zone"])
+	if zoneName == "" {
+		return usageErrorf("missing required flag for zones dev-mode: --zone")
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	path := "/zones/" + z.ID + "/settings/development_mode"
+	var resp apiResponse
+	switch action {
+	case "on", "off":
+		resp, err = requestCF(http.MethodPatch, path, map[string]string{"value": action})
+	case "status":
+		resp, err = requestCF(http.MethodGet, path, nil)
+	default:
+		return usageErrorf("usage: cf zones dev-mode on|off|status --zone <zone>")
+	}
+	if err != nil {
+		return err
+	}
+
+	var setting zoneSetting
+	if err := json.Unmarshal(resp.Result, &setting); err != nil {
+		return err
+	}
+
+	return printResult(setting, func() {
+		value := settingValueString(setting.Value)
+		if value == "on" && setting.TimeRemaining != nil && *setting.TimeRemaining > 0 {
+			remaining := time.Duration(*setting.TimeRemaining) * time.Second
+			fmt.Printf("Development mode for %s: on (turns off automatically in %s)\n", z.Name, remaining)
+			return
+		}
+		fmt.Printf("Development mode for %s: %s\n", z.Name, value)
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestZoneSettingsGet(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones/z1/settings", []zoneSetting{
+		{ID: "ssl", Value: []byte(`"strict"`), Editable: true},
+		{ID: "always_use_https", Value: []byte(`"on"`), Editable: true},
+		{ID: "rocket_loader", Value: []byte(`"off"`), Editable: true},
+	})
+
+	out, err := captureStdout(t, func() error { return runZones([]string{"settings", "get", "example.com"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "ssl = strict\nalways_use_https = on\n" {
+		t.Fatalf("expected the common settings only, got:\n%s", out)
+	}
+
+	out, err = captureStdout(t, func() error { return runZones([]string{"settings", "get", "example.com", "rocket-loader"}) })
+	if err != nil || out != "rocket_loader = off\n" {
+		t.Fatalf("expected the named setting, got err=%v:\n%s", err, out)
+	}
+
+	err = runZones([]string{"settings", "get", "example.com", "nope"})
+	if err == nil || !strings.Contains(err.Error(), `unknown zone setting "nope"`) {
+		t.Fatalf("expected an unknown setting error, got %v", err)
+	}
+}
+
+func TestZoneSettingsSet(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("PATCH", "/zones/z1/settings", []zoneSetting{
+		{ID: "ssl", Value: []byte(`"strict"`)},
+		{ID: "browser_cache_ttl", Value: []byte(`14400`)},
+		{ID: "brotli", Value: []byte(`"on"`)},
+	})
+
+	out, err := captureStdout(t, func() error {
+		return runZones([]string{"settings", "set", "example.com", "--ssl", "strict", "--browser-cache-ttl", "14400"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body struct {
+		Items []map[string]any `json:"items"`
+	}
+	if calls := srv.Calls("PATCH", "/zones/z1/settings"); len(calls) != 1 {
+		t.Fatalf("expected one PATCH, got %d", len(calls))
+	} else if err := calls[0].Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Items) != 2 || body.Items[0]["id"] != "browser_cache_ttl" || body.Items[0]["value"] != 14400.0 || body.Items[1]["id"] != "ssl" || body.Items[1]["value"] != "strict" {
+		t.Fatalf("unexpected items %v", body.Items)
+	}
+	// The endpoint echoes every setting; only the changed ones are reported.
+	if strings.Contains(out, "brotli") || !strings.Contains(out, "Zone setting updated: example.com ssl = strict") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestZoneSettingsSetAPIError(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Fail("PATCH", "/zones/z1/settings", 400, 1007, "Invalid value for zone setting ssl")
+
+	err := runZones([]string{"settings", "set", "example.com", "--ssl", "bogus"})
+	if exitCode(err) != exitAPI || !strings.Contains(err.Error(), "Invalid value for zone setting ssl") {
+		t.Fatalf("expected the API error, got %v", err)
+	}
+	if err := runZones([]string{"settings", "set", "example.com"}); err == nil {
+		t.Fatal("expected an error without settings")
+	}
+}
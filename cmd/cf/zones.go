@@ -0,0 +1,570 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+func runZones(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("usage: cf zones list|get|add|settings|dev-mode|nameservers|check|plan|pause|unpause|delete. run: cf zones --help")
+	}
+	switch args[0] {
+	case "list":
+		limit, err := parseIntWithDefault(parseFlags(args[1:])["limit"], 0)
+		if err != nil {
+			return usageErrorf("invalid --limit: %w", err)
+		}
+		return listZones(limit)
+	case "get":
+		positional, _ := splitArgs(args[1:])
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf zones get <zone>")
+		}
+		return showZone(positional[0])
+	case "add":
+		positional, flags := splitArgs(args[1:])
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf zones add <domain> [--type full|partial] [--no-scan]")
+		}
+		zoneType := strings.ToLower(flags["type"])
+		switch zoneType {
+		case "":
+			zoneType = "full"
+		case "full", "partial":
+		default:
+			return usageErrorf("invalid --type %q: use full or partial", flags["type"])
+		}
+		jumpStart := parseBoolWithDefault(flags["jump-start"], true) && !parseBoolWithDefault(flags["no-scan"], false)
+		z, err := addZone(positional[0], zoneType, jumpStart)
+		if err != nil {
+			return err
+		}
+		return printResult(z, func() {})
+	case "settings":
+		return runZoneSettings(args[1:])
+	case "dev-mode":
+		return runDevMode(args[1:])
+	case "nameservers":
+		positional, flags := splitArgs(args[1:])
+		zoneName := flags["zone"]
+		if len(positional) > 0 {
+			zoneName = positional[0]
+		}
+		zoneName = zoneOrDefault(zoneName)
+		if zoneName == "" {
+			return usageErrorf("usage: cf zones nameservers <zone>")
+		}
+		return showZoneNameservers(zoneName)
+	case "check":
+		zoneName := zoneOrDefault(parseFlags(args[1:])["zone"])
+		if zoneName == "" {
+			return usageErrorf("missing required flag for zones check: --zone")
+		}
+		return checkZone(zoneName)
+	case "plan":
+		return runZonePlan(args[1:])
+	case "pause", "unpause":
+		positional, _ := splitArgs(args[1:])
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf zones %s <zone>", args[0])
+		}
+		return setZonePaused(positional[0], args[0] == "pause")
+	case "delete":
+		positional, flags := splitArgs(args[1:])
+		if len(positional) == 0 {
+			return usageErrorf("usage: cf zones delete <zone> [--force]")
+		}
+		return deleteZone(positional[0], flags)
+	}
+	return usageErrorf("unknown zones command %q. run: cf zones --help", args[0])
+}
+
+// deleteZone removes a zone after the user retypes its name. force skips the
+// confirmation for scripts.
+func deleteZone(zoneName string, flags map[string]string) error {
+	if err := requireConfirmable(flags, "zones delete"); err != nil {
+		return err
+	}
+
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	if !skipConfirm(flags) {
+		fmt.Printf("This permanently deletes zone %s (id=%s, status=%s) and all of its DNS records and settings.\n", z.Name, z.ID, z.Status)
+		typed, err := prompt(bufio.NewReader(os.Stdin), "Type the zone name to confirm", "")
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(strings.TrimSuffix(typed, "."), z.Name) {
+			return errors.New("confirmation did not match zone name; nothing deleted")
+		}
+	}
+
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	err = c.DeleteZone(rootCtx, z.ID)
+	forgetZones()
+	if err != nil {
+		return err
+	}
+
+	return printResult(z, func() {
+		fmt.Printf("Zone deleted: %s (id=%s)\n", z.Name, z.ID)
+	})
+}
+
+// showZone prints everything the API reports about a zone.
+func showZone(zoneName string) error {
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+	return printResult(z, func() { printZone(z) })
+}
+
+func printZone(z *zone) {
+	row := func(label, value string) {
+		if value != "" {
+			fmt.Printf("  %-20s %s\n", label+":", value)
+		}
+	}
+	fmt.Printf("%s (id=%s)\n", z.Name, z.ID)
+	status := z.Status
+	if z.Paused {
+		status += ", paused (traffic bypasses Cloudflare)"
+	}
+	row("status", status)
+	row("type", z.Type)
+	if p := z.Plan; p != nil {
+		plan := p.Name
+		if p.Price > 0 {
+			plan += fmt.Sprintf(" (%.2f %s/%s)", p.Price, p.Currency, p.Frequency)
+		}
+		row("plan", plan)
+	}
+	if a := z.Account; a != nil {
+		row("account", fmt.Sprintf("%s (%s)", a.Name, a.ID))
+	}
+	row("nameservers", strings.Join(z.NameServers, ", "))
+	row("vanity nameservers", strings.Join(z.VanityNameServers, ", "))
+	row("original ns", strings.Join(z.OriginalNameServers, ", "))
+	row("original registrar", z.OriginalRegistrar)
+	row("original DNS host", z.OriginalDNSHost)
+	row("created", z.CreatedOn)
+	row("activated", z.ActivatedOn)
+	row("modified", z.ModifiedOn)
+	row("features", strings.Join(zoneFeatures(z), ", "))
+	if m := z.Meta; m != nil {
+		if m.PageRuleQuota > 0 {
+			row("page rule quota", fmt.Sprint(m.PageRuleQuota))
+		}
+		if m.CustomCertificateQuota > 0 {
+			row("custom cert quota", fmt.Sprint(m.CustomCertificateQuota))
+		}
+		if m.PhishingDetected {
+			fmt.Println("  WARNING: Cloudflare has flagged phishing on this zone.")
+		}
+	}
+}
+
+// zoneFeatures lists the notable features enabled on a zone.
+func zoneFeatures(z *zone) []string {
+	var out []string
+	if z.DevelopmentMode > 0 {
+		out = append(out, fmt.Sprintf("development mode (%s left)", time.Duration(z.DevelopmentMode)*time.Second))
+	}
+	if m := z.Meta; m != nil {
+		for _, f := range []struct {
+			on   bool
+			name string
+		}{
+			{m.WildcardProxiable, "wildcard proxying"},
+			{m.FoundationDNS, "foundation DNS"},
+			{m.CDNOnly, "CDN only"},
+			{m.DNSOnly, "DNS only"},
+		} {
+			if f.on {
+				out = append(out, f.name)
+			}
+		}
+	}
+	return out
+}
+
+// setZonePaused toggles whether Cloudflare proxies a zone's traffic, which
+// helps when debugging the origin directly.
+func setZonePaused(zoneName string, paused bool) error {
+	// The pause state may have changed in the dashboard since the zone was
+	// cached.
+	z, err := requireFreshZone(zoneName)
+	if err != nil {
+		return err
+	}
+	if z.Paused == paused {
+		return printResult(z, func() {
+			fmt.Printf("Zone %s is already %s.\n", z.Name, pausedState(paused))
+		})
+	}
+
+	c, err := apiClient()
+	if err != nil {
+		return err
+	}
+	updated, err := c.SetZonePaused(rootCtx, z.ID, paused)
+	forgetZones()
+	if err != nil {
+		return err
+	}
+	if updated.ID == "" {
+		// Dry run: nothing came back.
+		z.Paused = paused
+		updated = z
+	}
+
+	return printResult(updated, func() {
+		if paused {
+			fmt.Printf("Zone %s paused: proxied traffic now goes straight to the origin (DNS is still served by Cloudflare).\n", z.Name)
+			fmt.Printf("Undo with: cf zones unpause %s\n", z.Name)
+			return
+		}
+		fmt.Printf("Zone %s unpaused: traffic is proxied through Cloudflare again.\n", z.Name)
+	})
+}
+
+func pausedState(paused bool) string {
+	if paused {
+		return "paused"
+	}
+	return "active (not paused)"
+}
+
+// zoneForHostname finds the most specific zone in the account that contains
+// host, trying host itself and then each parent domain.
+func zoneForHostname(host string) (*zone, error) {
+	labels := strings.Split(strings.TrimSuffix(strings.ToLower(host), "."), ".")
+	for i := 0; i < len(labels)-1; i++ {
+		z, err := getZoneByName(strings.Join(labels[i:], "."))
+		if err != nil {
+			return nil, err
+		}
+		if z != nil {
+			return z, nil
+		}
+	}
+	return nil, fmt.Errorf("no zone in this account contains %s; pass --zone or run: cf zones add <domain>", host)
+}
+
+var lookupNS = func(ctx context.Context, host string) ([]*net.NS, error) {
+	return net.DefaultResolver.LookupNS(ctx, host)
+}
+
+// delegatedNameservers returns the nameservers live DNS delegates host to. A
+// name without NS records has an empty delegation; any other lookup failure
+// is returned so that it is not mistaken for one.
+func delegatedNameservers(ctx context.Context, host string) ([]string, error) {
+	records, err := lookupNS(ctx, host)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			return nil, fmt.Errorf("nameserver lookup for %s failed: %w", host, err)
+		}
+	}
+	delegated := make([]string, 0, len(records))
+	for _, r := range records {
+		delegated = append(delegated, r.Host)
+	}
+	return normalizeNameservers(delegated), nil
+}
+
+var lookupTXT = func(ctx context.Context, host string) ([]string, error) {
+	return net.DefaultResolver.LookupTXT(ctx, host)
+}
+
+type zoneCheck struct {
+	Zone            string   `json:"zone"`
+	Status          string   `json:"status"`
+	ActivationCheck string   `json:"activation_check"`
+	Assigned        []string `json:"assigned_name_servers"`
+	Delegated       []string `json:"delegated_name_servers"`
+	Missing         []string `json:"missing_name_servers,omitempty"`
+	Extra           []string `json:"extra_name_servers,omitempty"`
+
+	// From RDAP when delegation fails: what the registry holds, which live
+	// DNS lags behind, and where to change it.
+	Registrar           string   `json:"registrar,omitempty"`
+	RegistryNameservers []string `json:"registry_name_servers,omitempty"`
+	DNSSEC              bool     `json:"dnssec,omitempty"`
+
+	// Partial zones are verified by a TXT record instead of delegation.
+	Type               string   `json:"type,omitempty"`
+	VerificationRecord string   `json:"verification_record,omitempty"`
+	VerificationKey    string   `json:"verification_key,omitempty"`
+	VerificationFound  []string `json:"verification_found,omitempty"`
+
+	Pass bool `json:"pass"`
+}
+
+// checkZone asks Cloudflare to re-check activation, then compares the
+// nameservers the domain is delegated to in live DNS against the ones
+// Cloudflare assigned to the zone.
+func checkZone(zoneName string) error {
+	z, err := requireFreshZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	result := zoneCheck{Zone: z.Name, Status: z.Status, Assigned: normalizeNameservers(z.NameServers)}
+
+	if z.Status == "active" {
+		result.ActivationCheck = "skipped (zone already active)"
+	} else if _, err := requestCF(http.MethodPut, "/zones/"+z.ID+"/activation_check", nil); err != nil {
+		// The endpoint is rate limited; a failure here should not hide the
+		// nameserver comparison.
+		result.ActivationCheck = "failed: " + err.Error()
+	} else {
+		result.ActivationCheck = "requested"
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, 10*time.Second)
+	defer cancel()
+	if z.Type == "partial" {
+		return checkPartialZone(ctx, z, result)
+	}
+	delegated, err := delegatedNameservers(ctx, z.Name)
+	if err != nil {
+		return err
+	}
+	result.Delegated = delegated
+	result.Pass, result.Missing, result.Extra = compareNameservers(result.Assigned, result.Delegated)
+	if !result.Pass {
+		// Best effort: many TLDs do not publish RDAP.
+		if w, err := whoisDomain(z.Name); err == nil {
+			result.Registrar, result.RegistryNameservers, result.DNSSEC = w.Registrar, w.Nameservers, w.DNSSEC
+		}
+	}
+
+	if err := printResult(result, func() { printZoneCheck(result) }); err != nil {
+		return err
+	}
+	if !result.Pass {
+		return fmt.Errorf("nameserver check failed for %s", z.Name)
+	}
+	return nil
+}
+
+// checkPartialZone looks for the ownership TXT record a partial zone needs
+// at the customer's DNS provider.
+func checkPartialZone(ctx context.Context, z *zone, result zoneCheck) error {
+	result.Type = z.Type
+	result.Assigned = nil
+	result.VerificationRecord = partialVerificationName(z.Name)
+	result.VerificationKey = z.VerificationKey
+
+	found, err := lookupTXT(ctx, result.VerificationRecord)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			return fmt.Errorf("TXT lookup for %s failed: %w", result.VerificationRecord, err)
+		}
+	}
+	result.VerificationFound = found
+	result.Pass = z.Status == "active" || (z.VerificationKey != "" && slices.Contains(found, z.VerificationKey))
+
+	if err := printResult(result, func() { printPartialZoneCheck(result) }); err != nil {
+		return err
+	}
+	if !result.Pass {
+		return fmt.Errorf("verification record check failed for %s", z.Name)
+	}
+	return nil
+}
+
+func printPartialZoneCheck(c zoneCheck) {
+	fmt.Printf("Zone %s (partial): status=%s\n", c.Zone, c.Status)
+	fmt.Printf("Activation check: %s\n", c.ActivationCheck)
+	if len(c.VerificationFound) == 0 {
+		fmt.Printf("TXT %s (live DNS): none found\n", c.VerificationRecord)
+	} else {
+		fmt.Printf("TXT %s (live DNS): %s\n", c.VerificationRecord, strings.Join(c.VerificationFound, ", "))
+	}
+	if c.Pass {
+		fmt.Println("PASS: the ownership record is in place.")
+		fmt.Println("Point each proxied hostname at <hostname>.cdn.cloudflare.net with a CNAME at your DNS provider.")
+		return
+	}
+	fmt.Println("FAIL: the ownership record is missing or does not match.")
+	fmt.Printf("At your DNS provider, add: %s  TXT  %s\n", c.VerificationRecord, c.VerificationKey)
+	fmt.Printf("Then rerun: cf zones check --zone %s\n", c.Zone)
+}
+
+func normalizeNameservers(in []string) []string {
+	out := make([]string, 0, len(in))
+	for _, ns := range in {
+		out = append(out, strings.ToLower(strings.TrimSuffix(strings.TrimSpace(ns), ".")))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// compareNameservers reports whether delegated exactly matches assigned,
+// along with assigned servers missing from the delegation and delegated
+// servers Cloudflare did not assign.
+func compareNameservers(assigned, delegated []string) (bool, []string, []string) {
+	inDelegated := map[string]bool{}
+	for _, ns := range delegated {
+		inDelegated[ns] = true
+	}
+	inAssigned := map[string]bool{}
+	for _, ns := range assigned {
+		inAssigned[ns] = true
+	}
+
+	var missing, extra []string
+	for _, ns := range assigned {
+		if !inDelegated[ns] {
+			missing = append(missing, ns)
+		}
+	}
+	for _, ns := range delegated {
+		if !inAssigned[ns] {
+			extra = append(extra, ns)
+		}
+	}
+	return len(assigned) > 0 && len(missing) == 0 && len(extra) == 0, missing, extra
+}
+
+func printZoneCheck(c zoneCheck) {
+	fmt.Printf("Zone %s: status=%s\n", c.Zone, c.Status)
+	fmt.Printf("Activation check: %s\n", c.ActivationCheck)
+	fmt.Printf("Assigned Cloudflare nameservers: %s\n", strings.Join(c.Assigned, ", "))
+	if len(c.Delegated) == 0 {
+		fmt.Println("Delegated nameservers (live DNS): none found")
+	} else {
+		fmt.Printf("Delegated nameservers (live DNS): %s\n", strings.Join(c.Delegated, ", "))
+	}
+
+	if c.Pass {
+		fmt.Println("PASS: the domain is delegated to Cloudflare's nameservers.")
+		if c.Status != "active" {
+			fmt.Println("Cloudflare will mark the zone active once its own check sees the change.")
+		}
+		return
+	}
+
+	fmt.Println("FAIL: the domain is not delegated to Cloudflare's nameservers.")
+	if c.Registrar != "" {
+		fmt.Printf("Registrar (RDAP): %s\n", c.Registrar)
+	}
+	if len(c.RegistryNameservers) > 0 {
+		if ok, _, _ := compareNameservers(c.Assigned, c.RegistryNameservers); ok {
+			fmt.Println("The registry already lists Cloudflare's nameservers; live DNS has not caught up yet.")
+			fmt.Printf("Wait for the change to propagate (up to 24 hours), then rerun: cf zones check --zone %s\n", c.Zone)
+			return
+		}
+		fmt.Printf("Registry nameservers (RDAP): %s\n", strings.Join(c.RegistryNameservers, ", "))
+	}
+	if c.DNSSEC {
+		fmt.Println("DNSSEC is on at the registrar; turn it off before changing nameservers or the domain will stop resolving.")
+	}
+	fmt.Println("Next steps:")
+	if g, ok := findRegistrarGuide(c.Registrar); ok {
+		fmt.Printf("  1. At %s (%s), set the nameservers for %s to exactly: %s\n", g.Name, g.Steps, c.Zone, strings.Join(c.Assigned, ", "))
+	} else {
+		fmt.Printf("  1. At your registrar, set the nameservers for %s to exactly: %s\n", c.Zone, strings.Join(c.Assigned, ", "))
+	}
+	if len(c.Extra) > 0 {
+		fmt.Printf("  2. Remove these nameservers: %s\n", strings.Join(c.Extra, ", "))
+	} else {
+		fmt.Println("  2. Make sure no other nameservers are listed alongside them.")
+	}
+	fmt.Printf("  3. Wait for the change to propagate (up to 24 hours), then rerun: cf zones check --zone %s\n", c.Zone)
+}
+
+// printActivationInstructions tells the user the next step after adding a
+// zone that is not yet active: new nameservers for a full zone, or DNS
+// records at the current provider for a partial one.
+func printActivationInstructions(z *zone) {
+	if z != nil && z.Type == "partial" && z.Status != "active" {
+		printPartialSetupInstructions(z)
+		return
+	}
+	if z == nil || len(z.NameServers) == 0 || z.Status == "active" {
+		return
+	}
+	infof("Set these nameservers at your registrar to activate the zone:\n")
+	for _, ns := range z.NameServers {
+		infof("  %s\n", ns)
+	}
+}
+
+func printPartialSetupInstructions(z *zone) {
+	infof("This is a partial (CNAME) setup: DNS stays at your current provider.\n")
+	if z.VerificationKey != "" {
+		infof("1. Verify ownership by adding this TXT record at your DNS provider:\n")
+		infof("     %s  TXT  %s\n", partialVerificationName(z.Name), z.VerificationKey)
+	} else {
+		infof("1. Verify ownership with the TXT record shown in the dashboard under DNS > Records.\n")
+	}
+	infof("2. Add each hostname to proxy here (cf dns add --zone %s ...), then CNAME it at your provider, e.g.:\n", z.Name)
+	infof("     www.%s  CNAME  www.%s.cdn.cloudflare.net\n", z.Name, z.Name)
+	infof("3. Check progress with: cf zones check --zone %s\n", z.Name)
+}
+
+// printImportedRecords lists the DNS records the jump-start scan imported,
+// so nothing unexpected goes live unnoticed.
+func printImportedRecords(z *zone) {
+	records, err := listDNSRecords(z.ID)
+	if err != nil {
+		infof("Could not list imported DNS records: %v\n", err)
+		return
+	}
+	if len(records) == 0 {
+		infof("The DNS scan imported no records.\n")
+		return
+	}
+	infof("The DNS scan imported %d record(s); review them before changing nameservers:\n", len(records))
+	for _, r := range records {
+		infof("  %s %s -> %s  proxied=%t  id=%s\n", r.Type, r.Name, r.Content, r.Proxied, r.ID)
+	}
+	infof("Remove unwanted ones in the dashboard, or with: cf dns sync --zone %s --file <records.yaml> --prune\n", z.Name)
+}
+
+// partialVerificationName is where Cloudflare looks for a partial zone's
+// ownership TXT record.
+func partialVerificationName(zoneName string) string {
+	return "cloudflare-verify." + zoneName
+}
+
+func showZoneNameservers(zoneName string) error {
+	z, err := requireZone(zoneName)
+	if err != nil {
+		return err
+	}
+
+	out := struct {
+		Zone        string   `json:"zone"`
+		Status      string   `json:"status"`
+		NameServers []string `json:"name_servers"`
+	}{z.Name, z.Status, z.NameServers}
+
+	return printResult(out, func() {
+		fmt.Printf("Cloudflare nameservers for %s (status=%s):\n", z.Name, z.Status)
+		for _, ns := range z.NameServers {
+			fmt.Printf("  %s\n", ns)
+		}
+	})
+}
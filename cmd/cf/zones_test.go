@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cf/internal/cftest"
+	"cf/pkg/cfapi"
+)
+
+func TestCompareNameservers(t *testing.T) {
+	assigned := normalizeNameservers([]string{"Ada.ns.cloudflare.com.", "bob.ns.cloudflare.com"})
+
+	ok, missing, extra := compareNameservers(assigned, normalizeNameservers([]string{"bob.ns.cloudflare.com.", "ada.ns.cloudflare.com"}))
+	if !ok || len(missing) != 0 || len(extra) != 0 {
+		t.Fatalf("expected match, got ok=%t missing=%v extra=%v", ok, missing, extra)
+	}
+
+	ok, missing, extra = compareNameservers(assigned, []string{"ada.ns.cloudflare.com", "ns1.registrar.example"})
+	if ok {
+		t.Fatalf("expected mismatch")
+	}
+	if !reflect.DeepEqual(missing, []string{"bob.ns.cloudflare.com"}) || !reflect.DeepEqual(extra, []string{"ns1.registrar.example"}) {
+		t.Fatalf("unexpected diff: missing=%v extra=%v", missing, extra)
+	}
+
+	if ok, _, _ := compareNameservers(nil, nil); ok {
+		t.Fatalf("expected no assigned nameservers to fail")
+	}
+}
+
+func TestShowZone(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones?name=example.com", []zone{{
+		ID: "z1", Name: "example.com", Status: "active", Type: "full", Paused: true,
+		NameServers:       []string{"ada.ns.cloudflare.com", "bob.ns.cloudflare.com"},
+		OriginalRegistrar: "example registrar",
+		DevelopmentMode:   600,
+		Plan:              &cfapi.ZonePlan{Name: "Pro Website", Price: 20, Currency: "USD", Frequency: "monthly"},
+		Meta:              &cfapi.ZoneMeta{WildcardProxiable: true},
+	}})
+
+	out, err := captureStdout(t, func() error { return showZone("example.com") })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"paused (traffic bypasses Cloudflare)",
+		"Pro Website (20.00 USD/monthly)",
+		"ada.ns.cloudflare.com, bob.ns.cloudflare.com",
+		"example registrar",
+		"development mode (10m0s left), wildcard proxying",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSetZonePaused(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Handle("PATCH", "/zones/z1", func(r cftest.Request) cftest.Response {
+		var body map[string]bool
+		r.Decode(&body)
+		return cftest.Response{Result: zone{ID: "z1", Name: "example.com", Status: "active", Paused: body["paused"]}}
+	})
+
+	out, err := captureStdout(t, func() error { return setZonePaused("example.com", true) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Zone example.com paused") {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	calls := srv.Calls("PATCH", "/zones/z1")
+	if len(calls) != 1 || string(calls[0].Body) != `{"paused":true}` {
+		t.Fatalf("unexpected requests: %+v", calls)
+	}
+
+	// Unpausing a zone that is not paused sends nothing.
+	if _, err := captureStdout(t, func() error { return setZonePaused("example.com", false) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(srv.Calls("PATCH", "/zones/z1")); n != 1 {
+		t.Fatalf("expected no further PATCH, got %d in total", n)
+	}
+}
+
+func TestSetZonePausedIgnoresCache(t *testing.T) {
+	srv := useFakeAPI(t)
+	t.Setenv("CF_CACHE_TTL", "5m")
+	origStarted := runStarted
+	t.Cleanup(func() { runStarted = origStarted })
+	srv.Reply("PATCH", "/zones/z1", zone{ID: "z1", Name: "example.com", Status: "active"})
+
+	if _, err := requireZone("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	// Paused in the dashboard after the lookup above was cached.
+	srv.Reply("GET", "/zones?name=example.com", []zone{{ID: "z1", Name: "example.com", Status: "active", Paused: true}})
+	zoneCacheLoaded, runStarted = nil, time.Now().Add(time.Second)
+
+	out, err := captureStdout(t, func() error { return runZones([]string{"unpause", "example.com"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	calls := srv.Calls("PATCH", "/zones/z1")
+	if len(calls) != 1 || string(calls[0].Body) != `{"paused":false}` || strings.Contains(out, "already") {
+		t.Fatalf("expected the zone to be unpaused, got %d PATCHes:\n%s", len(calls), out)
+	}
+}
+
+func TestAddPartialZone(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("POST", "/zones", zone{ID: "z2", Name: "partial.example", Status: "pending", Type: "partial", VerificationKey: "476754457-428595283"})
+
+	out, err := captureStdout(t, func() error { return runZones([]string{"add", "partial.example", "--type", "partial"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var body map[string]any
+	if err := srv.Calls("POST", "/zones")[0].Decode(&body); err != nil || body["type"] != "partial" {
+		t.Fatalf("expected type partial in request, got %v (err=%v)", body, err)
+	}
+	if !strings.Contains(out, "cloudflare-verify.partial.example  TXT  476754457-428595283") {
+		t.Fatalf("expected verification record instructions, got:\n%s", out)
+	}
+
+	if err := runZones([]string{"add", "x.example", "--type", "secondary"}); exitCode(err) != exitUsage {
+		t.Fatalf("expected usage error for unknown type, got %v", err)
+	}
+}
+
+func TestCheckPartialZone(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones?name=example.com", []zone{{ID: "z1", Name: "example.com", Status: "pending", Type: "partial", VerificationKey: "key-1"}})
+	srv.Reply("PUT", "/zones/z1/activation_check", map[string]string{"id": "z1"})
+
+	orig := lookupTXT
+	t.Cleanup(func() { lookupTXT = orig })
+	txt := []string{"something-else"}
+	lookupTXT = func(_ context.Context, host string) ([]string, error) {
+		if host != "cloudflare-verify.example.com" {
+			t.Fatalf("unexpected lookup of %s", host)
+		}
+		return txt, nil
+	}
+
+	out, err := captureStdout(t, func() error { return checkZone("example.com") })
+	if err == nil || !strings.Contains(out, "add: cloudflare-verify.example.com  TXT  key-1") {
+		t.Fatalf("expected failure with instructions, got err=%v:\n%s", err, out)
+	}
+
+	txt = append(txt, "key-1")
+	if _, err := captureStdout(t, func() error { return checkZone("example.com") }); err != nil {
+		t.Fatalf("expected pass once the record exists, got %v", err)
+	}
+}
+
+func TestCheckZoneUsesRegistry(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("GET", "/zones?name=example.com", []zone{{ID: "z1", Name: "example.com", Status: "pending", NameServers: []string{"ada.ns.cloudflare.com", "bob.ns.cloudflare.com"}}})
+	srv.Reply("PUT", "/zones/z1/activation_check", map[string]string{"id": "z1"})
+
+	origNS, origRDAP := lookupNS, lookupRDAP
+	t.Cleanup(func() { lookupNS, lookupRDAP = origNS, origRDAP })
+	lookupNS = func(context.Context, string) ([]*net.NS, error) {
+		return []*net.NS{{Host: "dns1.registrar-servers.com."}}, nil
+	}
+	registry := &rdapDomain{Entities: []rdapEntity{{Roles: []string{"registrar"}, VCardArray: []any{"vcard", []any{[]any{"fn", map[string]any{}, "text", "NameCheap, Inc."}}}}}}
+	registry.SecureDNS.DelegationSigned = true
+	lookupRDAP = func(string) (*rdapDomain, error) { return registry, nil }
+
+	out, err := captureStdout(t, func() error { return checkZone("example.com") })
+	if err == nil || !strings.Contains(out, "At Namecheap (Domain List > Manage") || !strings.Contains(out, "DNSSEC is on") {
+		t.Fatalf("expected registrar-specific steps, got err=%v:\n%s", err, out)
+	}
+
+	registry.SecureDNS.DelegationSigned = false
+	registry.Nameservers = []struct {
+		Name string `json:"ldhName"`
+	}{{"ADA.NS.CLOUDFLARE.COM"}, {"BOB.NS.CLOUDFLARE.COM"}}
+	out, _ = captureStdout(t, func() error { return checkZone("example.com") })
+	if !strings.Contains(out, "registry already lists Cloudflare's nameservers") {
+		t.Fatalf("expected a propagation note, got:\n%s", out)
+	}
+}
+
+func TestAddZoneJumpStart(t *testing.T) {
+	srv := useFakeAPI(t)
+	srv.Reply("POST", "/zones", zone{ID: "z3", Name: "new.example", Status: "pending"})
+	srv.Reply("GET", "/zones/z3/dns_records", []dnsRecord{{ID: "r1", Type: "A", Name: "new.example", Content: "192.0.2.7"}})
+
+	out, err := captureStdout(t, func() error { return runZones([]string{"add", "new.example"}) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "imported 1 record(s)") || !strings.Contains(out, "A new.example -> 192.0.2.7") {
+		t.Fatalf("expected imported records to be listed, got:\n%s", out)
+	}
+
+	if _, err := captureStdout(t, func() error { return runZones([]string{"add", "new.example", "--no-scan"}) }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	posts := srv.Calls("POST", "/zones")
+	var body map[string]any
+	if err := posts[len(posts)-1].Decode(&body); err != nil || body["jump_start"] != false {
+		t.Fatalf("expected jump_start false with --no-scan, got %v (err=%v)", body, err)
+	}
+	if n := len(srv.Calls("GET", "/zones/z3/dns_records")); n != 1 {
+		t.Fatalf("expected records to be listed only after a scan, got %d lists", n)
+	}
+}
+
+func TestWaitForZoneActive(t *testing.T) {
+	srv := useFakeAPI(t)
+	polls := 0
+	srv.Handle("GET", "/zones?name=example.com", func(cftest.Request) cftest.Response {
+		polls++
+		status := "pending"
+		if polls >= 3 {
+			status = "active"
+		}
+		return cftest.Response{Result: []zone{{ID: "z1", Name: "example.com", Status: status}}}
+	})
+	srv.Reply("PUT", "/zones/z1/activation_check", map[string]string{"id": "z1"})
+
+	origSleep, origNS := sleep, lookupNS
+	t.Cleanup(func() { sleep, lookupNS = origSleep, origNS })
+	sleep = func(time.Duration) error { return nil }
+	lookupNS = func(context.Context, string) ([]*net.NS, error) {
+		return []*net.NS{{Host: "ada.ns.cloudflare.com."}, {Host: "bob.ns.cloudflare.com."}}, nil
+	}
+
+	z := &zone{ID: "z1", Name: "example.com", Status: "pending", NameServers: []string{"ada.ns.cloudflare.com", "bob.ns.cloudflare.com"}}
+	out, err := captureStdout(t, func() error { return waitForZoneActive(z, time.Second, time.Hour) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Zone example.com is active.") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if n := len(srv.Calls("PUT", "/zones/z1/activation_check")); n != 1 {
+		t.Fatalf("expected one activation check, got %d", n)
+	}
+}
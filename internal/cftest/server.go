@@ -0,0 +1,230 @@
+// Package cftest runs a fake Cloudflare v4 API for tests. Routes return
+// canned results wrapped in the standard response envelope, and every
+// request is recorded so tests can assert on what was sent.
+//
+//	srv := cftest.NewServer(t)
+//	srv.Reply("GET", "/zones", []map[string]any{{"id": "z1", "name": "example.com"}})
+//	srv.Fail("POST", "/zones/z1/dns_records", 400, 81057, "Record already exists.")
+//	client.BaseURL = srv.URL
+package cftest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Request is a request the server received.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Decode unmarshals the JSON request body into v.
+func (r Request) Decode(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Response is what a route returns. Result is marshalled into the envelope's
+// result field; a non-empty Errors marks the response unsuccessful.
+type Response struct {
+	Status     int
+	Result     any
+	ResultInfo map[string]any
+	Errors     []Error
+}
+
+// Error is one entry in the envelope's errors list.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Server is a fake API server. Its URL can be used as a client base URL.
+type Server struct {
+	*httptest.Server
+
+	t        testing.TB
+	mu       sync.Mutex
+	routes   []route
+	requests []Request
+}
+
+type route struct {
+	method   string
+	segments []string
+	query    url.Values
+	handle   func(Request) Response
+}
+
+// NewServer starts a server that is closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{t: t}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Handle routes method and pattern to h. A pattern is a path in which "*"
+// matches any one segment, optionally followed by "?key=value" pairs the
+// request query must contain. Later routes take precedence, so a test can
+// override a shared fixture.
+func (s *Server) Handle(method, pattern string, h func(Request) Response) {
+	path, rawQuery, _ := strings.Cut(pattern, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		s.t.Fatalf("cftest: bad pattern %q: %v", pattern, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append(s.routes, route{method: method, segments: splitPath(path), query: query, handle: h})
+}
+
+// Reply routes method and pattern to a successful response with result. A
+// slice result is paginated by the page and per_page query parameters, as
+// list endpoints are.
+func (s *Server) Reply(method, pattern string, result any) {
+	s.Handle(method, pattern, func(r Request) Response {
+		v := reflect.ValueOf(result)
+		if v.Kind() != reflect.Slice {
+			return Response{Result: result}
+		}
+		return paginate(v, r.Query)
+	})
+}
+
+// Fail routes method and pattern to an error response.
+func (s *Server) Fail(method, pattern string, status, code int, message string) {
+	s.Handle(method, pattern, func(Request) Response {
+		return Response{Status: status, Errors: []Error{{Code: code, Message: message}}}
+	})
+}
+
+// Requests returns every request received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Calls returns the requests that match method and pattern.
+func (s *Server) Calls(method, pattern string) []Request {
+	path, rawQuery, _ := strings.Cut(pattern, "?")
+	query, _ := url.ParseQuery(rawQuery)
+	r := route{method: method, segments: splitPath(path), query: query}
+	var out []Request
+	for _, req := range s.Requests() {
+		if r.matches(req) {
+			out = append(out, req)
+		}
+	}
+	return out
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	req := Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Header: r.Header.Clone(), Body: body}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	var handle func(Request) Response
+	for i := len(s.routes) - 1; i >= 0; i-- {
+		if s.routes[i].matches(req) {
+			handle = s.routes[i].handle
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	resp := Response{Status: http.StatusNotFound, Errors: []Error{{Code: 7003, Message: "No route for that URI"}}}
+	if handle != nil {
+		resp = handle(req)
+	} else {
+		s.t.Logf("cftest: no route for %s %s", r.Method, r.URL)
+	}
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+
+	envelope := map[string]any{
+		"success":  len(resp.Errors) == 0 && resp.Status < 400,
+		"errors":   orEmpty(resp.Errors),
+		"messages": []any{},
+		"result":   resp.Result,
+	}
+	if resp.ResultInfo != nil {
+		envelope["result_info"] = resp.ResultInfo
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.Status)
+	json.NewEncoder(w).Encode(envelope)
+}
+
+func (r route) matches(req Request) bool {
+	if r.method != req.Method {
+		return false
+	}
+	segments := splitPath(req.Path)
+	if len(segments) != len(r.segments) {
+		return false
+	}
+	for i, seg := range r.segments {
+		if seg != "*" && seg != segments[i] {
+			return false
+		}
+	}
+	for key, values := range r.query {
+		if req.Query.Get(key) != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitPath drops the /client/v4 prefix so patterns can be written as in
+// the API docs.
+func splitPath(path string) []string {
+	path = strings.TrimPrefix(path, "/client/v4")
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func paginate(items reflect.Value, query url.Values) Response {
+	total := items.Len()
+	perPage, _ := strconv.Atoi(query.Get("per_page"))
+	if perPage <= 0 {
+		perPage = max(total, 1)
+	}
+	page, _ := strconv.Atoi(query.Get("page"))
+	page = max(page, 1)
+
+	start := min((page-1)*perPage, total)
+	end := min(start+perPage, total)
+	return Response{
+		Result: items.Slice(start, end).Interface(),
+		ResultInfo: map[string]any{
+			"page":        page,
+			"per_page":    perPage,
+			"count":       end - start,
+			"total_count": total,
+			"total_pages": (total + perPage - 1) / perPage,
+		},
+	}
+}
+
+func orEmpty(errs []Error) []Error {
+	if errs == nil {
+		return []Error{}
+	}
+	return errs
+}
@@ -0,0 +1,43 @@
+package cfapi
+
+import (
+	"context"
+	"net/http"
+)
+
+// Membership links the authenticated user or token to an account.
+type Membership struct {
+	ID      string   `json:"id"`
+	Status  string   `json:"status"`
+	Roles   []string `json:"roles"`
+	Account struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"account"`
+}
+
+// TokenVerification is the result of verifying an API token.
+type TokenVerification struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	ExpiresOn string `json:"expires_on,omitempty"`
+	NotBefore string `json:"not_before,omitempty"`
+}
+
+// ListMemberships returns the accounts the credentials can access.
+func (c *Client) ListMemberships(ctx context.Context) ([]Membership, error) {
+	var memberships []Membership
+	if err := c.getResult(ctx, http.MethodGet, "/memberships", nil, &memberships); err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
+
+// VerifyToken checks that the client's API token is valid and active.
+func (c *Client) VerifyToken(ctx context.Context) (*TokenVerification, error) {
+	var v TokenVerification
+	if err := c.getResult(ctx, http.MethodGet, "/user/tokens/verify", nil, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,265 @@
+// Package cfapi is a client for the Cloudflare v4 REST API. It handles
+// authentication, the response envelope, pagination and retries, and has
+// typed methods for zones, DNS records, the registrar and accounts. The cf
+// command is built on it.
+package cfapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultBaseURL is the Cloudflare v4 API endpoint.
+const DefaultBaseURL = "https://api.cloudflare.com/client/v4"
+
+// Credentials authenticate requests with an API token or, when APIKey is
+// set, a legacy global API key and its account email.
+type Credentials struct {
+	Token  string
+	APIKey string
+	Email  string
+}
+
+// SetHeaders adds the authentication headers for c to req.
+func (c Credentials) SetHeaders(req *http.Request) {
+	if c.APIKey != "" {
+		req.Header.Set("X-Auth-Key", c.APIKey)
+		req.Header.Set("X-Auth-Email", c.Email)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+}
+
+// Client sends requests to the Cloudflare API. The zero value is not usable;
+// create one with NewClient and adjust the fields before the first request.
+type Client struct {
+	Credentials Credentials
+
+	// BaseURL defaults to DefaultBaseURL.
+	BaseURL string
+
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// MaxRetries bounds retries of rate-limited requests and, for
+	// idempotent methods, transient failures.
+	MaxRetries int
+
+	// Logger, if set, gets a debug record for every request attempt with
+	// credentials redacted.
+	Logger *slog.Logger
+
+	// Limiter, if set, paces every request attempt and is held off when the
+	// API reports the rate limit used up.
+	Limiter *RateLimiter
+
+	// DryRun, if set, is called before every request. Returning true skips
+	// the request and the call succeeds with a null result.
+	DryRun func(method, path, contentType string, body []byte) bool
+}
+
+// NewClient returns a client for creds with the default base URL, HTTP
+// client and retry budget.
+func NewClient(creds Credentials) *Client {
+	return &Client{Credentials: creds, MaxRetries: DefaultMaxRetries}
+}
+
+// Error is one entry of the errors array in an API response.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// ResultInfo describes the page a list response covers.
+type ResultInfo struct {
+	Page       int    `json:"page"`
+	PerPage    int    `json:"per_page"`
+	Count      int    `json:"count"`
+	TotalCount int    `json:"total_count"`
+	TotalPages int    `json:"total_pages"`
+	Cursor     string `json:"cursor,omitempty"`
+	Cursors    struct {
+		Before string `json:"before,omitempty"`
+		After  string `json:"after,omitempty"`
+	} `json:"cursors,omitempty"`
+}
+
+// Response is the envelope every REST endpoint returns.
+type Response struct {
+	Success    bool            `json:"success"`
+	Errors     []Error         `json:"errors"`
+	Result     json.RawMessage `json:"result"`
+	ResultInfo *ResultInfo     `json:"result_info,omitempty"`
+}
+
+// APIError is returned for failed API calls so callers can branch on the
+// HTTP status, e.g. to treat 404 as "nothing configured yet".
+type APIError struct {
+	Method string
+	Status int
+	Errors []Error
+}
+
+func (e *APIError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("Cloudflare API request failed (HTTP %d)", e.Status)
+	}
+	parts := make([]string, 0, len(e.Errors))
+	for _, ae := range e.Errors {
+		parts = append(parts, fmt.Sprintf("%d: %s", ae.Code, ae.Message))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// IsNotFound reports whether err is an API error with HTTP status 404.
+func IsNotFound(err error) bool {
+	var se *APIError
+	return errors.As(err, &se) && se.Status == http.StatusNotFound
+}
+
+// Do sends body as JSON (nil for no body) and decodes the response envelope.
+func (c *Client) Do(ctx context.Context, method, path string, body any) (*Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.DoRaw(ctx, method, path, "application/json", payload)
+}
+
+// DoRaw sends a pre-encoded body, e.g. a multipart upload, and decodes the
+// response envelope.
+func (c *Client) DoRaw(ctx context.Context, method, path, contentType string, payload []byte) (*Response, error) {
+	if c.DryRun != nil && c.DryRun(method, path, contentType, payload) {
+		// A null result unmarshals into any type as its zero value.
+		return &Response{Success: true, Result: json.RawMessage("null")}, nil
+	}
+
+	resp, err := c.send(ctx, method, path, contentType, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out Response
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 || !out.Success {
+		return &out, &APIError{Method: method, Status: resp.StatusCode, Errors: out.Errors}
+	}
+	return &out, nil
+}
+
+// DoBytes is for endpoints that return a raw body instead of the JSON
+// envelope, such as KV values or /graphql.
+func (c *Client) DoBytes(ctx context.Context, method, path, contentType string, payload []byte) ([]byte, error) {
+	if c.DryRun != nil && c.DryRun(method, path, contentType, payload) {
+		return []byte("null"), nil
+	}
+
+	resp, err := c.send(ctx, method, path, contentType, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		var out Response
+		json.Unmarshal(data, &out)
+		return nil, &APIError{Method: method, Status: resp.StatusCode, Errors: out.Errors}
+	}
+	return data, nil
+}
+
+func (c *Client) send(ctx context.Context, method, path, contentType string, payload []byte) (*http.Response, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return c.doWithRetry(ctx, func() (*http.Request, error) {
+		var reqBody io.Reader
+		if payload != nil {
+			reqBody = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, base+path, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		c.Credentials.SetHeaders(req)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		return req, nil
+	})
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// ListAll fetches every page of a list endpoint, following result_info
+// until the last page or until limit items were collected (limit <= 0 means
+// no limit).
+func ListAll[T any](ctx context.Context, c *Client, path string, perPage, limit int) ([]T, error) {
+	var out []T
+	for page := 1; ; page++ {
+		resp, err := c.Do(ctx, http.MethodGet, WithPageParams(path, page, perPage), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []T
+		if err := json.Unmarshal(resp.Result, &items); err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+
+		if limit > 0 && len(out) >= limit {
+			return out[:limit], nil
+		}
+		if len(items) == 0 || resp.ResultInfo == nil || page >= resp.ResultInfo.TotalPages {
+			return out, nil
+		}
+	}
+}
+
+// WithPageParams sets the page and per_page query parameters on path.
+func WithPageParams(path string, page, perPage int) string {
+	base, rawQuery, _ := strings.Cut(path, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		query = url.Values{}
+	}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+	return base + "?" + query.Encode()
+}
+
+// getResult sends a request and decodes its result into out.
+func (c *Client) getResult(ctx context.Context, method, path string, body, out any) error {
+	resp, err := c.Do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(resp.Result, out)
+}
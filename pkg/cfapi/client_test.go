@@ -0,0 +1,64 @@
+package cfapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cf/internal/cftest"
+)
+
+func TestWithPageParams(t *testing.T) {
+	got := WithPageParams("/zones?account.id=abc", 3, 50)
+	if got != "/zones?account.id=abc&page=3&per_page=50" {
+		t.Fatalf("unexpected path: %s", got)
+	}
+
+	got = WithPageParams("/zones/z1/dns_records", 1, 1000)
+	if got != "/zones/z1/dns_records?page=1&per_page=1000" {
+		t.Fatalf("unexpected path: %s", got)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if !IsNotFound(&APIError{Status: 404}) {
+		t.Fatalf("expected 404 to be not found")
+	}
+	if IsNotFound(&APIError{Status: 403, Errors: []Error{{Code: 10000, Message: "Authentication error"}}}) || IsNotFound(errors.New("x")) {
+		t.Fatalf("did not expect other errors to be not found")
+	}
+}
+
+func TestListAllPaginates(t *testing.T) {
+	srv := cftest.NewServer(t)
+	records := make([]DNSRecord, 5)
+	for i := range records {
+		records[i] = DNSRecord{ID: string(rune('a' + i)), Type: "A"}
+	}
+	srv.Reply("GET", "/zones/z1/dns_records", records)
+
+	c := NewClient(Credentials{Token: "t"})
+	c.BaseURL = srv.URL
+	got, err := ListAll[DNSRecord](context.Background(), c, "/zones/z1/dns_records", 2, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 5 || got[4].ID != "e" {
+		t.Fatalf("unexpected records: %+v", got)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Fatalf("expected 3 page requests, got %d", n)
+	}
+}
+
+func TestAPIErrorFromFakeServer(t *testing.T) {
+	srv := cftest.NewServer(t)
+	srv.Fail("GET", "/zones/*", 404, 1001, "Invalid zone identifier")
+
+	c := NewClient(Credentials{Token: "t"})
+	c.BaseURL = srv.URL
+	_, err := c.Do(context.Background(), "GET", "/zones/nope", nil)
+	if !IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
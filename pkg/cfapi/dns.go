@@ -0,0 +1,121 @@
+package cfapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DNSRecord is a record in a zone. Structured types such as SRV or CAA set
+// Data instead of Content.
+type DNSRecord struct {
+	ID       string         `json:"id"`
+	Type     string         `json:"type"`
+	Name     string         `json:"name"`
+	Content  string         `json:"content"`
+	TTL      int            `json:"ttl"`
+	Proxied  bool           `json:"proxied"`
+	Priority *int           `json:"priority,omitempty"`
+	Data     map[string]any `json:"data,omitempty"`
+	Comment  string         `json:"comment,omitempty"`
+	Tags     []string       `json:"tags,omitempty"`
+}
+
+// DNSRecordFilter narrows ListDNSRecords; empty fields match everything.
+// Name must be fully qualified.
+type DNSRecordFilter struct {
+	Type string
+	Name string
+	// NameContains matches part of the name; Content matches the whole
+	// content, ignoring case.
+	NameContains string
+	Content      string
+	// Tag matches records carrying a "name:value" tag, or any tag called
+	// name when there is no value.
+	Tag string
+}
+
+// ListDNSRecords returns the records in a zone; limit <= 0 means all of them.
+func (c *Client) ListDNSRecords(ctx context.Context, zoneID string, filter DNSRecordFilter, limit int) ([]DNSRecord, error) {
+	query := url.Values{}
+	if filter.Type != "" {
+		query.Set("type", filter.Type)
+	}
+	if filter.Name != "" {
+		query.Set("name", filter.Name)
+	}
+	if filter.NameContains != "" {
+		query.Set("name.contains", filter.NameContains)
+	}
+	if filter.Content != "" {
+		query.Set("content.exact", filter.Content)
+	}
+	if filter.Tag != "" {
+		if strings.Contains(filter.Tag, ":") {
+			query.Set("tag.exact", filter.Tag)
+		} else {
+			query.Set("tag.present", filter.Tag)
+		}
+	}
+	return ListAll[DNSRecord](ctx, c, "/zones/"+zoneID+"/dns_records?"+query.Encode(), 1000, limit)
+}
+
+// GetDNSRecord returns the record with the given ID.
+func (c *Client) GetDNSRecord(ctx context.Context, zoneID, recordID string) (*DNSRecord, error) {
+	var r DNSRecord
+	if err := c.getResult(ctx, http.MethodGet, "/zones/"+zoneID+"/dns_records/"+recordID, nil, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// CreateDNSRecord adds r to a zone and returns the created record.
+func (c *Client) CreateDNSRecord(ctx context.Context, zoneID string, r DNSRecord) (*DNSRecord, error) {
+	var created DNSRecord
+	if err := c.getResult(ctx, http.MethodPost, "/zones/"+zoneID+"/dns_records", dnsRecordBody(r), &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateDNSRecord replaces the record with ID r.ID.
+func (c *Client) UpdateDNSRecord(ctx context.Context, zoneID string, r DNSRecord) (*DNSRecord, error) {
+	var updated DNSRecord
+	if err := c.getResult(ctx, http.MethodPut, "/zones/"+zoneID+"/dns_records/"+r.ID, dnsRecordBody(r), &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteDNSRecord removes a record.
+func (c *Client) DeleteDNSRecord(ctx context.Context, zoneID, recordID string) error {
+	_, err := c.Do(ctx, http.MethodDelete, "/zones/"+zoneID+"/dns_records/"+recordID, nil)
+	return err
+}
+
+func dnsRecordBody(r DNSRecord) map[string]any {
+	body := map[string]any{
+		"type":    r.Type,
+		"name":    r.Name,
+		"content": r.Content,
+		"ttl":     r.TTL,
+		"proxied": r.Proxied,
+	}
+	if r.Priority != nil {
+		body["priority"] = *r.Priority
+	}
+	// PUT replaces the whole record, so these are always sent; leaving them
+	// out would clear them.
+	body["comment"] = r.Comment
+	body["tags"] = r.Tags
+	if r.Tags == nil {
+		body["tags"] = []string{}
+	}
+	if r.Data != nil {
+		// The API derives content from data for structured types.
+		delete(body, "content")
+		body["data"] = r.Data
+	}
+	return body
+}
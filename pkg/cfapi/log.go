@@ -0,0 +1,74 @@
+package cfapi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// logAttempt records one request attempt. Credentials are redacted so the
+// output can be pasted into bug reports.
+func (c *Client) logAttempt(req *http.Request, resp *http.Response, err error, elapsed time.Duration, attempt int) {
+	if c.Logger == nil {
+		return
+	}
+	attrs := []any{
+		"method", req.Method,
+		"url", redactURL(req.URL),
+		"auth", redactAuthHeader(req.Header),
+		"latency", elapsed.Round(time.Millisecond),
+	}
+	if attempt > 0 {
+		attrs = append(attrs, "attempt", attempt+1)
+	}
+	if err != nil {
+		c.Logger.Debug("http request failed", append(attrs, "error", err)...)
+		return
+	}
+	attrs = append(attrs, "status", resp.StatusCode)
+	if ray := resp.Header.Get("Cf-Ray"); ray != "" {
+		attrs = append(attrs, "cf_ray", ray)
+	}
+	c.Logger.Debug("http request", attrs...)
+}
+
+func redactAuthHeader(h http.Header) string {
+	if v := h.Get("Authorization"); v != "" {
+		scheme, token, _ := strings.Cut(v, " ")
+		return scheme + " " + redactSecret(token)
+	}
+	if v := h.Get("X-Auth-Key"); v != "" {
+		return "key " + redactSecret(v) + " email=" + h.Get("X-Auth-Email")
+	}
+	return "none"
+}
+
+// redactSecret keeps only the last four characters, enough to tell tokens
+// apart.
+func redactSecret(s string) string {
+	if len(s) <= 8 {
+		return "[redacted]"
+	}
+	return "[redacted]..." + s[len(s)-4:]
+}
+
+// redactURL hides query parameters that look like credentials, such as
+// signed upload URLs.
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	changed := false
+	for k := range q {
+		lk := strings.ToLower(k)
+		if strings.Contains(lk, "token") || strings.Contains(lk, "secret") || strings.Contains(lk, "signature") || strings.Contains(lk, "key") {
+			q.Set(k, "[redacted]")
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	cp := *u
+	cp.RawQuery = q.Encode()
+	return cp.String()
+}
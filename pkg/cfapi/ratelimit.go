@@ -0,0 +1,123 @@
+package cfapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit is Cloudflare's global limit of 1200 requests per five
+// minutes, in requests per second.
+const DefaultRateLimit = 1200.0 / 300
+
+// now is the limiter's clock; tests replace it.
+var now = time.Now
+
+// RateLimiter paces requests with a token bucket. Clients that count
+// against the same API limit should share one. A nil RateLimiter does not
+// limit.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	until  time.Time
+}
+
+// NewRateLimiter allows rps requests per second on average and up to burst
+// at once.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	b := float64(max(burst, 1))
+	return &RateLimiter{rate: rps, burst: b, tokens: b}
+}
+
+// Wait blocks until a request may be sent or ctx is done. Waiters queue:
+// each takes a token, going into debt when none is left, and sleeps until
+// its token would have been refilled.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	t := now()
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+t.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = t
+	l.tokens--
+	var d time.Duration
+	if l.tokens < 0 {
+		d = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if hold := l.until.Sub(t); hold > d {
+		d = hold
+	}
+	l.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	return sleep(ctx, d)
+}
+
+// HoldOff stops every waiter until d from now, e.g. after the server said
+// the limit is used up.
+func (l *RateLimiter) HoldOff(d time.Duration) {
+	if l == nil || d <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := now().Add(d); until.After(l.until) {
+		l.until = until
+	}
+}
+
+// observe holds off when resp reports the limit exhausted: a 429, or a
+// Ratelimit header with no requests remaining.
+func (l *RateLimiter) observe(resp *http.Response) {
+	if l == nil || resp == nil {
+		return
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		l.HoldOff(retryDelay(0, resp))
+		return
+	}
+	if d, ok := rateLimitReset(resp.Header); ok {
+		l.HoldOff(d)
+	}
+}
+
+// rateLimitReset reads the IETF draft header Cloudflare sends, e.g.
+// `Ratelimit: "default";r=0;t=30`, and returns how long to wait when no
+// requests remain.
+func rateLimitReset(h http.Header) (time.Duration, bool) {
+	v := h.Get("Ratelimit")
+	if v == "" {
+		return 0, false
+	}
+	remaining, reset := -1, -1
+	for _, part := range strings.Split(v, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "r":
+			remaining = n
+		case "t":
+			reset = n
+		}
+	}
+	if remaining != 0 || reset <= 0 {
+		return 0, false
+	}
+	return time.Duration(reset) * time.Second, true
+}
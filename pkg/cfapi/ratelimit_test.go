@@ -0,0 +1,106 @@
+package cfapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// useFakeClock makes the limiter's sleeps advance a fake clock and returns
+// the recorded sleeps.
+func useFakeClock(t *testing.T) *[]time.Duration {
+	origSleep, origNow := sleep, now
+	t.Cleanup(func() { sleep, now = origSleep, origNow })
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	var slept []time.Duration
+	sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		clock = clock.Add(d)
+		return nil
+	}
+	return &slept
+}
+
+func TestRateLimiterPaces(t *testing.T) {
+	slept := useFakeClock(t)
+	l := NewRateLimiter(2, 2)
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}
+	if len(*slept) != len(want) || (*slept)[0] != want[0] || (*slept)[1] != want[1] {
+		t.Fatalf("expected the burst to pass and then 2 rps, got sleeps %v", *slept)
+	}
+
+	l.HoldOff(3 * time.Second)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := (*slept)[len(*slept)-1]; got != 3*time.Second {
+		t.Fatalf("expected to wait out the hold-off, got %v", got)
+	}
+}
+
+func TestRateLimiterNilDoesNotLimit(t *testing.T) {
+	slept := useFakeClock(t)
+	var l *RateLimiter
+	for i := 0; i < 10; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.HoldOff(time.Minute)
+	if len(*slept) != 0 {
+		t.Fatalf("expected no sleeps, got %v", *slept)
+	}
+}
+
+func TestDoWithRetry_HoldsOffOnRateLimitHeader(t *testing.T) {
+	slept := useFakeClock(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Ratelimit", `"default";r=0;t=7`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := NewClient(Credentials{})
+	c.Limiter = NewRateLimiter(100, 10)
+	for i := 0; i < 2; i++ {
+		resp, err := c.doWithRetry(context.Background(), func() (*http.Request, error) {
+			return http.NewRequest(http.MethodGet, srv.URL, nil)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if len(*slept) != 1 || (*slept)[0] != 7*time.Second {
+		t.Fatalf("expected the second request to wait for the reset, got %v", *slept)
+	}
+}
+
+func TestRateLimitReset(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{`"default";r=0;t=30`, 30 * time.Second, true},
+		{`"default";r=5;t=30`, 0, false},
+		{"", 0, false},
+		{`"default";r=0`, 0, false},
+	} {
+		h := http.Header{}
+		if tc.header != "" {
+			h.Set("Ratelimit", tc.header)
+		}
+		if got, ok := rateLimitReset(h); got != tc.want || ok != tc.ok {
+			t.Fatalf("%q: got %v, %v; want %v, %v", tc.header, got, ok, tc.want, tc.ok)
+		}
+	}
+}
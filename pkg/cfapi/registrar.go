@@ -0,0 +1,45 @@
+package cfapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// RegistrarDomain is a domain registered with Cloudflare Registrar.
+type RegistrarDomain struct {
+	Name             string `json:"name"`
+	AutoRenew        bool   `json:"auto_renew"`
+	Locked           bool   `json:"locked"`
+	Privacy          bool   `json:"privacy"`
+	ExpiresAt        string `json:"expires_at,omitempty"`
+	CreatedAt        string `json:"created_at,omitempty"`
+	CurrentRegistrar string `json:"current_registrar,omitempty"`
+	RegistryStatuses string `json:"registry_statuses,omitempty"`
+}
+
+func registrarDomainPath(accountID, domain string) string {
+	return "/accounts/" + accountID + "/registrar/domains/" + url.PathEscape(domain)
+}
+
+// ListRegistrarDomains returns the account's registered domains; limit <= 0
+// means all of them.
+func (c *Client) ListRegistrarDomains(ctx context.Context, accountID string, limit int) ([]RegistrarDomain, error) {
+	return ListAll[RegistrarDomain](ctx, c, "/accounts/"+accountID+"/registrar/domains", 50, limit)
+}
+
+// GetRegistrarDomain decodes a registered domain into out, which may be a
+// *RegistrarDomain or any struct covering the fields the caller needs.
+func (c *Client) GetRegistrarDomain(ctx context.Context, accountID, domain string, out any) error {
+	return c.getResult(ctx, http.MethodGet, registrarDomainPath(accountID, domain), nil, out)
+}
+
+// UpdateRegistrarDomain changes auto_renew, locked or privacy; only the
+// fields present in settings are sent.
+func (c *Client) UpdateRegistrarDomain(ctx context.Context, accountID, domain string, settings map[string]bool) (*RegistrarDomain, error) {
+	var d RegistrarDomain
+	if err := c.getResult(ctx, http.MethodPut, registrarDomainPath(accountID, domain), settings, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
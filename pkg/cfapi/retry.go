@@ -0,0 +1,113 @@
+package cfapi
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxRetries is the retry budget NewClient sets.
+const DefaultMaxRetries = 3
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// sleep waits for d or until ctx is done; tests replace it.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// doWithRetry sends the request built by newReq, retrying rate-limited (429)
+// responses and, for idempotent methods, transient 5xx responses and network
+// errors. newReq is called once per attempt so request bodies can be replayed.
+func (c *Client) doWithRetry(ctx context.Context, newReq func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newReq()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := c.httpClient().Do(req)
+		c.logAttempt(req, resp, err, time.Since(start), attempt)
+		c.Limiter.observe(resp)
+		if attempt >= c.MaxRetries || ctx.Err() != nil || !shouldRetry(req.Method, resp, err) {
+			return resp, err
+		}
+
+		delay := retryDelay(attempt, resp)
+		if c.Logger != nil {
+			c.Logger.Debug("retrying", "method", req.Method, "url", redactURL(req.URL), "delay", delay)
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func shouldRetry(method string, resp *http.Response, err error) bool {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if !Idempotent(method) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500
+}
+
+// Idempotent reports whether repeating a request with method cannot leave
+// a second copy of what it changes behind.
+func Idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryDelay honors Retry-After when the server sends it, otherwise it uses
+// exponential backoff with jitter.
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if v := strings.TrimSpace(resp.Header.Get("Retry-After")); v != "" {
+			if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+				return time.Duration(secs) * time.Second
+			}
+			if at, err := http.ParseTime(v); err == nil {
+				if d := time.Until(at); d > 0 {
+					return d
+				}
+				return 0
+			}
+		}
+	}
+
+	d := retryBaseDelay << attempt
+	if d <= 0 || d > retryMaxDelay {
+		d = retryMaxDelay
+	}
+	return d/2 + rand.N(d/2+1)
+}
@@ -0,0 +1,75 @@
+package cfapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDoWithRetry_RetriesRateLimited(t *testing.T) {
+	origSleep := sleep
+	t.Cleanup(func() { sleep = origSleep })
+	var slept []time.Duration
+	sleep = func(_ context.Context, d time.Duration) error { slept = append(slept, d); return nil }
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	resp, err := NewClient(Credentials{}).doWithRetry(context.Background(), func() (*http.Request, error) {
+		return http.NewRequest(http.MethodPost, srv.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Fatalf("expected success on third call, got status %d after %d calls", resp.StatusCode, calls)
+	}
+	if len(slept) != 2 || slept[0] != 2*time.Second {
+		t.Fatalf("expected Retry-After delays, got %v", slept)
+	}
+}
+
+func TestDoWithRetry_DoesNotRetryPostOnServerError(t *testing.T) {
+	origSleep := sleep
+	t.Cleanup(func() { sleep = origSleep })
+	sleep = func(context.Context, time.Duration) error { return nil }
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	resp, err := NewClient(Credentials{}).doWithRetry(context.Background(), func() (*http.Request, error) {
+		return http.NewRequest(http.MethodPost, srv.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if calls != 1 {
+		t.Fatalf("expected POST not to be retried on 5xx, got %d calls", calls)
+	}
+}
+
+func TestRetryDelayBackoffBounds(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		d := retryDelay(attempt, nil)
+		if d <= 0 || d > retryMaxDelay {
+			t.Fatalf("attempt %d: delay %v out of bounds", attempt, d)
+		}
+	}
+}
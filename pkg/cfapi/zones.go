@@ -0,0 +1,119 @@
+package cfapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Zone is a domain added to a Cloudflare account.
+type Zone struct {
+	ID                  string       `json:"id"`
+	Name                string       `json:"name"`
+	Status              string       `json:"status"`
+	Type                string       `json:"type,omitempty"`
+	Paused              bool         `json:"paused"`
+	NameServers         []string     `json:"name_servers,omitempty"`
+	VanityNameServers   []string     `json:"vanity_name_servers,omitempty"`
+	OriginalNameServers []string     `json:"original_name_servers,omitempty"`
+	OriginalRegistrar   string       `json:"original_registrar,omitempty"`
+	OriginalDNSHost     string       `json:"original_dnshost,omitempty"`
+	DevelopmentMode     int          `json:"development_mode,omitempty"`
+	CreatedOn           string       `json:"created_on,omitempty"`
+	ModifiedOn          string       `json:"modified_on,omitempty"`
+	ActivatedOn         string       `json:"activated_on,omitempty"`
+	VerificationKey     string       `json:"verification_key,omitempty"`
+	Plan                *ZonePlan    `json:"plan,omitempty"`
+	Account             *ZoneAccount `json:"account,omitempty"`
+	Meta                *ZoneMeta    `json:"meta,omitempty"`
+}
+
+// ZonePlan is the plan a zone is subscribed to.
+type ZonePlan struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Price     float64 `json:"price"`
+	Currency  string  `json:"currency,omitempty"`
+	Frequency string  `json:"frequency,omitempty"`
+	LegacyID  string  `json:"legacy_id,omitempty"`
+}
+
+// ZoneAccount identifies the account that owns a zone.
+type ZoneAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ZoneMeta holds the zone's quotas and feature flags.
+type ZoneMeta struct {
+	PageRuleQuota          int  `json:"page_rule_quota,omitempty"`
+	CustomCertificateQuota int  `json:"custom_certificate_quota,omitempty"`
+	WildcardProxiable      bool `json:"wildcard_proxiable,omitempty"`
+	PhishingDetected       bool `json:"phishing_detected,omitempty"`
+	CDNOnly                bool `json:"cdn_only,omitempty"`
+	DNSOnly                bool `json:"dns_only,omitempty"`
+	FoundationDNS          bool `json:"foundation_dns,omitempty"`
+}
+
+// CreateZoneParams describes a zone to add. Type is "full" (the default),
+// where Cloudflare hosts DNS, or "partial" for a CNAME setup that keeps DNS
+// at the current provider.
+type CreateZoneParams struct {
+	AccountID string
+	Name      string
+	Type      string
+	JumpStart bool
+}
+
+// ListZones returns the zones in an account; limit <= 0 means all of them.
+func (c *Client) ListZones(ctx context.Context, accountID string, limit int) ([]Zone, error) {
+	return ListAll[Zone](ctx, c, "/zones?account.id="+url.QueryEscape(accountID), 50, limit)
+}
+
+// ZoneByName looks a zone up by name. It returns nil and no error when the
+// account has no such zone.
+func (c *Client) ZoneByName(ctx context.Context, accountID, name string) (*Zone, error) {
+	query := url.Values{"account.id": {accountID}, "name": {name}, "per_page": {"1"}}
+	var zones []Zone
+	if err := c.getResult(ctx, http.MethodGet, "/zones?"+query.Encode(), nil, &zones); err != nil {
+		return nil, err
+	}
+	if len(zones) == 0 {
+		return nil, nil
+	}
+	return &zones[0], nil
+}
+
+// CreateZone adds a zone to an account.
+func (c *Client) CreateZone(ctx context.Context, p CreateZoneParams) (*Zone, error) {
+	if p.Type == "" {
+		p.Type = "full"
+	}
+	body := map[string]any{
+		"account":    map[string]string{"id": p.AccountID},
+		"jump_start": p.JumpStart,
+		"name":       p.Name,
+		"type":       p.Type,
+	}
+	var z Zone
+	if err := c.getResult(ctx, http.MethodPost, "/zones", body, &z); err != nil {
+		return nil, err
+	}
+	return &z, nil
+}
+
+// SetZonePaused pauses or unpauses a zone. A paused zone still answers DNS
+// but proxied traffic goes straight to the origin.
+func (c *Client) SetZonePaused(ctx context.Context, zoneID string, paused bool) (*Zone, error) {
+	var z Zone
+	if err := c.getResult(ctx, http.MethodPatch, "/zones/"+zoneID, map[string]bool{"paused": paused}, &z); err != nil {
+		return nil, err
+	}
+	return &z, nil
+}
+
+// DeleteZone removes a zone and all of its configuration.
+func (c *Client) DeleteZone(ctx context.Context, zoneID string) error {
+	_, err := c.Do(ctx, http.MethodDelete, "/zones/"+zoneID, nil)
+	return err
+}